@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/macros"
+)
+
+// the custom model macro "set-owner" assigns an owner to a selection of technical assets
+
+const (
+	ownerQuestion  = "owner"
+	assetsQuestion = "technical-assets"
+)
+
+func main() {
+	getDetails := flag.Bool("get-details", false, "get macro details")
+	getNextQuestion := flag.Bool("get-next-question", false, "get next question")
+	applyAnswer := flag.Bool("apply-answer", false, "apply answer")
+	getFinalChangeImpact := flag.Bool("get-final-change-impact", false, "get final change impact")
+	execute := flag.Bool("execute", false, "execute macro")
+	flag.Parse()
+
+	if *getDetails {
+		writeResponse(macros.MacroDetails{
+			ID:          "set-owner",
+			Title:       "Set Owner",
+			Description: "This model macro sets the owner of the selected technical assets.",
+		})
+	}
+
+	request := readRequest()
+	response := macros.CustomMacroResponse{ValidResult: true}
+	switch {
+	case *getNextQuestion:
+		response.Question = nextQuestion(request)
+
+	case *applyAnswer:
+		response.Message, response.ValidResult = checkAnswer(request)
+
+	case *getFinalChangeImpact:
+		for _, title := range answer(request, assetsQuestion) {
+			response.Changes = append(response.Changes, fmt.Sprintf("set owner of technical asset %q to %q", title, ownerName(request)))
+		}
+
+	case *execute:
+		if request.ModelInput == nil {
+			fail("no model input")
+		}
+		for _, title := range answer(request, assetsQuestion) {
+			asset, ok := request.ModelInput.TechnicalAssets[title]
+			if !ok {
+				response.Message, response.ValidResult = fmt.Sprintf("unknown technical asset %q", title), false
+				break
+			}
+			asset.Owner = ownerName(request)
+			request.ModelInput.TechnicalAssets[title] = asset
+		}
+		if response.ValidResult {
+			response.Message = "Changeset valid"
+			response.ModelInput = request.ModelInput
+		}
+
+	default:
+		flag.Usage()
+		os.Exit(-2)
+	}
+
+	writeResponse(response)
+}
+
+func nextQuestion(request *macros.CustomMacroRequest) *macros.MacroQuestion {
+	switch len(request.Answers) {
+	case 0:
+		return &macros.MacroQuestion{
+			ID:          ownerQuestion,
+			Title:       "What is the name of the owner?",
+			Description: "The owner is set on all technical assets selected in the next step.",
+		}
+
+	case 1:
+		titles := make([]string, 0)
+		if request.ParsedModel != nil {
+			for _, asset := range request.ParsedModel.TechnicalAssets {
+				titles = append(titles, asset.Title)
+			}
+		}
+		sort.Strings(titles)
+		return &macros.MacroQuestion{
+			ID:              assetsQuestion,
+			Title:           "Which technical assets should be owned by " + ownerName(request) + "?",
+			PossibleAnswers: titles,
+			MultiSelect:     true,
+		}
+	}
+
+	return nil
+}
+
+func checkAnswer(request *macros.CustomMacroRequest) (string, bool) {
+	switch request.QuestionID {
+	case ownerQuestion:
+		if len(request.Answer) != 1 || len(strings.TrimSpace(request.Answer[0])) == 0 {
+			return "Please enter the name of the owner", false
+		}
+
+	case assetsQuestion:
+		if len(request.Answer) == 0 {
+			return "Please select at least one technical asset", false
+		}
+
+	default:
+		return "Unknown question: " + request.QuestionID, false
+	}
+
+	return "Answer processed", true
+}
+
+func answer(request *macros.CustomMacroRequest, questionID string) []string {
+	for _, given := range request.Answers {
+		if given.QuestionID == questionID {
+			return given.Answer
+		}
+	}
+	return nil
+}
+
+func ownerName(request *macros.CustomMacroRequest) string {
+	return strings.TrimSpace(strings.Join(answer(request, ownerQuestion), " "))
+}
+
+func readRequest() *macros.CustomMacroRequest {
+	inData, readError := io.ReadAll(bufio.NewReader(os.Stdin))
+	if readError != nil {
+		fail("failed to read request from stdin")
+	}
+
+	request := new(macros.CustomMacroRequest)
+	parseError := json.Unmarshal(inData, request)
+	if parseError != nil {
+		fail(fmt.Sprintf("failed to parse request: %v", parseError))
+	}
+	return request
+}
+
+func writeResponse(response any) {
+	outData, marshalError := json.Marshal(response)
+	if marshalError != nil {
+		fail(fmt.Sprintf("failed to print response: %v", marshalError))
+	}
+
+	_, _ = os.Stdout.Write(outData)
+	os.Exit(0)
+}
+
+func fail(message string) {
+	_, _ = fmt.Fprintln(os.Stderr, message)
+	os.Exit(-2)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"os"
 
 	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/plugin"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
@@ -21,23 +23,26 @@ var CustomRiskRule customRiskRule
 func main() {
 	getInfo := flag.Bool("get-info", false, "get rule info")
 	generateRisks := flag.Bool("generate-risks", false, "generate risks")
+	serve := flag.Bool("serve", false, "serve requests via the long-lived plugin protocol on stdin/stdout")
 	flag.Parse()
 
-	if *getInfo {
-		rule := new(customRiskRule)
-		category := rule.Category()
-		riskData, marshalError := json.Marshal(model.CustomRisk{
-			ID:       category.Id,
-			Category: category,
-			Tags:     rule.SupportedTags(),
-		})
+	if *serve {
+		serveError := plugin.Serve(os.Stdin, os.Stdout, handleRequest)
+		if serveError != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to serve requests: %v\n", serveError)
+			os.Exit(-2)
+		}
+		os.Exit(0)
+	}
 
+	if *getInfo {
+		riskData, marshalError := json.Marshal(getInfoData())
 		if marshalError != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "failed to print risk data: %v", marshalError)
 			os.Exit(-2)
 		}
 
-		_, _ = fmt.Fprint(os.Stdout, riskData)
+		_, _ = os.Stdout.Write(riskData)
 		os.Exit(0)
 	}
 
@@ -63,7 +68,7 @@ func main() {
 			os.Exit(-2)
 		}
 
-		_, _ = fmt.Fprint(os.Stdout, outData)
+		_, _ = os.Stdout.Write(outData)
 		os.Exit(0)
 	}
 
@@ -71,6 +76,34 @@ func main() {
 	os.Exit(-2)
 }
 
+func handleRequest(_ context.Context, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case plugin.GetInfoMethod:
+		return getInfoData(), nil
+
+	case plugin.GenerateRisksMethod:
+		var input types.ParsedModel
+		inError := json.Unmarshal(params, &input)
+		if inError != nil {
+			return nil, plugin.NewError(plugin.InvalidParamsCode, "failed to parse model: %v", inError)
+		}
+
+		return new(customRiskRule).GenerateRisks(&input), nil
+	}
+
+	return nil, plugin.NewError(plugin.MethodNotFoundCode, "unknown method %q", method)
+}
+
+func getInfoData() model.CustomRisk {
+	rule := new(customRiskRule)
+	category := rule.Category()
+	return model.CustomRisk{
+		ID:       category.Id,
+		Category: category,
+		Tags:     rule.SupportedTags(),
+	}
+}
+
 func (r customRiskRule) Category() types.RiskCategory {
 	return types.RiskCategory{
 		Id:                         "demo",
@@ -1,6 +1,11 @@
 package threagile
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/model"
@@ -13,21 +18,61 @@ func (what *Threagile) initAnalyze() *Threagile {
 		Short:   "Analyze model",
 		Aliases: []string{"analyze", "analyse", "run", "analyse-model"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg := what.readConfig(cmd, what.buildTimestamp)
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
 			commands := what.readCommands()
 			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
 
-			r, err := model.ReadAndAnalyzeModel(*cfg, progressReporter)
+			err = what.readRiskPolicy(cmd, cfg)
 			if err != nil {
-				cmd.Printf("Failed to read and analyze model: %v", err)
+				cmd.Printf("Invalid risk policy: %v\n", err)
 				return err
 			}
 
-			err = report.Generate(cfg, r, commands, progressReporter)
+			variants, err := what.readVariants(cfg)
 			if err != nil {
-				cmd.Printf("Failed to generate reports: %v \n", err)
+				cmd.Printf("Invalid variants: %v\n", err)
 				return err
 			}
+
+			violated := false
+			for _, variant := range variants {
+				if len(variant.name) > 0 {
+					progressReporter.Info("Analyzing", variant)
+					err = os.MkdirAll(variant.config.OutputFolder, 0700)
+					if err != nil {
+						cmd.Printf("Failed to create output dir: %v\n", err)
+						return err
+					}
+				}
+				r, err := model.ReadAndAnalyzeModel(variant.config, progressReporter)
+				if err != nil {
+					cmd.Printf("Failed to read and analyze %v: %v", variant, err)
+					return err
+				}
+
+				err = report.Generate(&variant.config, r, commands, progressReporter)
+				if err != nil {
+					cmd.Printf("Failed to generate reports: %v \n", err)
+					return err
+				}
+
+				violations, err := model.CheckRiskPolicy(variant.config.RiskPolicy, r.ParsedModel)
+				if err != nil {
+					cmd.Printf("Invalid risk policy: %v\n", err)
+					return err
+				}
+				if len(violations) > 0 {
+					printRiskPolicyViolations(cmd, variant, violations)
+					violated = true
+				}
+			}
+			if violated {
+				return fmt.Errorf("risk policy violated")
+			}
 			return nil
 		},
 		CompletionOptions: cobra.CompletionOptions{
@@ -35,7 +80,72 @@ func (what *Threagile) initAnalyze() *Threagile {
 		},
 	}
 
+	analyze.Flags().StringVar(&what.flags.failOnRiskSeverityFlag, failOnRiskSeverityFlagName, "", "fail if any risk of at least this severity is found (low, medium, elevated, high or critical)")
+	analyze.Flags().StringVar(&what.flags.maxRisksFlag, maxRisksFlagName, "", "comma-separated list of maximum risk counts allowed per severity, e.g. critical=0,high=3")
+	analyze.Flags().StringVar(&what.flags.riskPolicyStatusesFlag, riskPolicyStatusesFlagName, "", "comma-separated list of risk statuses taken into account by the risk policy (default: all statuses still at risk)")
+	analyze.Flags().BoolVar(&what.flags.failOnExpiredRiskAcceptancesFlag, failOnExpiredRiskAcceptancesFlagName, false, "fail if the risk tracking of any risk (e.g. an acceptance) has expired")
+
 	what.rootCmd.AddCommand(analyze)
 
 	return what
 }
+
+func (what *Threagile) readRiskPolicy(cmd *cobra.Command, cfg *common.Config) error {
+	flags := cmd.Flags()
+	if isFlagOverridden(flags, failOnRiskSeverityFlagName) {
+		cfg.RiskPolicy.FailOnSeverity = what.flags.failOnRiskSeverityFlag
+	}
+	if isFlagOverridden(flags, failOnExpiredRiskAcceptancesFlagName) {
+		cfg.RiskPolicy.FailOnExpiredRiskTracking = what.flags.failOnExpiredRiskAcceptancesFlag
+	}
+	if isFlagOverridden(flags, riskPolicyStatusesFlagName) {
+		cfg.RiskPolicy.Statuses = splitList(what.flags.riskPolicyStatusesFlag)
+	}
+	if isFlagOverridden(flags, maxRisksFlagName) {
+		maxRisks := make(map[string]int)
+		for _, entry := range splitList(what.flags.maxRisksFlag) {
+			severity, value, found := strings.Cut(entry, "=")
+			if !found {
+				return fmt.Errorf("invalid %v entry %q: expected severity=count", maxRisksFlagName, entry)
+			}
+			count, parseError := strconv.Atoi(strings.TrimSpace(value))
+			if parseError != nil || count < 0 {
+				return fmt.Errorf("invalid %v entry %q: count must be a non-negative number", maxRisksFlagName, entry)
+			}
+			maxRisks[strings.TrimSpace(severity)] = count
+		}
+		cfg.RiskPolicy.MaxRisks = maxRisks
+	}
+	return nil
+}
+
+func printRiskPolicyViolations(cmd *cobra.Command, variant variant, violations []model.RiskPolicyViolation) {
+	const maxRisksListed = 10
+
+	if len(variant.name) > 0 {
+		cmd.PrintErrf("Risk policy violated by %v:\n", variant)
+	} else {
+		cmd.PrintErrln("Risk policy violated:")
+	}
+	for _, violation := range violations {
+		cmd.PrintErrf("  - %v\n", violation.Message)
+		for i, risk := range violation.Risks {
+			if i == maxRisksListed {
+				cmd.PrintErrf("      ... and %d more\n", len(violation.Risks)-maxRisksListed)
+				break
+			}
+			cmd.PrintErrf("      [%v] %v\n", risk.Severity.String(), risk.SyntheticId)
+		}
+	}
+}
+
+func splitList(value string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) > 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package threagile
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func (what *Threagile) initDiff() *Threagile {
+	diff := &cobra.Command{
+		Use:     common.DiffModelsCommand + " <old-model> <new-model>",
+		Short:   "Compare two model versions and their risks",
+		Long:    "Compare two model yaml files: report added, removed and changed technical assets, communication links, data assets and trust boundaries as well as newly introduced and resolved risks (keyed by their synthetic ID).",
+		Aliases: []string{"diff-models"},
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+			var failOnSeverity *types.RiskSeverity
+			if len(what.flags.failOnNewRiskSeverityFlag) > 0 {
+				severity, err := types.ParseRiskSeverity(what.flags.failOnNewRiskSeverityFlag)
+				if err != nil {
+					cmd.Printf("Invalid value for %v: %v\n", failOnNewRiskSeverityFlagName, err)
+					return err
+				}
+				failOnSeverity = &severity
+			}
+
+			oldConfig := *cfg
+			oldConfig.InputFile = cfg.CleanPath(args[0])
+			oldResult, err := model.ReadAndAnalyzeModel(oldConfig, progressReporter)
+			if err != nil {
+				cmd.Printf("Failed to read and analyze model %q: %v\n", oldConfig.InputFile, err)
+				return err
+			}
+
+			newConfig := *cfg
+			newConfig.InputFile = cfg.CleanPath(args[1])
+			newResult, err := model.ReadAndAnalyzeModel(newConfig, progressReporter)
+			if err != nil {
+				cmd.Printf("Failed to read and analyze model %q: %v\n", newConfig.InputFile, err)
+				return err
+			}
+
+			modelDiff := model.DiffModels(oldResult.ParsedModel, newResult.ParsedModel)
+			modelDiff.OldModel = args[0]
+			modelDiff.NewModel = args[1]
+
+			err = report.WriteModelDiff(cmd.OutOrStdout(), modelDiff, what.flags.diffFormatFlag)
+			if err != nil {
+				cmd.Printf("Failed to write model diff: %v\n", err)
+				return err
+			}
+
+			if failOnSeverity != nil {
+				violations := modelDiff.NewRisksStillAtRisk(newResult.ParsedModel, *failOnSeverity)
+				if len(violations) > 0 {
+					cmd.PrintErrf("%d new or escalated risk(s) of severity %v or above introduced\n", len(violations), failOnSeverity.String())
+					return fmt.Errorf("%d new or escalated risk(s) of severity %v or above introduced", len(violations), failOnSeverity.String())
+				}
+			}
+			return nil
+		},
+	}
+
+	diff.Flags().StringVar(&what.flags.diffFormatFlag, diffFormatFlagName, report.DiffFormatText, "output format: "+report.DiffFormatText+", "+report.DiffFormatJSON+" or "+report.DiffFormatMarkdown)
+	diff.Flags().StringVar(&what.flags.failOnNewRiskSeverityFlag, failOnNewRiskSeverityFlagName, "", "exit with a non-zero code if a new or escalated (more severe or at risk again), still at risk risk of at least this severity appears (low, medium, elevated, high or critical)")
+
+	what.rootCmd.AddCommand(diff)
+
+	return what
+}
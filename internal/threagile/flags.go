@@ -18,53 +18,125 @@ const (
 	outputFlagName  = "output"
 	tempDirFlagName = "temp-dir"
 
-	serverDirFlagName  = "server-dir"
-	serverPortFlagName = "server-port"
+	serverDirFlagName     = "server-dir"
+	serverPortFlagName    = "server-port"
+	serverStorageFlagName = "server-storage"
+	toStorageFlagName     = "to-storage"
 
 	inputFileFlagName = "model"
 	raaPluginFlagName = "raa-run"
+	setFlagName       = "set"
+	valuesFlagName    = "values"
 
 	customRiskRulesPluginFlagName      = "custom-risk-rules-plugin"
+	customModelMacrosPluginFlagName    = "custom-model-macros-plugin"
+	customRiskRulesTimeoutFlagName     = "custom-risk-rules-timeout"
 	diagramDpiFlagName                 = "diagram-dpi"
+	diagramFormatsFlagName             = "diagram-formats"
 	skipRiskRulesFlagName              = "skip-risk-rules"
 	ignoreOrphanedRiskTrackingFlagName = "ignore-orphaned-risk-tracking"
 	templateFileNameFlagName           = "background"
+	maxAttackPathsFlagName             = "max-attack-paths"
+
+	failOnRiskSeverityFlagName = "fail-on-risk-severity"
+	maxRisksFlagName           = "max-risks"
+	riskPolicyStatusesFlagName = "risk-policy-statuses"
+
+	failOnExpiredRiskAcceptancesFlagName = "fail-on-expired-risk-acceptances"
+
+	diffFormatFlagName            = "format"
+	failOnNewRiskSeverityFlagName = "fail-on-new-risk-severity"
+
+	trustBoundaryFlagName  = "trust-boundary"
+	technicalAssetFlagName = "technical-asset"
+	hopsFlagName           = "hops"
+	tagsFlagName           = "tags"
+
+	mergeFlagName = "merge"
+
+	answersFileFlagName = "answers"
+	answerFlagName      = "answer"
+	dryRunFlagName      = "dry-run"
+
+	trackerFlagName        = "tracker"
+	trackerURLFlagName     = "tracker-url"
+	trackerProjectFlagName = "tracker-project"
 
 	generateDataFlowDiagramFlagName     = "generate-data-flow-diagram"
 	generateDataAssetDiagramFlagName    = "generate-data-asset-diagram"
 	generateRisksJSONFlagName           = "generate-risks-json"
 	generateTechnicalAssetsJSONFlagName = "generate-technical-assets-json"
 	generateStatsJSONFlagName           = "generate-stats-json"
+	generateRisksSARIFFlagName          = "generate-risks-sarif"
+	generateAttackPathsJSONFlagName     = "generate-attack-paths-json"
 	generateRisksExcelFlagName          = "generate-risks-excel"
 	generateTagsExcelFlagName           = "generate-tags-excel"
 	generateReportPDFFlagName           = "generate-report-pdf"
+	generateReportHTMLFlagName          = "generate-report-html"
+	generateSubDiagramsFlagName         = "generate-sub-diagrams"
 )
 
 type Flags struct {
-	configFlag      string
-	verboseFlag     bool
-	interactiveFlag bool
-	appDirFlag      string
-	binDirFlag      string
-	outputDirFlag   string
-	tempDirFlag     string
-	inputFileFlag   string
-	raaPluginFlag   string
-	serverPortFlag  int
-	serverDirFlag   string
+	configFlag        string
+	verboseFlag       bool
+	interactiveFlag   bool
+	appDirFlag        string
+	binDirFlag        string
+	outputDirFlag     string
+	tempDirFlag       string
+	inputFileFlag     string
+	raaPluginFlag     string
+	setFlag           []string
+	valuesFlag        []string
+	serverPortFlag    int
+	serverDirFlag     string
+	serverStorageFlag string
+	toStorageFlag     string
 
 	skipRiskRulesFlag              string
 	customRiskRulesPluginFlag      string
+	customModelMacrosPluginFlag    string
+	customRiskRulesTimeoutFlag     int
 	ignoreOrphanedRiskTrackingFlag bool
 	templateFileNameFlag           string
 	diagramDpiFlag                 int
+	diagramFormatsFlag             string
+	maxAttackPathsFlag             int
+
+	failOnRiskSeverityFlag string
+	maxRisksFlag           string
+	riskPolicyStatusesFlag string
+
+	failOnExpiredRiskAcceptancesFlag bool
+
+	diffFormatFlag            string
+	failOnNewRiskSeverityFlag string
+
+	trustBoundaryFlag  string
+	technicalAssetFlag string
+	hopsFlag           int
+	tagsFlag           string
+
+	mergeFlag bool
+
+	answersFileFlag string
+	answerFlag      []string
+	dryRunFlag      bool
+
+	trackerFlag        string
+	trackerURLFlag     string
+	trackerProjectFlag string
 
 	generateDataFlowDiagramFlag     bool
 	generateDataAssetDiagramFlag    bool
 	generateRisksJSONFlag           bool
 	generateTechnicalAssetsJSONFlag bool
 	generateStatsJSONFlag           bool
+	generateRisksSARIFFlag          bool
+	generateAttackPathsJSONFlag     bool
 	generateRisksExcelFlag          bool
 	generateTagsExcelFlag           bool
 	generateReportPDFFlag           bool
+	generateReportHTMLFlag          bool
+	generateSubDiagramsFlag         bool
 }
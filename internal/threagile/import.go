@@ -0,0 +1,84 @@
+package threagile
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func (what *Threagile) initImport() *Threagile {
+	importTerraform := &cobra.Command{
+		Use:   common.ImportTerraformCommand + " <terraform-json>",
+		Short: "Import technical assets and trust boundaries from terraform",
+		Long: "Import the output of 'terraform show -json' (of a state or a plan) for common AWS, Azure and GCP resources: technical assets, trust boundaries from networks, subnets and security groups, " +
+			"and communication links inferred from AWS security group rules and GCP firewall rules. The draft model is written as " + common.TerraformModelFilename + " into the output directory, " +
+			"or merged into the model file with --" + mergeFlagName + " (keeping all hand-written fields).",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
+
+			imported, err := importer.ImportTerraformFile(cfg.CleanPath(args[0]))
+			if err != nil {
+				cmd.Printf("Failed to import terraform: %v\n", err)
+				return err
+			}
+
+			modelFile := filepath.Join(cfg.OutputFolder, common.TerraformModelFilename)
+			modelInput := imported
+			if what.flags.mergeFlag {
+				modelFile = cfg.InputFile
+				modelYaml, err := os.ReadFile(filepath.Clean(modelFile))
+				if err != nil {
+					cmd.Printf("Unable to read model file: %v\n", err)
+					return err
+				}
+				modelInput = new(input.Model).Defaults()
+				err = yaml.Unmarshal(modelYaml, modelInput)
+				if err != nil {
+					cmd.Printf("Unable to parse model yaml: %v\n", err)
+					return err
+				}
+				for _, change := range importer.MergeImportedModel(modelInput, imported) {
+					cmd.Println(" -", change)
+				}
+
+				backupFilename := modelFile + ".backup"
+				cmd.Println("Creating backup model file:", backupFilename)
+				err = os.WriteFile(backupFilename, modelYaml, 0600)
+				if err != nil {
+					cmd.Printf("Unable to write backup model file: %v\n", err)
+					return err
+				}
+			}
+
+			yamlBytes, err := yaml.Marshal(modelInput)
+			if err != nil {
+				cmd.Printf("Unable to serialize model: %v\n", err)
+				return err
+			}
+			err = os.WriteFile(modelFile, yamlBytes, 0600)
+			if err != nil {
+				cmd.Printf("Unable to write model file: %v\n", err)
+				return err
+			}
+			cmd.Printf("Imported %d technical assets and %d trust boundaries into %v\n", len(imported.TechnicalAssets), len(imported.TrustBoundaries), modelFile)
+			return nil
+		},
+	}
+
+	importTerraform.Flags().BoolVar(&what.flags.mergeFlag, mergeFlagName, false, "merge into the model file instead of writing a draft model")
+
+	what.rootCmd.AddCommand(importTerraform)
+
+	return what
+}
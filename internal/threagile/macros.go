@@ -19,18 +19,26 @@ func (what *Threagile) initMacros() *Threagile {
 	what.rootCmd.AddCommand(&cobra.Command{
 		Use:   common.ListModelMacrosCommand,
 		Short: "Print model macros",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.Println(docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp))
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
+			customMacros := macros.ListCustomMacros(cfg.ModelMacroPlugins, common.DefaultProgressReporter{Verbose: cfg.Verbose})
 			cmd.Println("The following model macros are available (can be extended via custom model macros):")
 			cmd.Println()
-			/* TODO finish plugin stuff
-			cmd.Println("Custom model macros:")
-			for _, macros := range macros.ListCustomMacros() {
-				details := macros.GetMacroDetails()
-				cmd.Println(details.ID, "-->", details.Title)
+			if len(customMacros) > 0 {
+				cmd.Println("--------------------")
+				cmd.Println("Custom model macros:")
+				cmd.Println("--------------------")
+				for _, macros := range customMacros {
+					details := macros.GetMacroDetails()
+					cmd.Println(details.ID, "-->", details.Title)
+				}
+				cmd.Println()
 			}
-			cmd.Println()
-			*/
 			cmd.Println("----------------------")
 			cmd.Println("Built-in model macros:")
 			cmd.Println("----------------------")
@@ -39,24 +47,36 @@ func (what *Threagile) initMacros() *Threagile {
 				cmd.Println(details.ID, "-->", details.Title)
 			}
 			cmd.Println()
+			return nil
 		},
 	})
 
 	what.rootCmd.AddCommand(&cobra.Command{
 		Use:   common.ExplainModelMacrosCommand,
 		Short: "Explain model macros",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.Println(docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp))
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
+			customMacros := macros.ListCustomMacros(cfg.ModelMacroPlugins, common.DefaultProgressReporter{Verbose: cfg.Verbose})
 			cmd.Println("Explanation for the model macros:")
 			cmd.Println()
-			/* TODO finish plugin stuff
-			cmd.Println("Custom model macros:")
-			for _, macros := range macros.ListCustomMacros() {
-				details := macros.GetMacroDetails()
-				cmd.Println(details.ID, "-->", details.Title)
+			if len(customMacros) > 0 {
+				cmd.Println("--------------------")
+				cmd.Println("Custom model macros:")
+				cmd.Println("--------------------")
+				for _, macros := range customMacros {
+					details := macros.GetMacroDetails()
+					cmd.Printf("%v: %v\n", details.ID, details.Title)
+					if len(details.Description) > 0 {
+						cmd.Println("   ", details.Description)
+					}
+				}
+				cmd.Println()
 			}
-			cmd.Println()
-			*/
 			cmd.Println("----------------------")
 			cmd.Println("Built-in model macros:")
 			cmd.Println("----------------------")
@@ -66,15 +86,21 @@ func (what *Threagile) initMacros() *Threagile {
 			}
 
 			cmd.Println()
+			return nil
 		},
 	})
 
-	what.rootCmd.AddCommand(&cobra.Command{
+	executeMacro := &cobra.Command{
 		Use:   "execute-model-macro",
 		Short: "Execute model macro",
+		Long:  "Execute a model macro, asking its questions on the console unless the answers are given via --" + answersFileFlagName + " or --" + answerFlagName,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg := what.readConfig(cmd, what.buildTimestamp)
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
 			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
 
 			r, err := model.ReadAndAnalyzeModel(*cfg, progressReporter)
@@ -82,14 +108,47 @@ func (what *Threagile) initMacros() *Threagile {
 				return fmt.Errorf("unable to read and analyze model: %v", err)
 			}
 
+			answers, err := what.readMacroAnswers(cmd)
+			if err != nil {
+				return err
+			}
+
 			macrosId := args[0]
-			err = macros.ExecuteModelMacro(r.ModelInput, cfg.InputFile, r.ParsedModel, macrosId)
+			customMacros := macros.ListCustomMacros(cfg.ModelMacroPlugins, progressReporter)
+			err = macros.ExecuteModelMacro(r.ModelInput, cfg.InputFile, r.ParsedModel, macrosId, customMacros, answers, what.flags.dryRunFlag)
 			if err != nil {
 				return fmt.Errorf("unable to execute model macro: %v", err)
 			}
 			return nil
 		},
-	})
+	}
+	executeMacro.Flags().StringVar(&what.flags.answersFileFlag, answersFileFlagName, "", "YAML or JSON file mapping question IDs to answers, executes the macro non-interactively")
+	executeMacro.Flags().StringArrayVar(&what.flags.answerFlag, answerFlagName, nil, "answer as id=value (repeatable, e.g. for multi-select questions), executes the macro non-interactively and overrides the answers file")
+	executeMacro.Flags().BoolVar(&what.flags.dryRunFlag, dryRunFlagName, false, "print the change impact and a diff of the model file instead of writing it")
+	what.rootCmd.AddCommand(executeMacro)
 
 	return what
 }
+
+// readMacroAnswers returns nil (asking interactively) unless answers are given via file or flags
+func (what *Threagile) readMacroAnswers(cmd *cobra.Command) (macros.Answers, error) {
+	flags := cmd.Flags()
+	if !isFlagOverridden(flags, answersFileFlagName) && !isFlagOverridden(flags, answerFlagName) {
+		return nil, nil
+	}
+
+	answers := make(macros.Answers)
+	if len(what.flags.answersFileFlag) > 0 {
+		fileAnswers, err := macros.LoadAnswers(what.flags.answersFileFlag)
+		if err != nil {
+			return nil, err
+		}
+		answers = answers.Merge(fileAnswers)
+	}
+
+	flagAnswers, err := macros.ParseAnswers(what.flags.answerFlag)
+	if err != nil {
+		return nil, err
+	}
+	return answers.Merge(flagAnswers), nil
+}
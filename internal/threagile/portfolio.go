@@ -0,0 +1,87 @@
+package threagile
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
+)
+
+func (what *Threagile) initPortfolio() *Threagile {
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.AnalyzePortfolioCommand + " <dir>",
+		Short: "Analyze a directory of models referencing each other as one portfolio",
+		Long: "Analyze the models of a directory, each yaml file in it declaring a threagile_version and each subdirectory containing a model file named like the one given by --" + inputFileFlagName + " (" + common.InputFile + " by default), " +
+			"named after the file or subdirectory. Communication link targets and trust boundary members may reference the technical assets of other models as <model>" + model.ModelReferenceSeparator + "<id>, like payments-model" + model.ModelReferenceSeparator + "payment-api. " +
+			"The models are combined into a single model " + common.PortfolioModelFilename + " in the output directory, which is analyzed and reported like a single model, " +
+			"with the statistics per model and organisation-wide in " + common.JsonPortfolioFilename + " and in the report.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
+			commands := what.readCommands()
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+			variants, err := what.readVariants(cfg)
+			if err != nil {
+				cmd.Printf("Invalid variants: %v\n", err)
+				return err
+			}
+
+			for _, variant := range variants {
+				progressReporter.Info("Loading portfolio", args[0])
+				portfolio, err := model.LoadPortfolio(cfg.CleanPath(args[0]), filepath.Base(cfg.InputFile), variant.config.Variables)
+				if err != nil {
+					cmd.Printf("Unable to load portfolio of %v: %v\n", variant, err)
+					return err
+				}
+				combined, err := portfolio.Combine()
+				if err != nil {
+					cmd.Printf("Unable to combine the models of %v: %v\n", variant, err)
+					return err
+				}
+
+				yamlBytes, err := yaml.Marshal(combined)
+				if err != nil {
+					cmd.Printf("Unable to serialize model: %v\n", err)
+					return err
+				}
+				err = os.MkdirAll(variant.config.OutputFolder, 0700)
+				if err != nil {
+					cmd.Printf("Unable to create output dir: %v\n", err)
+					return err
+				}
+				variant.config.InputFile = filepath.Join(variant.config.OutputFolder, common.PortfolioModelFilename) // as reported
+				err = os.WriteFile(variant.config.InputFile, yamlBytes, 0600)
+				if err != nil {
+					cmd.Printf("Unable to write model file: %v\n", err)
+					return err
+				}
+
+				r, err := model.AnalyzeModel(variant.config, combined, progressReporter)
+				if err != nil {
+					cmd.Printf("Failed to analyze %v: %v\n", variant, err)
+					return err
+				}
+				r.Portfolio = portfolio.Statistics(r.ParsedModel)
+
+				err = report.Generate(&variant.config, r, commands, progressReporter)
+				if err != nil {
+					cmd.Printf("Failed to generate reports: %v \n", err)
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	return what
+}
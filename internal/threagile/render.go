@@ -0,0 +1,65 @@
+package threagile
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func (what *Threagile) initRender() *Threagile {
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.RenderModelCommand,
+		Short: "Render the model with its includes, overlays and variables resolved",
+		Long: "Render the model yaml file with all files included or overlaid by it and all variables replaced by their values (given by --" + setFlagName + " and --" + valuesFlagName + ") " +
+			"into a single model file " + common.RenderedModelFilename + " in the output directory, one per variant given by --" + valuesFlagName + " in a subdirectory named after its values file.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
+
+			variants, err := what.readVariants(cfg)
+			if err != nil {
+				cmd.Printf("Invalid variants: %v\n", err)
+				return err
+			}
+
+			for _, variant := range variants {
+				modelInput := new(input.Model).Defaults()
+				err = modelInput.LoadWithVariables(cfg.InputFile, variant.config.Variables)
+				if err != nil {
+					cmd.Printf("Unable to load %v: %v\n", variant, err)
+					return err
+				}
+				modelInput.Includes, modelInput.Overlays, modelInput.Variables = nil, nil, nil
+
+				yamlBytes, err := yaml.Marshal(modelInput)
+				if err != nil {
+					cmd.Printf("Unable to serialize model: %v\n", err)
+					return err
+				}
+				err = os.MkdirAll(variant.config.OutputFolder, 0700)
+				if err != nil {
+					cmd.Printf("Unable to create output dir: %v\n", err)
+					return err
+				}
+				modelFile := filepath.Join(variant.config.OutputFolder, common.RenderedModelFilename)
+				err = os.WriteFile(modelFile, yamlBytes, 0600)
+				if err != nil {
+					cmd.Printf("Unable to write model file: %v\n", err)
+					return err
+				}
+				cmd.Printf("Rendered %v into %v\n", variant, modelFile)
+			}
+			return nil
+		},
+	})
+
+	return what
+}
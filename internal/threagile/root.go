@@ -63,26 +63,36 @@ func (what *Threagile) initRoot() *Threagile {
 
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.inputFileFlag, inputFileFlagName, defaultConfig.InputFile, "input model yaml file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.raaPluginFlag, raaPluginFlagName, defaultConfig.RAAPlugin, "RAA calculation run file name")
+	what.rootCmd.PersistentFlags().StringArrayVar(&what.flags.setFlag, setFlagName, []string{}, "value of a variable referenced as ${name} in the model files, given as name=value (repeatable)")
+	what.rootCmd.PersistentFlags().StringArrayVar(&what.flags.valuesFlag, valuesFlagName, defaultConfig.ValuesFiles, "yaml file with the values of the variables of one variant of the model, e.g. an environment, analyzed into a subdirectory of the output directory named after the file (repeatable)")
 
 	what.rootCmd.PersistentFlags().BoolVarP(&what.flags.interactiveFlag, interactiveFlagName, interactiveFlagShorthand, defaultConfig.Interactive, "interactive mode")
 	what.rootCmd.PersistentFlags().BoolVarP(&what.flags.verboseFlag, verboseFlagName, verboseFlagShorthand, defaultConfig.Verbose, "verbose output")
 
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.configFlag, configFlagName, "", "config file")
 
-	what.rootCmd.PersistentFlags().StringVar(&what.flags.customRiskRulesPluginFlag, customRiskRulesPluginFlagName, strings.Join(defaultConfig.RiskRulesPlugins, ","), "comma-separated list of plugins file names with custom risk rules to load")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.customRiskRulesPluginFlag, customRiskRulesPluginFlagName, strings.Join(defaultConfig.RiskRulesPlugins, ","), "comma-separated list of plugins file names with custom risk rules to load (executables or declarative rule .yaml files)")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.customRiskRulesTimeoutFlag, customRiskRulesTimeoutFlagName, defaultConfig.RiskRulesPluginTimeout, "timeout in seconds for each call to a custom risk rules plugin")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.customModelMacrosPluginFlag, customModelMacrosPluginFlagName, strings.Join(defaultConfig.ModelMacroPlugins, ","), "comma-separated list of plugins file names with custom model macros to load")
 	what.rootCmd.PersistentFlags().IntVar(&what.flags.diagramDpiFlag, diagramDpiFlagName, defaultConfig.DiagramDPI, "DPI used to render: maximum is "+fmt.Sprintf("%d", common.MaxGraphvizDPI)+"")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.diagramFormatsFlag, diagramFormatsFlagName, strings.Join(defaultConfig.DiagramFormats, ","), "comma-separated list of formats to render the diagrams in: "+strings.Join([]string{common.DiagramFormatPNG, common.DiagramFormatSVG, common.DiagramFormatPDF}, ", "))
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.skipRiskRulesFlag, skipRiskRulesFlagName, defaultConfig.SkipRiskRules, "comma-separated list of risk rules (by their ID) to skip")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.ignoreOrphanedRiskTrackingFlag, ignoreOrphanedRiskTrackingFlagName, defaultConfig.IgnoreOrphanedRiskTracking, "ignore orphaned risk tracking (just log them) not matching a concrete risk")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.templateFileNameFlag, templateFileNameFlagName, defaultConfig.TemplateFilename, "background pdf file")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.maxAttackPathsFlag, maxAttackPathsFlagName, defaultConfig.MaxAttackPaths, "number of the cheapest attack paths reported per sensitive data asset")
 
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataFlowDiagramFlag, generateDataFlowDiagramFlagName, true, "generate data flow diagram")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataAssetDiagramFlag, generateDataAssetDiagramFlagName, true, "generate data asset diagram")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksJSONFlag, generateRisksJSONFlagName, true, "generate risks json")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateTechnicalAssetsJSONFlag, generateTechnicalAssetsJSONFlagName, true, "generate technical assets json")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateStatsJSONFlag, generateStatsJSONFlagName, true, "generate stats json")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksSARIFFlag, generateRisksSARIFFlagName, true, "generate risks sarif")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateAttackPathsJSONFlag, generateAttackPathsJSONFlagName, true, "generate attack paths json")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksExcelFlag, generateRisksExcelFlagName, true, "generate risks excel")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateTagsExcelFlag, generateTagsExcelFlagName, true, "generate tags excel")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateReportPDFFlag, generateReportPDFFlagName, true, "generate report pdf, including diagrams")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateReportHTMLFlag, generateReportHTMLFlagName, true, "generate offline report html, including diagrams")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateSubDiagramsFlag, generateSubDiagramsFlagName, true, "embed focused sub-diagrams of each technical asset and trust boundary into the report pdf")
 
 	return what
 }
@@ -209,13 +219,18 @@ func (what *Threagile) readCommands() *report.GenerateCommands {
 	commands.RisksJSON = what.flags.generateRisksJSONFlag
 	commands.StatsJSON = what.flags.generateStatsJSONFlag
 	commands.TechnicalAssetsJSON = what.flags.generateTechnicalAssetsJSONFlag
+	commands.RisksSARIF = what.flags.generateRisksSARIFFlag
+	commands.AttackPathsJSON = what.flags.generateAttackPathsJSONFlag
 	commands.RisksExcel = what.flags.generateRisksExcelFlag
 	commands.TagsExcel = what.flags.generateTagsExcelFlag
 	commands.ReportPDF = what.flags.generateReportPDFFlag
+	commands.ReportHTML = what.flags.generateReportHTMLFlag
+	commands.SubDiagrams = what.flags.generateSubDiagramsFlag
 	return commands
 }
 
-func (what *Threagile) readConfig(cmd *cobra.Command, buildTimestamp string) *common.Config {
+// readConfig returns the config file given by --config with the flags given applied to it, failing on invalid flag values
+func (what *Threagile) readConfig(cmd *cobra.Command, buildTimestamp string) (*common.Config, error) {
 	cfg := new(common.Config).Defaults(buildTimestamp)
 	configError := cfg.Load(what.flags.configFlag)
 	if configError != nil {
@@ -229,6 +244,9 @@ func (what *Threagile) readConfig(cmd *cobra.Command, buildTimestamp string) *co
 	if isFlagOverridden(flags, serverDirFlagName) {
 		cfg.ServerFolder = cfg.CleanPath(what.flags.serverDirFlag)
 	}
+	if isFlagOverridden(flags, serverStorageFlagName) {
+		cfg.ServerStorage = what.flags.serverStorageFlag
+	}
 
 	if isFlagOverridden(flags, appDirFlagName) {
 		cfg.AppFolder = cfg.CleanPath(what.flags.appDirFlag)
@@ -253,10 +271,37 @@ func (what *Threagile) readConfig(cmd *cobra.Command, buildTimestamp string) *co
 	if isFlagOverridden(flags, raaPluginFlagName) {
 		cfg.RAAPlugin = what.flags.raaPluginFlag
 	}
+	if isFlagOverridden(flags, setFlagName) {
+		for _, assignment := range what.flags.setFlag {
+			name, value, found := strings.Cut(assignment, "=")
+			if !found || len(strings.TrimSpace(name)) == 0 {
+				return nil, fmt.Errorf("invalid --%v %q (expected name=value)", setFlagName, assignment)
+			}
+			if cfg.Variables == nil {
+				cfg.Variables = make(map[string]string)
+			}
+			cfg.Variables[strings.TrimSpace(name)] = value
+		}
+	}
+	if isFlagOverridden(flags, valuesFlagName) {
+		cfg.ValuesFiles = make([]string, 0)
+		for _, valuesFile := range what.flags.valuesFlag {
+			cfg.ValuesFiles = append(cfg.ValuesFiles, cfg.CleanPath(valuesFile))
+		}
+	}
 
 	if isFlagOverridden(flags, customRiskRulesPluginFlagName) {
 		cfg.RiskRulesPlugins = strings.Split(what.flags.customRiskRulesPluginFlag, ",")
 	}
+	if isFlagOverridden(flags, customRiskRulesTimeoutFlagName) {
+		cfg.RiskRulesPluginTimeout = what.flags.customRiskRulesTimeoutFlag
+	}
+	if isFlagOverridden(flags, maxAttackPathsFlagName) {
+		cfg.MaxAttackPaths = what.flags.maxAttackPathsFlag
+	}
+	if isFlagOverridden(flags, customModelMacrosPluginFlagName) {
+		cfg.ModelMacroPlugins = strings.Split(what.flags.customModelMacrosPluginFlag, ",")
+	}
 	if isFlagOverridden(flags, skipRiskRulesFlagName) {
 		cfg.SkipRiskRules = what.flags.skipRiskRulesFlag
 	}
@@ -266,10 +311,18 @@ func (what *Threagile) readConfig(cmd *cobra.Command, buildTimestamp string) *co
 	if isFlagOverridden(flags, diagramDpiFlagName) {
 		cfg.DiagramDPI = what.flags.diagramDpiFlag
 	}
+	if isFlagOverridden(flags, diagramFormatsFlagName) {
+		cfg.DiagramFormats = make([]string, 0)
+		for _, format := range strings.Split(what.flags.diagramFormatsFlag, ",") {
+			if format = strings.ToLower(strings.TrimSpace(format)); len(format) > 0 {
+				cfg.DiagramFormats = append(cfg.DiagramFormats, format)
+			}
+		}
+	}
 	if isFlagOverridden(flags, templateFileNameFlagName) {
 		cfg.TemplateFilename = what.flags.templateFileNameFlag
 	}
-	return cfg
+	return cfg, nil
 }
 
 func isFlagOverridden(flags *pflag.FlagSet, flagName string) bool {
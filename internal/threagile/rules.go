@@ -7,6 +7,7 @@ package threagile
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/model"
@@ -28,7 +29,9 @@ func (what *Threagile) initRules() *Threagile {
 			cmd.Println("----------------------")
 			cmd.Println("Custom risk rules:")
 			cmd.Println("----------------------")
-			customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), common.DefaultProgressReporter{Verbose: what.flags.verboseFlag})
+			progressReporter := common.DefaultProgressReporter{Verbose: what.flags.verboseFlag}
+			customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), time.Duration(what.flags.customRiskRulesTimeoutFlag)*time.Second, progressReporter)
+			model.CloseCustomRiskRules(customRiskRules, progressReporter)
 			for id, customRule := range customRiskRules {
 				cmd.Println(id, "-->", customRule.Category.Title, "--> with tags:", customRule.Tags)
 			}
@@ -55,7 +58,9 @@ func (what *Threagile) initRules() *Threagile {
 			cmd.Println("----------------------")
 			cmd.Println("Custom risk rules:")
 			cmd.Println("----------------------")
-			customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), common.DefaultProgressReporter{Verbose: what.flags.verboseFlag})
+			progressReporter := common.DefaultProgressReporter{Verbose: what.flags.verboseFlag}
+			customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), time.Duration(what.flags.customRiskRulesTimeoutFlag)*time.Second, progressReporter)
+			model.CloseCustomRiskRules(customRiskRules, progressReporter)
 			for _, customRule := range customRiskRules {
 				cmd.Printf("%v: %v\n", customRule.Category.Id, customRule.Category.Description)
 			}
@@ -1,6 +1,8 @@
 package threagile
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/server"
@@ -13,7 +15,11 @@ func (what *Threagile) initServer() *Threagile {
 		Use:   "server",
 		Short: "Run server",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg := what.readConfig(cmd, what.buildTimestamp)
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
 			cfg.ServerMode = true
 			serverError := cfg.CheckServerFolder()
 			if serverError != nil {
@@ -26,6 +32,41 @@ func (what *Threagile) initServer() *Threagile {
 
 	serverCmd.PersistentFlags().IntVar(&what.flags.serverPortFlag, serverPortFlagName, defaultConfig.ServerPort, "server port")
 	serverCmd.PersistentFlags().StringVar(&what.flags.serverDirFlag, serverDirFlagName, defaultConfig.DataFolder, "base folder for server mode (default: "+common.DataDir+")")
+	serverCmd.PersistentFlags().StringVar(&what.flags.serverStorageFlag, serverStorageFlagName, defaultConfig.ServerStorage, "storage of keys, tokens and models in server mode: \""+server.FilesystemStorage+"\" or \""+server.SQLiteStoragePrefix+"<file>\" (on a local disk, not shared via a network filesystem)")
+
+	migrateStorageCmd := &cobra.Command{
+		Use:   common.MigrateServerStorageCommand,
+		Short: "Migrate the server storage to another storage backend",
+		Long:  "Copy all keys, models and model history from the server storage (" + serverStorageFlagName + ") to another storage (" + toStorageFlagName + "), tokens are not migrated and have to be created again",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+			serverError := cfg.CheckServerFolder()
+			if serverError != nil {
+				return serverError
+			}
+			if what.flags.toStorageFlag == "" || what.flags.toStorageFlag == cfg.ServerStorage {
+				return fmt.Errorf("the storage to migrate to (%v) must differ from the server storage", toStorageFlagName)
+			}
+			from, err := server.OpenStorage(cfg.ServerStorage, cfg)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = from.Close() }()
+			to, err := server.OpenStorage(what.flags.toStorageFlag, cfg)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = to.Close() }()
+			return server.MigrateStorage(from, to, progressReporter)
+		},
+	}
+	migrateStorageCmd.Flags().StringVar(&what.flags.toStorageFlag, toStorageFlagName, "", "storage to migrate to, e.g. \""+server.SQLiteStoragePrefix+"threagile.db\"")
+	serverCmd.AddCommand(migrateStorageCmd)
 
 	what.rootCmd.AddCommand(serverCmd)
 
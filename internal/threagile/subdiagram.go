@@ -0,0 +1,86 @@
+package threagile
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
+)
+
+func (what *Threagile) initSubDiagram() *Threagile {
+	subDiagram := &cobra.Command{
+		Use:     common.RenderSubDiagramCommand,
+		Short:   "Render a data flow diagram focused on part of the model",
+		Long:    "Render a data flow diagram scoped to one trust boundary, the neighbourhood of one technical asset (within the given number of hops) or all technical assets with any of the given tags. Technical assets outside the scope communicating with it are collapsed into placeholder nodes per trust boundary.",
+		Aliases: []string{"sub-diagram"},
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+			selected := 0
+			for _, value := range []string{what.flags.trustBoundaryFlag, what.flags.technicalAssetFlag, what.flags.tagsFlag} {
+				if len(value) > 0 {
+					selected++
+				}
+			}
+			if selected != 1 {
+				cmd.Printf("Exactly one of --%v, --%v or --%v is required\n", trustBoundaryFlagName, technicalAssetFlagName, tagsFlagName)
+				return fmt.Errorf("exactly one of --%v, --%v or --%v is required", trustBoundaryFlagName, technicalAssetFlagName, tagsFlagName)
+			}
+
+			diagramFormats, err := report.CheckDiagramFormats(cfg.DiagramFormats)
+			if err != nil {
+				cmd.Printf("Invalid diagram formats: %v\n", err)
+				return err
+			}
+
+			r, err := model.ReadAndAnalyzeModel(*cfg, progressReporter)
+			if err != nil {
+				cmd.Printf("Failed to read and analyze model: %v", err)
+				return err
+			}
+
+			var scope *report.DiagramScope
+			switch {
+			case len(what.flags.trustBoundaryFlag) > 0:
+				scope, err = report.NewTrustBoundaryDiagramScope(r.ParsedModel, what.flags.trustBoundaryFlag)
+			case len(what.flags.technicalAssetFlag) > 0:
+				scope, err = report.NewTechnicalAssetDiagramScope(r.ParsedModel, what.flags.technicalAssetFlag, what.flags.hopsFlag)
+			default:
+				scope, err = report.NewTagsDiagramScope(r.ParsedModel, splitList(what.flags.tagsFlag))
+			}
+			if err != nil {
+				cmd.Printf("Invalid diagram scope: %v\n", err)
+				return err
+			}
+
+			err = report.GenerateSubDiagram(r.ParsedModel, scope,
+				report.SubDiagramFilename(filepath.Join(cfg.OutputFolder, cfg.DataFlowDiagramFilenameDOT), scope),
+				report.SubDiagramFilename(filepath.Join(cfg.OutputFolder, cfg.DataFlowDiagramFilenamePNG), scope),
+				cfg.TempFolder, cfg.KeepDiagramSourceFiles, report.ClampDiagramDPI(cfg.DiagramDPI), diagramFormats, progressReporter)
+			if err != nil {
+				cmd.Printf("Failed to render sub-diagram: %v\n", err)
+				return err
+			}
+			return nil
+		},
+	}
+
+	subDiagram.Flags().StringVar(&what.flags.trustBoundaryFlag, trustBoundaryFlagName, "", "ID of the trust boundary to render")
+	subDiagram.Flags().StringVar(&what.flags.technicalAssetFlag, technicalAssetFlagName, "", "ID of the technical asset whose neighbourhood to render")
+	subDiagram.Flags().IntVar(&what.flags.hopsFlag, hopsFlagName, common.DefaultSubDiagramHops, "number of communication link hops around the technical asset to include")
+	subDiagram.Flags().StringVar(&what.flags.tagsFlag, tagsFlagName, "", "comma-separated list of tags: technical assets tagged with any of them are rendered")
+
+	what.rootCmd.AddCommand(subDiagram)
+
+	return what
+}
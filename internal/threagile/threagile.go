@@ -20,5 +20,5 @@ func (what *Threagile) Execute() {
 
 func (what *Threagile) Init(buildTimestamp string) *Threagile {
 	what.buildTimestamp = buildTimestamp
-	return what.initRoot().initAbout().initRules().initExamples().initMacros().initTypes().initAnalyze().initServer().initQuit()
+	return what.initRoot().initAbout().initRules().initExamples().initMacros().initTypes().initAnalyze().initValidate().initRender().initPortfolio().initDiff().initSubDiagram().initImport().initTracker().initServer().initQuit()
 }
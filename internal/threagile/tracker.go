@@ -0,0 +1,117 @@
+package threagile
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/tracker"
+)
+
+func (what *Threagile) initTracker() *Threagile {
+	syncRiskTracking := &cobra.Command{
+		Use:   common.SyncRiskTrackingCommand,
+		Short: "Synchronize risk tracking with an issue tracker",
+		Long: "Create or update an issue in GitHub, GitLab, Jira or a local JSON file for every risk still at risk, write the ticket references into the risk tracking of the model file " +
+			"and take over the status of closed (mitigated) and accepted issues. Credentials are read from the environment variables " +
+			tracker.TokenEnvironmentVariable + " and " + tracker.UserEnvironmentVariable + " (jira only).",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+			flags := cmd.Flags()
+			if isFlagOverridden(flags, trackerFlagName) {
+				cfg.Tracker.Type = what.flags.trackerFlag
+			}
+			if isFlagOverridden(flags, trackerURLFlagName) {
+				cfg.Tracker.URL = what.flags.trackerURLFlag
+			}
+			if isFlagOverridden(flags, trackerProjectFlagName) {
+				cfg.Tracker.Project = what.flags.trackerProjectFlag
+			}
+			if cfg.Tracker.Type == "file" {
+				cfg.Tracker.Project = cfg.CleanPath(cfg.Tracker.Project)
+			}
+			issueTracker, err := tracker.NewTracker(cfg.Tracker)
+			if err != nil {
+				cmd.Printf("Invalid tracker configuration: %v\n", err)
+				return err
+			}
+
+			r, err := model.ReadAndAnalyzeModel(*cfg, progressReporter)
+			if err != nil {
+				cmd.Printf("Failed to read and analyze model: %v\n", err)
+				return err
+			}
+
+			// the risk tracking is written into the model file itself, not into any of its includes
+			modelYaml, err := os.ReadFile(filepath.Clean(cfg.InputFile))
+			if err != nil {
+				cmd.Printf("Unable to read model file: %v\n", err)
+				return err
+			}
+			modelInput := new(input.Model).Defaults()
+			err = yaml.Unmarshal(modelYaml, modelInput)
+			if err != nil {
+				cmd.Printf("Unable to parse model yaml: %v\n", err)
+				return err
+			}
+			unchangedYaml, err := yaml.Marshal(modelInput)
+			if err != nil {
+				cmd.Printf("Unable to serialize model: %v\n", err)
+				return err
+			}
+
+			actions, syncError := tracker.Sync(issueTracker, modelInput, r.ParsedModel, progressReporter)
+			for _, action := range actions {
+				cmd.Println(" -", action)
+			}
+
+			yamlBytes, err := yaml.Marshal(modelInput)
+			if err != nil {
+				cmd.Printf("Unable to serialize model: %v\n", err)
+				return err
+			}
+			if !bytes.Equal(yamlBytes, unchangedYaml) {
+				backupFilename := cfg.InputFile + ".backup"
+				cmd.Println("Creating backup model file:", backupFilename)
+				err = os.WriteFile(backupFilename, modelYaml, 0600)
+				if err != nil {
+					cmd.Printf("Unable to write backup model file: %v\n", err)
+					return err
+				}
+				err = os.WriteFile(cfg.InputFile, yamlBytes, 0600)
+				if err != nil {
+					cmd.Printf("Unable to write model file: %v\n", err)
+					return err
+				}
+				cmd.Println("Updated risk tracking in model file:", cfg.InputFile)
+			}
+
+			if syncError != nil {
+				cmd.Printf("Failed to synchronize risk tracking with %v: %v\n", issueTracker.Name(), syncError)
+				return syncError
+			}
+			cmd.Printf("Synchronized risk tracking with %v: %d action(s)\n", issueTracker.Name(), len(actions))
+			return nil
+		},
+	}
+
+	syncRiskTracking.Flags().StringVar(&what.flags.trackerFlag, trackerFlagName, "", "issue tracker type: github, gitlab, jira or file")
+	syncRiskTracking.Flags().StringVar(&what.flags.trackerURLFlag, trackerURLFlagName, "", "base URL of the issue tracker API (default: the public GitHub or GitLab service)")
+	syncRiskTracking.Flags().StringVar(&what.flags.trackerProjectFlag, trackerProjectFlagName, "", "owner/repo (github), group/project (gitlab), project key (jira) or issue file (file)")
+
+	what.rootCmd.AddCommand(syncRiskTracking)
+
+	return what
+}
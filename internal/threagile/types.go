@@ -6,11 +6,17 @@ package threagile
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+
 	"github.com/threagile/threagile/pkg/common"
 
 	"github.com/spf13/cobra"
 
 	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
@@ -18,7 +24,7 @@ func (what *Threagile) initTypes() *Threagile {
 	what.rootCmd.AddCommand(&cobra.Command{
 		Use:   common.ListTypesCommand,
 		Short: "Print type information (enum values to be used in models)",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.Println(docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp))
 			cmd.Println()
 			cmd.Println()
@@ -27,13 +33,26 @@ func (what *Threagile) initTypes() *Threagile {
 			for name, values := range types.GetBuiltinTypeValues() {
 				cmd.Println(fmt.Sprintf("  %v: %v", name, values))
 			}
+			technologies, err := what.declaredTechnologies(cmd)
+			if err != nil {
+				return err
+			}
+			if len(technologies) > 0 {
+				cmd.Println()
+				cmd.Println("The following technologies are declared by the config or model:")
+				cmd.Println()
+				for _, technology := range technologies {
+					cmd.Println(fmt.Sprintf("  %v (%v): %v", technology.Name, technology.Parent, technology.TraitNames()))
+				}
+			}
+			return nil
 		},
 	})
 
 	what.rootCmd.AddCommand(&cobra.Command{
 		Use:   common.ExplainTypesCommand,
 		Short: "Print type information (enum values to be used in models)",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.Println(docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp))
 			fmt.Println("Explanation for the types:")
 			cmd.Println()
@@ -45,8 +64,48 @@ func (what *Threagile) initTypes() *Threagile {
 					cmd.Printf("\t %v: %v\n", candidate, candidate.Explain())
 				}
 			}
+			technologies, err := what.declaredTechnologies(cmd)
+			if err != nil {
+				return err
+			}
+			if len(technologies) > 0 {
+				cmd.Println("Declared Technology (of the config or model)")
+				for _, technology := range technologies {
+					cmd.Printf("\t %v: %v (a kind of %v with the traits %v)\n", technology.Name, technology.Description, technology.Parent, strings.Join(technology.TraitNames(), ", "))
+				}
+			}
+			return nil
 		},
 	})
 
 	return what
 }
+
+// declaredTechnologies returns the technologies declared by the config and the model file (if there is one), sorted by
+// name, with the traits resolved along their parents
+func (what *Threagile) declaredTechnologies(cmd *cobra.Command) ([]model.Technology, error) {
+	cfg, err := what.readConfig(cmd, what.buildTimestamp)
+	if err != nil {
+		cmd.Printf("Invalid configuration: %v\n", err)
+		return nil, err
+	}
+	modelInput := new(input.Model).Defaults()
+	if _, err := os.Stat(cfg.InputFile); err == nil {
+		if err := modelInput.LoadWithVariables(cfg.InputFile, cfg.Variables); err != nil {
+			cmd.Printf("WARNING: unable to load the technologies of model %q: %v\n", cfg.InputFile, err)
+		}
+	}
+
+	technologies, errs := model.ResolveTechnologies(model.WithTechnologies(modelInput, cfg.Technologies))
+	for _, err := range errs {
+		cmd.Printf("WARNING: %v\n", err)
+	}
+	result := make([]model.Technology, 0, len(technologies))
+	for _, technology := range technologies {
+		result = append(result, technology)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
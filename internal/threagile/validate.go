@@ -0,0 +1,81 @@
+package threagile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/schema"
+)
+
+func (what *Threagile) initValidate() *Threagile {
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.ValidateModelCommand,
+		Short: "Validate model against the schema and check its references",
+		Long:  "Validate the model yaml file and all files included or overlaid by it (for each variant given by --" + valuesFlagName + ") against the schema (as created by " + common.CreateEditingSupportCommand + ") and check all ids and references between model elements, reporting all errors found at once.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := what.readConfig(cmd, what.buildTimestamp)
+			if err != nil {
+				cmd.Printf("Invalid configuration: %v\n", err)
+				return err
+			}
+
+			variants, err := what.readVariants(cfg)
+			if err != nil {
+				cmd.Printf("Invalid variants: %v\n", err)
+				return err
+			}
+
+			invalid := 0
+			for _, variant := range variants {
+				errs := validateModel(variant.config)
+				for _, err := range errs {
+					cmd.Println(err)
+				}
+				if len(errs) > 0 {
+					cmd.PrintErrf("Model %q is invalid: %d error(s) found%v\n", cfg.InputFile, len(errs), variantSuffix(variant))
+					invalid++
+					continue
+				}
+				cmd.Printf("Model %q is valid%v\n", cfg.InputFile, variantSuffix(variant))
+			}
+			if invalid > 0 {
+				return fmt.Errorf("model %q is invalid", cfg.InputFile)
+			}
+			return nil
+		},
+	})
+
+	return what
+}
+
+// validateModel validates a model against the schema and checks its references, with the variables of the config
+func validateModel(cfg common.Config) []error {
+	errs := schema.ValidateModelFile(cfg.InputFile, cfg.Variables)
+	for _, err := range errs {
+		var validationError *schema.ValidationError
+		if !errors.As(err, &validationError) {
+			return errs // unreadable or no valid yaml
+		}
+	}
+
+	modelInput := new(input.Model).Defaults()
+	err := modelInput.LoadWithVariables(cfg.InputFile, cfg.Variables)
+	if err == nil {
+		errs = append(errs, model.CheckReferences(model.WithTechnologies(modelInput, cfg.Technologies))...)
+	} else if len(errs) == 0 { // otherwise already reported as schema violations, e.g. a list given as string
+		errs = append(errs, input.SplitErrors(err)...)
+	}
+	return errs
+}
+
+func variantSuffix(variant variant) string {
+	if len(variant.name) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%v)", variant)
+}
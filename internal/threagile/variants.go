@@ -0,0 +1,57 @@
+package threagile
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+// variant is one rendering of the model with its own values of the variables, like an environment
+type variant struct {
+	name   string // empty without --values
+	config common.Config
+}
+
+func (what variant) String() string {
+	if len(what.name) == 0 {
+		return "model"
+	}
+	return fmt.Sprintf("variant %q", what.name)
+}
+
+// readVariants returns the variants of the model given by --values, each named after its values file and written into
+// a subdirectory of the output directory of that name (created by the caller when writing into it), the values given
+// by --set override the ones of the files
+func (what *Threagile) readVariants(cfg *common.Config) ([]variant, error) {
+	if len(cfg.ValuesFiles) == 0 {
+		return []variant{{config: *cfg}}, nil
+	}
+
+	variants := make([]variant, 0)
+	names := make(map[string]string)
+	for _, valuesFile := range cfg.ValuesFiles {
+		valuesFile = cfg.CleanPath(valuesFile)
+		name := strings.TrimSuffix(filepath.Base(valuesFile), filepath.Ext(valuesFile))
+		if other, found := names[name]; found {
+			return nil, fmt.Errorf("values files %q and %q give the same variant name %q", other, valuesFile, name)
+		}
+		names[name] = valuesFile
+
+		values, err := input.LoadValues(valuesFile)
+		if err != nil {
+			return nil, err
+		}
+		for variable, value := range cfg.Variables {
+			values[variable] = value
+		}
+
+		config := *cfg
+		config.Variables = values
+		config.OutputFolder = filepath.Join(cfg.OutputFolder, name)
+		variants = append(variants, variant{name: name, config: config})
+	}
+	return variants, nil
+}
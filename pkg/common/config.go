@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
 )
 
 type Config struct {
@@ -23,26 +25,38 @@ type Config struct {
 	KeyFolder    string
 
 	InputFile                   string
+	Variables                   map[string]string // values of the variables referenced in the model files
+	ValuesFiles                 []string          // files with the values of the variables, one per variant of the model
 	DataFlowDiagramFilenamePNG  string
 	DataAssetDiagramFilenamePNG string
 	DataFlowDiagramFilenameDOT  string
 	DataAssetDiagramFilenameDOT string
 	ReportFilename              string
+	ReportHTMLFilename          string
 	ExcelRisksFilename          string
 	ExcelTagsFilename           string
 	JsonRisksFilename           string
 	JsonTechnicalAssetsFilename string
 	JsonStatsFilename           string
+	JsonPortfolioFilename       string
+	SarifRisksFilename          string
+	JsonAttackPathsFilename     string
 	TemplateFilename            string
 
-	RAAPlugin         string
-	RiskRulesPlugins  []string
-	SkipRiskRules     string
-	ExecuteModelMacro string
+	RAAPlugin              string
+	RiskRulesPlugins       []string
+	RiskRulesPluginTimeout int
+	SkipRiskRules          string
+	Technologies           map[string]input.Technology // declared in addition to the ones of the models, keyed by name
+	MaxAttackPaths         int                         // reported per sensitive data asset
+	ExecuteModelMacro      string
+	ModelMacroPlugins      []string
 
 	ServerMode               bool
 	DiagramDPI               int
+	DiagramFormats           []string
 	ServerPort               int
+	ServerStorage            string
 	GraphvizDPI              int
 	MaxGraphvizDPI           int
 	BackupHistoryFilesToKeep int
@@ -52,6 +66,10 @@ type Config struct {
 	IgnoreOrphanedRiskTracking bool
 
 	Attractiveness Attractiveness
+
+	RiskPolicy RiskPolicy
+
+	Tracker TrackerConfig
 }
 
 func (c *Config) Defaults(buildTimestamp string) *Config {
@@ -68,23 +86,35 @@ func (c *Config) Defaults(buildTimestamp string) *Config {
 		KeyFolder:    KeyDir,
 
 		InputFile:                   InputFile,
+		Variables:                   make(map[string]string),
+		ValuesFiles:                 make([]string, 0),
 		DataFlowDiagramFilenamePNG:  DataFlowDiagramFilenamePNG,
 		DataAssetDiagramFilenamePNG: DataAssetDiagramFilenamePNG,
 		DataFlowDiagramFilenameDOT:  DataFlowDiagramFilenameDOT,
 		DataAssetDiagramFilenameDOT: DataAssetDiagramFilenameDOT,
 		ReportFilename:              ReportFilename,
+		ReportHTMLFilename:          ReportHTMLFilename,
 		ExcelRisksFilename:          ExcelRisksFilename,
 		ExcelTagsFilename:           ExcelTagsFilename,
 		JsonRisksFilename:           JsonRisksFilename,
 		JsonTechnicalAssetsFilename: JsonTechnicalAssetsFilename,
 		JsonStatsFilename:           JsonStatsFilename,
+		JsonPortfolioFilename:       JsonPortfolioFilename,
+		SarifRisksFilename:          SarifRisksFilename,
+		JsonAttackPathsFilename:     JsonAttackPathsFilename,
 		TemplateFilename:            TemplateFilename,
 		RAAPlugin:                   RAAPluginName,
 		RiskRulesPlugins:            make([]string, 0),
+		RiskRulesPluginTimeout:      DefaultRiskRulesPluginTimeout,
 		SkipRiskRules:               "",
+		Technologies:                make(map[string]input.Technology),
+		MaxAttackPaths:              DefaultMaxAttackPaths,
 		ExecuteModelMacro:           "",
+		ModelMacroPlugins:           make([]string, 0),
 		ServerMode:                  false,
 		ServerPort:                  DefaultServerPort,
+		ServerStorage:               DefaultServerStorage,
+		DiagramFormats:              []string{DiagramFormatPNG},
 
 		GraphvizDPI:              DefaultGraphvizDPI,
 		BackupHistoryFilesToKeep: DefaultBackupHistoryFilesToKeep,
@@ -111,6 +141,19 @@ func (c *Config) Defaults(buildTimestamp string) *Config {
 				TransferredData:       0,
 			},
 		},
+
+		RiskPolicy: RiskPolicy{
+			FailOnSeverity: "",
+			MaxRisks:       make(map[string]int),
+			Statuses:       make([]string, 0),
+		},
+
+		Tracker: TrackerConfig{
+			Type:          "",
+			URL:           "",
+			Project:       "",
+			AcceptedLabel: DefaultTrackerAcceptedLabel,
+		},
 	}
 
 	return c
@@ -220,6 +263,12 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("InputFile"):
 			c.InputFile = config.InputFile
 
+		case strings.ToLower("Variables"):
+			c.Variables = config.Variables
+
+		case strings.ToLower("ValuesFiles"):
+			c.ValuesFiles = config.ValuesFiles
+
 		case strings.ToLower("DataFlowDiagramFilenamePNG"):
 			c.DataFlowDiagramFilenamePNG = config.DataFlowDiagramFilenamePNG
 
@@ -235,6 +284,9 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("ReportFilename"):
 			c.ReportFilename = config.ReportFilename
 
+		case strings.ToLower("ReportHTMLFilename"):
+			c.ReportHTMLFilename = config.ReportHTMLFilename
+
 		case strings.ToLower("ExcelRisksFilename"):
 			c.ExcelRisksFilename = config.ExcelRisksFilename
 
@@ -250,6 +302,15 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("JsonStatsFilename"):
 			c.JsonStatsFilename = config.JsonStatsFilename
 
+		case strings.ToLower("JsonPortfolioFilename"):
+			c.JsonPortfolioFilename = config.JsonPortfolioFilename
+
+		case strings.ToLower("SarifRisksFilename"):
+			c.SarifRisksFilename = config.SarifRisksFilename
+
+		case strings.ToLower("JsonAttackPathsFilename"):
+			c.JsonAttackPathsFilename = config.JsonAttackPathsFilename
+
 		case strings.ToLower("TemplateFilename"):
 			c.TemplateFilename = config.TemplateFilename
 
@@ -259,18 +320,36 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("RiskRulesPlugins"):
 			c.RiskRulesPlugins = config.RiskRulesPlugins
 
+		case strings.ToLower("RiskRulesPluginTimeout"):
+			c.RiskRulesPluginTimeout = config.RiskRulesPluginTimeout
+
 		case strings.ToLower("SkipRiskRules"):
 			c.SkipRiskRules = config.SkipRiskRules
 
+		case strings.ToLower("Technologies"):
+			c.Technologies = config.Technologies
+
+		case strings.ToLower("MaxAttackPaths"):
+			c.MaxAttackPaths = config.MaxAttackPaths
+
 		case strings.ToLower("ExecuteModelMacro"):
 			c.ExecuteModelMacro = config.ExecuteModelMacro
 
+		case strings.ToLower("ModelMacroPlugins"):
+			c.ModelMacroPlugins = config.ModelMacroPlugins
+
 		case strings.ToLower("DiagramDPI"):
 			c.DiagramDPI = config.DiagramDPI
 
+		case strings.ToLower("DiagramFormats"):
+			c.DiagramFormats = config.DiagramFormats
+
 		case strings.ToLower("ServerPort"):
 			c.ServerPort = config.ServerPort
 
+		case strings.ToLower("ServerStorage"):
+			c.ServerStorage = config.ServerStorage
+
 		case strings.ToLower("GraphvizDPI"):
 			c.GraphvizDPI = config.GraphvizDPI
 
@@ -291,6 +370,12 @@ func (c *Config) Merge(config Config, values map[string]any) {
 
 		case strings.ToLower("Attractiveness"):
 			c.Attractiveness = config.Attractiveness
+
+		case strings.ToLower("RiskPolicy"):
+			c.RiskPolicy = config.RiskPolicy
+
+		case strings.ToLower("Tracker"):
+			c.Tracker = config.Tracker
 		}
 	}
 }
@@ -9,32 +9,53 @@ const (
 	ServerDir = "/server"
 	KeyDir    = "keys"
 
-	DefaultServerPort = 8080
+	DefaultServerPort    = 8080
+	DefaultServerStorage = "filesystem"
 
 	InputFile                   = "threagile.yaml"
 	ReportFilename              = "report.pdf"
+	ReportHTMLFilename          = "report.html"
 	ExcelRisksFilename          = "risks.xlsx"
 	ExcelTagsFilename           = "tags.xlsx"
 	JsonRisksFilename           = "risks.json"
 	JsonTechnicalAssetsFilename = "technical-assets.json"
 	JsonStatsFilename           = "stats.json"
+	JsonPortfolioFilename       = "portfolio.json"
+	SarifRisksFilename          = "risks.sarif"
+	JsonAttackPathsFilename     = "attack-paths.json"
 	TemplateFilename            = "background.pdf"
 	DataFlowDiagramFilenameDOT  = "data-flow-diagram.gv"
 	DataFlowDiagramFilenamePNG  = "data-flow-diagram.png"
 	DataAssetDiagramFilenameDOT = "data-asset-diagram.gv"
 	DataAssetDiagramFilenamePNG = "data-asset-diagram.png"
+	TerraformModelFilename      = "threagile-terraform-model.yaml"
+	RenderedModelFilename       = "threagile-rendered.yaml"
+	PortfolioModelFilename      = "threagile-portfolio.yaml"
+
+	DiagramFormatPNG = "png"
+	DiagramFormatSVG = "svg"
+	DiagramFormatPDF = "pdf"
 
 	RAAPluginName = "raa_calc"
 
 	DefaultGraphvizDPI              = 120
 	MinGraphvizDPI                  = 20
 	MaxGraphvizDPI                  = 300
+	DefaultSubDiagramHops           = 1
 	DefaultBackupHistoryFilesToKeep = 50
+	DefaultRiskRulesPluginTimeout   = 60 // seconds
+	DefaultMaxAttackPaths           = 3  // per sensitive data asset
+
+	DefaultTrackerAcceptedLabel = "risk-accepted"
 )
 
 const (
 	QuitCommand                 = "quit"
 	AnalyzeModelCommand         = "analyze-model"
+	DiffModelsCommand           = "diff"
+	RenderSubDiagramCommand     = "render-sub-diagram"
+	ImportTerraformCommand      = "import-terraform"
+	SyncRiskTrackingCommand     = "sync-risk-tracking"
 	CreateExampleModelCommand   = "create-example-model"
 	CreateStubModelCommand      = "create-stub-model"
 	CreateEditingSupportCommand = "create-editing-support"
@@ -47,4 +68,8 @@ const (
 	ExplainModelMacrosCommand   = "explain-model-macros"
 	Print3rdPartyCommand        = "print-3rd-party-licenses"
 	PrintLicenseCommand         = "print-license"
+	MigrateServerStorageCommand = "migrate-storage"
+	ValidateModelCommand        = "validate-model"
+	RenderModelCommand          = "render-model"
+	AnalyzePortfolioCommand     = "analyze-portfolio"
 )
@@ -0,0 +1,14 @@
+package common
+
+// RiskPolicy defines when an analysis should fail (e.g. to block merges in CI pipelines)
+type RiskPolicy struct {
+	FailOnSeverity string         // lowest risk severity failing the analysis, empty disables the threshold
+	MaxRisks       map[string]int // maximum number of risks allowed per risk severity
+	Statuses       []string       // risk statuses taken into account, empty means all statuses still at risk
+
+	FailOnExpiredRiskTracking bool // fail if any risk tracking (e.g. an acceptance) has expired
+}
+
+func (p RiskPolicy) IsEmpty() bool {
+	return len(p.FailOnSeverity) == 0 && len(p.MaxRisks) == 0 && !p.FailOnExpiredRiskTracking
+}
@@ -0,0 +1,10 @@
+package common
+
+// TrackerConfig selects the issue tracker used to synchronize risk tracking tickets,
+// credentials are only read from the environment (see tracker.TokenEnvironmentVariable)
+type TrackerConfig struct {
+	Type          string // github, gitlab, jira or file
+	URL           string // base URL of the tracker API, empty means the public service (not applicable to jira)
+	Project       string // owner/repo (github), group/project (gitlab), project key (jira) or the issue file (file)
+	AcceptedLabel string // label marking an issue as accepted risk
+}
@@ -24,6 +24,9 @@ const (
 		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile -verbose -model -output app/work \n\n" +
 		"If you want to run Threagile as a server (REST API) on some port (here 8080):  \n" +
 		" docker run --rm -it --shm-size=256m  -p 8080:8080 --name --mount 'type=volume,src=threagile-storage,dst=/data,readonly=false' threagile/threagile server --server-port 8080 \n\n" +
+		"If you want several Threagile server replicas to share their keys and models, use a database as server storage (here SQLite in the server folder) and migrate an existing storage to it first: \n" +
+		" docker run --rm -it --mount 'type=volume,src=threagile-storage,dst=/data,readonly=false' threagile/threagile server " + common.MigrateServerStorageCommand + " --to-storage sqlite:threagile.db \n" +
+		" docker run --rm -it --shm-size=256m -p 8080:8080 --mount 'type=volume,src=threagile-storage,dst=/data,readonly=false' threagile/threagile server --server-port 8080 --server-storage sqlite:threagile.db \n\n" +
 		"If you want to find out about the different enum values usable in the model yaml file: \n" +
 		" docker run --rm -it threagile/threagile " + common.ListTypesCommand + "\n\n" +
 		"If you want to use some nice editing help (syntax validation, autocompletion, and live templates) in your favourite IDE: " +
@@ -42,5 +45,6 @@ const (
 		" - google-uuid (BSD License): https://github.com/google/uuid/blob/master/LICENSE\n" +
 		" - gin-gonic (MIT License): https://github.com/gin-gonic/gin/blob/master/LICENSE\n" +
 		" - swagger-ui (Apache License): https://swagger.io/license/\n" +
-		" - cobra-cli (Apache License): https://github.com/spf13/cobra-cli/blob/main/LICENSE.txt\n"
+		" - cobra-cli (Apache License): https://github.com/spf13/cobra-cli/blob/main/LICENSE.txt\n" +
+		" - modernc-sqlite (BSD License): https://gitlab.com/cznic/sqlite/-/blob/master/LICENSE\n"
 )
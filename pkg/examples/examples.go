@@ -6,6 +6,7 @@ package examples
 import (
 	"fmt"
 	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/schema"
 	"io"
 	"os"
 	"path/filepath"
@@ -40,7 +41,11 @@ func CreateStubModelFile(appFolder, outputDir string) error {
 }
 
 func CreateEditingSupportFiles(appFolder, outputDir string) error {
-	_, schemaError := copyFile(filepath.Join(appFolder, "schema.json"), filepath.Join(outputDir, "schema.json"))
+	schemaJSON, schemaError := schema.JSON()
+	if schemaError != nil {
+		return schemaError
+	}
+	schemaError = os.WriteFile(filepath.Join(outputDir, "schema.json"), schemaJSON, 0644)
 	if schemaError != nil {
 		return schemaError
 	}
@@ -0,0 +1,154 @@
+package importer
+
+import (
+	"sort"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+// MergeImportedModel adds the imported technical assets, communication links and trust boundaries to the model,
+// matching them by their IDs. Hand-written fields always win: existing elements only receive additional tags,
+// links to new targets and technical assets or nested trust boundaries not yet placed elsewhere.
+func MergeImportedModel(model *input.Model, imported *input.Model) []string {
+	changes := make([]string, 0)
+	if model.TechnicalAssets == nil {
+		model.TechnicalAssets = make(map[string]input.TechnicalAsset)
+	}
+	if model.TrustBoundaries == nil {
+		model.TrustBoundaries = make(map[string]input.TrustBoundary)
+	}
+
+	assetTitlesById := make(map[string]string)
+	for title, asset := range model.TechnicalAssets {
+		assetTitlesById[asset.ID] = title
+	}
+	for _, title := range sortedKeys(imported.TechnicalAssets) {
+		asset := imported.TechnicalAssets[title]
+		existingTitle, exists := assetTitlesById[asset.ID]
+		if !exists {
+			title = uniqueTitle(model.TechnicalAssets, title, asset.ID)
+			model.TechnicalAssets[title] = asset
+			assetTitlesById[asset.ID] = title
+			changes = append(changes, "adding technical asset: "+title)
+			continue
+		}
+
+		existing := model.TechnicalAssets[existingTitle]
+		for _, tag := range asset.Tags {
+			if !contains(existing.Tags, tag) {
+				existing.Tags = append(existing.Tags, tag)
+				changes = append(changes, "adding tag "+tag+" to technical asset: "+existingTitle)
+			}
+		}
+		for _, linkTitle := range sortedKeys(asset.CommunicationLinks) {
+			link := asset.CommunicationLinks[linkTitle]
+			if _, titleUsed := existing.CommunicationLinks[linkTitle]; titleUsed || hasLinkTo(existing, link.Target) {
+				continue
+			}
+			if existing.CommunicationLinks == nil {
+				existing.CommunicationLinks = make(map[string]input.CommunicationLink)
+			}
+			existing.CommunicationLinks[linkTitle] = link
+			changes = append(changes, "adding communication link "+linkTitle+" to technical asset: "+existingTitle)
+		}
+		model.TechnicalAssets[existingTitle] = existing
+	}
+
+	boundaryTitlesById := make(map[string]string)
+	placed := make(map[string]bool) // technical asset IDs inside of trust boundaries
+	nested := make(map[string]bool) // trust boundary IDs nested in trust boundaries
+	for title, boundary := range model.TrustBoundaries {
+		boundaryTitlesById[boundary.ID] = title
+		for _, id := range boundary.TechnicalAssetsInside {
+			placed[id] = true
+		}
+		for _, id := range boundary.TrustBoundariesNested {
+			nested[id] = true
+		}
+	}
+	for _, title := range sortedKeys(imported.TrustBoundaries) {
+		boundary := imported.TrustBoundaries[title]
+		assetsInside := make([]string, 0)
+		for _, id := range boundary.TechnicalAssetsInside {
+			if !placed[id] {
+				assetsInside = append(assetsInside, id)
+				placed[id] = true
+			}
+		}
+		boundariesNested := make([]string, 0)
+		for _, id := range boundary.TrustBoundariesNested {
+			if !nested[id] {
+				boundariesNested = append(boundariesNested, id)
+				nested[id] = true
+			}
+		}
+
+		existingTitle, exists := boundaryTitlesById[boundary.ID]
+		if !exists {
+			boundary.TechnicalAssetsInside = assetsInside
+			boundary.TrustBoundariesNested = boundariesNested
+			title = uniqueTitle(model.TrustBoundaries, title, boundary.ID)
+			model.TrustBoundaries[title] = boundary
+			boundaryTitlesById[boundary.ID] = title
+			changes = append(changes, "adding trust boundary: "+title)
+			continue
+		}
+
+		existing := model.TrustBoundaries[existingTitle]
+		for _, tag := range boundary.Tags {
+			if !contains(existing.Tags, tag) {
+				existing.Tags = append(existing.Tags, tag)
+				changes = append(changes, "adding tag "+tag+" to trust boundary: "+existingTitle)
+			}
+		}
+		for _, id := range assetsInside {
+			existing.TechnicalAssetsInside = append(existing.TechnicalAssetsInside, id)
+			changes = append(changes, "adding technical asset "+id+" to trust boundary: "+existingTitle)
+		}
+		for _, id := range boundariesNested {
+			existing.TrustBoundariesNested = append(existing.TrustBoundariesNested, id)
+			changes = append(changes, "nesting trust boundary "+id+" in trust boundary: "+existingTitle)
+		}
+		model.TrustBoundaries[existingTitle] = existing
+	}
+
+	for _, tag := range imported.TagsAvailable {
+		model.AddTagToModelInput(tag, false, &changes)
+	}
+	return changes
+}
+
+func hasLinkTo(asset input.TechnicalAsset, targetId string) bool {
+	for _, link := range asset.CommunicationLinks {
+		if link.Target == targetId {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueTitle keeps the title unless it is already used by another element, then the ID is appended
+func uniqueTitle[T any](elements map[string]T, title string, id string) string {
+	if _, used := elements[title]; used {
+		return title + " (" + id + ")"
+	}
+	return title
+}
+
+func sortedKeys[T any](elements map[string]T) []string {
+	keys := make([]string, 0, len(elements))
+	for key := range elements {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
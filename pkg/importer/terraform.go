@@ -0,0 +1,746 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+// terraformDocument is the part of the `terraform show -json` output (of a state or of a plan) needed for the import
+type terraformDocument struct {
+	FormatVersion string                  `json:"format_version"`
+	Values        *terraformValues        `json:"values"`
+	PlannedValues *terraformValues        `json:"planned_values"`
+	Configuration *terraformConfiguration `json:"configuration"`
+}
+
+type terraformValues struct {
+	RootModule terraformModule `json:"root_module"`
+}
+
+type terraformModule struct {
+	Address      string              `json:"address"`
+	Resources    []terraformResource `json:"resources"`
+	ChildModules []terraformModule   `json:"child_modules"`
+}
+
+type terraformResource struct {
+	Address string         `json:"address"`
+	Mode    string         `json:"mode"`
+	Type    string         `json:"type"`
+	Name    string         `json:"name"`
+	Values  map[string]any `json:"values"`
+}
+
+type terraformConfiguration struct {
+	RootModule terraformModuleConfiguration `json:"root_module"`
+}
+
+type terraformModuleConfiguration struct {
+	Resources   []terraformResourceConfiguration `json:"resources"`
+	ModuleCalls map[string]struct {
+		Module terraformModuleConfiguration `json:"module"`
+	} `json:"module_calls"`
+}
+
+type terraformResourceConfiguration struct {
+	Address     string         `json:"address"`
+	Expressions map[string]any `json:"expressions"`
+}
+
+type terraformAssetMapping struct {
+	technology string
+	assetType  string
+	machine    string
+	tags       []string
+}
+
+// terraformTechnicalAssets maps the supported resource types to technical assets, the tags are the ones understood
+// by the missing-cloud-hardening rule
+var terraformTechnicalAssets = map[string]terraformAssetMapping{
+	// AWS
+	"aws_instance":             {"application-server", "process", "virtual", []string{"aws", "aws:ec2"}},
+	"aws_s3_bucket":            {"file-server", "datastore", "serverless", []string{"aws", "aws:s3"}},
+	"aws_ebs_volume":           {"block-storage", "datastore", "virtual", []string{"aws", "aws:ebs"}},
+	"aws_db_instance":          {"database", "datastore", "virtual", []string{"aws", "aws:rds"}},
+	"aws_rds_cluster":          {"database", "datastore", "virtual", []string{"aws", "aws:rds"}},
+	"aws_dynamodb_table":       {"database", "datastore", "serverless", []string{"aws", "aws:dynamodb"}},
+	"aws_elasticache_cluster":  {"database", "datastore", "virtual", []string{"aws"}},
+	"aws_lambda_function":      {"function", "process", "serverless", []string{"aws", "aws:lambda"}},
+	"aws_lb":                   {"load-balancer", "process", "virtual", []string{"aws"}},
+	"aws_alb":                  {"load-balancer", "process", "virtual", []string{"aws"}},
+	"aws_elb":                  {"load-balancer", "process", "virtual", []string{"aws"}},
+	"aws_api_gateway_rest_api": {"gateway", "process", "serverless", []string{"aws", "aws:apigateway"}},
+	"aws_apigatewayv2_api":     {"gateway", "process", "serverless", []string{"aws", "aws:apigateway"}},
+	"aws_sqs_queue":            {"message-queue", "process", "serverless", []string{"aws", "aws:sqs"}},
+	"aws_sns_topic":            {"message-queue", "process", "serverless", []string{"aws"}},
+	"aws_ecs_service":          {"container-platform", "process", "container", []string{"aws"}},
+	"aws_eks_cluster":          {"container-platform", "process", "virtual", []string{"aws"}},
+
+	// Azure
+	"azurerm_linux_virtual_machine":      {"application-server", "process", "virtual", []string{"azure"}},
+	"azurerm_windows_virtual_machine":    {"application-server", "process", "virtual", []string{"azure"}},
+	"azurerm_virtual_machine":            {"application-server", "process", "virtual", []string{"azure"}},
+	"azurerm_storage_account":            {"file-server", "datastore", "serverless", []string{"azure"}},
+	"azurerm_mssql_server":               {"database", "datastore", "virtual", []string{"azure"}},
+	"azurerm_postgresql_server":          {"database", "datastore", "virtual", []string{"azure"}},
+	"azurerm_postgresql_flexible_server": {"database", "datastore", "virtual", []string{"azure"}},
+	"azurerm_mysql_server":               {"database", "datastore", "virtual", []string{"azure"}},
+	"azurerm_mysql_flexible_server":      {"database", "datastore", "virtual", []string{"azure"}},
+	"azurerm_cosmosdb_account":           {"database", "datastore", "serverless", []string{"azure"}},
+	"azurerm_function_app":               {"function", "process", "serverless", []string{"azure"}},
+	"azurerm_linux_function_app":         {"function", "process", "serverless", []string{"azure"}},
+	"azurerm_windows_function_app":       {"function", "process", "serverless", []string{"azure"}},
+	"azurerm_app_service":                {"web-application", "process", "virtual", []string{"azure"}},
+	"azurerm_linux_web_app":              {"web-application", "process", "virtual", []string{"azure"}},
+	"azurerm_windows_web_app":            {"web-application", "process", "virtual", []string{"azure"}},
+	"azurerm_lb":                         {"load-balancer", "process", "virtual", []string{"azure"}},
+	"azurerm_application_gateway":        {"gateway", "process", "virtual", []string{"azure"}},
+	"azurerm_kubernetes_cluster":         {"container-platform", "process", "virtual", []string{"azure"}},
+	"azurerm_key_vault":                  {"vault", "datastore", "serverless", []string{"azure"}},
+	"azurerm_servicebus_namespace":       {"message-queue", "process", "serverless", []string{"azure"}},
+	"azurerm_eventhub_namespace":         {"stream-processing", "process", "serverless", []string{"azure"}},
+
+	// GCP
+	"google_compute_instance":               {"application-server", "process", "virtual", []string{"gcp"}},
+	"google_storage_bucket":                 {"file-server", "datastore", "serverless", []string{"gcp"}},
+	"google_sql_database_instance":          {"database", "datastore", "virtual", []string{"gcp"}},
+	"google_spanner_instance":               {"database", "datastore", "serverless", []string{"gcp"}},
+	"google_bigtable_instance":              {"database", "datastore", "serverless", []string{"gcp"}},
+	"google_cloudfunctions_function":        {"function", "process", "serverless", []string{"gcp"}},
+	"google_cloudfunctions2_function":       {"function", "process", "serverless", []string{"gcp"}},
+	"google_cloud_run_service":              {"web-service-rest", "process", "serverless", []string{"gcp"}},
+	"google_cloud_run_v2_service":           {"web-service-rest", "process", "serverless", []string{"gcp"}},
+	"google_container_cluster":              {"container-platform", "process", "virtual", []string{"gcp"}},
+	"google_pubsub_topic":                   {"message-queue", "process", "serverless", []string{"gcp"}},
+	"google_compute_forwarding_rule":        {"load-balancer", "process", "virtual", []string{"gcp"}},
+	"google_compute_global_forwarding_rule": {"load-balancer", "process", "virtual", []string{"gcp"}},
+}
+
+type terraformBoundaryMapping struct {
+	boundaryType string
+	level        int // networks contain subnets contain security groups
+	tags         []string
+}
+
+var terraformTrustBoundaries = map[string]terraformBoundaryMapping{
+	"aws_vpc":                        {"network-cloud-provider", 0, []string{"aws", "aws:vpc"}},
+	"aws_subnet":                     {"network-virtual-lan", 1, []string{"aws", "aws:vpc"}},
+	"aws_security_group":             {"network-cloud-security-group", 2, []string{"aws", "aws:vpc"}},
+	"azurerm_virtual_network":        {"network-cloud-provider", 0, []string{"azure"}},
+	"azurerm_subnet":                 {"network-virtual-lan", 1, []string{"azure"}},
+	"azurerm_network_security_group": {"network-cloud-security-group", 2, []string{"azure"}},
+	"google_compute_network":         {"network-cloud-provider", 0, []string{"gcp"}},
+	"google_compute_subnetwork":      {"network-virtual-lan", 1, []string{"gcp"}},
+}
+
+// terraformNetworkInterfaces are followed when looking for the trust boundaries of a technical asset
+var terraformNetworkInterfaces = map[string]bool{
+	"aws_network_interface":     true,
+	"azurerm_network_interface": true,
+}
+
+var terraformWellKnownPorts = map[int]string{
+	21:    "ftp",
+	22:    "ssh",
+	25:    "smtp",
+	80:    "http",
+	389:   "ldap",
+	443:   "https",
+	445:   "smb",
+	465:   "smtp-encrypted",
+	587:   "smtp-encrypted",
+	636:   "ldaps",
+	1433:  "sql-access-protocol",
+	1521:  "sql-access-protocol",
+	2049:  "nfs",
+	3306:  "sql-access-protocol",
+	5432:  "sql-access-protocol",
+	5671:  "jms",
+	5672:  "jms",
+	6379:  "nosql-access-protocol",
+	8080:  "http",
+	8443:  "https",
+	9042:  "nosql-access-protocol",
+	27017: "nosql-access-protocol",
+}
+
+type terraformIngressRule struct {
+	origin   string   // address of the resource defining the rule
+	targets  []string // addresses of the trust boundaries (or network tags) receiving the traffic
+	sources  []string // addresses of the trust boundaries (or network tags) sending the traffic
+	protocol string
+	fromPort int
+	toPort   int
+}
+
+type terraformImporter struct {
+	resources           []terraformResource
+	resourcesByAddress  map[string]terraformResource
+	addressesByValue    map[string][]string // resource ids, arns, names and self links
+	addressesByConfig   map[string][]string // resource addresses without instance keys
+	expressionsByConfig map[string]map[string]any
+	assets              map[string]*input.TechnicalAsset // by resource address
+	boundaries          map[string]*input.TrustBoundary  // by resource address
+	titles              map[string]string                // by resource address
+}
+
+// ImportTerraformFile reads the output of `terraform show -json` for either a state or a plan file
+func ImportTerraformFile(filename string) (*input.Model, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read terraform json %q: %v", filename, err)
+	}
+	return ImportTerraform(data)
+}
+
+// ImportTerraform drafts a model from the output of `terraform show -json`: technical assets for the supported
+// AWS, Azure and GCP resources, trust boundaries for networks, subnets and security groups, and communication links
+// inferred from security group rules (AWS) and firewall rules between network tags (GCP). All ratings are defaults
+// which need to be reviewed.
+func ImportTerraform(data []byte) (*input.Model, error) {
+	var document terraformDocument
+	err := json.Unmarshal(data, &document)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse terraform json: %v", err)
+	}
+
+	values := document.Values
+	if values == nil {
+		values = document.PlannedValues
+	}
+	if values == nil {
+		return nil, fmt.Errorf("terraform json contains neither values nor planned values (expected output of `terraform show -json`)")
+	}
+
+	importer := &terraformImporter{
+		resourcesByAddress:  make(map[string]terraformResource),
+		addressesByValue:    make(map[string][]string),
+		addressesByConfig:   make(map[string][]string),
+		expressionsByConfig: make(map[string]map[string]any),
+		assets:              make(map[string]*input.TechnicalAsset),
+		boundaries:          make(map[string]*input.TrustBoundary),
+		titles:              make(map[string]string),
+	}
+	importer.collectResources(values.RootModule)
+	sort.Slice(importer.resources, func(i, j int) bool { return importer.resources[i].Address < importer.resources[j].Address })
+	if document.Configuration != nil {
+		importer.collectExpressions("", document.Configuration.RootModule)
+	}
+	return importer.importModel(), nil
+}
+
+func (what *terraformImporter) collectResources(module terraformModule) {
+	for _, resource := range module.Resources {
+		if resource.Mode != "" && resource.Mode != "managed" {
+			continue
+		}
+		what.resources = append(what.resources, resource)
+		what.resourcesByAddress[resource.Address] = resource
+		for _, key := range []string{"id", "arn", "self_link", "name"} {
+			if value, ok := resource.Values[key].(string); ok && len(value) > 0 {
+				what.addressesByValue[value] = append(what.addressesByValue[value], resource.Address)
+			}
+		}
+		configAddress := terraformConfigAddress(resource.Address)
+		what.addressesByConfig[configAddress] = append(what.addressesByConfig[configAddress], resource.Address)
+	}
+	for _, childModule := range module.ChildModules {
+		what.collectResources(childModule)
+	}
+}
+
+func (what *terraformImporter) collectExpressions(modulePrefix string, module terraformModuleConfiguration) {
+	for _, resource := range module.Resources {
+		what.expressionsByConfig[modulePrefix+resource.Address] = resource.Expressions
+	}
+	for name, call := range module.ModuleCalls {
+		what.collectExpressions(modulePrefix+"module."+name+".", call.Module)
+	}
+}
+
+var terraformInstanceKey = regexp.MustCompile(`\[[^]]*]`)
+
+// terraformConfigAddress removes the instance keys (of count and for_each) from a resource address
+func terraformConfigAddress(address string) string {
+	return terraformInstanceKey.ReplaceAllString(address, "")
+}
+
+func terraformModulePrefix(address string) string {
+	configAddress := terraformConfigAddress(address)
+	parts := strings.Split(configAddress, ".")
+	prefix := ""
+	for i := 0; i+1 < len(parts) && parts[i] == "module"; i += 2 {
+		prefix += "module." + parts[i+1] + "."
+	}
+	return prefix
+}
+
+// resolve returns the addresses of the resources referenced by value (via ids, arns, names) or by expression
+// (via references in the configuration of a plan)
+func (what *terraformImporter) resolve(modulePrefix string, value any, expression any) []string {
+	result := make([]string, 0)
+	for _, text := range terraformStrings(value) {
+		result = append(result, what.addressesByValue[text]...)
+	}
+	for _, reference := range terraformReferences(expression) {
+		parts := strings.Split(reference, ".")
+		for length := len(parts); length > 0; length-- {
+			if addresses, ok := what.addressesByConfig[modulePrefix+strings.Join(parts[:length], ".")]; ok {
+				result = append(result, addresses...)
+				break
+			}
+		}
+	}
+	return uniqueSorted(result)
+}
+
+// references returns all resources the resource refers to in any of its values or expressions
+func (what *terraformImporter) references(resource terraformResource) []string {
+	result := make([]string, 0)
+	for _, address := range what.resolve(terraformModulePrefix(resource.Address), resource.Values, what.expressions(resource)) {
+		if address != resource.Address {
+			result = append(result, address)
+		}
+	}
+	return result
+}
+
+func (what *terraformImporter) resolveAttribute(resource terraformResource, key string) []string {
+	return what.resolve(terraformModulePrefix(resource.Address), resource.Values[key], what.expressions(resource)[key])
+}
+
+func (what *terraformImporter) expressions(resource terraformResource) map[string]any {
+	return what.expressionsByConfig[terraformConfigAddress(resource.Address)]
+}
+
+func (what *terraformImporter) resource(address string) terraformResource {
+	return what.resourcesByAddress[address]
+}
+
+func (what *terraformImporter) importModel() *input.Model {
+	model := new(input.Model).Defaults()
+	model.ThreagileVersion = docs.ThreagileVersion
+	model.Title = "Terraform Import"
+	model.Date = time.Now().Format("2006-01-02")
+	model.BusinessCriticality = "important"
+	model.TechnicalOverview = input.Overview{Description: "Drafted from Terraform resources: all CIA ratings, data assets and communication links need to be reviewed."}
+
+	what.assignTitles()
+	changes := make([]string, 0)
+
+	for _, resource := range what.resources {
+		if mapping, ok := terraformTrustBoundaries[resource.Type]; ok {
+			what.boundaries[resource.Address] = &input.TrustBoundary{
+				ID:          terraformId(resource.Address),
+				Description: "Imported from Terraform resource " + resource.Address,
+				Type:        mapping.boundaryType,
+				Tags:        append([]string{}, mapping.tags...),
+			}
+		}
+		if mapping, ok := terraformTechnicalAssets[resource.Type]; ok {
+			what.assets[resource.Address] = &input.TechnicalAsset{
+				ID:                     terraformId(resource.Address),
+				Description:            "Imported from Terraform resource " + resource.Address,
+				Type:                   mapping.assetType,
+				Usage:                  "business",
+				Size:                   "service",
+				Technology:             mapping.technology,
+				Tags:                   append([]string{}, mapping.tags...),
+				Internet:               terraformIsInternetFacing(resource),
+				Machine:                mapping.machine,
+				Encryption:             terraformEncryption(resource),
+				Confidentiality:        "internal",
+				Integrity:              "operational",
+				Availability:           "operational",
+				JustificationCiaRating: "Default rating of the Terraform import, needs to be reviewed",
+				CommunicationLinks:     make(map[string]input.CommunicationLink),
+			}
+		}
+	}
+
+	what.nestTrustBoundaries()
+	what.placeTechnicalAssets()
+	what.inferCommunicationLinks()
+
+	for address, asset := range what.assets {
+		model.TechnicalAssets[what.titles[address]] = *asset
+		for _, tag := range asset.Tags {
+			model.AddTagToModelInput(tag, false, &changes)
+		}
+	}
+	for address, boundary := range what.boundaries {
+		model.TrustBoundaries[what.titles[address]] = *boundary
+		for _, tag := range boundary.Tags {
+			model.AddTagToModelInput(tag, false, &changes)
+		}
+	}
+	sort.Strings(model.TagsAvailable)
+	return model
+}
+
+// assignTitles uses the name of the resource (tag or attribute) as title, if unique
+func (what *terraformImporter) assignTitles() {
+	count := make(map[string]int)
+	names := make(map[string]string)
+	for _, resource := range what.resources {
+		_, isAsset := terraformTechnicalAssets[resource.Type]
+		_, isBoundary := terraformTrustBoundaries[resource.Type]
+		if !isAsset && !isBoundary {
+			continue
+		}
+		name := resource.Address
+		if tags, ok := resource.Values["tags"].(map[string]any); ok {
+			if tagName, ok := tags["Name"].(string); ok && len(tagName) > 0 {
+				name = tagName
+			}
+		}
+		for _, key := range []string{"name", "bucket"} {
+			if attributeName, ok := resource.Values[key].(string); ok && len(attributeName) > 0 && name == resource.Address {
+				name = attributeName
+			}
+		}
+		names[resource.Address] = name
+		count[name]++
+	}
+	for address, name := range names {
+		if count[name] > 1 {
+			name = address
+		}
+		what.titles[address] = name
+	}
+}
+
+// nestTrustBoundaries nests each subnet or security group into the network it refers to
+func (what *terraformImporter) nestTrustBoundaries() {
+	for _, resource := range what.resources {
+		mapping, ok := terraformTrustBoundaries[resource.Type]
+		if !ok {
+			continue
+		}
+		parent := ""
+		parentLevel := -1
+		for _, address := range what.references(resource) {
+			parentMapping, isBoundary := terraformTrustBoundaries[what.resource(address).Type]
+			if isBoundary && parentMapping.level < mapping.level && parentMapping.level > parentLevel {
+				parent, parentLevel = address, parentMapping.level
+			}
+		}
+		if len(parent) > 0 {
+			what.boundaries[parent].TrustBoundariesNested = append(what.boundaries[parent].TrustBoundariesNested, terraformId(resource.Address))
+		}
+	}
+}
+
+// placeTechnicalAssets puts each technical asset into the most specific trust boundary it refers to (directly or
+// via its network interfaces), as a technical asset can only be modeled inside a single trust boundary
+func (what *terraformImporter) placeTechnicalAssets() {
+	for _, resource := range what.resources {
+		if _, ok := what.assets[resource.Address]; !ok {
+			continue
+		}
+		boundary := ""
+		boundaryLevel := -1
+		for _, address := range what.boundaryReferences(resource) {
+			mapping := terraformTrustBoundaries[what.resource(address).Type]
+			if mapping.level > boundaryLevel {
+				boundary, boundaryLevel = address, mapping.level
+			}
+		}
+		if len(boundary) > 0 {
+			what.boundaries[boundary].TechnicalAssetsInside = append(what.boundaries[boundary].TechnicalAssetsInside, terraformId(resource.Address))
+		}
+	}
+}
+
+func (what *terraformImporter) boundaryReferences(resource terraformResource) []string {
+	result := make([]string, 0)
+	for _, address := range what.references(resource) {
+		referenced := what.resource(address)
+		if _, ok := terraformTrustBoundaries[referenced.Type]; ok {
+			result = append(result, address)
+		}
+		if terraformNetworkInterfaces[referenced.Type] {
+			for _, interfaceAddress := range what.references(referenced) {
+				if _, ok := terraformTrustBoundaries[what.resource(interfaceAddress).Type]; ok {
+					result = append(result, interfaceAddress)
+				}
+			}
+		}
+	}
+	return uniqueSorted(result)
+}
+
+// inferCommunicationLinks adds a link from each technical asset of a rule's source to each technical asset of the
+// rule's target
+func (what *terraformImporter) inferCommunicationLinks() {
+	members := make(map[string][]string) // technical asset addresses by security group address or network tag
+	for _, resource := range what.resources {
+		if _, ok := what.assets[resource.Address]; !ok {
+			continue
+		}
+		for _, address := range what.boundaryReferences(resource) {
+			if what.resource(address).Type == "aws_security_group" {
+				members[address] = append(members[address], resource.Address)
+			}
+		}
+		if resource.Type == "google_compute_instance" {
+			for _, tag := range terraformStrings(resource.Values["tags"]) {
+				members["tag:"+tag] = append(members["tag:"+tag], resource.Address)
+			}
+		}
+	}
+
+	for _, rule := range what.ingressRules() {
+		for _, source := range rule.sources {
+			for _, target := range rule.targets {
+				for _, sourceAsset := range members[source] {
+					for _, targetAsset := range members[target] {
+						if sourceAsset != targetAsset {
+							what.addCommunicationLink(sourceAsset, targetAsset, rule)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func (what *terraformImporter) addCommunicationLink(sourceAddress string, targetAddress string, rule terraformIngressRule) {
+	title := "Access to " + what.titles[targetAddress]
+	if rule.fromPort == rule.toPort && rule.fromPort > 0 {
+		title += " on port " + strconv.Itoa(rule.fromPort)
+	}
+	source := what.assets[sourceAddress]
+	if _, exists := source.CommunicationLinks[title]; exists {
+		return
+	}
+	source.CommunicationLinks[title] = input.CommunicationLink{
+		Target:         terraformId(targetAddress),
+		Description:    "Inferred from Terraform resource " + rule.origin,
+		Protocol:       terraformProtocol(rule),
+		Authentication: "none",
+		Authorization:  "none",
+		IpFiltered:     true,
+		Usage:          "business",
+	}
+}
+
+func (what *terraformImporter) ingressRules() []terraformIngressRule {
+	rules := make([]terraformIngressRule, 0)
+	for _, resource := range what.resources {
+		modulePrefix := terraformModulePrefix(resource.Address)
+		switch resource.Type {
+		case "aws_security_group":
+			blocks, _ := resource.Values["ingress"].([]any)
+			expressionBlocks, _ := what.expressions(resource)["ingress"].([]any)
+			for i, block := range blocks {
+				values, _ := block.(map[string]any)
+				var expressions map[string]any
+				if len(expressionBlocks) == len(blocks) {
+					expressions, _ = expressionBlocks[i].(map[string]any)
+				}
+				sources := what.resolve(modulePrefix, values["security_groups"], expressions["security_groups"])
+				if self, _ := values["self"].(bool); self {
+					sources = append(sources, resource.Address)
+				}
+				rules = append(rules, terraformIngressRule{origin: resource.Address, targets: []string{resource.Address}, sources: sources,
+					protocol: terraformString(values["protocol"]), fromPort: terraformInt(values["from_port"]), toPort: terraformInt(values["to_port"])})
+			}
+
+		case "aws_security_group_rule":
+			if terraformString(resource.Values["type"]) != "ingress" {
+				continue
+			}
+			targets := what.resolveAttribute(resource, "security_group_id")
+			sources := what.resolveAttribute(resource, "source_security_group_id")
+			if self, _ := resource.Values["self"].(bool); self {
+				sources = append(sources, targets...)
+			}
+			rules = append(rules, terraformIngressRule{origin: resource.Address, targets: targets, sources: sources,
+				protocol: terraformString(resource.Values["protocol"]), fromPort: terraformInt(resource.Values["from_port"]), toPort: terraformInt(resource.Values["to_port"])})
+
+		case "aws_vpc_security_group_ingress_rule":
+			rules = append(rules, terraformIngressRule{origin: resource.Address,
+				targets:  what.resolveAttribute(resource, "security_group_id"),
+				sources:  what.resolveAttribute(resource, "referenced_security_group_id"),
+				protocol: terraformString(resource.Values["ip_protocol"]), fromPort: terraformInt(resource.Values["from_port"]), toPort: terraformInt(resource.Values["to_port"])})
+
+		case "google_compute_firewall":
+			if direction := terraformString(resource.Values["direction"]); direction != "" && direction != "INGRESS" {
+				continue
+			}
+			sources := make([]string, 0)
+			for _, tag := range terraformStrings(resource.Values["source_tags"]) {
+				sources = append(sources, "tag:"+tag)
+			}
+			targets := make([]string, 0)
+			for _, tag := range terraformStrings(resource.Values["target_tags"]) {
+				targets = append(targets, "tag:"+tag)
+			}
+			allows, _ := resource.Values["allow"].([]any)
+			for _, allow := range allows {
+				values, _ := allow.(map[string]any)
+				ports := terraformStrings(values["ports"])
+				if len(ports) == 0 {
+					ports = []string{""}
+				}
+				for _, port := range ports {
+					from, to, _ := strings.Cut(port, "-")
+					if len(to) == 0 {
+						to = from
+					}
+					fromPort, _ := strconv.Atoi(from)
+					toPort, _ := strconv.Atoi(to)
+					rules = append(rules, terraformIngressRule{origin: resource.Address, targets: targets, sources: sources,
+						protocol: terraformString(values["protocol"]), fromPort: fromPort, toPort: toPort})
+				}
+			}
+		}
+	}
+	return rules
+}
+
+func terraformProtocol(rule terraformIngressRule) string {
+	if rule.fromPort != rule.toPort || rule.protocol == "-1" || rule.protocol == "all" {
+		return "unknown-protocol"
+	}
+	if protocol, ok := terraformWellKnownPorts[rule.fromPort]; ok {
+		return protocol
+	}
+	return "unknown-protocol"
+}
+
+func terraformIsInternetFacing(resource terraformResource) bool {
+	for _, key := range []string{"associate_public_ip_address", "publicly_accessible", "public_network_access_enabled"} {
+		if value, ok := resource.Values[key].(bool); ok && value {
+			return true
+		}
+	}
+	if internal, ok := resource.Values["internal"].(bool); ok && !internal {
+		return true
+	}
+	if publicIp := terraformString(resource.Values["public_ip"]); len(publicIp) > 0 {
+		return true
+	}
+	if strings.HasPrefix(terraformString(resource.Values["acl"]), "public-") {
+		return true
+	}
+	if interfaces, ok := resource.Values["network_interface"].([]any); ok { // GCP compute instances with external IPs
+		for _, networkInterface := range interfaces {
+			values, _ := networkInterface.(map[string]any)
+			if accessConfigs, ok := values["access_config"].([]any); ok && len(accessConfigs) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func terraformEncryption(resource terraformResource) string {
+	for _, key := range []string{"storage_encrypted", "encrypted"} {
+		if value, ok := resource.Values[key].(bool); ok && value {
+			return "transparent"
+		}
+	}
+	for _, key := range []string{"kms_key_id", "kms_key_arn", "server_side_encryption_configuration", "encryption"} {
+		switch value := resource.Values[key].(type) {
+		case string:
+			if len(value) > 0 {
+				return "transparent"
+			}
+		case []any:
+			if len(value) > 0 {
+				return "transparent"
+			}
+		}
+	}
+	return "none"
+}
+
+var terraformIdCleanup = regexp.MustCompile(`[^a-z0-9]+`)
+
+func terraformId(address string) string {
+	return strings.Trim(terraformIdCleanup.ReplaceAllString(strings.ToLower(address), "-"), "-")
+}
+
+func terraformString(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	}
+	return ""
+}
+
+func terraformInt(value any) int {
+	switch typed := value.(type) {
+	case float64:
+		return int(typed)
+	case string:
+		result, _ := strconv.Atoi(typed)
+		return result
+	}
+	return 0
+}
+
+// terraformStrings collects all strings of a (nested) value
+func terraformStrings(value any) []string {
+	result := make([]string, 0)
+	switch typed := value.(type) {
+	case string:
+		if len(typed) > 0 {
+			result = append(result, typed)
+		}
+	case []any:
+		for _, item := range typed {
+			result = append(result, terraformStrings(item)...)
+		}
+	case map[string]any:
+		for _, item := range typed {
+			result = append(result, terraformStrings(item)...)
+		}
+	}
+	return result
+}
+
+// terraformReferences collects all references of a (nested) configuration expression
+func terraformReferences(expression any) []string {
+	result := make([]string, 0)
+	switch typed := expression.(type) {
+	case []any:
+		for _, item := range typed {
+			result = append(result, terraformReferences(item)...)
+		}
+	case map[string]any:
+		for key, item := range typed {
+			if key == "references" {
+				result = append(result, terraformStrings(item)...)
+			} else {
+				result = append(result, terraformReferences(item)...)
+			}
+		}
+	}
+	return result
+}
+
+func uniqueSorted(values []string) []string {
+	sort.Strings(values)
+	result := make([]string, 0, len(values))
+	for i, value := range values {
+		if i == 0 || values[i-1] != value {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/risks"
+)
+
+const terraformState = `{
+  "format_version": "1.0",
+  "values": {
+    "root_module": {
+      "resources": [
+        {"address": "aws_vpc.main", "mode": "managed", "type": "aws_vpc", "name": "main",
+         "values": {"id": "vpc-1", "tags": {"Name": "Main VPC"}}},
+        {"address": "aws_subnet.private", "mode": "managed", "type": "aws_subnet", "name": "private",
+         "values": {"id": "subnet-1", "vpc_id": "vpc-1"}},
+        {"address": "aws_security_group.web", "mode": "managed", "type": "aws_security_group", "name": "web",
+         "values": {"id": "sg-web", "name": "web", "vpc_id": "vpc-1", "ingress": []}},
+        {"address": "aws_security_group.db", "mode": "managed", "type": "aws_security_group", "name": "db",
+         "values": {"id": "sg-db", "name": "db", "vpc_id": "vpc-1",
+                    "ingress": [{"from_port": 5432, "to_port": 5432, "protocol": "tcp", "security_groups": ["sg-web"], "self": false}]}},
+        {"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web",
+         "values": {"id": "i-1", "subnet_id": "subnet-1", "vpc_security_group_ids": ["sg-web"], "associate_public_ip_address": true, "tags": {"Name": "Web Server"}}},
+        {"address": "aws_db_instance.db", "mode": "managed", "type": "aws_db_instance", "name": "db",
+         "values": {"id": "db-1", "vpc_security_group_ids": ["sg-db"], "storage_encrypted": true}},
+        {"address": "aws_s3_bucket.assets", "mode": "managed", "type": "aws_s3_bucket", "name": "assets",
+         "values": {"id": "assets-bucket", "bucket": "assets-bucket"}},
+        {"address": "data.aws_ami.ubuntu", "mode": "data", "type": "aws_ami", "name": "ubuntu", "values": {"id": "ami-1"}}
+      ]
+    }
+  }
+}`
+
+const terraformPlan = `{
+  "format_version": "1.2",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {"address": "aws_security_group.app", "mode": "managed", "type": "aws_security_group", "name": "app", "values": {"name": "app"}},
+        {"address": "aws_instance.app[0]", "mode": "managed", "type": "aws_instance", "name": "app", "values": {}}
+      ],
+      "child_modules": [
+        {"address": "module.queue", "resources": [
+          {"address": "module.queue.aws_sqs_queue.jobs", "mode": "managed", "type": "aws_sqs_queue", "name": "jobs", "values": {"name": "jobs"}}
+        ]}
+      ]
+    }
+  },
+  "configuration": {
+    "root_module": {
+      "resources": [
+        {"address": "aws_instance.app", "expressions": {"vpc_security_group_ids": {"references": ["aws_security_group.app.id", "aws_security_group.app"]}}}
+      ]
+    }
+  }
+}`
+
+func TestImportTerraformState(t *testing.T) {
+	imported, err := ImportTerraform([]byte(terraformState))
+	assert.NoError(t, err)
+
+	assert.Len(t, imported.TechnicalAssets, 3)
+	assert.Len(t, imported.TrustBoundaries, 4)
+
+	web := imported.TechnicalAssets["Web Server"]
+	assert.Equal(t, "aws-instance-web", web.ID)
+	assert.Equal(t, "application-server", web.Technology)
+	assert.Equal(t, []string{"aws", "aws:ec2"}, web.Tags)
+	assert.True(t, web.Internet)
+	assert.Equal(t, "sql-access-protocol", web.CommunicationLinks["Access to aws_db_instance.db on port 5432"].Protocol)
+	assert.Equal(t, "aws-db-instance-db", web.CommunicationLinks["Access to aws_db_instance.db on port 5432"].Target)
+
+	assert.Equal(t, "transparent", imported.TechnicalAssets["aws_db_instance.db"].Encryption)
+	assert.Equal(t, []string{"aws", "aws:s3"}, imported.TechnicalAssets["assets-bucket"].Tags)
+
+	// the security group is more specific than the subnet
+	assert.Equal(t, []string{"aws-instance-web"}, imported.TrustBoundaries["web"].TechnicalAssetsInside)
+	assert.Empty(t, imported.TrustBoundaries["aws_subnet.private"].TechnicalAssetsInside)
+	assert.ElementsMatch(t, []string{"aws-subnet-private", "aws-security-group-web", "aws-security-group-db"}, imported.TrustBoundaries["Main VPC"].TrustBoundariesNested)
+
+	imported.Title = "Terraform"
+	_, err = model.ParseModel(imported, make(map[string]risks.RiskRule), make(map[string]*model.CustomRisk))
+	assert.NoError(t, err)
+}
+
+func TestImportTerraformPlanResolvesReferences(t *testing.T) {
+	imported, err := ImportTerraform([]byte(terraformPlan))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"aws-instance-app-0"}, imported.TrustBoundaries["app"].TechnicalAssetsInside)
+	assert.Equal(t, "module-queue-aws-sqs-queue-jobs", imported.TechnicalAssets["jobs"].ID)
+}
+
+func TestMergeImportedModelKeepsHandWrittenFields(t *testing.T) {
+	imported, err := ImportTerraform([]byte(terraformState))
+	assert.NoError(t, err)
+
+	existing := new(input.Model).Defaults()
+	existing.TechnicalAssets["Frontend"] = input.TechnicalAsset{ID: "aws-instance-web", Technology: "web-server", Tags: []string{"frontend"}}
+	existing.TrustBoundaries["DMZ"] = input.TrustBoundary{ID: "dmz", TechnicalAssetsInside: []string{"aws-instance-web"}}
+
+	changes := MergeImportedModel(existing, imported)
+	assert.NotEmpty(t, changes)
+
+	frontend := existing.TechnicalAssets["Frontend"]
+	assert.Equal(t, "web-server", frontend.Technology)
+	assert.Equal(t, []string{"frontend", "aws", "aws:ec2"}, frontend.Tags)
+	assert.Len(t, frontend.CommunicationLinks, 1)
+	assert.NotContains(t, existing.TechnicalAssets, "Web Server")
+	assert.Empty(t, existing.TrustBoundaries["web"].TechnicalAssetsInside)
+	assert.Len(t, existing.TechnicalAssets, 3)
+}
@@ -3,20 +3,21 @@ package input
 import "fmt"
 
 type CommunicationLink struct {
-	Target                 string   `yaml:"target,omitempty" json:"target,omitempty"`
-	Description            string   `yaml:"description,omitempty" json:"description,omitempty"`
-	Protocol               string   `yaml:"protocol,omitempty" json:"protocol,omitempty"`
-	Authentication         string   `yaml:"authentication,omitempty" json:"authentication,omitempty"`
-	Authorization          string   `yaml:"authorization,omitempty" json:"authorization,omitempty"`
-	Tags                   []string `yaml:"tags,omitempty" json:"tags,omitempty"`
-	VPN                    bool     `yaml:"vpn,omitempty" json:"vpn,omitempty"`
-	IpFiltered             bool     `yaml:"ip_filtered,omitempty" json:"ip_filtered,omitempty"`
-	Readonly               bool     `yaml:"readonly,omitempty" json:"readonly,omitempty"`
-	Usage                  string   `yaml:"usage,omitempty" json:"usage,omitempty"`
-	DataAssetsSent         []string `yaml:"data_assets_sent,omitempty" json:"data_assets_sent,omitempty"`
-	DataAssetsReceived     []string `yaml:"data_assets_received,omitempty" json:"data_assets_received,omitempty"`
-	DiagramTweakWeight     int      `yaml:"diagram_tweak_weight,omitempty" json:"diagram_tweak_weight,omitempty"`
-	DiagramTweakConstraint bool     `yaml:"diagram_tweak_constraint,omitempty" json:"diagram_tweak_constraint,omitempty"`
+	Target                 string   `yaml:"target,omitempty" json:"target,omitempty" description:"Target" schema:"required"`
+	Description            string   `yaml:"description,omitempty" json:"description,omitempty" description:"Description" schema:"required,nullable"`
+	Protocol               string   `yaml:"protocol,omitempty" json:"protocol,omitempty" description:"Protocol" schema:"required,enum=protocol"`
+	Authentication         string   `yaml:"authentication,omitempty" json:"authentication,omitempty" description:"Authentication" schema:"required,enum=authentication"`
+	Authorization          string   `yaml:"authorization,omitempty" json:"authorization,omitempty" description:"Authorization" schema:"required,enum=authorization"`
+	Tags                   []string `yaml:"tags,omitempty" json:"tags,omitempty" description:"Tags"`
+	VPN                    bool     `yaml:"vpn,omitempty" json:"vpn,omitempty" description:"VPN" schema:"required"`
+	IpFiltered             bool     `yaml:"ip_filtered,omitempty" json:"ip_filtered,omitempty" description:"IP filtered" schema:"required"`
+	Readonly               bool     `yaml:"readonly,omitempty" json:"readonly,omitempty" description:"Readonly" schema:"required"`
+	Usage                  string   `yaml:"usage,omitempty" json:"usage,omitempty" description:"Usage" schema:"required,enum=usage"`
+	DataAssetsSent         []string `yaml:"data_assets_sent,omitempty" json:"data_assets_sent,omitempty" description:"Data assets sent"`
+	DataAssetsReceived     []string `yaml:"data_assets_received,omitempty" json:"data_assets_received,omitempty" description:"Data assets received"`
+	DiagramTweakWeight     int      `yaml:"diagram_tweak_weight,omitempty" json:"diagram_tweak_weight,omitempty" description:"Diagram tweak weight"`
+	DiagramTweakConstraint bool     `yaml:"diagram_tweak_constraint,omitempty" json:"diagram_tweak_constraint,omitempty" description:"Diagram tweak constraint"`
+	Overlay                string   `yaml:"overlay,omitempty" json:"overlay,omitempty" description:"How the element of an overlay file is applied to the matching element of the model" schema:"enum=overlay"`
 }
 
 func (what *CommunicationLink) Merge(other CommunicationLink) error {
@@ -3,17 +3,18 @@ package input
 import "fmt"
 
 type DataAsset struct {
-	ID                     string   `yaml:"id,omitempty" json:"id,omitempty"`
-	Description            string   `yaml:"description,omitempty" json:"description,omitempty"`
-	Usage                  string   `yaml:"usage,omitempty" json:"usage,omitempty"`
-	Tags                   []string `yaml:"tags,omitempty" json:"tags,omitempty"`
-	Origin                 string   `yaml:"origin,omitempty" json:"origin,omitempty"`
-	Owner                  string   `yaml:"owner,omitempty" json:"owner,omitempty"`
-	Quantity               string   `yaml:"quantity,omitempty" json:"quantity,omitempty"`
-	Confidentiality        string   `yaml:"confidentiality,omitempty" json:"confidentiality,omitempty"`
-	Integrity              string   `yaml:"integrity,omitempty" json:"integrity,omitempty"`
-	Availability           string   `yaml:"availability,omitempty" json:"availability,omitempty"`
-	JustificationCiaRating string   `yaml:"justification_cia_rating,omitempty" json:"justification_cia_rating,omitempty"`
+	ID                     string   `yaml:"id,omitempty" json:"id,omitempty" description:"ID" schema:"required"`
+	Description            string   `yaml:"description,omitempty" json:"description,omitempty" description:"Description" schema:"required,nullable"`
+	Usage                  string   `yaml:"usage,omitempty" json:"usage,omitempty" description:"Usage" schema:"required,enum=usage"`
+	Tags                   []string `yaml:"tags,omitempty" json:"tags,omitempty" description:"Tags"`
+	Origin                 string   `yaml:"origin,omitempty" json:"origin,omitempty" description:"Origin"`
+	Owner                  string   `yaml:"owner,omitempty" json:"owner,omitempty" description:"Owner"`
+	Quantity               string   `yaml:"quantity,omitempty" json:"quantity,omitempty" description:"Quantity" schema:"required,enum=quantity"`
+	Confidentiality        string   `yaml:"confidentiality,omitempty" json:"confidentiality,omitempty" description:"Confidentiality" schema:"required,enum=confidentiality"`
+	Integrity              string   `yaml:"integrity,omitempty" json:"integrity,omitempty" description:"Integrity" schema:"required,enum=criticality"`
+	Availability           string   `yaml:"availability,omitempty" json:"availability,omitempty" description:"Availability" schema:"required,enum=criticality"`
+	JustificationCiaRating string   `yaml:"justification_cia_rating,omitempty" json:"justification_cia_rating,omitempty" description:"Justification of the rating"`
+	Overlay                string   `yaml:"overlay,omitempty" json:"overlay,omitempty" description:"How the element of an overlay file is applied to the matching element of the model" schema:"enum=overlay"`
 }
 
 func (what *DataAsset) Merge(other DataAsset) error {
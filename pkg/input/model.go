@@ -5,8 +5,8 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package input
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"slices"
@@ -21,34 +21,42 @@ import (
 // === Model Type Stuff ======================================
 
 type Model struct { // TODO: Eventually remove this and directly use ParsedModelRoot? But then the error messages for model errors are not quite as good anymore...
-	ThreagileVersion                              string                            `yaml:"threagile_version,omitempty" json:"threagile_version,omitempty"`
-	Includes                                      []string                          `yaml:"includes,omitempty" json:"includes,omitempty"`
-	Title                                         string                            `yaml:"title,omitempty" json:"title,omitempty"`
-	Author                                        Author                            `yaml:"author,omitempty" json:"author,omitempty"`
-	Contributors                                  []Author                          `yaml:"contributors,omitempty" json:"contributors,omitempty"`
-	Date                                          string                            `yaml:"date,omitempty" json:"date,omitempty"`
-	AppDescription                                Overview                          `yaml:"application_description,omitempty" json:"application_description,omitempty"`
-	BusinessOverview                              Overview                          `yaml:"business_overview,omitempty" json:"business_overview,omitempty"`
-	TechnicalOverview                             Overview                          `yaml:"technical_overview,omitempty" json:"technical_overview,omitempty"`
-	BusinessCriticality                           string                            `yaml:"business_criticality,omitempty" json:"business_criticality,omitempty"`
-	ManagementSummaryComment                      string                            `yaml:"management_summary_comment,omitempty" json:"management_summary_comment,omitempty"`
-	SecurityRequirements                          map[string]string                 `yaml:"security_requirements,omitempty" json:"security_requirements,omitempty"`
-	Questions                                     map[string]string                 `yaml:"questions,omitempty" json:"questions,omitempty"`
-	AbuseCases                                    map[string]string                 `yaml:"abuse_cases,omitempty" json:"abuse_cases,omitempty"`
-	TagsAvailable                                 []string                          `yaml:"tags_available,omitempty" json:"tags_available,omitempty"`
-	DataAssets                                    map[string]DataAsset              `yaml:"data_assets,omitempty" json:"data_assets,omitempty"`
-	TechnicalAssets                               map[string]TechnicalAsset         `yaml:"technical_assets,omitempty" json:"technical_assets,omitempty"`
-	TrustBoundaries                               map[string]TrustBoundary          `yaml:"trust_boundaries,omitempty" json:"trust_boundaries,omitempty"`
-	SharedRuntimes                                map[string]SharedRuntime          `yaml:"shared_runtimes,omitempty" json:"shared_runtimes,omitempty"`
-	IndividualRiskCategories                      map[string]IndividualRiskCategory `yaml:"individual_risk_categories,omitempty" json:"individual_risk_categories,omitempty"`
-	RiskTracking                                  map[string]RiskTracking           `yaml:"risk_tracking,omitempty" json:"risk_tracking,omitempty"`
-	DiagramTweakNodesep                           int                               `yaml:"diagram_tweak_nodesep,omitempty" json:"diagram_tweak_nodesep,omitempty"`
-	DiagramTweakRanksep                           int                               `yaml:"diagram_tweak_ranksep,omitempty" json:"diagram_tweak_ranksep,omitempty"`
-	DiagramTweakEdgeLayout                        string                            `yaml:"diagram_tweak_edge_layout,omitempty" json:"diagram_tweak_edge_layout,omitempty"`
-	DiagramTweakSuppressEdgeLabels                bool                              `yaml:"diagram_tweak_suppress_edge_labels,omitempty" json:"diagram_tweak_suppress_edge_labels,omitempty"`
-	DiagramTweakLayoutLeftToRight                 bool                              `yaml:"diagram_tweak_layout_left_to_right,omitempty" json:"diagram_tweak_layout_left_to_right,omitempty"`
-	DiagramTweakInvisibleConnectionsBetweenAssets []string                          `yaml:"diagram_tweak_invisible_connections_between_assets,omitempty" json:"diagram_tweak_invisible_connections_between_assets,omitempty"`
-	DiagramTweakSameRankAssets                    []string                          `yaml:"diagram_tweak_same_rank_assets,omitempty" json:"diagram_tweak_same_rank_assets,omitempty"`
+	ThreagileVersion                              string                            `yaml:"threagile_version,omitempty" json:"threagile_version,omitempty" description:"Version of the Threagile toolkit" schema:"required"`
+	Includes                                      []string                          `yaml:"includes,omitempty" json:"includes,omitempty" description:"Model files to include (relative to this file)"`
+	Overlays                                      []string                          `yaml:"overlays,omitempty" json:"overlays,omitempty" description:"Model files to apply on top of the model after the includes, extending, replacing or deleting elements matched by their key or ID (relative to this file)"`
+	Variables                                     map[string]string                 `yaml:"variables,omitempty" json:"variables,omitempty" description:"Default values of the variables referenced as ${name} in the model files, overridden by --set and --values" schema:"scalar"`
+	Title                                         string                            `yaml:"title,omitempty" json:"title,omitempty" description:"Title of the model" schema:"required"`
+	Author                                        Author                            `yaml:"author,omitempty" json:"author,omitempty" description:"Author of the model" schema:"required"`
+	Contributors                                  []Author                          `yaml:"contributors,omitempty" json:"contributors,omitempty" description:"Contributors to the model"`
+	Date                                          string                            `yaml:"date,omitempty" json:"date,omitempty" description:"Date of the model" schema:"format=date"`
+	AppDescription                                Overview                          `yaml:"application_description,omitempty" json:"application_description,omitempty" description:"General description of the application, its purpose and functionality"`
+	BusinessOverview                              Overview                          `yaml:"business_overview,omitempty" json:"business_overview,omitempty" description:"Individual business overview for the report"`
+	TechnicalOverview                             Overview                          `yaml:"technical_overview,omitempty" json:"technical_overview,omitempty" description:"Individual technical overview for the report"`
+	BusinessCriticality                           string                            `yaml:"business_criticality,omitempty" json:"business_criticality,omitempty" description:"Business criticality of the target" schema:"required,enum=criticality"`
+	ManagementSummaryComment                      string                            `yaml:"management_summary_comment,omitempty" json:"management_summary_comment,omitempty" description:"Individual management summary for the report"`
+	SecurityRequirements                          map[string]string                 `yaml:"security_requirements,omitempty" json:"security_requirements,omitempty" description:"Custom security requirements for the report"`
+	Questions                                     map[string]string                 `yaml:"questions,omitempty" json:"questions,omitempty" description:"Custom questions for the report"`
+	AbuseCases                                    map[string]string                 `yaml:"abuse_cases,omitempty" json:"abuse_cases,omitempty" description:"Custom abuse cases for the report"`
+	TagsAvailable                                 []string                          `yaml:"tags_available,omitempty" json:"tags_available,omitempty" description:"Tags available" schema:"required,nullable"`
+	Technologies                                  map[string]Technology             `yaml:"technologies,omitempty" json:"technologies,omitempty" description:"Technologies used by the technical assets in addition to the built-in ones, keyed by name"`
+	DataAssets                                    map[string]DataAsset              `yaml:"data_assets,omitempty" json:"data_assets,omitempty" description:"Data assets" schema:"required"`
+	TechnicalAssets                               map[string]TechnicalAsset         `yaml:"technical_assets,omitempty" json:"technical_assets,omitempty" description:"Technical assets" schema:"required"`
+	TrustBoundaries                               map[string]TrustBoundary          `yaml:"trust_boundaries,omitempty" json:"trust_boundaries,omitempty" description:"Trust boundaries"`
+	SharedRuntimes                                map[string]SharedRuntime          `yaml:"shared_runtimes,omitempty" json:"shared_runtimes,omitempty" description:"Shared runtimes" schema:"required"`
+	IndividualRiskCategories                      map[string]IndividualRiskCategory `yaml:"individual_risk_categories,omitempty" json:"individual_risk_categories,omitempty" description:"Individual risk categories"`
+	RiskTracking                                  map[string]RiskTracking           `yaml:"risk_tracking,omitempty" json:"risk_tracking,omitempty" description:"Risk tracking"`
+	DiagramTweakNodesep                           int                               `yaml:"diagram_tweak_nodesep,omitempty" json:"diagram_tweak_nodesep,omitempty" description:"Diagram tweak nodesep"`
+	DiagramTweakRanksep                           int                               `yaml:"diagram_tweak_ranksep,omitempty" json:"diagram_tweak_ranksep,omitempty" description:"Diagram tweak ranksep"`
+	DiagramTweakEdgeLayout                        string                            `yaml:"diagram_tweak_edge_layout,omitempty" json:"diagram_tweak_edge_layout,omitempty" description:"Diagram tweak edge layout" schema:"enum=edge-layout"`
+	DiagramTweakSuppressEdgeLabels                bool                              `yaml:"diagram_tweak_suppress_edge_labels,omitempty" json:"diagram_tweak_suppress_edge_labels,omitempty" description:"Diagram tweak suppress edge labels"`
+	DiagramTweakLayoutLeftToRight                 bool                              `yaml:"diagram_tweak_layout_left_to_right,omitempty" json:"diagram_tweak_layout_left_to_right,omitempty" description:"Diagram tweak layout left to right"`
+	DiagramTweakInvisibleConnectionsBetweenAssets []string                          `yaml:"diagram_tweak_invisible_connections_between_assets,omitempty" json:"diagram_tweak_invisible_connections_between_assets,omitempty" description:"Diagram tweak invisible connections between assets"`
+	DiagramTweakSameRankAssets                    []string                          `yaml:"diagram_tweak_same_rank_assets,omitempty" json:"diagram_tweak_same_rank_assets,omitempty" description:"Diagram tweak same rank assets"`
+
+	Positions Positions `yaml:"-" json:"-"` // where the values of the model are defined, filled by Load and Parse
+	Portfolio bool      `yaml:"-" json:"-"` // for the combined model of a portfolio, whose ids are qualified with the names of its models
+
+	values map[string]string // of the variables, as given when loading plus the defaults declared by the model files
 }
 
 func (model *Model) Defaults() *Model {
@@ -56,6 +64,7 @@ func (model *Model) Defaults() *Model {
 		Questions:                make(map[string]string),
 		AbuseCases:               make(map[string]string),
 		SecurityRequirements:     make(map[string]string),
+		Technologies:             make(map[string]Technology),
 		DataAssets:               make(map[string]DataAsset),
 		TechnicalAssets:          make(map[string]TechnicalAsset),
 		TrustBoundaries:          make(map[string]TrustBoundary),
@@ -67,43 +76,106 @@ func (model *Model) Defaults() *Model {
 	return model
 }
 
+// Load reads a model file, merges all files included by it and applies its overlays, the returned errors carry the
+// positions (file, line and column) they refer to
 func (model *Model) Load(inputFilename string) error {
+	return model.LoadWithVariables(inputFilename, nil)
+}
+
+// LoadWithVariables loads a model like Load, with the given values of the variables referenced in the model files
+// overriding the defaults declared by them
+func (model *Model) LoadWithVariables(inputFilename string, values map[string]string) error {
+	model.values = make(map[string]string)
+	for name, value := range values {
+		model.values[name] = value
+	}
+
 	modelYaml, readError := os.ReadFile(filepath.Clean(inputFilename))
 	if readError != nil {
-		log.Fatal("Unable to read model file: ", readError)
+		return fmt.Errorf("unable to read model file: %w", readError)
 	}
 
-	unmarshalError := yaml.Unmarshal(modelYaml, &model)
-	if unmarshalError != nil {
-		log.Fatal("Unable to parse model yaml: ", unmarshalError)
+	parseError := model.Parse(inputFilename, modelYaml)
+	if parseError != nil {
+		return parseError
+	}
+	for i, overlayFile := range model.Overlays { // overlays are relative to the file declaring them, like includes
+		model.Overlays[i] = filepath.Join(filepath.Dir(inputFilename), overlayFile)
 	}
 
+	mergeErrors := make([]error, 0)
 	for _, includeFile := range model.Includes {
 		mergeError := model.Merge(filepath.Dir(inputFilename), includeFile)
 		if mergeError != nil {
-			log.Fatalf("Unable to merge model include %q: %v", includeFile, mergeError)
+			mergeErrors = append(mergeErrors, includeError(includeFile, mergeError))
 		}
 	}
+	if len(mergeErrors) > 0 {
+		return errors.Join(mergeErrors...)
+	}
 
+	for _, overlayFile := range model.Overlays {
+		overlayError := model.Overlay("", overlayFile)
+		if overlayError != nil {
+			return includeError(overlayFile, overlayError)
+		}
+	}
+	return nil
+}
+
+// Parse decodes the yaml of a model file, resolving the variables referenced, and records the positions of its values
+func (model *Model) Parse(filename string, modelYaml []byte) error {
+	var node yaml.Node
+	unmarshalError := yaml.Unmarshal(modelYaml, &node)
+	if unmarshalError != nil {
+		return yamlError(filename, "unable to parse model yaml", unmarshalError)
+	}
+
+	if model.values == nil {
+		model.values = make(map[string]string)
+	}
+	resolveError := ResolveVariables(filename, &node, model.values)
+	if resolveError != nil {
+		return resolveError
+	}
+
+	decodeError := node.Decode(model)
+	if decodeError != nil {
+		return yamlError(filename, "unable to parse model yaml", decodeError)
+	}
+
+	if model.Positions == nil {
+		model.Positions = make(Positions)
+	}
+	model.Positions.add(filename, &node, "")
 	return nil
 }
 
 func (model *Model) Merge(dir string, includeFilename string) error {
-	modelYaml, readError := os.ReadFile(filepath.Clean(filepath.Join(dir, includeFilename)))
+	filename := filepath.Clean(filepath.Join(dir, includeFilename))
+	modelYaml, readError := os.ReadFile(filename)
 	if readError != nil {
-		return fmt.Errorf("unable to read model file: %v", readError)
+		return fmt.Errorf("unable to read model file: %w", readError)
 	}
 
 	var fileStructure map[string]any
 	unmarshalStructureError := yaml.Unmarshal(modelYaml, &fileStructure)
 	if unmarshalStructureError != nil {
-		return fmt.Errorf("unable to parse model structure: %v", unmarshalStructureError)
+		return yamlError(filename, "unable to parse model structure", unmarshalStructureError)
 	}
 
-	var includedModel Model
-	unmarshalError := yaml.Unmarshal(modelYaml, &includedModel)
+	includedModel := Model{values: model.values}
+	unmarshalError := includedModel.Parse(filename, modelYaml)
 	if unmarshalError != nil {
-		return fmt.Errorf("unable to parse model yaml: %v", unmarshalError)
+		return unmarshalError
+	}
+	if model.Positions == nil {
+		model.Positions = make(Positions)
+	}
+	for path, position := range includedModel.Positions {
+		if _, found := model.Positions[path]; !found { // the including file comes first
+			model.Positions[path] = position
+		}
 	}
 
 	var mergeError error
@@ -113,62 +185,77 @@ func (model *Model) Merge(dir string, includeFilename string) error {
 			for _, includeFile := range includedModel.Includes {
 				mergeError = model.Merge(filepath.Join(dir, filepath.Dir(includeFilename)), includeFile)
 				if mergeError != nil {
-					return fmt.Errorf("failed to merge model include %q: %v", includeFile, mergeError)
+					return includeError(includeFile, mergeError)
+				}
+			}
+
+		case strings.ToLower("overlays"):
+			for _, overlayFile := range includedModel.Overlays {
+				model.Overlays = append(model.Overlays, filepath.Join(dir, filepath.Dir(includeFilename), overlayFile))
+			}
+
+		case strings.ToLower("variables"):
+			for name, value := range includedModel.Variables {
+				if _, declared := model.Variables[name]; !declared {
+					if model.Variables == nil {
+						model.Variables = make(map[string]string)
+					}
+					model.Variables[name] = value
 				}
 			}
 
 		case strings.ToLower("threagile_version"):
 			model.ThreagileVersion, mergeError = new(Strings).MergeSingleton(model.ThreagileVersion, includedModel.ThreagileVersion)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge threagile version: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge threagile version: %w", mergeError), item)
 			}
 
 		case strings.ToLower("title"):
 			model.Title, mergeError = new(Strings).MergeSingleton(model.Title, includedModel.Title)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge title: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge title: %w", mergeError), item)
 			}
 
 		case strings.ToLower("author"):
 			mergeError = model.Author.Merge(includedModel.Author)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge author: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge author: %w", mergeError), item)
 			}
 
 		case strings.ToLower("contributors"):
 			model.Contributors, mergeError = new(Author).MergeList(append(model.Contributors, includedModel.Author))
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge contributors: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge contributors: %w", mergeError), item)
 			}
 
 		case strings.ToLower("date"):
 			model.Date, mergeError = new(Strings).MergeSingleton(model.Date, includedModel.Date)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge date: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge date: %w", mergeError), item)
 			}
 
 		case strings.ToLower("application_description"):
 			mergeError = model.AppDescription.Merge(includedModel.AppDescription)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge application description: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge application description: %w", mergeError), item)
 			}
 
 		case strings.ToLower("business_overview"):
 			mergeError = model.BusinessOverview.Merge(includedModel.BusinessOverview)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge business overview: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge business overview: %w", mergeError), item)
 			}
 
 		case strings.ToLower("technical_overview"):
 			mergeError = model.TechnicalOverview.Merge(includedModel.TechnicalOverview)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge technical overview: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge technical overview: %w", mergeError), item)
 			}
 
 		case strings.ToLower("business_criticality"):
 			model.BusinessCriticality, mergeError = new(Strings).MergeSingleton(model.BusinessCriticality, includedModel.BusinessCriticality)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge business criticality: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge business criticality: %w", mergeError), item)
 			}
 
 		case strings.ToLower("management_summary_comment"):
@@ -177,58 +264,64 @@ func (model *Model) Merge(dir string, includeFilename string) error {
 		case strings.ToLower("security_requirements"):
 			model.SecurityRequirements, mergeError = new(Strings).MergeMap(model.SecurityRequirements, includedModel.SecurityRequirements)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge security requirements: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge security requirements: %w", mergeError), item)
 			}
 
 		case strings.ToLower("questions"):
 			model.Questions, mergeError = new(Strings).MergeMap(model.Questions, includedModel.Questions)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge questions: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge questions: %w", mergeError), item)
 			}
 
 		case strings.ToLower("abuse_cases"):
 			model.AbuseCases, mergeError = new(Strings).MergeMap(model.AbuseCases, includedModel.AbuseCases)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge abuse cases: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge abuse cases: %w", mergeError), item)
 			}
 
 		case strings.ToLower("tags_available"):
 			model.TagsAvailable = new(Strings).MergeUniqueSlice(model.TagsAvailable, includedModel.TagsAvailable)
 
+		case strings.ToLower("technologies"):
+			model.Technologies, mergeError = new(Technology).MergeMap(model.Technologies, includedModel.Technologies)
+			if mergeError != nil {
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge technologies: %w", mergeError), item)
+			}
+
 		case strings.ToLower("data_assets"):
 			model.DataAssets, mergeError = new(DataAsset).MergeMap(model.DataAssets, includedModel.DataAssets)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge data assets: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge data assets: %w", mergeError), item)
 			}
 
 		case strings.ToLower("technical_assets"):
 			model.TechnicalAssets, mergeError = new(TechnicalAsset).MergeMap(model.TechnicalAssets, includedModel.TechnicalAssets)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge technical assets: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge technical assets: %w", mergeError), item)
 			}
 
 		case strings.ToLower("trust_boundaries"):
 			model.TrustBoundaries, mergeError = new(TrustBoundary).MergeMap(model.TrustBoundaries, includedModel.TrustBoundaries)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge trust boundaries: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge trust boundaries: %w", mergeError), item)
 			}
 
 		case strings.ToLower("shared_runtimes"):
 			model.SharedRuntimes, mergeError = new(SharedRuntime).MergeMap(model.SharedRuntimes, includedModel.SharedRuntimes)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge shared runtimes: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge shared runtimes: %w", mergeError), item)
 			}
 
 		case strings.ToLower("individual_risk_categories"):
 			model.IndividualRiskCategories, mergeError = new(IndividualRiskCategory).MergeMap(model.IndividualRiskCategories, includedModel.IndividualRiskCategories)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge risk categories: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge risk categories: %w", mergeError), item)
 			}
 
 		case strings.ToLower("risk_tracking"):
 			model.RiskTracking, mergeError = new(RiskTracking).MergeMap(model.RiskTracking, includedModel.RiskTracking)
 			if mergeError != nil {
-				return fmt.Errorf("failed to merge risk tracking: %v", mergeError)
+				return includedModel.Positions.Error(fmt.Errorf("failed to merge risk tracking: %w", mergeError), item)
 			}
 
 		case "diagram_tweak_nodesep":
@@ -275,3 +368,35 @@ func (model *Model) AddTagToModelInput(tag string, dryRun bool, changes *[]strin
 func NormalizeTag(tag string) string {
 	return strings.TrimSpace(strings.ToLower(tag))
 }
+
+// includeError names the include (or overlay) an error occurred in, unless its position does already
+func includeError(includeFilename string, err error) error {
+	var positionError *PositionError
+	if errors.As(err, &positionError) {
+		return err
+	}
+	return fmt.Errorf("unable to merge model include %q: %w", includeFilename, err)
+}
+
+// yamlError turns an error of the yaml parser into errors with the position of the model file they refer to, as far as
+// the parser reports it (i.e. only the line)
+func yamlError(filename string, message string, err error) error {
+	var typeError *yaml.TypeError
+	if errors.As(err, &typeError) {
+		errs := make([]error, 0)
+		for _, text := range typeError.Errors {
+			errs = append(errs, lineError(filename, message, text))
+		}
+		return errors.Join(errs...)
+	}
+	return lineError(filename, message, strings.TrimPrefix(err.Error(), "yaml: "))
+}
+
+func lineError(filename string, message string, text string) error {
+	var line int
+	if _, scanError := fmt.Sscanf(text, "line %d:", &line); scanError == nil {
+		_, text, _ = strings.Cut(text, ": ")
+		return &PositionError{Position: Position{File: filename, Line: line}, Err: fmt.Errorf("%v: %v", message, text)}
+	}
+	return fmt.Errorf("%v: %v: %v", filename, message, text)
+}
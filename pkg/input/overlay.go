@@ -0,0 +1,270 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// modes of the elements of an overlay file, given by their "overlay" property
+const (
+	OverlayAppend  = "append"  // adds the element, or extends the matching element (default)
+	OverlayReplace = "replace" // replaces the matching element (or adds it)
+	OverlayDelete  = "delete"  // removes the matching element
+)
+
+const overlayModeKey = "overlay"
+
+// Overlay applies an overlay file on top of the model. The elements of the file (anything given as a map, like a
+// technical asset or one of its communication links) are matched with the elements of the model by their key (title)
+// or else by their ID, and depending on their mode
+//   - extend the matching element (append): the values given override the ones of the model, lists are appended to
+//     and the elements contained are matched and applied the same way, unmatched elements are added,
+//   - replace the matching element as a whole (or are added) or
+//   - delete the matching element, which must exist
+//
+// Values given as null are removed from the model.
+func (model *Model) Overlay(dir string, overlayFilename string) error {
+	filename := filepath.Clean(filepath.Join(dir, overlayFilename))
+	modelYaml, readError := os.ReadFile(filename)
+	if readError != nil {
+		return fmt.Errorf("unable to read overlay file: %w", readError)
+	}
+
+	var overlayNode yaml.Node
+	unmarshalError := yaml.Unmarshal(modelYaml, &overlayNode)
+	if unmarshalError != nil {
+		return yamlError(filename, "unable to parse overlay yaml", unmarshalError)
+	}
+	if model.values == nil {
+		model.values = make(map[string]string)
+	}
+	resolveError := ResolveVariables(filename, &overlayNode, model.values)
+	if resolveError != nil {
+		return resolveError
+	}
+	if len(overlayNode.Content) == 0 {
+		return nil // empty file, e.g. for an environment without any changes
+	}
+	overlay := plain(overlayNode.Content[0])
+	if overlay.Kind != yaml.MappingNode {
+		return &PositionError{Position: Position{File: filename, Line: overlay.Line, Column: overlay.Column}, Err: errors.New("overlay is no map")}
+	}
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		if key := overlay.Content[i]; key.Value == "includes" || key.Value == "overlays" {
+			return &PositionError{Position: Position{File: filename, Line: key.Line, Column: key.Column}, Err: fmt.Errorf("%v are not supported in overlay files", key.Value)}
+		}
+	}
+
+	var base yaml.Node
+	encodeError := base.Encode(model)
+	if encodeError != nil {
+		return fmt.Errorf("unable to apply overlay %q: %w", filename, encodeError)
+	}
+	errs := applyOverlay(filename, &base, overlay)
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	var result Model
+	decodeError := base.Decode(&result)
+	if decodeError != nil {
+		return yamlError(filename, "unable to apply overlay", decodeError)
+	}
+
+	// the overlay has the last word, also about where things are defined
+	result.Positions, result.values = model.Positions, model.values
+	if result.Positions == nil {
+		result.Positions = make(Positions)
+	}
+	overlayPositions := make(Positions)
+	overlayPositions.add(filename, overlay, "")
+	for path, position := range overlayPositions {
+		result.Positions[path] = position
+	}
+	*model = result
+	return nil
+}
+
+func applyOverlay(filename string, base *yaml.Node, overlay *yaml.Node) []error {
+	errs := make([]error, 0)
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, value := overlay.Content[i], overlay.Content[i+1]
+		if key.Value == overlayModeKey {
+			continue // applied by the caller
+		}
+		index := mappingIndex(base, key.Value)
+
+		switch value.Kind {
+		case yaml.MappingNode:
+			mode := OverlayAppend
+			if modeNode := mappingValue(value, overlayModeKey); modeNode != nil {
+				mode = modeNode.Value
+			}
+			if index < 0 {
+				if id := mappingValue(value, "id"); id != nil && id.Kind == yaml.ScalarNode {
+					index = elementIndex(base, id.Value)
+				}
+			}
+
+			switch mode {
+			case OverlayDelete:
+				if index < 0 {
+					errs = append(errs, &PositionError{Position: Position{File: filename, Line: key.Line, Column: key.Column}, Err: fmt.Errorf("unable to delete %q: no element with this key or ID", key.Value)})
+					continue
+				}
+				base.Content = append(base.Content[:index], base.Content[index+2:]...)
+
+			case OverlayReplace:
+				setMappingValue(base, index, key, withoutModes(value))
+
+			case OverlayAppend:
+				if index < 0 || base.Content[index+1].Kind != yaml.MappingNode {
+					added := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"} // applied as well, reporting deletes of missing elements
+					setMappingValue(base, index, key, added)
+					errs = append(errs, applyOverlay(filename, added, value)...)
+					continue
+				}
+				base.Content[index].Value = key.Value // elements matched by ID take the title of the overlay
+				errs = append(errs, applyOverlay(filename, base.Content[index+1], value)...)
+
+			default:
+				modeNode := mappingValue(value, overlayModeKey)
+				errs = append(errs, &PositionError{Position: Position{File: filename, Line: modeNode.Line, Column: modeNode.Column}, Err: fmt.Errorf("unknown overlay mode %q, expected one of: %v, %v, %v", mode, OverlayAppend, OverlayReplace, OverlayDelete)})
+			}
+
+		case yaml.SequenceNode:
+			if index < 0 || base.Content[index+1].Kind != yaml.SequenceNode {
+				setMappingValue(base, index, key, value)
+				continue
+			}
+			items := base.Content[index+1]
+			for _, item := range value.Content {
+				if item.Kind != yaml.ScalarNode || !containsScalar(items, item.Value) {
+					items.Content = append(items.Content, item)
+				}
+			}
+
+		default:
+			if value.ShortTag() == "!!null" {
+				if index >= 0 {
+					base.Content = append(base.Content[:index], base.Content[index+2:]...)
+				}
+				continue
+			}
+			setMappingValue(base, index, key, value)
+		}
+	}
+	return errs
+}
+
+// plain returns a copy of a yaml node with all aliases and merge keys resolved
+func plain(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	result := *node
+	result.Anchor = ""
+	result.Content = nil
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		merged := make([]*yaml.Node, 0)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "<<" {
+				value := plain(node.Content[i+1])
+				if value.Kind == yaml.MappingNode {
+					merged = append(merged, value.Content...)
+				} else {
+					for _, item := range value.Content {
+						merged = append(merged, item.Content...)
+					}
+				}
+				continue
+			}
+			result.Content = append(result.Content, plain(node.Content[i]), plain(node.Content[i+1]))
+		}
+		for i := 0; i+1 < len(merged); i += 2 { // the explicit keys win
+			if mappingIndex(&result, merged[i].Value) < 0 {
+				result.Content = append(result.Content, merged[i], merged[i+1])
+			}
+		}
+
+	default:
+		for _, child := range node.Content {
+			result.Content = append(result.Content, plain(child))
+		}
+	}
+	return &result
+}
+
+// withoutModes returns a copy of a yaml node without the overlay modes of its elements
+func withoutModes(node *yaml.Node) *yaml.Node {
+	result := *node
+	result.Content = nil
+	for i := 0; i < len(node.Content); i++ {
+		if node.Kind == yaml.MappingNode && i%2 == 0 && node.Content[i].Value == overlayModeKey {
+			i++
+			continue
+		}
+		result.Content = append(result.Content, withoutModes(node.Content[i]))
+	}
+	return &result
+}
+
+// mappingValue returns the value of a key of a yaml mapping, nil if there is no such key
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if index := mappingIndex(node, key); index >= 0 {
+		return node.Content[index+1]
+	}
+	return nil
+}
+
+// mappingIndex returns the index of a key of a yaml mapping within its content, -1 if there is no such key
+func mappingIndex(node *yaml.Node, key string) int {
+	if node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// elementIndex returns the index of the key of the element with the given ID within the content of a yaml mapping, -1
+// if there is no such element
+func elementIndex(node *yaml.Node, id string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if value := mappingValue(node.Content[i+1], "id"); value != nil && value.Kind == yaml.ScalarNode && value.Value == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// setMappingValue replaces the key and value at the given index of a yaml mapping, or adds them if the index is -1
+func setMappingValue(node *yaml.Node, index int, key *yaml.Node, value *yaml.Node) {
+	if index < 0 {
+		node.Content = append(node.Content, key, value)
+		return
+	}
+	node.Content[index], node.Content[index+1] = key, value
+}
+
+func containsScalar(node *yaml.Node, value string) bool {
+	for _, item := range node.Content {
+		if item.Kind == yaml.ScalarNode && item.Value == value {
+			return true
+		}
+	}
+	return false
+}
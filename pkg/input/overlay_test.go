@@ -0,0 +1,109 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadWithVariablesAppliesOverlays(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.yaml", `
+title: Some Model (${env})
+variables:
+  env: dev
+overlays:
+  - ${env}.yaml
+technical_assets:
+  Web Server:
+    id: web-server
+    redundant: false
+    tags:
+      - linux
+    communication_links:
+      Database Access:
+        target: database
+        vpn: false
+  Database:
+    id: database
+    description: Database with $${placeholders}
+trust_boundaries:
+  Dev Network:
+    id: dev-network
+`)
+	writeFile(t, dir, "dev.yaml", ``)
+	writeFile(t, dir, "prod.yaml", `
+technical_assets:
+  Web Server:
+    redundant: ${redundant}
+    tags:
+      - waf
+    communication_links:
+      Database Access:
+        vpn: true
+  Renamed Database:
+    id: database
+    overlay: replace
+    description: Managed database
+  Load Balancer:
+    id: load-balancer
+trust_boundaries:
+  dev-network:
+    id: dev-network
+    overlay: delete
+`)
+
+	dev := new(Model).Defaults()
+	assert.NoError(t, dev.LoadWithVariables(filepath.Join(dir, "main.yaml"), nil))
+	assert.Equal(t, "Some Model (dev)", dev.Title)
+	assert.Equal(t, "Database with ${placeholders}", dev.TechnicalAssets["Database"].Description)
+	assert.False(t, dev.TechnicalAssets["Web Server"].Redundant)
+	assert.Len(t, dev.TrustBoundaries, 1)
+
+	prod := new(Model).Defaults()
+	assert.NoError(t, prod.LoadWithVariables(filepath.Join(dir, "main.yaml"), map[string]string{"env": "prod", "redundant": "true"}))
+	assert.Equal(t, "Some Model (prod)", prod.Title)
+	webServer := prod.TechnicalAssets["Web Server"]
+	assert.True(t, webServer.Redundant)
+	assert.Equal(t, []string{"linux", "waf"}, webServer.Tags)
+	assert.True(t, webServer.CommunicationLinks["Database Access"].VPN)
+	assert.Equal(t, "database", webServer.CommunicationLinks["Database Access"].Target)
+	assert.Equal(t, TechnicalAsset{ID: "database", Description: "Managed database"}, prod.TechnicalAssets["Renamed Database"])
+	assert.NotContains(t, prod.TechnicalAssets, "Database")
+	assert.Contains(t, prod.TechnicalAssets, "Load Balancer")
+	assert.Empty(t, prod.TrustBoundaries)
+	position, found := prod.Positions.Find("technical_assets", "Web Server", "redundant")
+	assert.True(t, found)
+	assert.Equal(t, filepath.Join(dir, "prod.yaml")+":4:16", position.String())
+}
+
+func TestLoadWithVariablesReportsErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.yaml", `
+title: ${title}
+overlays:
+  - overlay.yaml
+`)
+	writeFile(t, dir, "overlay.yaml", `
+technical_assets:
+  Missing:
+    id: missing
+    overlay: delete
+`)
+
+	err := new(Model).Defaults().LoadWithVariables(filepath.Join(dir, "main.yaml"), nil)
+	assert.EqualError(t, err, filepath.Join(dir, "main.yaml")+`:2:8: undefined variable "title"`)
+
+	err = new(Model).Defaults().LoadWithVariables(filepath.Join(dir, "main.yaml"), map[string]string{"title": "Some Model"})
+	assert.EqualError(t, err, filepath.Join(dir, "overlay.yaml")+`:3:3: unable to delete "Missing": no element with this key or ID`)
+}
+
+func writeFile(t *testing.T, dir string, name string, content string) {
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+}
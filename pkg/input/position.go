@@ -0,0 +1,211 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is the location of a value in a model file (or one of its includes)
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (what Position) String() string {
+	if what.Column == 0 { // unknown, e.g. for yaml syntax errors
+		return fmt.Sprintf("%v:%d", what.File, what.Line)
+	}
+	return fmt.Sprintf("%v:%d:%d", what.File, what.Line, what.Column)
+}
+
+// PositionError is an error about a value of a model file at a known position
+type PositionError struct {
+	Position Position
+	Err      error
+}
+
+func (what *PositionError) Error() string {
+	return what.Position.String() + ": " + what.Err.Error()
+}
+
+func (what *PositionError) Unwrap() error {
+	return what.Err
+}
+
+// Positions maps the paths of the values of a loaded model to where they are defined, a path consists of the yaml keys
+// leading to a value, like "technical_assets.Some Server.technology", with sequence items given by their value if it is
+// a scalar, like "technical_assets.Some Server.data_assets_stored[some-data]", or else by their index
+type Positions map[string]Position
+
+// Path joins the keys (and item values in brackets) leading to a value of a model
+func Path(keys ...string) string {
+	builder := new(strings.Builder)
+	for _, key := range keys {
+		if builder.Len() > 0 && !strings.HasPrefix(key, "[") {
+			builder.WriteString(".")
+		}
+		builder.WriteString(key)
+	}
+	return builder.String()
+}
+
+// Item returns the path element of a sequence item
+func Item(value string) string {
+	return "[" + value + "]"
+}
+
+// Find returns the position of the value at the given path, falling back to the closest enclosing value that has one
+func (what Positions) Find(keys ...string) (Position, bool) {
+	for n := len(keys); n > 0; n-- {
+		if position, found := what[Path(keys[:n]...)]; found {
+			return position, true
+		}
+	}
+	return Position{}, false
+}
+
+// Error adds the position of the value at the given path to an error, if it is known
+func (what Positions) Error(err error, keys ...string) error {
+	if err == nil {
+		return nil
+	}
+	var positionError *PositionError
+	if errors.As(err, &positionError) {
+		return err
+	}
+	position, found := what.Find(keys...)
+	if !found {
+		return err
+	}
+	return &PositionError{Position: position, Err: err}
+}
+
+// AddRenamed records the positions of the value at a path of other positions and of all values within it under a new
+// path, values already known keep their position
+func (what Positions) AddRenamed(other Positions, path string, newPath string) {
+	for otherPath, position := range other {
+		if otherPath != path && !strings.HasPrefix(otherPath, path+".") && !strings.HasPrefix(otherPath, path+"[") {
+			continue
+		}
+		renamedPath := newPath + strings.TrimPrefix(otherPath, path)
+		if _, found := what[renamedPath]; !found {
+			what[renamedPath] = position
+		}
+	}
+}
+
+// add records the positions of a yaml node and all its children, values already known (i.e. defined by an earlier file)
+// keep their position
+func (what Positions) add(filename string, node *yaml.Node, path string) {
+	for node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if len(path) > 0 {
+		if _, found := what[path]; !found {
+			what[path] = Position{File: filename, Line: node.Line, Column: node.Column}
+		}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" { // yaml merge key, the merged values count as defined here
+				what.addMerged(filename, value, path)
+				continue
+			}
+			what.add(filename, value, Path(path, key.Value))
+		}
+
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			if item.Kind == yaml.ScalarNode {
+				what.add(filename, item, Path(path, Item(item.Value)))
+			} else {
+				what.add(filename, item, Path(path, Item(strconv.Itoa(i))))
+			}
+		}
+	}
+}
+
+func (what Positions) addMerged(filename string, node *yaml.Node, path string) {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "<<" {
+				what.addMerged(filename, node.Content[i+1], path)
+			} else {
+				what.add(filename, node.Content[i+1], Path(path, node.Content[i].Value))
+			}
+		}
+
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			what.addMerged(filename, item, path)
+		}
+	}
+}
+
+// SortErrors orders errors by their position, as errors are usually found while iterating over maps, errors without a
+// position keep their order and go last
+func SortErrors(errs []error) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		var a, b *PositionError
+		aFound, bFound := errors.As(errs[i], &a), errors.As(errs[j], &b)
+		switch {
+		case aFound && bFound:
+			if a.Position.File != b.Position.File {
+				return a.Position.File < b.Position.File
+			}
+			if a.Position.Line != b.Position.Line {
+				return a.Position.Line < b.Position.Line
+			}
+			if a.Position.Column != b.Position.Column {
+				return a.Position.Column < b.Position.Column
+			}
+			return a.Err.Error() < b.Err.Error()
+
+		case aFound != bFound:
+			return aFound
+		}
+		return false
+	})
+}
+
+// SplitErrors returns the errors joined into an error (like the ones returned by Model.Load), flattened, the joined
+// errors may also be wrapped by the error
+func SplitErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		if wrapped := errors.Unwrap(err); wrapped != nil {
+			if errs := SplitErrors(wrapped); len(errs) > 1 {
+				return errs
+			}
+		}
+		return []error{err}
+	}
+	result := make([]error, 0)
+	for _, e := range joined.Unwrap() {
+		result = append(result, SplitErrors(e)...)
+	}
+	return result
+}
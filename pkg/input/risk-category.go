@@ -5,22 +5,23 @@ import (
 )
 
 type IndividualRiskCategory struct {
-	ID                         string                    `yaml:"id,omitempty" json:"id,omitempty"`
-	Description                string                    `yaml:"description,omitempty" json:"description,omitempty"`
-	Impact                     string                    `yaml:"impact,omitempty" json:"impact,omitempty"`
-	ASVS                       string                    `yaml:"asvs,omitempty" json:"asvs,omitempty"`
-	CheatSheet                 string                    `yaml:"cheat_sheet,omitempty" json:"cheat_sheet,omitempty"`
-	Action                     string                    `yaml:"action,omitempty" json:"action,omitempty"`
-	Mitigation                 string                    `yaml:"mitigation,omitempty" json:"mitigation,omitempty"`
-	Check                      string                    `yaml:"check,omitempty" json:"check,omitempty"`
-	Function                   string                    `yaml:"function,omitempty" json:"function,omitempty"`
-	STRIDE                     string                    `yaml:"stride,omitempty" json:"stride,omitempty"`
-	DetectionLogic             string                    `yaml:"detection_logic,omitempty" json:"detection_logic,omitempty"`
-	RiskAssessment             string                    `yaml:"risk_assessment,omitempty" json:"risk_assessment,omitempty"`
-	FalsePositives             string                    `yaml:"false_positives,omitempty" json:"false_positives,omitempty"`
-	ModelFailurePossibleReason bool                      `yaml:"model_failure_possible_reason,omitempty" json:"model_failure_possible_reason,omitempty"`
-	CWE                        int                       `yaml:"cwe,omitempty" json:"cwe,omitempty"`
-	RisksIdentified            map[string]RiskIdentified `yaml:"risks_identified,omitempty" json:"risks_identified,omitempty"`
+	ID                         string                    `yaml:"id,omitempty" json:"id,omitempty" description:"ID" schema:"required"`
+	Description                string                    `yaml:"description,omitempty" json:"description,omitempty" description:"Description" schema:"required,nullable"`
+	Impact                     string                    `yaml:"impact,omitempty" json:"impact,omitempty" description:"Impact" schema:"required"`
+	ASVS                       string                    `yaml:"asvs,omitempty" json:"asvs,omitempty" description:"ASVS" schema:"required"`
+	CheatSheet                 string                    `yaml:"cheat_sheet,omitempty" json:"cheat_sheet,omitempty" description:"Cheat sheet" schema:"required"`
+	Action                     string                    `yaml:"action,omitempty" json:"action,omitempty" description:"Action" schema:"required"`
+	Mitigation                 string                    `yaml:"mitigation,omitempty" json:"mitigation,omitempty" description:"Mitigation" schema:"required"`
+	Check                      string                    `yaml:"check,omitempty" json:"check,omitempty" description:"Check" schema:"required"`
+	Function                   string                    `yaml:"function,omitempty" json:"function,omitempty" description:"Function" schema:"required,enum=risk-function"`
+	STRIDE                     string                    `yaml:"stride,omitempty" json:"stride,omitempty" description:"STRIDE" schema:"required,enum=stride"`
+	DetectionLogic             string                    `yaml:"detection_logic,omitempty" json:"detection_logic,omitempty" description:"Detection logic" schema:"required"`
+	RiskAssessment             string                    `yaml:"risk_assessment,omitempty" json:"risk_assessment,omitempty" description:"Risk assessment" schema:"required"`
+	FalsePositives             string                    `yaml:"false_positives,omitempty" json:"false_positives,omitempty" description:"False positives" schema:"required"`
+	ModelFailurePossibleReason bool                      `yaml:"model_failure_possible_reason,omitempty" json:"model_failure_possible_reason,omitempty" description:"Model failure possible reason" schema:"required"`
+	CWE                        int                       `yaml:"cwe,omitempty" json:"cwe,omitempty" description:"CWE" schema:"required"`
+	RisksIdentified            map[string]RiskIdentified `yaml:"risks_identified,omitempty" json:"risks_identified,omitempty" description:"Risks identified" schema:"required"`
+	Overlay                    string                    `yaml:"overlay,omitempty" json:"overlay,omitempty" description:"How the element of an overlay file is applied to the matching element of the model" schema:"enum=overlay"`
 }
 
 func (what *IndividualRiskCategory) Merge(other IndividualRiskCategory) error {
@@ -3,11 +3,15 @@ package input
 import "fmt"
 
 type RiskTracking struct {
-	Status        string `yaml:"status,omitempty" json:"status,omitempty"`
-	Justification string `yaml:"justification,omitempty" json:"justification,omitempty"`
-	Ticket        string `yaml:"ticket,omitempty" json:"ticket,omitempty"`
-	Date          string `yaml:"date,omitempty" json:"date,omitempty"`
-	CheckedBy     string `yaml:"checked_by,omitempty" json:"checked_by,omitempty"`
+	Status        string `yaml:"status,omitempty" json:"status,omitempty" description:"Status" schema:"required,enum=risk-status"`
+	Justification string `yaml:"justification,omitempty" json:"justification,omitempty" description:"Justification" schema:"required,nullable"`
+	Ticket        string `yaml:"ticket,omitempty" json:"ticket,omitempty" description:"Ticket" schema:"required,nullable"`
+	Date          string `yaml:"date,omitempty" json:"date,omitempty" description:"Date" schema:"required,nullable,format=date"`
+	CheckedBy     string `yaml:"checked_by,omitempty" json:"checked_by,omitempty" description:"Checked by" schema:"required,nullable"`
+	Owner         string `yaml:"owner,omitempty" json:"owner,omitempty" description:"Owner responsible for the next review"`
+	Expires       string `yaml:"expires,omitempty" json:"expires,omitempty" description:"Date after which an accepted risk is unchecked again" schema:"format=date"`
+	ReviewBy      string `yaml:"review_by,omitempty" json:"review_by,omitempty" description:"Date by which the risk tracking should be reviewed" schema:"format=date"`
+	Overlay       string `yaml:"overlay,omitempty" json:"overlay,omitempty" description:"How the element of an overlay file is applied to the matching element of the model" schema:"enum=overlay"`
 }
 
 func (what *RiskTracking) Merge(other RiskTracking) error {
@@ -37,6 +41,21 @@ func (what *RiskTracking) Merge(other RiskTracking) error {
 		return fmt.Errorf("failed to merge checked_by: %v", mergeError)
 	}
 
+	what.Owner, mergeError = new(Strings).MergeSingleton(what.Owner, other.Owner)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge owner: %v", mergeError)
+	}
+
+	what.Expires, mergeError = new(Strings).MergeSingleton(what.Expires, other.Expires)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge expires: %v", mergeError)
+	}
+
+	what.ReviewBy, mergeError = new(Strings).MergeSingleton(what.ReviewBy, other.ReviewBy)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge review_by: %v", mergeError)
+	}
+
 	return nil
 }
 
@@ -3,16 +3,17 @@ package input
 import "fmt"
 
 type RiskIdentified struct {
-	Severity                      string   `yaml:"severity,omitempty" json:"severity,omitempty"`
-	ExploitationLikelihood        string   `yaml:"exploitation_likelihood,omitempty" json:"exploitation_likelihood,omitempty"`
-	ExploitationImpact            string   `yaml:"exploitation_impact,omitempty" json:"exploitation_impact,omitempty"`
-	DataBreachProbability         string   `yaml:"data_breach_probability,omitempty" json:"data_breach_probability,omitempty"`
-	DataBreachTechnicalAssets     []string `yaml:"data_breach_technical_assets,omitempty" json:"data_breach_technical_assets,omitempty"`
-	MostRelevantDataAsset         string   `yaml:"most_relevant_data_asset,omitempty" json:"most_relevant_data_asset,omitempty"`
-	MostRelevantTechnicalAsset    string   `yaml:"most_relevant_technical_asset,omitempty" json:"most_relevant_technical_asset,omitempty"`
-	MostRelevantCommunicationLink string   `yaml:"most_relevant_communication_link,omitempty" json:"most_relevant_communication_link,omitempty"`
-	MostRelevantTrustBoundary     string   `yaml:"most_relevant_trust_boundary,omitempty" json:"most_relevant_trust_boundary,omitempty"`
-	MostRelevantSharedRuntime     string   `yaml:"most_relevant_shared_runtime,omitempty" json:"most_relevant_shared_runtime,omitempty"`
+	Severity                      string   `yaml:"severity,omitempty" json:"severity,omitempty" description:"Severity" schema:"enum=risk-severity"`
+	ExploitationLikelihood        string   `yaml:"exploitation_likelihood,omitempty" json:"exploitation_likelihood,omitempty" description:"Exploitation likelihood" schema:"enum=risk-exploitation-likelihood"`
+	ExploitationImpact            string   `yaml:"exploitation_impact,omitempty" json:"exploitation_impact,omitempty" description:"Exploitation impact" schema:"enum=risk-exploitation-impact"`
+	DataBreachProbability         string   `yaml:"data_breach_probability,omitempty" json:"data_breach_probability,omitempty" description:"Data breach probability" schema:"enum=data-breach-probability"`
+	DataBreachTechnicalAssets     []string `yaml:"data_breach_technical_assets,omitempty" json:"data_breach_technical_assets,omitempty" description:"Data breach technical assets"`
+	MostRelevantDataAsset         string   `yaml:"most_relevant_data_asset,omitempty" json:"most_relevant_data_asset,omitempty" description:"Most relevant data asset"`
+	MostRelevantTechnicalAsset    string   `yaml:"most_relevant_technical_asset,omitempty" json:"most_relevant_technical_asset,omitempty" description:"Most relevant technical asset"`
+	MostRelevantCommunicationLink string   `yaml:"most_relevant_communication_link,omitempty" json:"most_relevant_communication_link,omitempty" description:"Most relevant communication link"`
+	MostRelevantTrustBoundary     string   `yaml:"most_relevant_trust_boundary,omitempty" json:"most_relevant_trust_boundary,omitempty" description:"Most relevant trust boundary"`
+	MostRelevantSharedRuntime     string   `yaml:"most_relevant_shared_runtime,omitempty" json:"most_relevant_shared_runtime,omitempty" description:"Most relevant shared runtime"`
+	Overlay                       string   `yaml:"overlay,omitempty" json:"overlay,omitempty" description:"How the element of an overlay file is applied to the matching element of the model" schema:"enum=overlay"`
 }
 
 func (what *RiskIdentified) Merge(other RiskIdentified) error {
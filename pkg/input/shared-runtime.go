@@ -3,10 +3,11 @@ package input
 import "fmt"
 
 type SharedRuntime struct {
-	ID                     string   `yaml:"id,omitempty" json:"id,omitempty"`
-	Description            string   `yaml:"description,omitempty" json:"description,omitempty"`
-	Tags                   []string `yaml:"tags,omitempty" json:"tag,omitempty"`
-	TechnicalAssetsRunning []string `yaml:"technical_assets_running,omitempty" json:"technical_assets_running,omitempty"`
+	ID                     string   `yaml:"id,omitempty" json:"id,omitempty" description:"ID" schema:"required"`
+	Description            string   `yaml:"description,omitempty" json:"description,omitempty" description:"Description" schema:"required,nullable"`
+	Tags                   []string `yaml:"tags,omitempty" json:"tag,omitempty" description:"Tags"`
+	TechnicalAssetsRunning []string `yaml:"technical_assets_running,omitempty" json:"technical_assets_running,omitempty" description:"Technical assets running" schema:"required,nullable"`
+	Overlay                string   `yaml:"overlay,omitempty" json:"overlay,omitempty" description:"How the element of an overlay file is applied to the matching element of the model" schema:"enum=overlay"`
 }
 
 func (what *SharedRuntime) Merge(other SharedRuntime) error {
@@ -3,32 +3,33 @@ package input
 import "fmt"
 
 type TechnicalAsset struct {
-	ID                      string                       `yaml:"id,omitempty" json:"id,omitempty"`
-	Description             string                       `yaml:"description,omitempty" json:"description,omitempty"`
-	Type                    string                       `yaml:"type,omitempty" json:"type,omitempty"`
-	Usage                   string                       `yaml:"usage,omitempty" json:"usage,omitempty"`
-	UsedAsClientByHuman     bool                         `yaml:"used_as_client_by_human,omitempty" json:"used_as_client_by_human,omitempty"`
-	OutOfScope              bool                         `yaml:"out_of_scope,omitempty" json:"out_of_scope,omitempty"`
-	JustificationOutOfScope string                       `yaml:"justification_out_of_scope,omitempty" json:"justification_out_of_scope,omitempty"`
-	Size                    string                       `yaml:"size,omitempty" json:"size,omitempty"`
-	Technology              string                       `yaml:"technology,omitempty" json:"technology,omitempty"`
-	Tags                    []string                     `yaml:"tags,omitempty" json:"tags,omitempty"`
-	Internet                bool                         `yaml:"internet,omitempty" json:"internet,omitempty"`
-	Machine                 string                       `yaml:"machine,omitempty" json:"machine,omitempty"`
-	Encryption              string                       `yaml:"encryption,omitempty" json:"encryption,omitempty"`
-	Owner                   string                       `yaml:"owner,omitempty" json:"owner,omitempty"`
-	Confidentiality         string                       `yaml:"confidentiality,omitempty" json:"confidentiality,omitempty"`
-	Integrity               string                       `yaml:"integrity,omitempty" json:"integrity,omitempty"`
-	Availability            string                       `yaml:"availability,omitempty" json:"availability,omitempty"`
-	JustificationCiaRating  string                       `yaml:"justification_cia_rating,omitempty" json:"justification_cia_rating,omitempty"`
-	MultiTenant             bool                         `yaml:"multi_tenant,omitempty" json:"multi_tenant,omitempty"`
-	Redundant               bool                         `yaml:"redundant,omitempty" json:"redundant,omitempty"`
-	CustomDevelopedParts    bool                         `yaml:"custom_developed_parts,omitempty" json:"custom_developed_parts,omitempty"`
-	DataAssetsProcessed     []string                     `yaml:"data_assets_processed,omitempty" json:"data_assets_processed,omitempty"`
-	DataAssetsStored        []string                     `yaml:"data_assets_stored,omitempty" json:"data_assets_stored,omitempty"`
-	DataFormatsAccepted     []string                     `yaml:"data_formats_accepted,omitempty" json:"data_formats_accepted,omitempty"`
-	DiagramTweakOrder       int                          `yaml:"diagram_tweak_order,omitempty" json:"diagram_tweak_order,omitempty"`
-	CommunicationLinks      map[string]CommunicationLink `yaml:"communication_links,omitempty" json:"communication_links,omitempty"`
+	ID                      string                       `yaml:"id,omitempty" json:"id,omitempty" description:"ID" schema:"required"`
+	Description             string                       `yaml:"description,omitempty" json:"description,omitempty" description:"Description" schema:"required,nullable"`
+	Type                    string                       `yaml:"type,omitempty" json:"type,omitempty" description:"Type" schema:"required,enum=technical-asset-type"`
+	Usage                   string                       `yaml:"usage,omitempty" json:"usage,omitempty" description:"Usage" schema:"required,enum=usage"`
+	UsedAsClientByHuman     bool                         `yaml:"used_as_client_by_human,omitempty" json:"used_as_client_by_human,omitempty" description:"Used as client by human" schema:"required"`
+	OutOfScope              bool                         `yaml:"out_of_scope,omitempty" json:"out_of_scope,omitempty" description:"Out of scope" schema:"required"`
+	JustificationOutOfScope string                       `yaml:"justification_out_of_scope,omitempty" json:"justification_out_of_scope,omitempty" description:"Justification of out of scope"`
+	Size                    string                       `yaml:"size,omitempty" json:"size,omitempty" description:"Size" schema:"required,enum=technical-asset-size"`
+	Technology              string                       `yaml:"technology,omitempty" json:"technology,omitempty" description:"Technology" schema:"required,enum=technical-asset-technology,extensible"`
+	Tags                    []string                     `yaml:"tags,omitempty" json:"tags,omitempty" description:"Tags"`
+	Internet                bool                         `yaml:"internet,omitempty" json:"internet,omitempty" description:"Internet" schema:"required"`
+	Machine                 string                       `yaml:"machine,omitempty" json:"machine,omitempty" description:"Machine" schema:"required,enum=technical-asset-machine"`
+	Encryption              string                       `yaml:"encryption,omitempty" json:"encryption,omitempty" description:"Encryption" schema:"required,enum=encryption-style"`
+	Owner                   string                       `yaml:"owner,omitempty" json:"owner,omitempty" description:"Owner" schema:"required,nullable"`
+	Confidentiality         string                       `yaml:"confidentiality,omitempty" json:"confidentiality,omitempty" description:"Confidentiality" schema:"required,enum=confidentiality"`
+	Integrity               string                       `yaml:"integrity,omitempty" json:"integrity,omitempty" description:"Integrity" schema:"required,enum=criticality"`
+	Availability            string                       `yaml:"availability,omitempty" json:"availability,omitempty" description:"Availability" schema:"required,enum=criticality"`
+	JustificationCiaRating  string                       `yaml:"justification_cia_rating,omitempty" json:"justification_cia_rating,omitempty" description:"Justification of the rating"`
+	MultiTenant             bool                         `yaml:"multi_tenant,omitempty" json:"multi_tenant,omitempty" description:"Multi tenant" schema:"required"`
+	Redundant               bool                         `yaml:"redundant,omitempty" json:"redundant,omitempty" description:"Redundant" schema:"required"`
+	CustomDevelopedParts    bool                         `yaml:"custom_developed_parts,omitempty" json:"custom_developed_parts,omitempty" description:"Custom developed parts" schema:"required"`
+	DataAssetsProcessed     []string                     `yaml:"data_assets_processed,omitempty" json:"data_assets_processed,omitempty" description:"Data assets processed; all data assets stored or sent or received via a communication link (be it as a source or a target) are implicitly also processed and do not need to be listed here" schema:"required,nullable"`
+	DataAssetsStored        []string                     `yaml:"data_assets_stored,omitempty" json:"data_assets_stored,omitempty" description:"Data assets stored" schema:"required,nullable"`
+	DataFormatsAccepted     []string                     `yaml:"data_formats_accepted,omitempty" json:"data_formats_accepted,omitempty" description:"Data formats accepted" schema:"required,nullable,enum=data-format"`
+	DiagramTweakOrder       int                          `yaml:"diagram_tweak_order,omitempty" json:"diagram_tweak_order,omitempty" description:"Diagram tweak order (affects left to right positioning)"`
+	CommunicationLinks      map[string]CommunicationLink `yaml:"communication_links,omitempty" json:"communication_links,omitempty" description:"Communication links" schema:"required,nullable"`
+	Overlay                 string                       `yaml:"overlay,omitempty" json:"overlay,omitempty" description:"How the element of an overlay file is applied to the matching element of the model" schema:"enum=overlay"`
 }
 
 func (what *TechnicalAsset) Merge(other TechnicalAsset) error {
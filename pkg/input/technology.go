@@ -0,0 +1,53 @@
+package input
+
+import "fmt"
+
+type Technology struct {
+	Description string          `yaml:"description,omitempty" json:"description,omitempty" description:"Description"`
+	Parent      string          `yaml:"parent,omitempty" json:"parent,omitempty" description:"Technology (built-in or declared) this technology is a kind of, inheriting its traits" schema:"required"`
+	Traits      map[string]bool `yaml:"traits,omitempty" json:"traits,omitempty" description:"Traits of the technology the risk rules rely on (see list-types), overriding the ones of the parent technology"`
+	Overlay     string          `yaml:"overlay,omitempty" json:"overlay,omitempty" description:"How the element of an overlay file is applied to the matching element of the model" schema:"enum=overlay"`
+}
+
+func (what *Technology) Merge(other Technology) error {
+	var mergeError error
+	what.Description, mergeError = new(Strings).MergeSingleton(what.Description, other.Description)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge description: %v", mergeError)
+	}
+
+	what.Parent, mergeError = new(Strings).MergeSingleton(what.Parent, other.Parent)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge parent: %v", mergeError)
+	}
+
+	for trait, value := range other.Traits {
+		if current, found := what.Traits[trait]; found && current != value {
+			return fmt.Errorf("failed to merge trait %q: conflicting values", trait)
+		}
+		if what.Traits == nil {
+			what.Traits = make(map[string]bool)
+		}
+		what.Traits[trait] = value
+	}
+
+	return nil
+}
+
+func (what *Technology) MergeMap(first map[string]Technology, second map[string]Technology) (map[string]Technology, error) {
+	for mapKey, mapValue := range second {
+		mapItem, ok := first[mapKey]
+		if ok {
+			mergeError := mapItem.Merge(mapValue)
+			if mergeError != nil {
+				return first, fmt.Errorf("failed to merge technology %q: %v", mapKey, mergeError)
+			}
+
+			first[mapKey] = mapItem
+		} else {
+			first[mapKey] = mapValue
+		}
+	}
+
+	return first, nil
+}
@@ -3,12 +3,13 @@ package input
 import "fmt"
 
 type TrustBoundary struct {
-	ID                    string   `yaml:"id,omitempty" json:"id,omitempty"`
-	Description           string   `yaml:"description,omitempty" json:"description,omitempty"`
-	Type                  string   `yaml:"type,omitempty" json:"type,omitempty"`
-	Tags                  []string `yaml:"tags,omitempty" json:"tags,omitempty"`
-	TechnicalAssetsInside []string `yaml:"technical_assets_inside,omitempty" json:"technical_assets_inside,omitempty"`
-	TrustBoundariesNested []string `yaml:"trust_boundaries_nested,omitempty" json:"trust_boundaries_nested,omitempty"`
+	ID                    string   `yaml:"id,omitempty" json:"id,omitempty" description:"ID" schema:"required"`
+	Description           string   `yaml:"description,omitempty" json:"description,omitempty" description:"Description" schema:"required,nullable"`
+	Type                  string   `yaml:"type,omitempty" json:"type,omitempty" description:"Type" schema:"required,enum=trust-boundary-type"`
+	Tags                  []string `yaml:"tags,omitempty" json:"tags,omitempty" description:"Tags"`
+	TechnicalAssetsInside []string `yaml:"technical_assets_inside,omitempty" json:"technical_assets_inside,omitempty" description:"Technical assets inside" schema:"required,nullable"`
+	TrustBoundariesNested []string `yaml:"trust_boundaries_nested,omitempty" json:"trust_boundaries_nested,omitempty" description:"Trust boundaries nested" schema:"required,nullable"`
+	Overlay               string   `yaml:"overlay,omitempty" json:"overlay,omitempty" description:"How the element of an overlay file is applied to the matching element of the model" schema:"enum=overlay"`
 }
 
 func (what *TrustBoundary) Merge(other TrustBoundary) error {
@@ -0,0 +1,107 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// variablePattern matches a variable reference like ${env}, as well as the escaped form $${env} standing for ${env}
+var variablePattern = regexp.MustCompile(`\$?\$\{([^}]*)}`)
+
+// ResolveVariables replaces the variable references (like ${env}) in all keys and values of a parsed model file by
+// their values, the defaults declared in the "variables" section of the file are added to the values unless they are
+// already given (e.g. by --set or --values or by an earlier model file)
+func ResolveVariables(filename string, node *yaml.Node, values map[string]string) error {
+	for node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	errs := make([]error, 0)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "variables" {
+			continue
+		}
+		declarations := node.Content[i+1]
+		if declarations.Kind != yaml.MappingNode {
+			continue // reported by the schema validation, or when decoding the model
+		}
+		for j := 0; j+1 < len(declarations.Content); j += 2 {
+			name, value := declarations.Content[j].Value, declarations.Content[j+1]
+			if _, given := values[name]; !given && value.Kind == yaml.ScalarNode {
+				values[name] = value.Value
+			}
+		}
+	}
+
+	var resolve func(node *yaml.Node, topLevel bool)
+	resolve = func(node *yaml.Node, topLevel bool) {
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if topLevel && node.Content[i].Value == "variables" {
+					continue
+				}
+				resolve(node.Content[i], false)
+				resolve(node.Content[i+1], false)
+			}
+
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				resolve(item, false)
+			}
+
+		case yaml.ScalarNode:
+			if !strings.Contains(node.Value, "${") {
+				return
+			}
+			node.Value = variablePattern.ReplaceAllStringFunc(node.Value, func(reference string) string {
+				if strings.HasPrefix(reference, "$$") {
+					return reference[1:]
+				}
+				name := strings.TrimSpace(reference[2 : len(reference)-1])
+				value, found := values[name]
+				if !found {
+					errs = append(errs, &PositionError{
+						Position: Position{File: filename, Line: node.Line, Column: node.Column},
+						Err:      fmt.Errorf("undefined variable %q", name),
+					})
+				}
+				return value
+			})
+			if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+				node.Tag = "" // a plain value is typed by its content, like "redundant: ${redundant}" becoming a boolean
+			}
+		}
+	}
+	resolve(node, true)
+
+	return errors.Join(errs...)
+}
+
+// LoadValues reads the values of the variables of one variant of a model (e.g. an environment) from a yaml file
+func LoadValues(filename string) (map[string]string, error) {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, fmt.Errorf("unable to read values file: %w", readError)
+	}
+
+	values := make(map[string]string)
+	unmarshalError := yaml.Unmarshal(data, &values)
+	if unmarshalError != nil {
+		return nil, yamlError(filename, "unable to parse values yaml", unmarshalError)
+	}
+	return values, nil
+}
@@ -0,0 +1,79 @@
+package macros
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Answers maps question IDs of a model macro to the answers given in advance, which allows executing macros
+// non-interactively (e.g. in automation); multi-select questions take several answers
+type Answers map[string][]string
+
+// LoadAnswers reads answers from a YAML (or JSON) file mapping question IDs to a single value or a list of values
+func LoadAnswers(filename string) (Answers, error) {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, fmt.Errorf("unable to read answers file: %w", readError)
+	}
+
+	values := make(map[string]any)
+	parseError := yaml.Unmarshal(data, &values)
+	if parseError != nil {
+		return nil, fmt.Errorf("unable to parse answers file %v: %w", filename, parseError)
+	}
+
+	answers := make(Answers)
+	for id, value := range values {
+		switch typedValue := value.(type) {
+		case nil:
+			answers[id] = []string{}
+
+		case []any:
+			answers[id] = make([]string, 0)
+			for _, item := range typedValue {
+				switch item.(type) {
+				case []any, map[string]any:
+					return nil, fmt.Errorf("invalid answer to question %q in %v: lists must only contain values", id, filename)
+				}
+				answers[id] = append(answers[id], fmt.Sprint(item))
+			}
+
+		case map[string]any:
+			return nil, fmt.Errorf("invalid answer to question %q in %v: expected a value or a list of values", id, filename)
+
+		default:
+			answers[id] = []string{fmt.Sprint(typedValue)}
+		}
+	}
+	return answers, nil
+}
+
+// ParseAnswers parses answers given as id=value, repeating an id adds further values (e.g. for multi-select questions)
+func ParseAnswers(values []string) (Answers, error) {
+	answers := make(Answers)
+	for _, value := range values {
+		id, answer, found := strings.Cut(value, "=")
+		id = strings.TrimSpace(id)
+		if !found || len(id) == 0 {
+			return nil, fmt.Errorf("invalid answer %q: expected id=value", value)
+		}
+		answers[id] = append(answers[id], strings.TrimSpace(answer))
+	}
+	return answers, nil
+}
+
+// Merge returns the answers with those of other taking precedence per question
+func (what Answers) Merge(other Answers) Answers {
+	merged := make(Answers)
+	for id, answer := range what {
+		merged[id] = answer
+	}
+	for id, answer := range other {
+		merged[id] = answer
+	}
+	return merged
+}
@@ -0,0 +1,96 @@
+package macros
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestLoadAndParseAnswers(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "answers.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte("vault-name: Vault\nmulti-tenant: no\nclients:\n  - A\n  - B\nport: 8080\n"), 0600))
+
+	fileAnswers, err := LoadAnswers(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, Answers{"vault-name": {"Vault"}, "multi-tenant": {"no"}, "clients": {"A", "B"}, "port": {"8080"}}, fileAnswers)
+
+	flagAnswers, err := ParseAnswers([]string{"clients=C", "clients = D", "owner=Jane=Doe"})
+	assert.NoError(t, err)
+	assert.Equal(t, Answers{"clients": {"C", "D"}, "owner": {"Jane=Doe"}}, flagAnswers)
+
+	merged := fileAnswers.Merge(flagAnswers)
+	assert.Equal(t, []string{"C", "D"}, merged["clients"])
+	assert.Equal(t, []string{"Vault"}, merged["vault-name"])
+
+	_, err = ParseAnswers([]string{"no-value"})
+	assert.Error(t, err)
+}
+
+type answersTestMacro struct {
+	answers map[string][]string
+}
+
+func (what *answersTestMacro) GetMacroDetails() MacroDetails {
+	return MacroDetails{ID: "answers-test"}
+}
+
+func (what *answersTestMacro) GetNextQuestion(*types.ParsedModel) (MacroQuestion, error) {
+	if _, ok := what.answers["name"]; !ok {
+		return MacroQuestion{ID: "name", Title: "Name?"}, nil
+	}
+	if _, ok := what.answers["enabled"]; !ok {
+		return MacroQuestion{ID: "enabled", Title: "Enabled?", PossibleAnswers: []string{"Yes", "No"}, DefaultAnswer: "No"}, nil
+	}
+	return NoMoreQuestions(), nil
+}
+
+func (what *answersTestMacro) ApplyAnswer(questionID string, answer ...string) (string, bool, error) {
+	what.answers[questionID] = answer
+	return "Answer processed", true, nil
+}
+
+func (what *answersTestMacro) GoBack() (string, bool, error) {
+	return "Cannot go back further", false, nil
+}
+
+func (what *answersTestMacro) GetFinalChangeImpact(*input.Model, *types.ParsedModel) ([]string, string, bool, error) {
+	return []string{"setting title"}, "Changeset valid", true, nil
+}
+
+func (what *answersTestMacro) Execute(modelInput *input.Model, _ *types.ParsedModel) (string, bool, error) {
+	modelInput.Title = what.answers["name"][0] + " " + what.answers["enabled"][0]
+	return "Changeset valid", true, nil
+}
+
+func TestExecuteModelMacroWithAnswers(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte("title: Old\n"), 0600))
+
+	macro := &answersTestMacro{answers: make(map[string][]string)}
+	err := executeModelMacroWithAnswers(macro, &input.Model{Title: "Old"}, filename, new(types.ParsedModel), Answers{"name": {"New"}}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"No"}, macro.answers["enabled"]) // default answer
+	data, _ := os.ReadFile(filename)
+	assert.Equal(t, "title: Old\n", string(data)) // dry run
+	assert.NoFileExists(t, filename+".backup")
+
+	macro = &answersTestMacro{answers: make(map[string][]string)}
+	modelInput := &input.Model{Title: "Old"}
+	err = executeModelMacroWithAnswers(macro, modelInput, filename, new(types.ParsedModel), Answers{"name": {"New"}, "enabled": {"yes"}}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "New Yes", modelInput.Title)
+	assert.FileExists(t, filename+".backup")
+
+	macro = &answersTestMacro{answers: make(map[string][]string)}
+	err = executeModelMacroWithAnswers(macro, &input.Model{}, filename, new(types.ParsedModel), Answers{"enabled": {"Yes"}}, true)
+	assert.ErrorContains(t, err, `no answer given to question "name"`)
+
+	macro = &answersTestMacro{answers: make(map[string][]string)}
+	err = executeModelMacroWithAnswers(macro, &input.Model{}, filename, new(types.ParsedModel), Answers{"name": {"New"}, "enabled": {"maybe"}}, true)
+	assert.ErrorContains(t, err, "does not match any allowed value")
+}
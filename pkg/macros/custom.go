@@ -0,0 +1,141 @@
+package macros
+
+import (
+	"fmt"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// parameters of the exec-based protocol of custom model macros: each call runs the plugin with one of these parameters,
+// passes a CustomMacroRequest as JSON via stdin and reads a CustomMacroResponse (or MacroDetails) as JSON from stdout
+const (
+	GetDetailsParameter           = "-get-details"
+	GetNextQuestionParameter      = "-get-next-question"
+	ApplyAnswerParameter          = "-apply-answer"
+	GetFinalChangeImpactParameter = "-get-final-change-impact"
+	ExecuteParameter              = "-execute"
+)
+
+type progressReporter interface {
+	Info(a ...any)
+	Warn(a ...any)
+	Error(a ...any)
+}
+
+// CustomMacroAnswer is an answer given so far, custom macros are stateless: they get all previous answers with each call
+type CustomMacroAnswer struct {
+	QuestionID string   `json:"question_id"`
+	Answer     []string `json:"answer"`
+}
+
+type CustomMacroRequest struct {
+	ModelInput  *input.Model        `json:"model_input,omitempty"`  // only for -get-final-change-impact and -execute
+	ParsedModel *types.ParsedModel  `json:"parsed_model,omitempty"` // not for -apply-answer
+	Answers     []CustomMacroAnswer `json:"answers"`
+	QuestionID  string              `json:"question_id,omitempty"` // only for -apply-answer
+	Answer      []string            `json:"answer,omitempty"`      // only for -apply-answer
+}
+
+type CustomMacroResponse struct {
+	Question    *MacroQuestion `json:"question,omitempty"`    // -get-next-question: empty when there are no more questions
+	Changes     []string       `json:"changes,omitempty"`     // -get-final-change-impact
+	ModelInput  *input.Model   `json:"model_input,omitempty"` // -execute: the changed model
+	Message     string         `json:"message,omitempty"`
+	ValidResult bool           `json:"valid_result"`
+}
+
+type customMacro struct {
+	runner  *model.Runner
+	details MacroDetails
+	answers []CustomMacroAnswer
+}
+
+func loadCustomMacro(pluginFile string) (*customMacro, error) {
+	runner, loadError := new(model.Runner).Load(pluginFile)
+	if loadError != nil {
+		return nil, loadError
+	}
+
+	macro := &customMacro{runner: runner, answers: make([]CustomMacroAnswer, 0)}
+	runError := runner.Run(nil, &macro.details, GetDetailsParameter)
+	if runError != nil {
+		return nil, runError
+	}
+	if len(macro.details.ID) == 0 {
+		return nil, fmt.Errorf("no macro id")
+	}
+	return macro, nil
+}
+
+// newInstance returns the macro without any answers given, not sharing the runner (which keeps the state of a call)
+func (m *customMacro) newInstance() *customMacro {
+	runner := *m.runner
+	return &customMacro{runner: &runner, details: m.details, answers: make([]CustomMacroAnswer, 0)}
+}
+
+func (m *customMacro) GetMacroDetails() MacroDetails {
+	return m.details
+}
+
+func (m *customMacro) GetNextQuestion(parsedModel *types.ParsedModel) (nextQuestion MacroQuestion, err error) {
+	response, err := m.run(GetNextQuestionParameter, CustomMacroRequest{ParsedModel: parsedModel, Answers: m.answers})
+	if err != nil {
+		return NoMoreQuestions(), err
+	}
+	if response.Question == nil {
+		return NoMoreQuestions(), nil
+	}
+	return *response.Question, nil
+}
+
+func (m *customMacro) ApplyAnswer(questionID string, answer ...string) (message string, validResult bool, err error) {
+	response, err := m.run(ApplyAnswerParameter, CustomMacroRequest{Answers: m.answers, QuestionID: questionID, Answer: answer})
+	if err != nil {
+		return "", false, err
+	}
+	if response.ValidResult {
+		m.answers = append(m.answers, CustomMacroAnswer{QuestionID: questionID, Answer: answer})
+	}
+	return response.Message, response.ValidResult, nil
+}
+
+func (m *customMacro) GoBack() (message string, validResult bool, err error) {
+	if len(m.answers) == 0 {
+		return "Cannot go back further", false, nil
+	}
+	m.answers = m.answers[:len(m.answers)-1]
+	return "Undo successful", true, nil
+}
+
+func (m *customMacro) GetFinalChangeImpact(modelInput *input.Model, parsedModel *types.ParsedModel) (changes []string, message string, validResult bool, err error) {
+	response, err := m.run(GetFinalChangeImpactParameter, CustomMacroRequest{ModelInput: modelInput, ParsedModel: parsedModel, Answers: m.answers})
+	if err != nil {
+		return nil, "", false, err
+	}
+	return response.Changes, response.Message, response.ValidResult, nil
+}
+
+func (m *customMacro) Execute(modelInput *input.Model, parsedModel *types.ParsedModel) (message string, validResult bool, err error) {
+	response, err := m.run(ExecuteParameter, CustomMacroRequest{ModelInput: modelInput, ParsedModel: parsedModel, Answers: m.answers})
+	if err != nil {
+		return "", false, err
+	}
+	if response.ValidResult {
+		if response.ModelInput == nil {
+			return "", false, fmt.Errorf("custom model macro %q returned no model", m.details.ID)
+		}
+		*modelInput = *response.ModelInput
+	}
+	return response.Message, response.ValidResult, nil
+}
+
+func (m *customMacro) run(parameter string, request CustomMacroRequest) (*CustomMacroResponse, error) {
+	response := new(CustomMacroResponse)
+	runError := m.runner.Run(request, response, parameter)
+	if runError != nil {
+		return nil, fmt.Errorf("custom model macro %q failed: %w", m.details.ID, runError)
+	}
+	return response, nil
+}
@@ -0,0 +1,180 @@
+package macros
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type recordingProgressReporter struct {
+	warnings, errors []string
+}
+
+func (r *recordingProgressReporter) Info(a ...any) {}
+
+func (r *recordingProgressReporter) Warn(a ...any) {
+	r.warnings = append(r.warnings, fmt.Sprint(a...))
+}
+
+func (r *recordingProgressReporter) Error(a ...any) {
+	r.errors = append(r.errors, fmt.Sprint(a...))
+}
+
+// the test binary acts as custom macro plugin when started with this environment variable set
+const testMacroPluginEnvironmentVariable = "THREAGILE_TEST_MACRO_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testMacroPluginEnvironmentVariable) == "1" {
+		os.Exit(runTestMacroPlugin(os.Args[1:]))
+	}
+	os.Exit(m.Run())
+}
+
+// runTestMacroPlugin implements a custom macro asking for the tags to add to the tags available in the model
+func runTestMacroPlugin(args []string) int {
+	if len(args) != 1 {
+		_, _ = fmt.Fprintln(os.Stderr, "expected one parameter")
+		return 1
+	}
+	var request CustomMacroRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	tags := make([]string, 0)
+	for _, answer := range request.Answers {
+		if answer.QuestionID == "tags" {
+			tags = append(tags, answer.Answer...)
+		}
+	}
+
+	var response any
+	switch args[0] {
+	case GetDetailsParameter:
+		response = MacroDetails{ID: "add-tags", Title: "Add Tags", Description: "Adds tags to the model"}
+	case GetNextQuestionParameter:
+		if len(tags) > 0 || request.ParsedModel == nil {
+			response = CustomMacroResponse{}
+		} else {
+			response = CustomMacroResponse{Question: &MacroQuestion{ID: "tags", Title: "Which tags to add to " + request.ParsedModel.Title + "?", MultiSelect: true}}
+		}
+	case ApplyAnswerParameter:
+		if request.QuestionID != "tags" || len(request.Answer) == 0 {
+			response = CustomMacroResponse{Message: "Please name at least one tag"}
+		} else {
+			response = CustomMacroResponse{Message: "Answer processed", ValidResult: true}
+		}
+	case GetFinalChangeImpactParameter:
+		response = CustomMacroResponse{Changes: []string{"adding tags: " + strings.Join(tags, ", ")}, Message: "Changeset valid", ValidResult: true}
+	case ExecuteParameter:
+		request.ModelInput.TagsAvailable = append(request.ModelInput.TagsAvailable, tags...)
+		response = CustomMacroResponse{ModelInput: request.ModelInput, Message: "Model macro executed", ValidResult: true}
+	default:
+		_, _ = fmt.Fprintln(os.Stderr, "unknown parameter", args[0])
+		return 1
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(response); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func TestCustomMacroProtocol(t *testing.T) {
+	t.Setenv(testMacroPluginEnvironmentVariable, "1")
+
+	macro, err := loadCustomMacro(os.Args[0])
+	assert.NoError(t, err)
+	assert.Equal(t, MacroDetails{ID: "add-tags", Title: "Add Tags", Description: "Adds tags to the model"}, macro.GetMacroDetails())
+
+	parsedModel := &types.ParsedModel{Title: "Some Model"}
+	question, err := macro.GetNextQuestion(parsedModel)
+	assert.NoError(t, err)
+	assert.Equal(t, "tags", question.ID)
+	assert.Equal(t, "Which tags to add to Some Model?", question.Title)
+	assert.True(t, question.MultiSelect)
+
+	message, validResult, err := macro.ApplyAnswer("tags")
+	assert.NoError(t, err)
+	assert.False(t, validResult)
+	assert.Equal(t, "Please name at least one tag", message)
+	assert.Empty(t, macro.answers) // an invalid answer is not kept
+
+	_, validResult, err = macro.ApplyAnswer("tags", "some-tag", "other-tag")
+	assert.NoError(t, err)
+	assert.True(t, validResult)
+	question, err = macro.GetNextQuestion(parsedModel)
+	assert.NoError(t, err)
+	assert.True(t, question.NoMoreQuestions())
+
+	_, validResult, err = macro.GoBack()
+	assert.NoError(t, err)
+	assert.True(t, validResult)
+	question, err = macro.GetNextQuestion(parsedModel)
+	assert.NoError(t, err)
+	assert.Equal(t, "tags", question.ID) // asked again after going back
+	_, validResult, _ = macro.GoBack()
+	assert.False(t, validResult)
+
+	_, validResult, err = macro.ApplyAnswer("tags", "some-tag")
+	assert.NoError(t, err)
+	assert.True(t, validResult)
+
+	modelInput := &input.Model{Title: "Some Model", TagsAvailable: []string{"existing-tag"}}
+	changes, _, validResult, err := macro.GetFinalChangeImpact(modelInput, parsedModel)
+	assert.NoError(t, err)
+	assert.True(t, validResult)
+	assert.Equal(t, []string{"adding tags: some-tag"}, changes)
+
+	message, validResult, err = macro.Execute(modelInput, parsedModel)
+	assert.NoError(t, err)
+	assert.True(t, validResult)
+	assert.Equal(t, "Model macro executed", message)
+	assert.Equal(t, []string{"existing-tag", "some-tag"}, modelInput.TagsAvailable)
+	assert.Equal(t, "Some Model", modelInput.Title)
+}
+
+func TestCustomMacroFailures(t *testing.T) {
+	_, err := loadCustomMacro("unknown-plugin")
+	assert.Error(t, err)
+
+	t.Setenv(testMacroPluginEnvironmentVariable, "1")
+	macro, err := loadCustomMacro(os.Args[0])
+	assert.NoError(t, err)
+
+	_, err = macro.run("-unknown", CustomMacroRequest{})
+	assert.ErrorContains(t, err, `custom model macro "add-tags" failed`)
+	assert.ErrorContains(t, err, "unknown parameter -unknown")
+}
+
+func TestCustomMacrosLoadedOnceServeSeveralSessions(t *testing.T) {
+	t.Setenv(testMacroPluginEnvironmentVariable, "1")
+	reporter := &recordingProgressReporter{}
+	customMacros := ListCustomMacros([]string{os.Args[0], "unknown-plugin", os.Args[0]}, reporter)
+	assert.Len(t, customMacros, 1) // neither the unknown nor the duplicate plugin
+	assert.Len(t, reporter.warnings, 2)
+	assert.Empty(t, reporter.errors)
+
+	first, err := GetMacroByID("add-tags", customMacros)
+	assert.NoError(t, err)
+	second, err := GetMacroByID("add-tags", customMacros)
+	assert.NoError(t, err)
+	assert.NotSame(t, first, second)
+
+	_, validResult, err := first.ApplyAnswer("tags", "some-tag")
+	assert.NoError(t, err)
+	assert.True(t, validResult)
+	question, err := second.GetNextQuestion(&types.ParsedModel{Title: "Some Model"})
+	assert.NoError(t, err)
+	assert.Equal(t, "tags", question.ID) // not answered in this session
+	assert.Empty(t, customMacros[0].(*customMacro).answers)
+}
@@ -13,6 +13,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pmezard/go-difflib/difflib"
+
 	"github.com/threagile/threagile/pkg/input"
 	"github.com/threagile/threagile/pkg/security/types"
 	"gopkg.in/yaml.v3"
@@ -38,29 +40,57 @@ func ListBuiltInMacros() []Macros {
 	}
 }
 
-func ListCustomMacros() []Macros {
-	// TODO: implement
-	return []Macros{}
+func ListCustomMacros(pluginFiles []string, reporter progressReporter) []Macros {
+	customMacros := make([]Macros, 0)
+	for _, pluginFile := range pluginFiles {
+		if len(pluginFile) == 0 {
+			continue
+		}
+
+		macro, loadError := loadCustomMacro(pluginFile)
+		if loadError != nil {
+			reporter.Warn(fmt.Sprintf("WARNING: Custom model macro %q not loaded: %v", pluginFile, loadError))
+			continue
+		}
+
+		if _, err := GetMacroByID(macro.details.ID, customMacros); err == nil {
+			reporter.Warn(fmt.Sprintf("WARNING: Custom model macro %q not loaded: duplicate macro id %q", pluginFile, macro.details.ID))
+			continue
+		}
+
+		customMacros = append(customMacros, macro)
+		reporter.Info("Custom model macro loaded:", macro.details.ID)
+	}
+	return customMacros
 }
 
-func GetMacroByID(id string) (Macros, error) {
+// GetMacroByID returns a new instance of the macro, custom macros loaded once can thus be used by several sessions
+func GetMacroByID(id string, customMacros []Macros) (Macros, error) {
 	builtinMacros := ListBuiltInMacros()
-	customMacros := ListCustomMacros()
 	allMacros := append(builtinMacros, customMacros...)
 	for _, macro := range allMacros {
 		if macro.GetMacroDetails().ID == id {
+			if custom, ok := macro.(*customMacro); ok {
+				return custom.newInstance(), nil
+			}
 			return macro, nil
 		}
 	}
 	return nil, errors.New("unknown macro id: " + id)
 }
 
-func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *types.ParsedModel, macroID string) error {
-	macros, err := GetMacroByID(macroID)
+// ExecuteModelMacro asks the questions of the macro on the console, unless answers are given, and updates the model file;
+// a dry run prints the change impact and a diff of the model file instead of writing it
+func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *types.ParsedModel, macroID string, customMacros []Macros, answers Answers, dryRun bool) error {
+	macros, err := GetMacroByID(macroID, customMacros)
 	if err != nil {
 		return err
 	}
 
+	if answers != nil {
+		return executeModelMacroWithAnswers(macros, modelInput, inputFile, parsedModel, answers, dryRun)
+	}
+
 	macroDetails := macros.GetMacroDetails()
 
 	fmt.Println("Executing model macro:", macroDetails.ID)
@@ -219,6 +249,9 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 		fmt.Println()
 		fmt.Println(message)
 		fmt.Println()
+		if dryRun {
+			return applyModelMacro(macros, modelInput, inputFile, parsedModel, true)
+		}
 		fmt.Print("Apply these changes to the model file?\nType Yes or No: ")
 		answer, err := reader.ReadString('\n')
 		// convert CRLF to LF
@@ -229,37 +262,7 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 		answer = strings.ToLower(answer)
 		fmt.Println()
 		if answer == "yes" || answer == "y" {
-			message, validResult, err = macros.Execute(modelInput, parsedModel)
-			if err != nil {
-				return err
-			}
-			if !validResult {
-				fmt.Println()
-				fmt.Println(">>> INVALID <<<")
-			}
-			fmt.Println(message)
-			fmt.Println()
-			backupFilename := inputFile + ".backup"
-			fmt.Println("Creating backup model file:", backupFilename) // TODO add random files in /dev/shm space?
-			_, err = copyFile(inputFile, backupFilename)
-			if err != nil {
-				return err
-			}
-			fmt.Println("Updating model")
-			yamlBytes, err := yaml.Marshal(modelInput)
-			if err != nil {
-				return err
-			}
-			/*
-				yamlBytes = model.ReformatYAML(yamlBytes)
-			*/
-			fmt.Println("Writing model file:", inputFile)
-			err = os.WriteFile(inputFile, yamlBytes, 0400)
-			if err != nil {
-				return err
-			}
-			fmt.Println("Model file successfully updated")
-			return nil
+			return applyModelMacro(macros, modelInput, inputFile, parsedModel, false)
 		} else if answer == "no" || answer == "n" {
 			fmt.Println("Quitting without executing the model macro")
 			return nil
@@ -267,6 +270,131 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 	}
 }
 
+func executeModelMacroWithAnswers(macros Macros, modelInput *input.Model, inputFile string, parsedModel *types.ParsedModel, answers Answers, dryRun bool) error {
+	answered := make(map[string]bool)
+	for {
+		nextQuestion, err := macros.GetNextQuestion(parsedModel)
+		if err != nil {
+			return err
+		}
+		if nextQuestion.NoMoreQuestions() {
+			break
+		}
+		if answered[nextQuestion.ID] {
+			return fmt.Errorf("question %q was asked again, the answer %q was not accepted", nextQuestion.ID, strings.Join(answers[nextQuestion.ID], ", "))
+		}
+		answered[nextQuestion.ID] = true
+
+		answer, ok := answers[nextQuestion.ID]
+		if !ok {
+			if len(nextQuestion.DefaultAnswer) == 0 {
+				return fmt.Errorf("no answer given to question %q: %v", nextQuestion.ID, nextQuestion.Title)
+			}
+			answer = []string{nextQuestion.DefaultAnswer}
+		}
+		if !nextQuestion.MultiSelect && len(answer) != 1 {
+			return fmt.Errorf("question %q takes exactly one answer, got %d", nextQuestion.ID, len(answer))
+		}
+		if nextQuestion.IsValueConstrained() {
+			answer = append([]string{}, answer...)
+			for i, value := range answer {
+				if !nextQuestion.IsMatchingValueConstraint(value) {
+					return fmt.Errorf("answer %q to question %q does not match any allowed value: %v", value, nextQuestion.ID, strings.Join(nextQuestion.PossibleAnswers, ", "))
+				}
+				for _, possibleAnswer := range nextQuestion.PossibleAnswers {
+					if strings.EqualFold(possibleAnswer, value) {
+						answer[i] = possibleAnswer
+					}
+				}
+			}
+		}
+
+		fmt.Println(nextQuestion.Title, strings.Join(answer, ", "))
+		message, validResult, err := macros.ApplyAnswer(nextQuestion.ID, answer...)
+		if err != nil {
+			return err
+		}
+		if !validResult {
+			return fmt.Errorf("invalid answer to question %q: %v", nextQuestion.ID, message)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("The following changes will be applied:")
+	changes, message, validResult, err := macros.GetFinalChangeImpact(modelInput, parsedModel)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		fmt.Println(" -", change)
+	}
+	if !validResult {
+		return fmt.Errorf("invalid changes: %v", message)
+	}
+	fmt.Println()
+	fmt.Println(message)
+	fmt.Println()
+	return applyModelMacro(macros, modelInput, inputFile, parsedModel, dryRun)
+}
+
+// applyModelMacro executes the macro and writes the model file (after creating a backup), or prints a unified diff of
+// the model file on a dry run
+func applyModelMacro(macros Macros, modelInput *input.Model, inputFile string, parsedModel *types.ParsedModel, dryRun bool) error {
+	message, validResult, err := macros.Execute(modelInput, parsedModel)
+	if err != nil {
+		return err
+	}
+	if !validResult {
+		fmt.Println()
+		fmt.Println(">>> INVALID <<<")
+	}
+	fmt.Println(message)
+	fmt.Println()
+
+	yamlBytes, err := yaml.Marshal(modelInput)
+	if err != nil {
+		return err
+	}
+	/*
+		yamlBytes = model.ReformatYAML(yamlBytes)
+	*/
+
+	if dryRun {
+		original, readError := os.ReadFile(filepath.Clean(inputFile))
+		if readError != nil {
+			return readError
+		}
+		diff, diffError := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(original)),
+			B:        difflib.SplitLines(string(yamlBytes)),
+			FromFile: inputFile,
+			ToFile:   inputFile,
+			Context:  3,
+		})
+		if diffError != nil {
+			return diffError
+		}
+		fmt.Println("Dry run, not writing model file:", inputFile)
+		fmt.Print(diff)
+		return nil
+	}
+
+	backupFilename := inputFile + ".backup"
+	fmt.Println("Creating backup model file:", backupFilename) // TODO add random files in /dev/shm space?
+	_, err = copyFile(inputFile, backupFilename)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Updating model")
+	fmt.Println("Writing model file:", inputFile)
+	err = os.WriteFile(inputFile, yamlBytes, 0400)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Model file successfully updated")
+	return nil
+}
+
 func printBorder(length int, bold bool) {
 	char := "-"
 	if bold {
@@ -0,0 +1,198 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type ModelDiff struct {
+	OldModel           string       `json:"old_model,omitempty"`
+	NewModel           string       `json:"new_model,omitempty"`
+	TechnicalAssets    ElementDiff  `json:"technical_assets"`
+	CommunicationLinks ElementDiff  `json:"communication_links"`
+	DataAssets         ElementDiff  `json:"data_assets"`
+	TrustBoundaries    ElementDiff  `json:"trust_boundaries"`
+	NewRisks           []types.Risk `json:"new_risks"`
+	ResolvedRisks      []types.Risk `json:"resolved_risks"`
+	ChangedRisks       []RiskChange `json:"changed_risks"`
+}
+
+type ElementDiff struct {
+	Added   []string        `json:"added"`
+	Removed []string        `json:"removed"`
+	Changed []ElementChange `json:"changed"`
+}
+
+type ElementChange struct {
+	Id     string   `json:"id"`
+	Fields []string `json:"fields"`
+}
+
+// RiskChange is a risk of both models whose severity or risk tracking status changed, Risk is the one of the new model
+type RiskChange struct {
+	Risk        types.Risk         `json:"risk"`
+	OldSeverity types.RiskSeverity `json:"old_severity"`
+	OldStatus   types.RiskStatus   `json:"old_status"`
+	NewStatus   types.RiskStatus   `json:"new_status"`
+}
+
+// IsEscalation tells whether the risk got more severe or is at risk again (like a mitigation being reverted)
+func (what RiskChange) IsEscalation() bool {
+	return what.Risk.Severity > what.OldSeverity || (!what.OldStatus.IsStillAtRisk() && what.NewStatus.IsStillAtRisk())
+}
+
+func (what ElementDiff) IsEmpty() bool {
+	return len(what.Added) == 0 && len(what.Removed) == 0 && len(what.Changed) == 0
+}
+
+func (what ModelDiff) IsEmpty() bool {
+	return what.TechnicalAssets.IsEmpty() && what.CommunicationLinks.IsEmpty() && what.DataAssets.IsEmpty() &&
+		what.TrustBoundaries.IsEmpty() && len(what.NewRisks) == 0 && len(what.ResolvedRisks) == 0 && len(what.ChangedRisks) == 0
+}
+
+// NewRisksStillAtRisk returns the newly introduced and the escalated risks of at least the given severity, which are
+// not yet mitigated or marked as false positive in the new model's risk tracking
+func (what ModelDiff) NewRisksStillAtRisk(newModel *types.ParsedModel, minimumSeverity types.RiskSeverity) []types.Risk {
+	result := make([]types.Risk, 0)
+	for _, risk := range what.NewRisks {
+		if risk.Severity >= minimumSeverity && risk.GetRiskTrackingStatusDefaultingUnchecked(newModel).IsStillAtRisk() {
+			result = append(result, risk)
+		}
+	}
+	for _, change := range what.ChangedRisks {
+		if change.IsEscalation() && change.Risk.Severity >= minimumSeverity && change.NewStatus.IsStillAtRisk() {
+			result = append(result, change.Risk)
+		}
+	}
+	return result
+}
+
+// DiffModels compares two analyzed models element by element (keyed by their IDs) and risk by risk (keyed by their synthetic IDs)
+func DiffModels(oldModel *types.ParsedModel, newModel *types.ParsedModel) *ModelDiff {
+	result := &ModelDiff{
+		TechnicalAssets:    diffElements(oldModel.TechnicalAssets, newModel.TechnicalAssets, "communication_links", "raa"),
+		CommunicationLinks: diffElements(oldModel.CommunicationLinks, newModel.CommunicationLinks),
+		DataAssets:         diffElements(oldModel.DataAssets, newModel.DataAssets),
+		TrustBoundaries:    diffElements(oldModel.TrustBoundaries, newModel.TrustBoundaries),
+		NewRisks:           make([]types.Risk, 0),
+		ResolvedRisks:      make([]types.Risk, 0),
+		ChangedRisks:       make([]RiskChange, 0),
+	}
+
+	for _, syntheticId := range sortedRiskIDs(newModel) {
+		newRisk := newModel.GeneratedRisksBySyntheticId[syntheticId]
+		oldRisk, ok := oldModel.GeneratedRisksBySyntheticId[syntheticId]
+		if !ok {
+			result.NewRisks = append(result.NewRisks, newRisk)
+			continue
+		}
+
+		change := RiskChange{
+			Risk:        newRisk,
+			OldSeverity: oldRisk.Severity,
+			OldStatus:   oldRisk.GetRiskTrackingStatusDefaultingUnchecked(oldModel),
+			NewStatus:   newRisk.GetRiskTrackingStatusDefaultingUnchecked(newModel),
+		}
+		if change.OldSeverity != newRisk.Severity || change.OldStatus != change.NewStatus {
+			result.ChangedRisks = append(result.ChangedRisks, change)
+		}
+	}
+	for _, syntheticId := range sortedRiskIDs(oldModel) {
+		if _, ok := newModel.GeneratedRisksBySyntheticId[syntheticId]; !ok {
+			result.ResolvedRisks = append(result.ResolvedRisks, oldModel.GeneratedRisksBySyntheticId[syntheticId])
+		}
+	}
+	types.SortByRiskSeverity(result.NewRisks, newModel)
+	types.SortByRiskSeverity(result.ResolvedRisks, oldModel)
+	sort.SliceStable(result.ChangedRisks, func(i, j int) bool {
+		return result.ChangedRisks[i].Risk.Severity > result.ChangedRisks[j].Risk.Severity
+	})
+
+	return result
+}
+
+func diffElements[T any](oldElements map[string]T, newElements map[string]T, ignoredFields ...string) ElementDiff {
+	result := ElementDiff{
+		Added:   make([]string, 0),
+		Removed: make([]string, 0),
+		Changed: make([]ElementChange, 0),
+	}
+
+	for id, newElement := range newElements {
+		oldElement, ok := oldElements[id]
+		if !ok {
+			result.Added = append(result.Added, id)
+			continue
+		}
+
+		fields := changedFields(oldElement, newElement, ignoredFields)
+		if len(fields) > 0 {
+			result.Changed = append(result.Changed, ElementChange{Id: id, Fields: fields})
+		}
+	}
+	for id := range oldElements {
+		if _, ok := newElements[id]; !ok {
+			result.Removed = append(result.Removed, id)
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Slice(result.Changed, func(i, j int) bool {
+		return result.Changed[i].Id < result.Changed[j].Id
+	})
+	return result
+}
+
+// changedFields compares the JSON representation of two elements, so the field names reported match the model's attribute names
+func changedFields(oldElement any, newElement any, ignoredFields []string) []string {
+	oldValues := toFieldMap(oldElement)
+	newValues := toFieldMap(newElement)
+
+	ignored := make(map[string]bool)
+	for _, field := range ignoredFields {
+		ignored[field] = true
+	}
+
+	fieldNames := make(map[string]bool)
+	for name := range oldValues {
+		fieldNames[name] = true
+	}
+	for name := range newValues {
+		fieldNames[name] = true
+	}
+
+	result := make([]string, 0)
+	for name := range fieldNames {
+		if ignored[name] {
+			continue
+		}
+		if !reflect.DeepEqual(oldValues[name], newValues[name]) {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func toFieldMap(element any) map[string]any {
+	result := make(map[string]any)
+	data, marshalError := json.Marshal(element)
+	if marshalError != nil {
+		return result
+	}
+	_ = json.Unmarshal(data, &result)
+	return result
+}
+
+func sortedRiskIDs(parsedModel *types.ParsedModel) []string {
+	result := make([]string, 0)
+	for syntheticId := range parsedModel.GeneratedRisksBySyntheticId {
+		result = append(result, syntheticId)
+	}
+	sort.Strings(result)
+	return result
+}
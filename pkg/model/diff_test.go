@@ -0,0 +1,116 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestDiffModels_ReportsElementAndRiskChanges(t *testing.T) {
+	oldModel := &types.ParsedModel{
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web":    {Id: "web", Title: "Web", Internet: false, RAA: 10},
+			"legacy": {Id: "legacy", Title: "Legacy"},
+		},
+		GeneratedRisksBySyntheticId: map[string]types.Risk{
+			"old-risk@legacy": {SyntheticId: "old-risk@legacy", Severity: types.HighSeverity},
+			"kept-risk@web":   {SyntheticId: "kept-risk@web", Severity: types.MediumSeverity},
+		},
+	}
+	newModel := &types.ParsedModel{
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web": {Id: "web", Title: "Web", Internet: true, RAA: 42},
+			"api": {Id: "api", Title: "API"},
+		},
+		GeneratedRisksBySyntheticId: map[string]types.Risk{
+			"kept-risk@web": {SyntheticId: "kept-risk@web", Severity: types.MediumSeverity},
+			"new-risk@api":  {SyntheticId: "new-risk@api", Severity: types.CriticalSeverity},
+			"new-risk@web":  {SyntheticId: "new-risk@web", Severity: types.LowSeverity},
+		},
+		RiskTracking: map[string]types.RiskTracking{
+			"new-risk@web": {SyntheticRiskId: "new-risk@web", Status: types.Mitigated},
+		},
+	}
+
+	diff := DiffModels(oldModel, newModel)
+
+	assert.Equal(t, []string{"api"}, diff.TechnicalAssets.Added)
+	assert.Equal(t, []string{"legacy"}, diff.TechnicalAssets.Removed)
+	assert.Equal(t, []ElementChange{{Id: "web", Fields: []string{"internet"}}}, diff.TechnicalAssets.Changed)
+	assert.Len(t, diff.NewRisks, 2)
+	assert.Equal(t, "new-risk@api", diff.NewRisks[0].SyntheticId)
+	assert.Len(t, diff.ResolvedRisks, 1)
+	assert.Equal(t, "old-risk@legacy", diff.ResolvedRisks[0].SyntheticId)
+
+	assert.Len(t, diff.NewRisksStillAtRisk(newModel, types.LowSeverity), 1)
+	assert.Len(t, diff.NewRisksStillAtRisk(newModel, types.CriticalSeverity), 1)
+	assert.Empty(t, DiffModels(newModel, newModel).NewRisks)
+	assert.True(t, DiffModels(newModel, newModel).IsEmpty())
+}
+
+func TestDiffModels_ReportsChangedRisksAndEscalations(t *testing.T) {
+	risk := func(syntheticId string, severity types.RiskSeverity) types.Risk {
+		return types.Risk{SyntheticId: syntheticId, Severity: severity}
+	}
+	oldModel := &types.ParsedModel{
+		GeneratedRisksBySyntheticId: map[string]types.Risk{
+			"more-severe@web":   risk("more-severe@web", types.MediumSeverity),
+			"less-severe@web":   risk("less-severe@web", types.HighSeverity),
+			"reverted@web":      risk("reverted@web", types.HighSeverity),
+			"mitigated@web":     risk("mitigated@web", types.HighSeverity),
+			"tracked-again@web": risk("tracked-again@web", types.HighSeverity),
+			"unchanged@web":     risk("unchanged@web", types.CriticalSeverity),
+		},
+		RiskTracking: map[string]types.RiskTracking{
+			"reverted@web":      {Status: types.Mitigated},
+			"tracked-again@web": {Status: types.Accepted},
+			"unchanged@web":     {Status: types.InProgress},
+		},
+	}
+	newModel := &types.ParsedModel{
+		GeneratedRisksBySyntheticId: map[string]types.Risk{
+			"more-severe@web":   risk("more-severe@web", types.CriticalSeverity),
+			"less-severe@web":   risk("less-severe@web", types.LowSeverity),
+			"reverted@web":      risk("reverted@web", types.HighSeverity),
+			"mitigated@web":     risk("mitigated@web", types.HighSeverity),
+			"tracked-again@web": risk("tracked-again@web", types.HighSeverity),
+			"unchanged@web":     risk("unchanged@web", types.CriticalSeverity),
+		},
+		RiskTracking: map[string]types.RiskTracking{
+			"mitigated@web":     {Status: types.Mitigated},
+			"tracked-again@web": {Status: types.FalsePositive},
+			"unchanged@web":     {Status: types.InProgress},
+		},
+	}
+
+	diff := DiffModels(oldModel, newModel)
+
+	assert.Empty(t, diff.NewRisks)
+	assert.Empty(t, diff.ResolvedRisks)
+	assert.False(t, diff.IsEmpty())
+	changes := make(map[string]RiskChange)
+	for _, change := range diff.ChangedRisks {
+		changes[change.Risk.SyntheticId] = change
+	}
+	assert.Len(t, changes, 5)
+	assert.NotContains(t, changes, "unchanged@web")
+	assert.Equal(t, "more-severe@web", diff.ChangedRisks[0].Risk.SyntheticId) // sorted by severity
+	assert.Equal(t, types.MediumSeverity, changes["more-severe@web"].OldSeverity)
+	assert.Equal(t, types.Mitigated, changes["reverted@web"].OldStatus)
+	assert.Equal(t, types.Unchecked, changes["reverted@web"].NewStatus)
+
+	assert.True(t, changes["more-severe@web"].IsEscalation())
+	assert.True(t, changes["reverted@web"].IsEscalation())
+	assert.False(t, changes["less-severe@web"].IsEscalation())
+	assert.False(t, changes["mitigated@web"].IsEscalation())
+	assert.False(t, changes["tracked-again@web"].IsEscalation()) // from one not at risk status to another
+
+	stillAtRisk := make([]string, 0)
+	for _, risk := range diff.NewRisksStillAtRisk(newModel, types.LowSeverity) {
+		stillAtRisk = append(stillAtRisk, risk.SyntheticId)
+	}
+	assert.Equal(t, []string{"more-severe@web", "reverted@web"}, stillAtRisk)
+	assert.Len(t, diff.NewRisksStillAtRisk(newModel, types.CriticalSeverity), 1)
+	assert.Empty(t, DiffModels(newModel, newModel).ChangedRisks)
+}
@@ -13,10 +13,17 @@ import (
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
+// ParseModel turns a loaded model into a parsed model, all errors found are returned at once (joined) and carry the
+// positions (file, line and column) of the values they refer to as far as these are known by the model input
 func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskRule, customRiskRules map[string]*CustomRisk) (*types.ParsedModel, error) {
+	errs := make([]error, 0)
+	fail := func(err error, path ...string) {
+		errs = append(errs, modelInput.Positions.Error(err, path...))
+	}
+
 	businessCriticality, err := types.ParseCriticality(modelInput.BusinessCriticality)
 	if err != nil {
-		return nil, errors.New("unknown 'business_criticality' value of application: " + modelInput.BusinessCriticality)
+		fail(errors.New("unknown 'business_criticality' value of application: "+modelInput.BusinessCriticality), "business_criticality")
 	}
 
 	reportDate := time.Now()
@@ -24,10 +31,13 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 		var parseError error
 		reportDate, parseError = time.Parse("2006-01-02", modelInput.Date)
 		if parseError != nil {
-			return nil, errors.New("unable to parse 'date' value of model file (expected format: '2006-01-02')")
+			fail(errors.New("unable to parse 'date' value of model file (expected format: '2006-01-02')"), "date")
 		}
 	}
 
+	modelNames := modelNamesOf(modelInput) // of a portfolio, references to assets of other models are skipped
+	technologies, technologyErrors := ResolveTechnologies(modelInput)
+	errs = append(errs, technologyErrors...)
 	parsedModel := types.ParsedModel{
 		ThreagileVersion:               modelInput.ThreagileVersion,
 		Title:                          modelInput.Title,
@@ -66,6 +76,19 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 		parsedModel.DiagramTweakRanksep = 2
 	}
 
+	// reports all referenced tags missing in the overall tag list
+	checkTags := func(tags []string, where string, path ...string) []string {
+		tagsUsed := make([]string, 0)
+		for _, tag := range lowerCaseAndTrim(tags) {
+			if err := parsedModel.CheckTagExists(tag, where); err != nil {
+				fail(err, append(path, input.Item(tag))...)
+				continue
+			}
+			tagsUsed = append(tagsUsed, tag)
+		}
+		return tagsUsed
+	}
+
 	// Data Assets ===============================================================================
 	parsedModel.DataAssets = make(map[string]types.DataAsset)
 	for title, asset := range modelInput.DataAssets {
@@ -73,36 +96,32 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 
 		usage, err := types.ParseUsage(asset.Usage)
 		if err != nil {
-			return nil, errors.New("unknown 'usage' value of data asset '" + title + "': " + asset.Usage)
+			fail(errors.New("unknown 'usage' value of data asset '"+title+"': "+asset.Usage), "data_assets", title, "usage")
 		}
 		quantity, err := types.ParseQuantity(asset.Quantity)
 		if err != nil {
-			return nil, errors.New("unknown 'quantity' value of data asset '" + title + "': " + asset.Quantity)
+			fail(errors.New("unknown 'quantity' value of data asset '"+title+"': "+asset.Quantity), "data_assets", title, "quantity")
 		}
 		confidentiality, err := types.ParseConfidentiality(asset.Confidentiality)
 		if err != nil {
-			return nil, errors.New("unknown 'confidentiality' value of data asset '" + title + "': " + asset.Confidentiality)
+			fail(errors.New("unknown 'confidentiality' value of data asset '"+title+"': "+asset.Confidentiality), "data_assets", title, "confidentiality")
 		}
 		integrity, err := types.ParseCriticality(asset.Integrity)
 		if err != nil {
-			return nil, errors.New("unknown 'integrity' value of data asset '" + title + "': " + asset.Integrity)
+			fail(errors.New("unknown 'integrity' value of data asset '"+title+"': "+asset.Integrity), "data_assets", title, "integrity")
 		}
 		availability, err := types.ParseCriticality(asset.Availability)
 		if err != nil {
-			return nil, errors.New("unknown 'availability' value of data asset '" + title + "': " + asset.Availability)
+			fail(errors.New("unknown 'availability' value of data asset '"+title+"': "+asset.Availability), "data_assets", title, "availability")
 		}
 
-		err = checkIdSyntax(id)
-		if err != nil {
-			return nil, err
+		if err := checkIdSyntax(id, modelInput.Portfolio); err != nil {
+			fail(err, "data_assets", title, "id")
 		}
 		if _, exists := parsedModel.DataAssets[id]; exists {
-			return nil, errors.New("duplicate id used: " + id)
-		}
-		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(asset.Tags), "data asset '"+title+"'")
-		if err != nil {
-			return nil, err
+			fail(errors.New("duplicate id used: "+id), "data_assets", title, "id")
 		}
+		tags := checkTags(asset.Tags, "data asset '"+title+"'", "data_assets", title, "tags")
 		parsedModel.DataAssets[id] = types.DataAsset{
 			Id:                     id,
 			Title:                  title,
@@ -126,7 +145,7 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 
 		usage, err := types.ParseUsage(asset.Usage)
 		if err != nil {
-			return nil, errors.New("unknown 'usage' value of technical asset '" + title + "': " + asset.Usage)
+			fail(errors.New("unknown 'usage' value of technical asset '"+title+"': "+asset.Usage), "technical_assets", title, "usage")
 		}
 
 		var dataAssetsStored = make([]string, 0)
@@ -139,7 +158,8 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 
 				err := parsedModel.CheckDataAssetTargetExists(referencedAsset, "technical asset '"+title+"'")
 				if err != nil {
-					return nil, err
+					fail(err, "technical_assets", title, "data_assets_stored", input.Item(referencedAsset))
+					continue
 				}
 				dataAssetsStored = append(dataAssetsStored, referencedAsset)
 			}
@@ -155,7 +175,8 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 
 				err := parsedModel.CheckDataAssetTargetExists(referencedAsset, "technical asset '"+title+"'")
 				if err != nil {
-					return nil, err
+					fail(err, "technical_assets", title, "data_assets_processed", input.Item(referencedAsset))
+					continue
 				}
 				dataAssetsProcessed = append(dataAssetsProcessed, referencedAsset)
 			}
@@ -163,35 +184,40 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 
 		technicalAssetType, err := types.ParseTechnicalAssetType(asset.Type)
 		if err != nil {
-			return nil, errors.New("unknown 'type' value of technical asset '" + title + "': " + fmt.Sprintf("%v", asset.Type))
+			fail(errors.New("unknown 'type' value of technical asset '"+title+"': "+fmt.Sprintf("%v", asset.Type)), "technical_assets", title, "type")
 		}
 		technicalAssetSize, err := types.ParseTechnicalAssetSize(asset.Size)
 		if err != nil {
-			return nil, errors.New("unknown 'size' value of technical asset '" + title + "': " + fmt.Sprintf("%v", asset.Size))
+			fail(errors.New("unknown 'size' value of technical asset '"+title+"': "+fmt.Sprintf("%v", asset.Size)), "technical_assets", title, "size")
 		}
-		technicalAssetTechnology, err := types.ParseTechnicalAssetTechnology(asset.Technology)
-		if err != nil {
-			return nil, errors.New("unknown 'technology' value of technical asset '" + title + "': " + fmt.Sprintf("%v", asset.Technology))
+		technicalAssetTechnology, customTechnology, err := parseTechnology(asset.Technology, technologies)
+		if _, declared := modelInput.Technologies[asset.Technology]; err != nil && !declared { // otherwise already reported
+			fail(errors.New("unknown 'technology' value of technical asset '"+title+"': "+fmt.Sprintf("%v", asset.Technology)), "technical_assets", title, "technology")
+		}
+		var customTechnologyName string
+		var technologyTraits map[string]bool
+		if customTechnology != nil {
+			customTechnologyName, technologyTraits = customTechnology.Name, customTechnology.Traits
 		}
 		encryption, err := types.ParseEncryptionStyle(asset.Encryption)
 		if err != nil {
-			return nil, errors.New("unknown 'encryption' value of technical asset '" + title + "': " + fmt.Sprintf("%v", asset.Encryption))
+			fail(errors.New("unknown 'encryption' value of technical asset '"+title+"': "+fmt.Sprintf("%v", asset.Encryption)), "technical_assets", title, "encryption")
 		}
 		technicalAssetMachine, err := types.ParseTechnicalAssetMachine(asset.Machine)
 		if err != nil {
-			return nil, errors.New("unknown 'machine' value of technical asset '" + title + "': " + fmt.Sprintf("%v", asset.Machine))
+			fail(errors.New("unknown 'machine' value of technical asset '"+title+"': "+fmt.Sprintf("%v", asset.Machine)), "technical_assets", title, "machine")
 		}
 		confidentiality, err := types.ParseConfidentiality(asset.Confidentiality)
 		if err != nil {
-			return nil, errors.New("unknown 'confidentiality' value of technical asset '" + title + "': " + fmt.Sprintf("%v", asset.Confidentiality))
+			fail(errors.New("unknown 'confidentiality' value of technical asset '"+title+"': "+fmt.Sprintf("%v", asset.Confidentiality)), "technical_assets", title, "confidentiality")
 		}
 		integrity, err := types.ParseCriticality(asset.Integrity)
 		if err != nil {
-			return nil, errors.New("unknown 'integrity' value of technical asset '" + title + "': " + fmt.Sprintf("%v", asset.Integrity))
+			fail(errors.New("unknown 'integrity' value of technical asset '"+title+"': "+fmt.Sprintf("%v", asset.Integrity)), "technical_assets", title, "integrity")
 		}
 		availability, err := types.ParseCriticality(asset.Availability)
 		if err != nil {
-			return nil, errors.New("unknown 'availability' value of technical asset '" + title + "': " + fmt.Sprintf("%v", asset.Availability))
+			fail(errors.New("unknown 'availability' value of technical asset '"+title+"': "+fmt.Sprintf("%v", asset.Availability)), "technical_assets", title, "availability")
 		}
 
 		dataFormatsAccepted := make([]types.DataFormat, 0)
@@ -199,7 +225,8 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			for _, dataFormatName := range asset.DataFormatsAccepted {
 				dataFormat, err := types.ParseDataFormat(dataFormatName)
 				if err != nil {
-					return nil, errors.New("unknown 'data_formats_accepted' value of technical asset '" + title + "': " + fmt.Sprintf("%v", dataFormatName))
+					fail(errors.New("unknown 'data_formats_accepted' value of technical asset '"+title+"': "+fmt.Sprintf("%v", dataFormatName)), "technical_assets", title, "data_formats_accepted", input.Item(dataFormatName))
+					continue
 				}
 				dataFormatsAccepted = append(dataFormatsAccepted, dataFormat)
 			}
@@ -208,25 +235,28 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 		communicationLinks := make([]types.CommunicationLink, 0)
 		if asset.CommunicationLinks != nil {
 			for commLinkTitle, commLink := range asset.CommunicationLinks {
+				if isExternalReference(commLink.Target, modelNames) { // resolved when analyzing the portfolio of the models
+					continue
+				}
 				weight := 1
 				var dataAssetsSent []string
 				var dataAssetsReceived []string
 
 				authentication, err := types.ParseAuthentication(commLink.Authentication)
 				if err != nil {
-					return nil, errors.New("unknown 'authentication' value of technical asset '" + title + "' communication link '" + commLinkTitle + "': " + fmt.Sprintf("%v", commLink.Authentication))
+					fail(errors.New("unknown 'authentication' value of technical asset '"+title+"' communication link '"+commLinkTitle+"': "+fmt.Sprintf("%v", commLink.Authentication)), "technical_assets", title, "communication_links", commLinkTitle, "authentication")
 				}
 				authorization, err := types.ParseAuthorization(commLink.Authorization)
 				if err != nil {
-					return nil, errors.New("unknown 'authorization' value of technical asset '" + title + "' communication link '" + commLinkTitle + "': " + fmt.Sprintf("%v", commLink.Authorization))
+					fail(errors.New("unknown 'authorization' value of technical asset '"+title+"' communication link '"+commLinkTitle+"': "+fmt.Sprintf("%v", commLink.Authorization)), "technical_assets", title, "communication_links", commLinkTitle, "authorization")
 				}
 				usage, err := types.ParseUsage(commLink.Usage)
 				if err != nil {
-					return nil, errors.New("unknown 'usage' value of technical asset '" + title + "' communication link '" + commLinkTitle + "': " + fmt.Sprintf("%v", commLink.Usage))
+					fail(errors.New("unknown 'usage' value of technical asset '"+title+"' communication link '"+commLinkTitle+"': "+fmt.Sprintf("%v", commLink.Usage)), "technical_assets", title, "communication_links", commLinkTitle, "usage")
 				}
 				protocol, err := types.ParseProtocol(commLink.Protocol)
 				if err != nil {
-					return nil, errors.New("unknown 'protocol' value of technical asset '" + title + "' communication link '" + commLinkTitle + "': " + fmt.Sprintf("%v", commLink.Protocol))
+					fail(errors.New("unknown 'protocol' value of technical asset '"+title+"' communication link '"+commLinkTitle+"': "+fmt.Sprintf("%v", commLink.Protocol)), "technical_assets", title, "communication_links", commLinkTitle, "protocol")
 				}
 
 				if commLink.DataAssetsSent != nil {
@@ -235,7 +265,8 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 						if !contains(dataAssetsSent, referencedAsset) {
 							err := parsedModel.CheckDataAssetTargetExists(referencedAsset, "communication link '"+commLinkTitle+"' of technical asset '"+title+"'")
 							if err != nil {
-								return nil, err
+								fail(err, "technical_assets", title, "communication_links", commLinkTitle, "data_assets_sent", input.Item(referencedAsset))
+								continue
 							}
 
 							dataAssetsSent = append(dataAssetsSent, referencedAsset)
@@ -255,7 +286,8 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 
 						err := parsedModel.CheckDataAssetTargetExists(referencedAsset, "communication link '"+commLinkTitle+"' of technical asset '"+title+"'")
 						if err != nil {
-							return nil, err
+							fail(err, "technical_assets", title, "communication_links", commLinkTitle, "data_assets_received", input.Item(referencedAsset))
+							continue
 						}
 						dataAssetsReceived = append(dataAssetsReceived, referencedAsset)
 
@@ -270,17 +302,11 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 				}
 
 				dataFlowTitle := fmt.Sprintf("%v", commLinkTitle)
+				commLinkId, err := CreateDataFlowId(id, dataFlowTitle)
 				if err != nil {
-					return nil, err
-				}
-				commLinkId, err := createDataFlowId(id, dataFlowTitle)
-				if err != nil {
-					return nil, err
-				}
-				tags, err := parsedModel.CheckTags(lowerCaseAndTrim(commLink.Tags), "communication link '"+commLinkTitle+"' of technical asset '"+title+"'")
-				if err != nil {
-					return nil, err
+					fail(err, "technical_assets", title, "communication_links", commLinkTitle)
 				}
+				tags := checkTags(commLink.Tags, "communication link '"+commLinkTitle+"' of technical asset '"+title+"'", "technical_assets", title, "communication_links", commLinkTitle, "tags")
 				commLink := types.CommunicationLink{
 					Id:                     commLinkId,
 					SourceId:               id,
@@ -309,17 +335,13 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			}
 		}
 
-		err = checkIdSyntax(id)
-		if err != nil {
-			return nil, err
+		if err := checkIdSyntax(id, modelInput.Portfolio); err != nil {
+			fail(err, "technical_assets", title, "id")
 		}
 		if _, exists := parsedModel.TechnicalAssets[id]; exists {
-			return nil, errors.New("duplicate id used: " + id)
-		}
-		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(asset.Tags), "technical asset '"+title+"'")
-		if err != nil {
-			return nil, err
+			fail(errors.New("duplicate id used: "+id), "technical_assets", title, "id")
 		}
+		tags := checkTags(asset.Tags, "technical asset '"+title+"'", "technical_assets", title, "tags")
 		parsedModel.TechnicalAssets[id] = types.TechnicalAsset{
 			Id:                      id,
 			Usage:                   usage,
@@ -328,6 +350,8 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			Type:                    technicalAssetType,
 			Size:                    technicalAssetSize,
 			Technology:              technicalAssetTechnology,
+			CustomTechnology:        customTechnologyName,
+			TechnologyTraits:        technologyTraits,
 			Tags:                    tags,
 			Machine:                 technicalAssetMachine,
 			Internet:                asset.Internet,
@@ -377,7 +401,10 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			if commLink.TargetId == id {
 				continue
 			}
-			targetTechAsset := parsedModel.TechnicalAssets[commLink.TargetId]
+			targetTechAsset, found := parsedModel.TechnicalAssets[commLink.TargetId]
+			if !found { // reported below
+				continue
+			}
 			dataAssetsProcessedByTarget := targetTechAsset.DataAssetsProcessed
 			for _, dataAssetSent := range commLink.DataAssetsSent {
 				if !contains(dataAssetsProcessedByTarget, dataAssetSent) {
@@ -403,18 +430,20 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 		var technicalAssetsInside = make([]string, 0)
 		if boundary.TechnicalAssetsInside != nil {
 			parsedInsideAssets := boundary.TechnicalAssetsInside
-			technicalAssetsInside = make([]string, len(parsedInsideAssets))
-			for i, parsedInsideAsset := range parsedInsideAssets {
-				technicalAssetsInside[i] = fmt.Sprintf("%v", parsedInsideAsset)
-				_, found := parsedModel.TechnicalAssets[technicalAssetsInside[i]]
+			for _, parsedInsideAsset := range parsedInsideAssets {
+				insideAsset := fmt.Sprintf("%v", parsedInsideAsset)
+				if isExternalReference(insideAsset, modelNames) { // resolved when analyzing the portfolio of the models
+					continue
+				}
+				_, found := parsedModel.TechnicalAssets[insideAsset]
 				if !found {
-					return nil, errors.New("missing referenced technical asset " + technicalAssetsInside[i] + " at trust boundary '" + title + "'")
+					fail(errors.New("missing referenced technical asset "+insideAsset+" at trust boundary '"+title+"'"), "trust_boundaries", title, "technical_assets_inside", input.Item(insideAsset))
 				}
-				if checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[technicalAssetsInside[i]] {
-					return nil, errors.New("referenced technical asset " + technicalAssetsInside[i] + " at trust boundary '" + title + "' is modeled in multiple trust boundaries")
+				if checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[insideAsset] {
+					fail(errors.New("referenced technical asset "+insideAsset+" at trust boundary '"+title+"' is modeled in multiple trust boundaries"), "trust_boundaries", title, "technical_assets_inside", input.Item(insideAsset))
 				}
-				checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[technicalAssetsInside[i]] = true
-				//fmt.Println("asset "+technicalAssetsInside[i]+" at i="+strconv.Itoa(i))
+				checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[insideAsset] = true
+				technicalAssetsInside = append(technicalAssetsInside, insideAsset)
 			}
 		}
 
@@ -429,12 +458,9 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 
 		trustBoundaryType, err := types.ParseTrustBoundary(boundary.Type)
 		if err != nil {
-			return nil, errors.New("unknown 'type' of trust boundary '" + title + "': " + fmt.Sprintf("%v", boundary.Type))
-		}
-		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(boundary.Tags), "trust boundary '"+title+"'")
-		if err != nil {
-			return nil, err
+			fail(errors.New("unknown 'type' of trust boundary '"+title+"': "+fmt.Sprintf("%v", boundary.Type)), "trust_boundaries", title, "type")
 		}
+		tags := checkTags(boundary.Tags, "trust boundary '"+title+"'", "trust_boundaries", title, "tags")
 		trustBoundary := types.TrustBoundary{
 			Id:                    id,
 			Title:                 title, //fmt.Sprintf("%v", boundary["title"]),
@@ -444,12 +470,11 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			TechnicalAssetsInside: technicalAssetsInside,
 			TrustBoundariesNested: trustBoundariesNested,
 		}
-		err = checkIdSyntax(id)
-		if err != nil {
-			return nil, err
+		if err := checkIdSyntax(id, modelInput.Portfolio); err != nil {
+			fail(err, "trust_boundaries", title, "id")
 		}
 		if _, exists := parsedModel.TrustBoundaries[id]; exists {
-			return nil, errors.New("duplicate id used: " + id)
+			fail(errors.New("duplicate id used: "+id), "trust_boundaries", title, "id")
 		}
 		parsedModel.TrustBoundaries[id] = trustBoundary
 		for _, technicalAsset := range trustBoundary.TechnicalAssetsInside {
@@ -457,9 +482,12 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			//fmt.Println("Asset "+technicalAsset+" is directly in trust boundary "+trustBoundary.Id)
 		}
 	}
-	err = parsedModel.CheckNestedTrustBoundariesExisting()
-	if err != nil {
-		return nil, err
+	for title, boundary := range modelInput.TrustBoundaries {
+		for _, nestedId := range boundary.TrustBoundariesNested {
+			if _, found := parsedModel.TrustBoundaries[nestedId]; !found {
+				fail(errors.New("missing referenced nested trust boundary: "+nestedId), "trust_boundaries", title, "trust_boundaries_nested", input.Item(nestedId))
+			}
+		}
 	}
 
 	// Shared Runtime ===============================================================================
@@ -475,15 +503,12 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 				assetId := fmt.Sprintf("%v", parsedRunningAsset)
 				err := parsedModel.CheckTechnicalAssetExists(assetId, "shared runtime '"+title+"'", false)
 				if err != nil {
-					return nil, err
+					fail(err, "shared_runtimes", title, "technical_assets_running", input.Item(assetId))
 				}
 				technicalAssetsRunning[i] = assetId
 			}
 		}
-		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(inputRuntime.Tags), "shared runtime '"+title+"'")
-		if err != nil {
-			return nil, err
-		}
+		tags := checkTags(inputRuntime.Tags, "shared runtime '"+title+"'", "shared_runtimes", title, "tags")
 		sharedRuntime := types.SharedRuntime{
 			Id:                     id,
 			Title:                  title, //fmt.Sprintf("%v", boundary["title"]),
@@ -491,12 +516,11 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			Tags:                   tags,
 			TechnicalAssetsRunning: technicalAssetsRunning,
 		}
-		err = checkIdSyntax(id)
-		if err != nil {
-			return nil, err
+		if err := checkIdSyntax(id, modelInput.Portfolio); err != nil {
+			fail(err, "shared_runtimes", title, "id")
 		}
 		if _, exists := parsedModel.SharedRuntimes[id]; exists {
-			return nil, errors.New("duplicate id used: " + id)
+			fail(errors.New("duplicate id used: "+id), "shared_runtimes", title, "id")
 		}
 		parsedModel.SharedRuntimes[id] = sharedRuntime
 	}
@@ -514,22 +538,22 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 
 	// Individual Risk Categories (just used as regular risk categories) ===============================================================================
 	//	parsedModel.IndividualRiskCategories = make(map[string]types.RiskCategory)
-	for title, individualCategory := range modelInput.IndividualRiskCategories {
+	for categoryTitle, individualCategory := range modelInput.IndividualRiskCategories {
 		id := fmt.Sprintf("%v", individualCategory.ID)
 
 		function, err := types.ParseRiskFunction(individualCategory.Function)
 		if err != nil {
-			return nil, errors.New("unknown 'function' value of individual risk category '" + title + "': " + fmt.Sprintf("%v", individualCategory.Function))
+			fail(errors.New("unknown 'function' value of individual risk category '"+categoryTitle+"': "+fmt.Sprintf("%v", individualCategory.Function)), "individual_risk_categories", categoryTitle, "function")
 		}
 		stride, err := types.ParseSTRIDE(individualCategory.STRIDE)
 		if err != nil {
-			return nil, errors.New("unknown 'stride' value of individual risk category '" + title + "': " + fmt.Sprintf("%v", individualCategory.STRIDE))
+			fail(errors.New("unknown 'stride' value of individual risk category '"+categoryTitle+"': "+fmt.Sprintf("%v", individualCategory.STRIDE)), "individual_risk_categories", categoryTitle, "stride")
 		}
 
 		cat := types.RiskCategory{
 			Id:                         id,
-			Title:                      title,
-			Description:                withDefault(fmt.Sprintf("%v", individualCategory.Description), title),
+			Title:                      categoryTitle,
+			Description:                withDefault(fmt.Sprintf("%v", individualCategory.Description), categoryTitle),
 			Impact:                     fmt.Sprintf("%v", individualCategory.Impact),
 			ASVS:                       fmt.Sprintf("%v", individualCategory.ASVS),
 			CheatSheet:                 fmt.Sprintf("%v", individualCategory.CheatSheet),
@@ -544,12 +568,11 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			ModelFailurePossibleReason: individualCategory.ModelFailurePossibleReason,
 			CWE:                        individualCategory.CWE,
 		}
-		err = checkIdSyntax(id)
-		if err != nil {
-			return nil, err
+		if err := checkIdSyntax(id, modelInput.Portfolio); err != nil {
+			fail(err, "individual_risk_categories", categoryTitle, "id")
 		}
 		if _, exists := parsedModel.IndividualRiskCategories[id]; exists {
-			return nil, errors.New("duplicate id used: " + id)
+			fail(errors.New("duplicate id used: "+id), "individual_risk_categories", categoryTitle, "id")
 		}
 		parsedModel.IndividualRiskCategories[id] = cat
 
@@ -562,22 +585,22 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 				var dataBreachTechnicalAssetIDs []string
 				severity, err := types.ParseRiskSeverity(individualRiskInstance.Severity)
 				if err != nil {
-					return nil, errors.New("unknown 'severity' value of individual risk instance '" + title + "': " + fmt.Sprintf("%v", individualRiskInstance.Severity))
+					fail(errors.New("unknown 'severity' value of individual risk instance '"+title+"': "+fmt.Sprintf("%v", individualRiskInstance.Severity)), "individual_risk_categories", categoryTitle, "risks_identified", title, "severity")
 				}
 				exploitationLikelihood, err := types.ParseRiskExploitationLikelihood(individualRiskInstance.ExploitationLikelihood)
 				if err != nil {
-					return nil, errors.New("unknown 'exploitation_likelihood' value of individual risk instance '" + title + "': " + fmt.Sprintf("%v", individualRiskInstance.ExploitationLikelihood))
+					fail(errors.New("unknown 'exploitation_likelihood' value of individual risk instance '"+title+"': "+fmt.Sprintf("%v", individualRiskInstance.ExploitationLikelihood)), "individual_risk_categories", categoryTitle, "risks_identified", title, "exploitation_likelihood")
 				}
 				exploitationImpact, err := types.ParseRiskExploitationImpact(individualRiskInstance.ExploitationImpact)
 				if err != nil {
-					return nil, errors.New("unknown 'exploitation_impact' value of individual risk instance '" + title + "': " + fmt.Sprintf("%v", individualRiskInstance.ExploitationImpact))
+					fail(errors.New("unknown 'exploitation_impact' value of individual risk instance '"+title+"': "+fmt.Sprintf("%v", individualRiskInstance.ExploitationImpact)), "individual_risk_categories", categoryTitle, "risks_identified", title, "exploitation_impact")
 				}
 
 				if len(individualRiskInstance.MostRelevantDataAsset) > 0 {
 					mostRelevantDataAssetId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantDataAsset)
 					err := parsedModel.CheckDataAssetTargetExists(mostRelevantDataAssetId, "individual risk '"+title+"'")
 					if err != nil {
-						return nil, err
+						fail(err, "individual_risk_categories", categoryTitle, "risks_identified", title, "most_relevant_data_asset")
 					}
 				}
 
@@ -585,7 +608,7 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 					mostRelevantTechnicalAssetId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantTechnicalAsset)
 					err := parsedModel.CheckTechnicalAssetExists(mostRelevantTechnicalAssetId, "individual risk '"+title+"'", false)
 					if err != nil {
-						return nil, err
+						fail(err, "individual_risk_categories", categoryTitle, "risks_identified", title, "most_relevant_technical_asset")
 					}
 				}
 
@@ -593,7 +616,7 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 					mostRelevantCommunicationLinkId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantCommunicationLink)
 					err := parsedModel.CheckCommunicationLinkExists(mostRelevantCommunicationLinkId, "individual risk '"+title+"'")
 					if err != nil {
-						return nil, err
+						fail(err, "individual_risk_categories", categoryTitle, "risks_identified", title, "most_relevant_communication_link")
 					}
 				}
 
@@ -601,7 +624,7 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 					mostRelevantTrustBoundaryId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantTrustBoundary)
 					err := parsedModel.CheckTrustBoundaryExists(mostRelevantTrustBoundaryId, "individual risk '"+title+"'")
 					if err != nil {
-						return nil, err
+						fail(err, "individual_risk_categories", categoryTitle, "risks_identified", title, "most_relevant_trust_boundary")
 					}
 				}
 
@@ -609,13 +632,13 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 					mostRelevantSharedRuntimeId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantSharedRuntime)
 					err := parsedModel.CheckSharedRuntimeExists(mostRelevantSharedRuntimeId, "individual risk '"+title+"'")
 					if err != nil {
-						return nil, err
+						fail(err, "individual_risk_categories", categoryTitle, "risks_identified", title, "most_relevant_shared_runtime")
 					}
 				}
 
 				dataBreachProbability, err = types.ParseDataBreachProbability(individualRiskInstance.DataBreachProbability)
 				if err != nil {
-					return nil, errors.New("unknown 'data_breach_probability' value of individual risk instance '" + title + "': " + fmt.Sprintf("%v", individualRiskInstance.DataBreachProbability))
+					fail(errors.New("unknown 'data_breach_probability' value of individual risk instance '"+title+"': "+fmt.Sprintf("%v", individualRiskInstance.DataBreachProbability)), "individual_risk_categories", categoryTitle, "risks_identified", title, "data_breach_probability")
 				}
 
 				if individualRiskInstance.DataBreachTechnicalAssets != nil {
@@ -624,7 +647,7 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 						assetId := fmt.Sprintf("%v", parsedReferencedAsset)
 						err := parsedModel.CheckTechnicalAssetExists(assetId, "data breach technical assets of individual risk '"+title+"'", false)
 						if err != nil {
-							return nil, err
+							fail(err, "individual_risk_categories", categoryTitle, "risks_identified", title, "data_breach_technical_assets", input.Item(assetId))
 						}
 						dataBreachTechnicalAssetIDs[i] = assetId
 					}
@@ -660,13 +683,30 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			var parseError error
 			date, parseError = time.Parse("2006-01-02", riskTracking.Date)
 			if parseError != nil {
-				return nil, errors.New("unable to parse 'date' of risk tracking '" + syntheticRiskId + "': " + riskTracking.Date)
+				fail(errors.New("unable to parse 'date' of risk tracking '"+syntheticRiskId+"': "+riskTracking.Date), "risk_tracking", syntheticRiskId, "date")
+			}
+		}
+
+		var expires time.Time
+		if len(riskTracking.Expires) > 0 {
+			var parseError error
+			expires, parseError = time.Parse("2006-01-02", riskTracking.Expires)
+			if parseError != nil {
+				fail(errors.New("unable to parse 'expires' of risk tracking '"+syntheticRiskId+"': "+riskTracking.Expires), "risk_tracking", syntheticRiskId, "expires")
+			}
+		}
+		var reviewBy time.Time
+		if len(riskTracking.ReviewBy) > 0 {
+			var parseError error
+			reviewBy, parseError = time.Parse("2006-01-02", riskTracking.ReviewBy)
+			if parseError != nil {
+				fail(errors.New("unable to parse 'review_by' of risk tracking '"+syntheticRiskId+"': "+riskTracking.ReviewBy), "risk_tracking", syntheticRiskId, "review_by")
 			}
 		}
 
 		status, err := types.ParseRiskStatus(riskTracking.Status)
 		if err != nil {
-			return nil, errors.New("unknown 'status' value of risk tracking '" + syntheticRiskId + "': " + riskTracking.Status)
+			fail(errors.New("unknown 'status' value of risk tracking '"+syntheticRiskId+"': "+riskTracking.Status), "risk_tracking", syntheticRiskId, "status")
 		}
 
 		tracking := types.RiskTracking{
@@ -674,7 +714,10 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			Justification:   justification,
 			CheckedBy:       checkedBy,
 			Ticket:          ticket,
+			Owner:           strings.TrimSpace(riskTracking.Owner),
 			Date:            types.Date{Time: date},
+			Expires:         types.Date{Time: expires},
+			ReviewBy:        types.Date{Time: reviewBy},
 			Status:          status,
 		}
 
@@ -686,10 +729,14 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 		for _, commLink := range technicalAsset.CommunicationLinks {
 			err := parsedModel.CheckTechnicalAssetExists(commLink.TargetId, "communication link '"+commLink.Title+"' of technical asset '"+technicalAsset.Title+"'", false)
 			if err != nil {
-				return nil, err
+				fail(err, "technical_assets", technicalAsset.Title, "communication_links", commLink.Title, "target")
 			}
 		}
 	}
+	if len(errs) > 0 {
+		input.SortErrors(errs)
+		return nil, errors.Join(errs...)
+	}
 
 	/*
 		data, _ := json.MarshalIndent(parsedModel, "", "  ")
@@ -713,15 +760,21 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 	return &parsedModel, nil
 }
 
-func checkIdSyntax(id string) error {
+// checkIdSyntax checks the id of an element, which is qualified with the name of its model only in the combined model of
+// a portfolio
+func checkIdSyntax(id string, qualified bool) error {
 	validIdSyntax := regexp.MustCompile(`^[a-zA-Z0-9\-]+$`)
+	if qualified {
+		validIdSyntax = regexp.MustCompile(`^[a-zA-Z0-9\-]+` + ModelReferenceSeparator + `[a-zA-Z0-9\-]+$`)
+	}
 	if !validIdSyntax.MatchString(id) {
 		return errors.New("invalid id syntax used (only letters, numbers, and hyphen allowed): " + id)
 	}
 	return nil
 }
 
-func createDataFlowId(sourceAssetId, title string) (string, error) {
+// CreateDataFlowId returns the id of a communication link, derived from the id of its source technical asset and its title
+func CreateDataFlowId(sourceAssetId, title string) (string, error) {
 	reg, err := regexp.Compile("[^A-Za-z0-9]+")
 	if err != nil {
 		return "", err
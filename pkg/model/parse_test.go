@@ -5,6 +5,8 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package model
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/uuid"
@@ -150,6 +152,59 @@ func TestInferAvailability_ExpectHighestAvailability(t *testing.T) {
 	assert.Equal(t, types.Operational, parsedModel.TechnicalAssets[taWithArchiveAvailabilityDataAsset.ID].Availability)
 }
 
+func TestParseModelReportsAllErrorsWithPositions(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.yaml"), []byte(`includes:
+  - assets.yaml
+business_criticality: archive
+data_assets:
+  Some Data:
+    id: some-data
+    usage: business
+    quantity: few
+    confidentiality: internal
+    integrity: critical
+    availability: hihg
+`), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "assets.yaml"), []byte(`technical_assets:
+  Some Server:
+    id: some-server
+    usage: business
+    type: process
+    size: system
+    technology: web-server
+    encryption: none
+    machine: virtual
+    confidentiality: internal
+    integrity: critical
+    availability: critical
+    data_assets_stored:
+      - some-data
+      - other-data
+    communication_links:
+      Some Traffic:
+        target: other-server
+        protocol: https
+        authentication: none
+        authorization: none
+        usage: business
+`), 0600))
+
+	modelInput := new(input.Model).Defaults()
+	assert.NoError(t, modelInput.Load(filepath.Join(dir, "main.yaml")))
+
+	_, err := ParseModel(modelInput, make(map[string]risks.RiskRule), make(map[string]*CustomRisk))
+	messages := make([]string, 0)
+	for _, e := range input.SplitErrors(err) {
+		messages = append(messages, e.Error())
+	}
+	assert.Equal(t, []string{
+		filepath.Join(dir, "assets.yaml") + ":15:9: missing referenced data asset target at technical asset 'Some Server': other-data",
+		filepath.Join(dir, "assets.yaml") + ":18:17: missing referenced technical asset target at communication link 'Some Traffic' of technical asset 'Some Server': other-server",
+		filepath.Join(dir, "main.yaml") + ":11:19: unknown 'availability' value of data asset 'Some Data': hihg",
+	}, messages)
+}
+
 func createInputModel(technicalAssets map[string]input.TechnicalAsset, dataAssets map[string]input.DataAsset) *input.Model {
 	return &input.Model{
 		TechnicalAssets: technicalAssets,
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type RiskPolicyViolation struct {
+	Message string
+	Risks   []types.Risk
+}
+
+// CheckRiskPolicy evaluates the policy against the overall risk statistics of the model and returns all violations
+func CheckRiskPolicy(policy common.RiskPolicy, parsedModel *types.ParsedModel) ([]RiskPolicyViolation, error) {
+	violations := make([]RiskPolicyViolation, 0)
+	if policy.IsEmpty() {
+		return violations, nil
+	}
+
+	statuses, statusError := riskPolicyStatuses(policy.Statuses)
+	if statusError != nil {
+		return nil, statusError
+	}
+
+	for name := range policy.MaxRisks {
+		if _, parseError := parseRiskPolicySeverity(name); parseError != nil {
+			return nil, parseError
+		}
+	}
+
+	statistics := types.OverallRiskStatistics(parsedModel)
+	countRisks := func(severity types.RiskSeverity) int {
+		count := 0
+		for _, status := range statuses {
+			count += statistics.Risks[severity.String()][status.String()]
+		}
+		return count
+	}
+
+	if len(policy.FailOnSeverity) > 0 {
+		threshold, parseError := parseRiskPolicySeverity(policy.FailOnSeverity)
+		if parseError != nil {
+			return nil, parseError
+		}
+
+		count := 0
+		for _, severity := range types.RiskSeverityValues() {
+			if severity.(types.RiskSeverity) >= threshold {
+				count += countRisks(severity.(types.RiskSeverity))
+			}
+		}
+		if count > 0 {
+			violations = append(violations, RiskPolicyViolation{
+				Message: fmt.Sprintf("%d risk(s) of severity %v or above", count, threshold.String()),
+				Risks:   risksMatchingPolicy(parsedModel, statuses, func(severity types.RiskSeverity) bool { return severity >= threshold }),
+			})
+		}
+	}
+
+	for _, value := range types.RiskSeverityValues() {
+		severity := value.(types.RiskSeverity)
+		maxCount, ok := maxRisksOfSeverity(policy.MaxRisks, severity)
+		if !ok {
+			continue
+		}
+
+		count := countRisks(severity)
+		if count > maxCount {
+			violations = append(violations, RiskPolicyViolation{
+				Message: fmt.Sprintf("%d risk(s) of severity %v, but at most %d allowed", count, severity.String(), maxCount),
+				Risks:   risksMatchingPolicy(parsedModel, statuses, func(candidate types.RiskSeverity) bool { return candidate == severity }),
+			})
+		}
+	}
+
+	if policy.FailOnExpiredRiskTracking {
+		expired := types.ExpiredRiskTrackings(parsedModel)
+		if len(expired) > 0 {
+			expiredRisks := make([]types.Risk, 0)
+			for _, syntheticRiskId := range expired {
+				expiredRisks = append(expiredRisks, parsedModel.GeneratedRisksBySyntheticId[syntheticRiskId])
+			}
+			types.SortByRiskSeverity(expiredRisks, parsedModel)
+			violations = append(violations, RiskPolicyViolation{
+				Message: fmt.Sprintf("%d risk tracking(s) expired", len(expired)),
+				Risks:   expiredRisks,
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+func riskPolicyStatuses(names []string) ([]types.RiskStatus, error) {
+	result := make([]types.RiskStatus, 0)
+	if len(names) == 0 {
+		for _, value := range types.RiskStatusValues() {
+			if value.(types.RiskStatus).IsStillAtRisk() {
+				result = append(result, value.(types.RiskStatus))
+			}
+		}
+		return result, nil
+	}
+
+	for _, name := range names {
+		status, parseError := types.ParseRiskStatus(strings.ToLower(strings.TrimSpace(name)))
+		if parseError != nil {
+			return nil, fmt.Errorf("invalid risk status %q in risk policy", name)
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+func parseRiskPolicySeverity(name string) (types.RiskSeverity, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) == 0 {
+		return types.LowSeverity, fmt.Errorf("empty risk severity in risk policy")
+	}
+
+	severity, parseError := types.ParseRiskSeverity(name)
+	if parseError != nil {
+		return severity, fmt.Errorf("invalid risk severity %q in risk policy", name)
+	}
+	return severity, nil
+}
+
+func maxRisksOfSeverity(maxRisks map[string]int, severity types.RiskSeverity) (int, bool) {
+	for name, count := range maxRisks {
+		if strings.EqualFold(strings.TrimSpace(name), severity.String()) {
+			return count, true
+		}
+	}
+	return 0, false
+}
+
+func risksMatchingPolicy(parsedModel *types.ParsedModel, statuses []types.RiskStatus, severityMatches func(types.RiskSeverity) bool) []types.Risk {
+	result := make([]types.Risk, 0)
+	for _, category := range types.SortedRiskCategories(parsedModel) {
+		for _, risk := range types.SortedRisksOfCategory(parsedModel, category) {
+			if !severityMatches(risk.Severity) {
+				continue
+			}
+			riskStatus := risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel)
+			for _, status := range statuses {
+				if riskStatus == status {
+					result = append(result, risk)
+					break
+				}
+			}
+		}
+	}
+	types.SortByRiskSeverity(result, parsedModel)
+	return result
+}
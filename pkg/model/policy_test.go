@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestCheckRiskPolicy(t *testing.T) {
+	parsedModel := &types.ParsedModel{
+		GeneratedRisksByCategory: map[string][]types.Risk{
+			"some-category": {
+				{CategoryId: "some-category", SyntheticId: "some-category@a", Severity: types.HighSeverity},
+				{CategoryId: "some-category", SyntheticId: "some-category@b", Severity: types.HighSeverity},
+				{CategoryId: "some-category", SyntheticId: "some-category@c", Severity: types.MediumSeverity},
+			},
+		},
+		BuiltInRiskCategories: map[string]types.RiskCategory{
+			"some-category": {Id: "some-category", Title: "Some Category"},
+		},
+		RiskTracking: map[string]types.RiskTracking{
+			"some-category@b": {SyntheticRiskId: "some-category@b", Status: types.Mitigated},
+		},
+	}
+
+	violations, err := CheckRiskPolicy(common.RiskPolicy{}, parsedModel)
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = CheckRiskPolicy(common.RiskPolicy{FailOnSeverity: "high"}, parsedModel)
+	assert.NoError(t, err)
+	assert.Len(t, violations, 1)
+	assert.Len(t, violations[0].Risks, 1)
+	assert.Equal(t, "some-category@a", violations[0].Risks[0].SyntheticId)
+
+	violations, err = CheckRiskPolicy(common.RiskPolicy{FailOnSeverity: "critical", MaxRisks: map[string]int{"high": 1, "medium": 0}}, parsedModel)
+	assert.NoError(t, err)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, types.MediumSeverity, violations[0].Risks[0].Severity)
+
+	violations, err = CheckRiskPolicy(common.RiskPolicy{MaxRisks: map[string]int{"high": 1}, Statuses: []string{"unchecked", "mitigated"}}, parsedModel)
+	assert.NoError(t, err)
+	assert.Len(t, violations, 1)
+	assert.Len(t, violations[0].Risks, 2)
+
+	violations, err = CheckRiskPolicy(common.RiskPolicy{FailOnExpiredRiskTracking: true}, parsedModel)
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	parsedModel.GeneratedRisksBySyntheticId = map[string]types.Risk{"some-category@b": parsedModel.GeneratedRisksByCategory["some-category"][1]}
+	parsedModel.RiskTracking["some-category@b"] = types.RiskTracking{SyntheticRiskId: "some-category@b", Expired: true, ExpiredStatus: types.Accepted}
+	violations, err = CheckRiskPolicy(common.RiskPolicy{FailOnExpiredRiskTracking: true}, parsedModel)
+	assert.NoError(t, err)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "some-category@b", violations[0].Risks[0].SyntheticId)
+
+	_, err = CheckRiskPolicy(common.RiskPolicy{FailOnSeverity: "severe"}, parsedModel)
+	assert.Error(t, err)
+	_, err = CheckRiskPolicy(common.RiskPolicy{FailOnSeverity: "high", Statuses: []string{"done"}}, parsedModel)
+	assert.Error(t, err)
+}
@@ -0,0 +1,398 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// ModelReferenceSeparator separates the name of a model of a portfolio from the id of one of its elements in a
+// reference to an element of another model, like payments-model#payment-api
+const ModelReferenceSeparator = "#"
+
+// SplitReference splits a reference into the name of the model and the id of the referenced element, the model name is
+// empty for references within the same model
+func SplitReference(reference string) (modelName string, id string) {
+	modelName, id, found := strings.Cut(reference, ModelReferenceSeparator)
+	if !found {
+		return "", reference
+	}
+	return modelName, id
+}
+
+// QualifyId returns the reference to an element of the given model, references to other models are kept as they are
+func QualifyId(modelName string, id string) string {
+	if len(id) == 0 || strings.Contains(id, ModelReferenceSeparator) {
+		return id
+	}
+	return modelName + ModelReferenceSeparator + id
+}
+
+// ExternalReferences describes the references of a model to technical assets of other models, as communication link
+// target or trust boundary member, which are skipped when analyzing the model on its own
+func ExternalReferences(modelInput *input.Model) []string {
+	modelNames := modelNamesOf(modelInput)
+	result := make([]string, 0)
+	for _, title := range sortedKeys(modelInput.TechnicalAssets) {
+		asset := modelInput.TechnicalAssets[title]
+		for _, linkTitle := range sortedKeys(asset.CommunicationLinks) {
+			if target := asset.CommunicationLinks[linkTitle].Target; isExternalReference(target, modelNames) {
+				result = append(result, "communication link '"+linkTitle+"' of technical asset '"+title+"' to "+target)
+			}
+		}
+	}
+	for _, title := range sortedKeys(modelInput.TrustBoundaries) {
+		for _, assetId := range modelInput.TrustBoundaries[title].TechnicalAssetsInside {
+			if isExternalReference(assetId, modelNames) {
+				result = append(result, "technical asset "+assetId+" at trust boundary '"+title+"'")
+			}
+		}
+	}
+	return result
+}
+
+// modelNamesOf returns the names of the models whose technical assets are part of a model, which are the models of a
+// portfolio for its combined model and none for a single model
+func modelNamesOf(modelInput *input.Model) map[string]bool {
+	result := make(map[string]bool)
+	if !modelInput.Portfolio {
+		return result
+	}
+	for _, asset := range modelInput.TechnicalAssets {
+		if modelName, _ := SplitReference(asset.ID); len(modelName) > 0 {
+			result[modelName] = true
+		}
+	}
+	return result
+}
+
+func isExternalReference(reference string, modelNames map[string]bool) bool {
+	modelName, _ := SplitReference(reference)
+	return len(modelName) > 0 && !modelNames[modelName]
+}
+
+// Portfolio is a directory of models whose elements may reference each other, analyzed as one combined model
+type Portfolio struct {
+	Name   string
+	Dir    string
+	Models []PortfolioModel
+}
+
+// PortfolioModel is a model of a portfolio, named after its model file or the directory containing it
+type PortfolioModel struct {
+	Name  string
+	File  string
+	Input *input.Model
+}
+
+// LoadPortfolio loads the models of a directory: each yaml file in it declaring a threagile_version and each
+// subdirectory containing a model file of the given name (like threagile.yaml), the values of the variables apply to all
+// of them
+func LoadPortfolio(dir string, modelFilename string, values map[string]string) (*Portfolio, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read portfolio directory: %w", err)
+	}
+
+	portfolio := &Portfolio{Name: filepath.Base(filepath.Clean(dir)), Dir: dir, Models: make([]PortfolioModel, 0)}
+	errs := make([]error, 0)
+	for _, entry := range entries {
+		var name, filename string
+		if entry.IsDir() {
+			filename = filepath.Join(dir, entry.Name(), modelFilename)
+			if _, statError := os.Stat(filename); statError != nil {
+				continue
+			}
+			name = entry.Name()
+		} else {
+			extension := filepath.Ext(entry.Name())
+			if extension != ".yaml" && extension != ".yml" {
+				continue
+			}
+			filename = filepath.Join(dir, entry.Name())
+			if !isModelFile(filename) { // e.g. included by a model or a values file
+				continue
+			}
+			name = strings.TrimSuffix(entry.Name(), extension)
+		}
+
+		if checkIdSyntax(name, false) != nil {
+			errs = append(errs, fmt.Errorf("invalid model name %q of %q (only letters, numbers, and hyphen allowed)", name, filename))
+			continue
+		}
+		for _, other := range portfolio.Models {
+			if other.Name == name {
+				errs = append(errs, fmt.Errorf("model files %q and %q give the same model name %q", other.File, filename, name))
+			}
+		}
+
+		modelInput := new(input.Model).Defaults()
+		loadError := modelInput.LoadWithVariables(filename, values)
+		if loadError != nil {
+			errs = append(errs, fmt.Errorf("unable to load model %q: %w", name, loadError))
+			continue
+		}
+		portfolio.Models = append(portfolio.Models, PortfolioModel{Name: name, File: filename, Input: modelInput})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(portfolio.Models) == 0 {
+		return nil, fmt.Errorf("no models found in portfolio directory %q", dir)
+	}
+	return portfolio, nil
+}
+
+func isModelFile(filename string) bool {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return false
+	}
+	var values map[string]any
+	if yaml.Unmarshal(data, &values) != nil {
+		return false
+	}
+	_, found := values["threagile_version"]
+	return found
+}
+
+// Combine merges the models of the portfolio into one model, qualifying the ids of all elements with the name of their
+// model (see QualifyId) and appending it to their titles, so that references between the models are resolved
+func (what *Portfolio) Combine() (*input.Model, error) {
+	modelNames := make(map[string]bool)
+	for _, portfolioModel := range what.Models {
+		modelNames[portfolioModel.Name] = true
+	}
+
+	combined := new(input.Model).Defaults()
+	combined.Title = "Portfolio " + what.Name
+	combined.Portfolio = true
+	combined.Positions = make(input.Positions)
+	description := new(strings.Builder)
+	description.WriteString("Combined model of the portfolio " + what.Name + ":")
+	criticality := types.Archive
+	errs := make([]error, 0)
+	for i, portfolioModel := range what.Models {
+		name, modelInput := portfolioModel.Name, portfolioModel.Input
+		qualify := func(id string) string {
+			return QualifyId(name, id)
+		}
+		qualifyAll := func(ids []string) []string {
+			if ids == nil {
+				return nil
+			}
+			result := make([]string, len(ids))
+			for j, id := range ids {
+				result[j] = qualify(id)
+			}
+			return result
+		}
+		checkModel := func(reference string, where string, path ...string) {
+			if referencedModel, _ := SplitReference(reference); len(referencedModel) > 0 && !modelNames[referencedModel] {
+				errs = append(errs, modelInput.Positions.Error(fmt.Errorf("unknown model %q referenced by %v", referencedModel, where), path...))
+			}
+		}
+		rename := func(section string, title string) string {
+			newTitle := title + " (" + name + ")"
+			combined.Positions.AddRenamed(modelInput.Positions, input.Path(section, title), input.Path(section, newTitle))
+			return newTitle
+		}
+
+		if i == 0 {
+			combined.ThreagileVersion = modelInput.ThreagileVersion
+			combined.Author = modelInput.Author
+		} else if len(modelInput.Author.Name) > 0 && !combined.Author.Match(modelInput.Author) {
+			combined.Contributors = append(combined.Contributors, modelInput.Author)
+		}
+		combined.Contributors = append(combined.Contributors, modelInput.Contributors...)
+		if modelInput.Date > combined.Date { // dates are formatted as 2006-01-02
+			combined.Date = modelInput.Date
+		}
+		if modelCriticality, err := types.ParseCriticality(modelInput.BusinessCriticality); err == nil && modelCriticality > criticality {
+			criticality = modelCriticality
+		}
+		description.WriteString("\n\n" + modelInput.Title + " (" + name + "): " + modelInput.AppDescription.Description)
+		for _, tag := range modelInput.TagsAvailable {
+			if !contains(combined.TagsAvailable, tag) {
+				combined.TagsAvailable = append(combined.TagsAvailable, tag)
+			}
+		}
+		technologies, err := new(input.Technology).MergeMap(combined.Technologies, modelInput.Technologies) // shared by name
+		if err != nil {
+			errs = append(errs, modelInput.Positions.Error(err, "technologies"))
+		}
+		combined.Technologies = technologies
+		for title, value := range modelInput.SecurityRequirements {
+			combined.SecurityRequirements[title+" ("+name+")"] = value
+		}
+		for title, value := range modelInput.Questions {
+			combined.Questions[title+" ("+name+")"] = value
+		}
+		for title, value := range modelInput.AbuseCases {
+			combined.AbuseCases[title+" ("+name+")"] = value
+		}
+
+		for title, dataAsset := range modelInput.DataAssets {
+			dataAsset.ID = qualify(dataAsset.ID)
+			combined.DataAssets[rename("data_assets", title)] = dataAsset
+		}
+
+		for title, technicalAsset := range modelInput.TechnicalAssets {
+			technicalAsset.ID = qualify(technicalAsset.ID)
+			technicalAsset.DataAssetsProcessed = qualifyAll(technicalAsset.DataAssetsProcessed)
+			technicalAsset.DataAssetsStored = qualifyAll(technicalAsset.DataAssetsStored)
+			communicationLinks := make(map[string]input.CommunicationLink)
+			for linkTitle, link := range technicalAsset.CommunicationLinks {
+				checkModel(link.Target, "communication link '"+linkTitle+"' of technical asset '"+title+"'", "technical_assets", title, "communication_links", linkTitle, "target")
+				link.Target = qualify(link.Target)
+				link.DataAssetsSent = qualifyAll(link.DataAssetsSent)
+				link.DataAssetsReceived = qualifyAll(link.DataAssetsReceived)
+				communicationLinks[linkTitle] = link // the ids of the links are derived from the qualified id of their asset
+			}
+			technicalAsset.CommunicationLinks = communicationLinks
+			combined.TechnicalAssets[rename("technical_assets", title)] = technicalAsset
+		}
+
+		for title, trustBoundary := range modelInput.TrustBoundaries {
+			for _, assetId := range trustBoundary.TechnicalAssetsInside {
+				checkModel(assetId, "trust boundary '"+title+"'", "trust_boundaries", title, "technical_assets_inside", input.Item(assetId))
+			}
+			trustBoundary.ID = qualify(trustBoundary.ID)
+			trustBoundary.TechnicalAssetsInside = qualifyAll(trustBoundary.TechnicalAssetsInside)
+			trustBoundary.TrustBoundariesNested = qualifyAll(trustBoundary.TrustBoundariesNested)
+			combined.TrustBoundaries[rename("trust_boundaries", title)] = trustBoundary
+		}
+
+		for title, sharedRuntime := range modelInput.SharedRuntimes {
+			sharedRuntime.ID = qualify(sharedRuntime.ID)
+			sharedRuntime.TechnicalAssetsRunning = qualifyAll(sharedRuntime.TechnicalAssetsRunning)
+			combined.SharedRuntimes[rename("shared_runtimes", title)] = sharedRuntime
+		}
+
+		individualCategories := make(map[string]bool)
+		for title, category := range modelInput.IndividualRiskCategories {
+			individualCategories[category.ID] = true
+			category.ID = qualify(category.ID)
+			risksIdentified := make(map[string]input.RiskIdentified)
+			for riskTitle, risk := range category.RisksIdentified {
+				risk.MostRelevantDataAsset = qualify(risk.MostRelevantDataAsset)
+				risk.MostRelevantTechnicalAsset = qualify(risk.MostRelevantTechnicalAsset)
+				risk.MostRelevantCommunicationLink = qualify(risk.MostRelevantCommunicationLink)
+				risk.MostRelevantTrustBoundary = qualify(risk.MostRelevantTrustBoundary)
+				risk.MostRelevantSharedRuntime = qualify(risk.MostRelevantSharedRuntime)
+				risk.DataBreachTechnicalAssets = qualifyAll(risk.DataBreachTechnicalAssets)
+				risksIdentified[riskTitle] = risk
+			}
+			category.RisksIdentified = risksIdentified
+			combined.IndividualRiskCategories[rename("individual_risk_categories", title)] = category
+		}
+
+		for syntheticRiskId, tracking := range modelInput.RiskTracking {
+			// the parts of a synthetic risk id are separated by @, the first one being the id of the risk category
+			parts := strings.Split(syntheticRiskId, "@")
+			for j := range parts {
+				if j > 0 || individualCategories[parts[j]] {
+					parts[j] = qualify(parts[j]) // a wildcard then only matches the elements of this model
+				}
+			}
+			qualifiedId := strings.Join(parts, "@")
+			combined.Positions.AddRenamed(modelInput.Positions, input.Path("risk_tracking", syntheticRiskId), input.Path("risk_tracking", qualifiedId))
+			combined.RiskTracking[qualifiedId] = tracking
+		}
+
+		for _, sameRank := range modelInput.DiagramTweakSameRankAssets {
+			combined.DiagramTweakSameRankAssets = append(combined.DiagramTweakSameRankAssets, strings.Join(qualifyAll(strings.Split(sameRank, ":")), ":"))
+		}
+		for _, invisibleConnection := range modelInput.DiagramTweakInvisibleConnectionsBetweenAssets {
+			combined.DiagramTweakInvisibleConnectionsBetweenAssets = append(combined.DiagramTweakInvisibleConnectionsBetweenAssets, strings.Join(qualifyAll(strings.Split(invisibleConnection, ":")), ":"))
+		}
+	}
+	if len(errs) > 0 {
+		input.SortErrors(errs)
+		return nil, errors.Join(errs...)
+	}
+
+	combined.BusinessCriticality = criticality.String()
+	combined.AppDescription.Description = description.String()
+	return combined, nil
+}
+
+// PortfolioStatistics are the statistics of the analysis of a portfolio, per model and organisation-wide
+type PortfolioStatistics struct {
+	Name    string            `yaml:"name" json:"name"`
+	Models  []ModelStatistics `yaml:"models" json:"models"`
+	Overall ModelStatistics   `yaml:"overall" json:"overall"`
+}
+
+// ModelStatistics are the statistics of a model of a portfolio (or all of them), the risks are counted by severity and
+// status like in stats.json and assigned to the model of their most relevant element
+type ModelStatistics struct {
+	Name                    string                    `yaml:"name" json:"name"`
+	Title                   string                    `yaml:"title" json:"title"`
+	DataAssets              int                       `yaml:"data_assets" json:"data_assets"`
+	TechnicalAssets         int                       `yaml:"technical_assets" json:"technical_assets"`
+	CommunicationLinks      int                       `yaml:"communication_links" json:"communication_links"`
+	CrossModelCommunication int                       `yaml:"cross_model_communication_links" json:"cross_model_communication_links"`
+	Risks                   map[string]map[string]int `yaml:"risks" json:"risks"`
+}
+
+// Statistics returns the statistics of the parsed and analyzed combined model of the portfolio
+func (what *Portfolio) Statistics(parsedModel *types.ParsedModel) *PortfolioStatistics {
+	result := &PortfolioStatistics{Name: what.Name, Models: make([]ModelStatistics, 0)}
+	risksByModel := make(map[string][]types.Risk)
+	for _, risk := range types.AllRisks(parsedModel) {
+		modelName := ModelOfRisk(risk)
+		risksByModel[modelName] = append(risksByModel[modelName], risk)
+	}
+
+	for _, portfolioModel := range what.Models {
+		statistics := ModelStatistics{Name: portfolioModel.Name, Title: portfolioModel.Input.Title}
+		for _, dataAsset := range parsedModel.DataAssets {
+			if modelName, _ := SplitReference(dataAsset.Id); modelName == portfolioModel.Name {
+				statistics.DataAssets++
+			}
+		}
+		for _, technicalAsset := range parsedModel.TechnicalAssets {
+			if modelName, _ := SplitReference(technicalAsset.Id); modelName == portfolioModel.Name {
+				statistics.TechnicalAssets++
+				for _, link := range technicalAsset.CommunicationLinks {
+					statistics.CommunicationLinks++
+					if targetModelName, _ := SplitReference(link.TargetId); targetModelName != modelName {
+						statistics.CrossModelCommunication++
+					}
+				}
+			}
+		}
+		statistics.Risks = types.CountRisksBySeverityAndStatus(parsedModel, risksByModel[portfolioModel.Name])
+		result.Models = append(result.Models, statistics)
+
+		result.Overall.DataAssets += statistics.DataAssets
+		result.Overall.TechnicalAssets += statistics.TechnicalAssets
+		result.Overall.CommunicationLinks += statistics.CommunicationLinks
+		result.Overall.CrossModelCommunication += statistics.CrossModelCommunication
+	}
+	result.Overall.Name = what.Name
+	result.Overall.Title = parsedModel.Title
+	result.Overall.Risks = types.CountRisksBySeverityAndStatus(parsedModel, types.AllRisks(parsedModel)) // including the ones of no model, e.g. of custom rules
+	return result
+}
+
+// ModelOfRisk returns the name of the model of a portfolio the most relevant element of a risk belongs to, empty if it
+// has none
+func ModelOfRisk(risk types.Risk) string {
+	for _, id := range []string{risk.MostRelevantTechnicalAssetId, risk.MostRelevantCommunicationLinkId, risk.MostRelevantDataAssetId,
+		risk.MostRelevantTrustBoundaryId, risk.MostRelevantSharedRuntimeId} {
+		if len(id) > 0 {
+			modelName, _ := SplitReference(id)
+			return modelName
+		}
+	}
+	return ""
+}
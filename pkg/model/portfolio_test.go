@@ -0,0 +1,196 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestAnalyzePortfolio(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "payments-model"), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "payments-model", "threagile.yaml"), []byte(paymentsModel), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "shop.yaml"), []byte(shopModel), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("env: prod\n"), 0600))
+
+	shop := new(input.Model).Defaults()
+	assert.NoError(t, shop.Load(filepath.Join(dir, "shop.yaml")))
+	assert.Empty(t, CheckReferences(shop))
+	assert.Equal(t, []string{"communication link 'Payment Call' of technical asset 'Shop Frontend' to payments-model#payment-api"}, ExternalReferences(shop))
+
+	portfolio, err := LoadPortfolio(dir, "threagile.yaml", nil)
+	assert.NoError(t, err)
+	assert.Len(t, portfolio.Models, 2)
+	combined, err := portfolio.Combine()
+	assert.NoError(t, err)
+	assert.Equal(t, "payments-model#payment-api", combined.TechnicalAssets["Shop Frontend (shop)"].CommunicationLinks["Payment Call"].Target)
+	assert.Equal(t, []string{"shop#order"}, combined.TechnicalAssets["Shop Frontend (shop)"].CommunicationLinks["Payment Call"].DataAssetsSent)
+	assert.Contains(t, combined.RiskTracking, "unencrypted-asset@payments-model#payment-api")
+	assert.Equal(t, "critical", combined.BusinessCriticality)
+	assert.Empty(t, CheckReferences(combined))
+	assert.Empty(t, ExternalReferences(combined))
+
+	result, err := AnalyzeModel(*new(common.Config).Defaults(""), combined, common.DefaultProgressReporter{})
+	assert.NoError(t, err)
+	assert.Contains(t, result.ParsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId, "payments-model#payment-api")
+	assert.Equal(t, types.Accepted, result.ParsedModel.RiskTracking["unencrypted-asset@payments-model#payment-api"].Status)
+
+	statistics := portfolio.Statistics(result.ParsedModel)
+	assert.Equal(t, "payments-model", statistics.Models[0].Name)
+	assert.Equal(t, 0, statistics.Models[0].CrossModelCommunication)
+	assert.Equal(t, 1, statistics.Models[0].Risks[types.MediumSeverity.String()][types.Accepted.String()])
+	assert.Equal(t, "shop", statistics.Models[1].Name)
+	assert.Equal(t, 1, statistics.Models[1].CrossModelCommunication)
+	assert.Equal(t, 2, statistics.Overall.TechnicalAssets)
+	total := 0
+	for _, counts := range statistics.Overall.Risks {
+		for _, count := range counts {
+			total += count
+		}
+	}
+	assert.Equal(t, types.TotalRiskCount(result.ParsedModel), total)
+}
+
+func TestCombinePortfolioReportsUnknownModels(t *testing.T) {
+	shop := new(input.Model).Defaults()
+	shop.TechnicalAssets["Shop Frontend"] = input.TechnicalAsset{ID: "shop-frontend", CommunicationLinks: map[string]input.CommunicationLink{
+		"Payment Call": {Target: "payment#payment-api"},
+	}}
+	portfolio := &Portfolio{Name: "some-portfolio", Models: []PortfolioModel{{Name: "shop", Input: shop}}}
+
+	_, err := portfolio.Combine()
+	assert.EqualError(t, err, `unknown model "payment" referenced by communication link 'Payment Call' of technical asset 'Shop Frontend'`)
+}
+
+func TestAnalyzeModelWarnsAboutReferencesToOtherModels(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "shop.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte(shopModel), 0600))
+	shop := new(input.Model).Defaults()
+	assert.NoError(t, shop.Load(filename))
+
+	reporter := new(recordingReporter)
+	_, err := AnalyzeModel(*new(common.Config).Defaults(""), shop, reporter)
+	assert.NoError(t, err)
+	assert.Contains(t, reporter.warnings, "Skipping reference to another model (resolved by "+common.AnalyzePortfolioCommand+"):"+
+		"communication link 'Payment Call' of technical asset 'Shop Frontend' to payments-model#payment-api")
+	assert.Empty(t, reporter.errors)
+}
+
+func TestQualifiedIdsOnlyInPortfolio(t *testing.T) {
+	assert.NoError(t, checkIdSyntax("payment-api", false))
+	assert.Error(t, checkIdSyntax("payments-model#payment-api", false))
+	assert.NoError(t, checkIdSyntax("payments-model#payment-api", true))
+	assert.Error(t, checkIdSyntax("payment-api", true)) // all ids of the combined model are qualified
+	assert.Error(t, checkIdSyntax("portfolio#payments-model#payment-api", true))
+
+	shop := new(input.Model).Defaults()
+	assert.NoError(t, shop.Parse("shop.yaml", []byte(shopModel)))
+	shop.TechnicalAssets["Shop Frontend"] = withId(shop.TechnicalAssets["Shop Frontend"], "payments-model#shop-frontend")
+	assert.Len(t, CheckReferences(shop), 1)
+	_, err := ParseModel(shop, nil, nil)
+	assert.ErrorContains(t, err, "invalid id syntax used (only letters, numbers, and hyphen allowed): payments-model#shop-frontend")
+}
+
+func withId(asset input.TechnicalAsset, id string) input.TechnicalAsset {
+	asset.ID = id
+	return asset
+}
+
+const paymentsModel = `threagile_version: 1.0.0
+title: Payments
+author:
+  name: Alice
+date: 2024-03-01
+business_criticality: critical
+application_description:
+  description: Payment processing
+tags_available: [pci]
+data_assets:
+  Card Data:
+    id: card-data
+    usage: business
+    quantity: many
+    confidentiality: strictly-confidential
+    integrity: critical
+    availability: critical
+technical_assets:
+  Payment API:
+    id: payment-api
+    type: process
+    usage: business
+    size: service
+    technology: web-service-rest
+    internet: false
+    machine: container
+    encryption: none
+    owner: Payments Team
+    confidentiality: strictly-confidential
+    integrity: critical
+    availability: critical
+    custom_developed_parts: true
+    data_assets_processed: [card-data]
+    data_formats_accepted: [json]
+trust_boundaries:
+  Payments Network:
+    id: payments-network
+    type: network-cloud-security-group
+    technical_assets_inside: [payment-api]
+shared_runtimes: {}
+risk_tracking:
+  unencrypted-asset@payment-api:
+    status: accepted
+    justification: internal only
+    ticket: PAY-1
+    date: 2024-03-01
+    checked_by: Alice
+`
+
+const shopModel = `threagile_version: 1.0.0
+title: Shop
+author:
+  name: Bob
+date: 2024-02-01
+business_criticality: important
+application_description:
+  description: Web shop
+tags_available: []
+data_assets:
+  Order:
+    id: order
+    usage: business
+    quantity: many
+    confidentiality: confidential
+    integrity: important
+    availability: important
+technical_assets:
+  Shop Frontend:
+    id: shop-frontend
+    type: process
+    usage: business
+    size: application
+    technology: web-server
+    internet: true
+    machine: container
+    encryption: none
+    owner: Shop Team
+    confidentiality: confidential
+    integrity: important
+    availability: important
+    custom_developed_parts: true
+    data_assets_processed: [order]
+    data_formats_accepted: [json]
+    communication_links:
+      Payment Call:
+        target: payments-model#payment-api
+        protocol: https
+        authentication: token
+        authorization: technical-user
+        usage: business
+        data_assets_sent: [order]
+shared_runtimes: {}
+`
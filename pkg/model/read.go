@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/attackpath"
 	"github.com/threagile/threagile/pkg/security/risks"
 	"github.com/threagile/threagile/pkg/security/types"
 )
@@ -23,6 +25,7 @@ type ReadResult struct {
 	IntroTextRAA     string
 	BuiltinRiskRules map[string]risks.RiskRule
 	CustomRiskRules  map[string]*CustomRisk
+	Portfolio        *PortfolioStatistics // of the models combined into the analyzed one, only set by analyze-portfolio
 }
 
 // TODO: consider about splitting this function into smaller ones for better reusability
@@ -30,24 +33,42 @@ func ReadAndAnalyzeModel(config common.Config, progressReporter progressReporter
 	progressReporter.Info("Writing into output directory:", config.OutputFolder)
 	progressReporter.Info("Parsing model:", config.InputFile)
 
+	modelInput := new(input.Model).Defaults()
+	loadError := modelInput.LoadWithVariables(config.InputFile, config.Variables)
+	if loadError != nil {
+		return nil, wrapModelErrors("unable to load model yaml", loadError)
+	}
+
+	return AnalyzeModel(config, modelInput, progressReporter)
+}
+
+// AnalyzeModel parses an already loaded model and applies RAA, risk generation and risk tracking to it
+func AnalyzeModel(config common.Config, modelInput *input.Model, progressReporter progressReporter) (*ReadResult, error) {
+	customRiskRules := LoadCustomRiskRules(config.RiskRulesPlugins, time.Duration(config.RiskRulesPluginTimeout)*time.Second, progressReporter)
+	defer CloseCustomRiskRules(customRiskRules, progressReporter)
+
+	return AnalyzeModelWithRules(config, modelInput, customRiskRules, progressReporter)
+}
+
+// AnalyzeModelWithRules is AnalyzeModel with custom risk rules already loaded (and kept running) by the caller,
+// the risk rules plugins of the config are not loaded again
+func AnalyzeModelWithRules(config common.Config, modelInput *input.Model, customRiskRules map[string]*CustomRisk, progressReporter progressReporter) (*ReadResult, error) {
 	builtinRiskRules := make(map[string]risks.RiskRule)
 	for _, rule := range risks.GetBuiltInRiskRules() {
 		builtinRiskRules[rule.Category().Id] = rule
 	}
-	customRiskRules := LoadCustomRiskRules(config.RiskRulesPlugins, progressReporter)
 
-	modelInput := new(input.Model).Defaults()
-	loadError := modelInput.Load(config.InputFile)
-	if loadError != nil {
-		return nil, fmt.Errorf("unable to load model yaml: %v", loadError)
+	parsedModel, parseError := ParseModel(WithTechnologies(modelInput, config.Technologies), builtinRiskRules, customRiskRules)
+	if parseError != nil {
+		return nil, wrapModelErrors("unable to parse model yaml", parseError)
 	}
 
-	parsedModel, parseError := ParseModel(modelInput, builtinRiskRules, customRiskRules)
-	if parseError != nil {
-		return nil, fmt.Errorf("unable to parse model yaml: %v", parseError)
+	for _, reference := range ExternalReferences(modelInput) {
+		progressReporter.Warn("Skipping reference to another model (resolved by "+common.AnalyzePortfolioCommand+"):", reference)
 	}
 
 	introTextRAA := applyRAA(parsedModel, config.BinFolder, config.RAAPlugin, progressReporter)
+	attackpath.Apply(parsedModel, config.MaxAttackPaths)
 
 	applyRiskGeneration(parsedModel, customRiskRules, builtinRiskRules,
 		config.SkipRiskRules, progressReporter)
@@ -70,6 +91,14 @@ func ReadAndAnalyzeModel(config common.Config, progressReporter progressReporter
 	}, nil
 }
 
+// wrapModelErrors keeps the errors found in a model accessible (see input.SplitErrors), one per line
+func wrapModelErrors(message string, err error) error {
+	if errs := input.SplitErrors(err); len(errs) > 1 {
+		return fmt.Errorf("%v (%d errors):\n%w", message, len(errs), err)
+	}
+	return fmt.Errorf("%v: %w", message, err)
+}
+
 func applyRisk(parsedModel *types.ParsedModel, rule risks.RiskRule, skippedRules *map[string]bool) {
 	id := rule.Category().Id
 	_, ok := (*skippedRules)[id]
@@ -117,7 +146,11 @@ func applyRiskGeneration(parsedModel *types.ParsedModel, customRiskRules map[str
 		} else {
 			progressReporter.Info("Executing custom risk rule:", id)
 			parsedModel.AddToListOfSupportedTags(customRule.Tags)
-			customRisks := customRule.GenerateRisks(parsedModel)
+			customRisks, generateError := customRule.GenerateRisks(parsedModel)
+			if generateError != nil {
+				progressReporter.Warn(fmt.Sprintf("WARNING: Custom risk rule %q failed: %v\n", id, generateError))
+				continue
+			}
 			if len(customRisks) > 0 {
 				parsedModel.GeneratedRisksByCategory[customRule.Category.Id] = customRisks
 			}
@@ -148,7 +181,7 @@ func applyRiskGeneration(parsedModel *types.ParsedModel, customRiskRules map[str
 func applyRAA(parsedModel *types.ParsedModel, binFolder, raaPlugin string, progressReporter progressReporter) string {
 	progressReporter.Info("Applying RAA calculation:", raaPlugin)
 
-	runner, loadError := new(runner).Load(filepath.Join(binFolder, raaPlugin))
+	runner, loadError := new(Runner).Load(filepath.Join(binFolder, raaPlugin))
 	if loadError != nil {
 		progressReporter.Warn(fmt.Sprintf("WARNING: raa %q not loaded: %v\n", raaPlugin, loadError))
 		return ""
@@ -0,0 +1,213 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// CheckReferences checks the ids of a loaded model and all references between its elements (by id or tag), returning
+// all errors found (with their positions as far as known) without building the whole parsed model like ParseModel does
+func CheckReferences(modelInput *input.Model) []error {
+	errs := make([]error, 0)
+	reported := make(map[string]bool)
+	add := func(err error, path ...string) {
+		if err == nil {
+			return
+		}
+		err = modelInput.Positions.Error(err, path...)
+		if !reported[err.Error()] { // e.g. a duplicate id of an element merged from several files
+			reported[err.Error()] = true
+			errs = append(errs, err)
+		}
+	}
+
+	// collect the ids first, as elements may reference each other regardless of their order
+	parsedModel := types.ParsedModel{
+		TagsAvailable:      lowerCaseAndTrim(modelInput.TagsAvailable),
+		DataAssets:         make(map[string]types.DataAsset),
+		TechnicalAssets:    make(map[string]types.TechnicalAsset),
+		CommunicationLinks: make(map[string]types.CommunicationLink),
+		TrustBoundaries:    make(map[string]types.TrustBoundary),
+		SharedRuntimes:     make(map[string]types.SharedRuntime),
+	}
+	modelNames := modelNamesOf(modelInput)
+	ids := make(map[string]map[string]bool)
+	checkId := func(kind string, id string, path ...string) {
+		add(checkIdSyntax(id, modelInput.Portfolio), path...)
+		if ids[kind] == nil {
+			ids[kind] = make(map[string]bool)
+		}
+		if ids[kind][id] {
+			add(errors.New("duplicate id used: "+id), path...)
+		}
+		ids[kind][id] = true
+	}
+	for _, title := range sortedKeys(modelInput.DataAssets) {
+		id := modelInput.DataAssets[title].ID
+		checkId("data asset", id, "data_assets", title, "id")
+		parsedModel.DataAssets[id] = types.DataAsset{Id: id, Title: title}
+	}
+	for _, title := range sortedKeys(modelInput.TechnicalAssets) {
+		asset := modelInput.TechnicalAssets[title]
+		checkId("technical asset", asset.ID, "technical_assets", title, "id")
+		parsedModel.TechnicalAssets[asset.ID] = types.TechnicalAsset{Id: asset.ID, Title: title}
+		for commLinkTitle := range asset.CommunicationLinks {
+			commLinkId, err := CreateDataFlowId(asset.ID, commLinkTitle)
+			if err == nil {
+				parsedModel.CommunicationLinks[commLinkId] = types.CommunicationLink{Id: commLinkId, Title: commLinkTitle}
+			}
+		}
+	}
+	for _, title := range sortedKeys(modelInput.TrustBoundaries) {
+		id := modelInput.TrustBoundaries[title].ID
+		checkId("trust boundary", id, "trust_boundaries", title, "id")
+		parsedModel.TrustBoundaries[id] = types.TrustBoundary{Id: id, Title: title}
+	}
+	for _, title := range sortedKeys(modelInput.SharedRuntimes) {
+		id := modelInput.SharedRuntimes[title].ID
+		checkId("shared runtime", id, "shared_runtimes", title, "id")
+		parsedModel.SharedRuntimes[id] = types.SharedRuntime{Id: id, Title: title}
+	}
+	for _, title := range sortedKeys(modelInput.IndividualRiskCategories) {
+		checkId("individual risk category", modelInput.IndividualRiskCategories[title].ID, "individual_risk_categories", title, "id")
+	}
+
+	technologies, technologyErrors := ResolveTechnologies(modelInput)
+	for _, err := range technologyErrors {
+		add(err)
+	}
+
+	checkTags := func(tags []string, where string, path ...string) {
+		for _, tag := range tags {
+			add(parsedModel.CheckTagExists(input.NormalizeTag(tag), where), append(path, input.Item(tag))...)
+		}
+	}
+
+	for _, title := range sortedKeys(modelInput.DataAssets) {
+		checkTags(modelInput.DataAssets[title].Tags, "data asset '"+title+"'", "data_assets", title, "tags")
+	}
+
+	for _, title := range sortedKeys(modelInput.TechnicalAssets) {
+		asset := modelInput.TechnicalAssets[title]
+		where := "technical asset '" + title + "'"
+		checkTags(asset.Tags, where, "technical_assets", title, "tags")
+		if _, declared := modelInput.Technologies[asset.Technology]; len(asset.Technology) > 0 && !declared { // otherwise already checked when resolved (or missing)
+			if _, _, err := parseTechnology(asset.Technology, technologies); err != nil {
+				add(errors.New("unknown 'technology' value of "+where+": "+asset.Technology), "technical_assets", title, "technology")
+			}
+		}
+		for _, referencedAsset := range asset.DataAssetsStored {
+			add(parsedModel.CheckDataAssetTargetExists(referencedAsset, where), "technical_assets", title, "data_assets_stored", input.Item(referencedAsset))
+		}
+		for _, referencedAsset := range asset.DataAssetsProcessed {
+			add(parsedModel.CheckDataAssetTargetExists(referencedAsset, where), "technical_assets", title, "data_assets_processed", input.Item(referencedAsset))
+		}
+		for _, commLinkTitle := range sortedKeys(asset.CommunicationLinks) {
+			commLink := asset.CommunicationLinks[commLinkTitle]
+			where := "communication link '" + commLinkTitle + "' of technical asset '" + title + "'"
+			path := []string{"technical_assets", title, "communication_links", commLinkTitle}
+			_, err := CreateDataFlowId(asset.ID, commLinkTitle)
+			add(err, path...)
+			checkTags(commLink.Tags, where, append(path, "tags")...)
+			if !isExternalReference(commLink.Target, modelNames) { // resolved when analyzing the portfolio of the models
+				add(parsedModel.CheckTechnicalAssetExists(commLink.Target, where, false), append(path, "target")...)
+			}
+			for _, referencedAsset := range commLink.DataAssetsSent {
+				add(parsedModel.CheckDataAssetTargetExists(referencedAsset, where), append(path, "data_assets_sent", input.Item(referencedAsset))...)
+			}
+			for _, referencedAsset := range commLink.DataAssetsReceived {
+				add(parsedModel.CheckDataAssetTargetExists(referencedAsset, where), append(path, "data_assets_received", input.Item(referencedAsset))...)
+			}
+		}
+	}
+
+	trustBoundaryOfTechnicalAsset := make(map[string]string)
+	for _, title := range sortedKeys(modelInput.TrustBoundaries) {
+		boundary := modelInput.TrustBoundaries[title]
+		checkTags(boundary.Tags, "trust boundary '"+title+"'", "trust_boundaries", title, "tags")
+		for _, assetId := range boundary.TechnicalAssetsInside {
+			if isExternalReference(assetId, modelNames) {
+				continue
+			}
+			if _, found := parsedModel.TechnicalAssets[assetId]; !found {
+				add(errors.New("missing referenced technical asset "+assetId+" at trust boundary '"+title+"'"), "trust_boundaries", title, "technical_assets_inside", input.Item(assetId))
+			}
+			if _, inside := trustBoundaryOfTechnicalAsset[assetId]; inside {
+				add(errors.New("referenced technical asset "+assetId+" at trust boundary '"+title+"' is modeled in multiple trust boundaries"), "trust_boundaries", title, "technical_assets_inside", input.Item(assetId))
+			}
+			trustBoundaryOfTechnicalAsset[assetId] = title
+		}
+		for _, nestedId := range boundary.TrustBoundariesNested {
+			if _, found := parsedModel.TrustBoundaries[nestedId]; !found {
+				add(errors.New("missing referenced nested trust boundary at trust boundary '"+title+"': "+nestedId), "trust_boundaries", title, "trust_boundaries_nested", input.Item(nestedId))
+			}
+		}
+	}
+
+	for _, title := range sortedKeys(modelInput.SharedRuntimes) {
+		runtime := modelInput.SharedRuntimes[title]
+		checkTags(runtime.Tags, "shared runtime '"+title+"'", "shared_runtimes", title, "tags")
+		for _, assetId := range runtime.TechnicalAssetsRunning {
+			add(parsedModel.CheckTechnicalAssetExists(assetId, "shared runtime '"+title+"'", false), "shared_runtimes", title, "technical_assets_running", input.Item(assetId))
+		}
+	}
+
+	for _, categoryTitle := range sortedKeys(modelInput.IndividualRiskCategories) {
+		category := modelInput.IndividualRiskCategories[categoryTitle]
+		for _, title := range sortedKeys(category.RisksIdentified) {
+			risk := category.RisksIdentified[title]
+			where := "individual risk '" + title + "'"
+			path := []string{"individual_risk_categories", categoryTitle, "risks_identified", title}
+			if len(risk.MostRelevantDataAsset) > 0 {
+				add(parsedModel.CheckDataAssetTargetExists(risk.MostRelevantDataAsset, where), append(path, "most_relevant_data_asset")...)
+			}
+			if len(risk.MostRelevantTechnicalAsset) > 0 {
+				add(parsedModel.CheckTechnicalAssetExists(risk.MostRelevantTechnicalAsset, where, false), append(path, "most_relevant_technical_asset")...)
+			}
+			if len(risk.MostRelevantCommunicationLink) > 0 {
+				add(parsedModel.CheckCommunicationLinkExists(risk.MostRelevantCommunicationLink, where), append(path, "most_relevant_communication_link")...)
+			}
+			if len(risk.MostRelevantTrustBoundary) > 0 {
+				add(parsedModel.CheckTrustBoundaryExists(risk.MostRelevantTrustBoundary, where), append(path, "most_relevant_trust_boundary")...)
+			}
+			if len(risk.MostRelevantSharedRuntime) > 0 {
+				add(parsedModel.CheckSharedRuntimeExists(risk.MostRelevantSharedRuntime, where), append(path, "most_relevant_shared_runtime")...)
+			}
+			for _, assetId := range risk.DataBreachTechnicalAssets {
+				add(parsedModel.CheckTechnicalAssetExists(assetId, "data breach technical assets of "+where, false), append(path, "data_breach_technical_assets", input.Item(assetId))...)
+			}
+		}
+	}
+
+	for _, sameRank := range modelInput.DiagramTweakSameRankAssets {
+		for _, assetId := range strings.Split(sameRank, ":") {
+			add(parsedModel.CheckTechnicalAssetExists(assetId, "diagram tweak same-rank", true), "diagram_tweak_same_rank_assets", input.Item(sameRank))
+		}
+	}
+	for _, invisibleConnection := range modelInput.DiagramTweakInvisibleConnectionsBetweenAssets {
+		assetIds := strings.Split(invisibleConnection, ":")
+		if len(assetIds) != 2 {
+			add(errors.New("invalid diagram tweak connection (expected format: <asset-id>:<asset-id>): "+invisibleConnection), "diagram_tweak_invisible_connections_between_assets", input.Item(invisibleConnection))
+			continue
+		}
+		for _, assetId := range assetIds {
+			add(parsedModel.CheckTechnicalAssetExists(assetId, "diagram tweak connections", true), "diagram_tweak_invisible_connections_between_assets", input.Item(invisibleConnection))
+		}
+	}
+
+	input.SortErrors(errs)
+	return errs
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func TestCheckReferences(t *testing.T) {
+	modelInput := &input.Model{
+		TagsAvailable: []string{"linux"},
+		DataAssets: map[string]input.DataAsset{
+			"Some Data": {ID: "some-data"},
+		},
+		TechnicalAssets: map[string]input.TechnicalAsset{
+			"Some Server": {
+				ID:               "some-server",
+				Tags:             []string{"Linux", "windows"},
+				DataAssetsStored: []string{"some-data", "other-data"},
+				CommunicationLinks: map[string]input.CommunicationLink{
+					"Some Traffic": {Target: "other-server", DataAssetsSent: []string{"other-data"}},
+				},
+			},
+		},
+		TrustBoundaries: map[string]input.TrustBoundary{
+			"Some Network": {ID: "some-network", TechnicalAssetsInside: []string{"some-server"}, TrustBoundariesNested: []string{"other-network"}},
+		},
+		IndividualRiskCategories: map[string]input.IndividualRiskCategory{
+			"Some Risk Category": {
+				ID: "some category",
+				RisksIdentified: map[string]input.RiskIdentified{
+					"Some Risk": {MostRelevantCommunicationLink: "some-server>some-traffic"},
+				},
+			},
+		},
+	}
+
+	messages := make([]string, 0)
+	for _, err := range CheckReferences(modelInput) {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		"invalid id syntax used (only letters, numbers, and hyphen allowed): some category",
+		"missing referenced tag in overall tag list at technical asset 'Some Server': windows",
+		"missing referenced data asset target at technical asset 'Some Server': other-data",
+		"missing referenced technical asset target at communication link 'Some Traffic' of technical asset 'Some Server': other-server",
+		"missing referenced data asset target at communication link 'Some Traffic' of technical asset 'Some Server': other-data",
+		"missing referenced nested trust boundary at trust boundary 'Some Network': other-network",
+	}, messages)
+}
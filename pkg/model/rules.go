@@ -1,51 +1,140 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"strings"
+	"time"
 
+	"github.com/threagile/threagile/pkg/plugin"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/risks/declarative"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
+const (
+	pluginShutdownTimeout  = 5 * time.Second
+	pluginHandshakeTimeout = 5 * time.Second // plugins not answering the handshake in time don't speak the protocol
+)
+
 type CustomRisk struct {
 	ID       string
 	Category types.RiskCategory
 	Tags     []string
-	Runner   *runner
+	Runner   *Runner
+	Plugin   *plugin.Client    `json:"-"` // set when the plugin supports the long-lived protocol, otherwise Runner is used per call
+	Timeout  time.Duration     `json:"-"`
+	Rule     *declarative.Rule `json:"-"` // set for declarative rules loaded from YAML files, which run in-process
 }
 
-func (r *CustomRisk) GenerateRisks(m *types.ParsedModel) []types.Risk {
-	if r.Runner == nil {
-		return nil
+func (r *CustomRisk) GenerateRisks(m *types.ParsedModel) ([]types.Risk, error) {
+	if r.Rule != nil {
+		return r.Rule.GenerateRisks(m)
 	}
 
 	risks := make([]types.Risk, 0)
+	if r.Plugin != nil {
+		ctx, cancel := r.context()
+		defer cancel()
+
+		callError := r.Plugin.Call(ctx, plugin.GenerateRisksMethod, m, &risks)
+		if callError != nil {
+			return nil, fmt.Errorf("failed to generate risks for custom risk rule %q: %w", r.Plugin.Filename, callError)
+		}
+
+		return risks, nil
+	}
+
+	if r.Runner == nil {
+		return nil, fmt.Errorf("custom risk rule %q is not loaded", r.ID)
+	}
+
 	runError := r.Runner.Run(m, &risks, "-generate-risks")
 	if runError != nil {
-		log.Fatalf("Failed to generate risks for custom risk rule %q: %v\n", r.Runner.Filename, runError)
+		return nil, fmt.Errorf("failed to generate risks for custom risk rule %q: %w", r.Runner.Filename, runError)
 	}
 
-	return risks
+	return risks, nil
 }
 
-func LoadCustomRiskRules(pluginFiles []string, reporter progressReporter) map[string]*CustomRisk {
+// Close stops the plugin process of a custom risk rule loaded via the long-lived protocol
+func (r *CustomRisk) Close() error {
+	if r.Plugin == nil {
+		return nil
+	}
+
+	closeError := r.Plugin.Close(pluginShutdownTimeout)
+	r.Plugin = nil
+	return closeError
+}
+
+func (r *CustomRisk) context() (context.Context, context.CancelFunc) {
+	if r.Timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), r.Timeout)
+}
+
+func LoadCustomRiskRules(pluginFiles []string, timeout time.Duration, reporter progressReporter) map[string]*CustomRisk {
 	customRiskRuleList := make([]string, 0)
 	customRiskRules := make(map[string]*CustomRisk)
 	if len(pluginFiles) > 0 {
 		reporter.Info("Loading custom risk rules:", strings.Join(pluginFiles, ", "))
 
+		builtInRiskRuleIds := make(map[string]bool)
+		for _, rule := range risks.GetBuiltInRiskRules() {
+			builtInRiskRuleIds[rule.Category().Id] = true
+		}
+
 		for _, pluginFile := range pluginFiles {
-			if len(pluginFile) > 0 {
-				runner, loadError := new(runner).Load(pluginFile)
+			if len(pluginFile) > 0 && declarative.IsRuleFile(pluginFile) {
+				rules, loadError := declarative.LoadRules(pluginFile)
 				if loadError != nil {
-					reporter.Error(fmt.Sprintf("WARNING: Custom risk rule %q not loaded: %v\n", pluginFile, loadError))
+					reporter.Warn(fmt.Sprintf("WARNING: Declarative risk rules %q not loaded: %v\n", pluginFile, loadError))
+					continue
 				}
 
-				risk := new(CustomRisk)
-				runError := runner.Run(nil, &risk, "-get-info")
-				if runError != nil {
-					reporter.Error(fmt.Sprintf("WARNING: Failed to get info for custom risk rule %q: %v\n", pluginFile, runError))
+				for _, rule := range rules {
+					if builtInRiskRuleIds[rule.Category().Id] {
+						reporter.Warn(fmt.Sprintf("WARNING: Declarative risk rule %q of %q not loaded: the id is used by a built-in risk rule\n", rule.Category().Id, pluginFile))
+						continue
+					}
+					customRiskRules[rule.Category().Id] = &CustomRisk{
+						ID:       rule.Category().Id,
+						Category: rule.Category(),
+						Tags:     rule.SupportedTags(),
+						Rule:     rule,
+					}
+					customRiskRuleList = append(customRiskRuleList, rule.Category().Id)
+					reporter.Info("Declarative risk rule loaded:", rule.Category().Id)
+				}
+			} else if len(pluginFile) > 0 {
+				runner, loadError := new(Runner).Load(pluginFile)
+				if loadError != nil {
+					reporter.Warn(fmt.Sprintf("WARNING: Custom risk rule %q not loaded: %v\n", pluginFile, loadError))
+					continue
+				}
+
+				risk, pluginError := loadPluginRiskRule(pluginFile, timeout)
+				if errors.Is(pluginError, context.DeadlineExceeded) {
+					reporter.Error(fmt.Sprintf("ERROR: Custom risk rule %q did not answer in time: %v\n", pluginFile, pluginError))
+					continue
+				}
+				if pluginError != nil {
+					reporter.Warn(fmt.Sprintf("WARNING: Failed to get info for custom risk rule %q: %v\n", pluginFile, pluginError))
+					continue
+				}
+
+				if risk == nil {
+					reporter.Info("Custom risk rule does not support the plugin protocol, running it per call:", pluginFile)
+
+					risk = &CustomRisk{Timeout: timeout}
+					runError := runner.Run(nil, &risk, "-get-info")
+					if runError != nil {
+						reporter.Warn(fmt.Sprintf("WARNING: Failed to get info for custom risk rule %q: %v\n", pluginFile, runError))
+						continue
+					}
 				}
 
 				risk.Runner = runner
@@ -60,3 +149,54 @@ func LoadCustomRiskRules(pluginFiles []string, reporter progressReporter) map[st
 
 	return customRiskRules
 }
+
+func CloseCustomRiskRules(customRiskRules map[string]*CustomRisk, reporter progressReporter) {
+	for id, customRule := range customRiskRules {
+		closeError := customRule.Close()
+		if closeError != nil {
+			reporter.Warn(fmt.Sprintf("WARNING: Failed to stop custom risk rule %q: %v\n", id, closeError))
+		}
+	}
+}
+
+// loadPluginRiskRule starts the plugin in serve mode; a nil rule without error means the plugin does not speak
+// the long-lived protocol and has to be run in compatibility mode (-get-info / -generate-risks per call)
+func loadPluginRiskRule(pluginFile string, timeout time.Duration) (*CustomRisk, error) {
+	client, startError := plugin.Start(pluginFile)
+	if startError != nil {
+		return nil, startError
+	}
+
+	if !handshake(client, timeout) {
+		_ = client.Close(pluginHandshakeTimeout)
+		return nil, nil
+	}
+
+	risk := &CustomRisk{Plugin: client, Timeout: timeout}
+	ctx, cancel := risk.context()
+	defer cancel()
+
+	callError := client.Call(ctx, plugin.GetInfoMethod, nil, risk)
+	if callError != nil {
+		_ = client.Close(pluginShutdownTimeout)
+		return nil, callError
+	}
+
+	risk.Plugin = client
+	return risk, nil
+}
+
+// handshake tells whether the plugin speaks the long-lived protocol: plugins exiting on the unknown serve
+// parameter or waiting for input in compatibility mode don't answer the handshake (in time)
+func handshake(client *plugin.Client, timeout time.Duration) bool {
+	handshakeTimeout := pluginHandshakeTimeout
+	if timeout > 0 && timeout < handshakeTimeout {
+		handshakeTimeout = timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+
+	callError := client.Call(ctx, plugin.HandshakeMethod, nil, nil)
+	var rpcError *plugin.Error
+	return callError == nil || errors.As(callError, &rpcError) // any structured answer comes from the protocol
+}
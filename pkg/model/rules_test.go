@@ -0,0 +1,142 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/plugin"
+)
+
+// the test binary acts as custom risk rule plugin when started with this environment variable set to one of the
+// test plugin modes
+const testRiskRulePluginEnvironmentVariable = "THREAGILE_TEST_RISK_RULE_PLUGIN"
+
+const (
+	servingTestRiskRulePlugin = "serve" // speaks the protocol
+	slowTestRiskRulePlugin    = "slow"  // speaks the protocol, but never answers the info request
+	oldTestRiskRulePlugin     = "old"   // ignores the serve parameter and waits for the end of its input
+)
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(testRiskRulePluginEnvironmentVariable); len(mode) > 0 {
+		os.Exit(runTestRiskRulePlugin(mode, os.Args[1:]))
+	}
+	os.Exit(m.Run())
+}
+
+func runTestRiskRulePlugin(mode string, args []string) int {
+	info := CustomRisk{ID: "test-rule-" + mode}
+	info.Category.Id = info.ID
+	if mode == oldTestRiskRulePlugin {
+		if len(args) == 1 && args[0] == "-get-info" {
+			_ = json.NewEncoder(os.Stdout).Encode(info)
+			return 0
+		}
+		_, _ = io.ReadAll(os.Stdin)
+		return 2
+	}
+
+	serveError := plugin.Serve(os.Stdin, os.Stdout, func(ctx context.Context, method string, params json.RawMessage) (any, error) {
+		if method != plugin.GetInfoMethod {
+			return nil, plugin.NewError(plugin.MethodNotFoundCode, "unknown method %q", method)
+		}
+		if mode == slowTestRiskRulePlugin {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return info, nil
+	})
+	if serveError != nil {
+		return 2
+	}
+	return 0
+}
+
+type recordingReporter struct {
+	warnings []string
+	errors   []string
+}
+
+func (r *recordingReporter) Info(a ...any) {}
+
+func (r *recordingReporter) Warn(a ...any) {
+	r.warnings = append(r.warnings, fmt.Sprint(a...))
+}
+
+func (r *recordingReporter) Error(a ...any) {
+	r.errors = append(r.errors, fmt.Sprint(a...))
+}
+
+func TestLoadCustomRiskRulesSkipsFailingRules(t *testing.T) {
+	dir := t.TempDir()
+	brokenRules := filepath.Join(dir, "broken-rules.yaml")
+	assert.NoError(t, os.WriteFile(brokenRules, []byte("risk_rules:\n  - where: technology ==\n"), 0600))
+
+	reporter := new(recordingReporter)
+	rules := LoadCustomRiskRules([]string{filepath.Join(dir, "missing-plugin"), brokenRules}, time.Second, reporter)
+
+	assert.Empty(t, rules)
+	assert.Empty(t, reporter.errors) // which would stop the analysis (or the server)
+	assert.Len(t, reporter.warnings, 2)
+}
+
+func TestLoadCustomRiskRulesSkipsDeclarativeRulesWithBuiltInIds(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "rules.yaml")
+	assert.NoError(t, os.WriteFile(rulesFile, []byte(`
+risk_rules:
+  - category: {id: sql-nosql-injection, title: Own SQL Injection}
+    select: technical-asset
+  - category: {id: own-rule, title: Own Rule}
+    select: technical-asset
+`), 0600))
+
+	reporter := new(recordingReporter)
+	rules := LoadCustomRiskRules([]string{rulesFile}, time.Second, reporter)
+
+	assert.Contains(t, rules, "own-rule")
+	assert.NotContains(t, rules, "sql-nosql-injection") // which would replace the built-in rule
+	assert.Empty(t, reporter.errors)
+	assert.Len(t, reporter.warnings, 1)
+	assert.Contains(t, fmt.Sprint(reporter.warnings), "sql-nosql-injection")
+}
+
+func TestLoadCustomRiskRulesDetectsThePluginProtocol(t *testing.T) {
+	for _, test := range []struct {
+		mode   string
+		serves bool
+	}{
+		{servingTestRiskRulePlugin, true},
+		{oldTestRiskRulePlugin, false}, // run per call after the handshake timed out
+	} {
+		t.Setenv(testRiskRulePluginEnvironmentVariable, test.mode)
+		reporter := new(recordingReporter)
+		rules := LoadCustomRiskRules([]string{os.Args[0]}, time.Second, reporter)
+
+		rule := rules["test-rule-"+test.mode]
+		if assert.NotNil(t, rule, test.mode) {
+			assert.Equal(t, test.serves, rule.Plugin != nil, test.mode)
+			assert.NotNil(t, rule.Runner, test.mode)
+		}
+		assert.Empty(t, reporter.errors, test.mode)
+		assert.Empty(t, reporter.warnings, test.mode)
+		CloseCustomRiskRules(rules, reporter)
+	}
+}
+
+func TestLoadCustomRiskRulesReportsTimeouts(t *testing.T) {
+	t.Setenv(testRiskRulePluginEnvironmentVariable, slowTestRiskRulePlugin)
+	reporter := new(recordingReporter)
+	rules := LoadCustomRiskRules([]string{os.Args[0]}, time.Second, reporter)
+
+	assert.Empty(t, rules)
+	assert.Len(t, reporter.errors, 1) // no fallback to running the plugin per call
+	assert.Contains(t, fmt.Sprint(reporter.errors), "did not answer in time")
+}
@@ -9,7 +9,8 @@ import (
 	"os/exec"
 )
 
-type runner struct {
+// Runner executes a plugin once per call, passing the input as JSON via stdin and reading the output as JSON from stdout
+type Runner struct {
 	Filename    string
 	Parameters  []string
 	In          any
@@ -17,8 +18,8 @@ type runner struct {
 	ErrorOutput string
 }
 
-func (p *runner) Load(filename string) (*runner, error) {
-	*p = runner{
+func (p *Runner) Load(filename string) (*Runner, error) {
+	*p = Runner{
 		Filename: filename,
 	}
 
@@ -34,8 +35,8 @@ func (p *runner) Load(filename string) (*runner, error) {
 	return p, nil
 }
 
-func (p *runner) Run(in any, out any, parameters ...string) error {
-	*p = runner{
+func (p *Runner) Run(in any, out any, parameters ...string) error {
+	*p = Runner{
 		Filename:   p.Filename,
 		Parameters: parameters,
 		In:         in,
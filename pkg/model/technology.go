@@ -0,0 +1,151 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Technology is a technology declared in the technologies section of a model (or the config), resolved to the
+// built-in technology it is a kind of
+type Technology struct {
+	Name        string
+	Description string
+	Parent      string
+	BuiltIn     types.TechnicalAssetTechnology // the built-in ancestor, compared to by rules checking for a technology
+	Traits      map[string]bool                // declared for the technology or its declared ancestors, the closest one winning
+}
+
+// Has tells whether the technology has a trait, as declared or else by default for its built-in ancestor
+func (what Technology) Has(trait types.TechnologyTrait) bool {
+	if value, found := what.Traits[trait.String()]; found {
+		return value
+	}
+	return what.BuiltIn.Has(trait)
+}
+
+// TraitNames returns the names of the traits the technology has
+func (what Technology) TraitNames() []string {
+	names := make([]string, 0)
+	for _, trait := range types.TechnologyTraitValues() {
+		if what.Has(trait.(types.TechnologyTrait)) {
+			names = append(names, trait.String())
+		}
+	}
+	return names
+}
+
+// WithTechnologies returns a copy of a model with technologies (e.g. of the config) added, unless declared by the
+// model itself, leaving the model as it is, e.g. to be written back by macros
+func WithTechnologies(modelInput *input.Model, technologies map[string]input.Technology) *input.Model {
+	if len(technologies) == 0 {
+		return modelInput
+	}
+	result := *modelInput
+	result.Technologies = make(map[string]input.Technology)
+	for name, technology := range technologies {
+		result.Technologies[name] = technology
+	}
+	for name, technology := range modelInput.Technologies {
+		result.Technologies[name] = technology
+	}
+	return &result
+}
+
+// ResolveTechnologies resolves the technologies declared by a model to their built-in ancestors, returning all errors
+// found (with their positions as far as known) like unknown parents, cycles or unknown traits
+func ResolveTechnologies(modelInput *input.Model) (map[string]Technology, []error) {
+	errs := make([]error, 0)
+	reported := make(map[string]bool)
+	resolved := make(map[string]Technology)
+	for _, name := range sortedKeys(modelInput.Technologies) {
+		technology, err := resolveTechnology(modelInput, name, resolved, make(map[string]bool))
+		if err != nil {
+			if !reported[err.Error()] { // e.g. the error of a parent
+				reported[err.Error()] = true
+				errs = append(errs, err)
+			}
+			continue
+		}
+		resolved[name] = technology
+	}
+	input.SortErrors(errs)
+	return resolved, errs
+}
+
+func resolveTechnology(modelInput *input.Model, name string, resolved map[string]Technology, visiting map[string]bool) (Technology, error) {
+	if technology, found := resolved[name]; found {
+		return technology, nil
+	}
+	fail := func(err error, path ...string) (Technology, error) {
+		return Technology{}, modelInput.Positions.Error(err, append([]string{"technologies", name}, path...)...)
+	}
+
+	if _, err := types.ParseTechnicalAssetTechnology(name); err == nil {
+		return fail(fmt.Errorf("technology %q is already a built-in one", name))
+	}
+	if visiting[name] {
+		return fail(fmt.Errorf("technology %q is its own ancestor", name), "parent")
+	}
+	visiting[name] = true
+
+	declared := modelInput.Technologies[name]
+	technology := Technology{
+		Name:        name,
+		Description: declared.Description,
+		Parent:      declared.Parent,
+		Traits:      make(map[string]bool),
+	}
+	if builtIn, err := types.ParseTechnicalAssetTechnology(declared.Parent); err == nil {
+		technology.BuiltIn = builtIn
+	} else if _, found := modelInput.Technologies[declared.Parent]; found {
+		parent, err := resolveTechnology(modelInput, declared.Parent, resolved, visiting)
+		if err != nil {
+			return Technology{}, err
+		}
+		technology.BuiltIn = parent.BuiltIn
+		for trait, value := range parent.Traits {
+			technology.Traits[trait] = value
+		}
+	} else {
+		return fail(fmt.Errorf("unknown parent technology %q of technology %q", declared.Parent, name), "parent")
+	}
+
+	traits := make([]string, 0, len(declared.Traits))
+	for trait := range declared.Traits {
+		traits = append(traits, trait)
+	}
+	sort.Strings(traits)
+	for _, trait := range traits {
+		if _, err := types.ParseTechnologyTrait(trait); err != nil {
+			return fail(fmt.Errorf("unknown trait %q of technology %q (expected one of: %v)", trait, name, traitNames()), "traits", trait)
+		}
+		technology.Traits[trait] = declared.Traits[trait]
+	}
+
+	resolved[name] = technology
+	return technology, nil
+}
+
+// parseTechnology parses the technology of a technical asset, either a built-in one or one declared by the model
+func parseTechnology(value string, technologies map[string]Technology) (types.TechnicalAssetTechnology, *Technology, error) {
+	if builtIn, err := types.ParseTechnicalAssetTechnology(value); err == nil {
+		return builtIn, nil, nil
+	}
+	if technology, found := technologies[strings.TrimSpace(value)]; found {
+		return technology.BuiltIn, &technology, nil
+	}
+	return types.UnknownTechnology, nil, errors.New("unknown technology " + value)
+}
+
+func traitNames() string {
+	names := make([]string, 0)
+	for _, trait := range types.TechnologyTraitValues() {
+		names = append(names, trait.String())
+	}
+	return strings.Join(names, ", ")
+}
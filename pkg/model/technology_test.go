@@ -0,0 +1,61 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestParseModelWithDeclaredTechnologies(t *testing.T) {
+	asset := createTechnicalAsset(types.Internal, types.Operational, types.Operational)
+	asset.Technology = "edge-router"
+	modelInput := createInputModel(map[string]input.TechnicalAsset{asset.ID: asset}, make(map[string]input.DataAsset))
+	modelInput.Technologies = map[string]input.Technology{
+		"edge-proxy":  {Parent: "reverse-proxy", Traits: map[string]bool{"traffic-forwarding": false, "web-application": true}},
+		"edge-router": {Parent: "edge-proxy", Traits: map[string]bool{"web-application": false}},
+	}
+
+	parsedModel, err := ParseModel(modelInput, make(map[string]risks.RiskRule), make(map[string]*CustomRisk))
+	assert.NoError(t, err)
+	parsedAsset := parsedModel.TechnicalAssets[asset.ID]
+	assert.Equal(t, types.ReverseProxy, parsedAsset.Technology)
+	assert.Equal(t, "edge-router", parsedAsset.TechnologyName())
+	assert.False(t, parsedAsset.HasTrait(types.TrafficForwardingTrait))
+	assert.False(t, parsedAsset.HasTrait(types.WebApplicationTrait))
+	assert.True(t, parsedAsset.HasTrait(types.ExclusivelyFrontendRelatedTrait))
+}
+
+func TestResolveTechnologiesReportsErrors(t *testing.T) {
+	asset := createTechnicalAsset(types.Internal, types.Operational, types.Operational)
+	asset.Technology = "some-technology"
+	modelInput := createInputModel(map[string]input.TechnicalAsset{"Some Asset": asset}, make(map[string]input.DataAsset))
+	modelInput.Technologies = map[string]input.Technology{
+		"database":          {Parent: "file-server"},
+		"looping":           {Parent: "looping"},
+		"orphan":            {Parent: "unknown"},
+		"overly-trusted":    {Parent: "vault", Traits: map[string]bool{"trusted": true}},
+		"well-defined-tool": {Parent: "tool", Traits: map[string]bool{"development-relevant": true}},
+	}
+
+	messages := make([]string, 0)
+	for _, err := range CheckReferences(modelInput) {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		`technology "database" is already a built-in one`,
+		`technology "looping" is its own ancestor`,
+		`unknown parent technology "unknown" of technology "orphan"`,
+		`unknown trait "trusted" of technology "overly-trusted" (expected one of: ` + traitNames() + `)`,
+		"unknown 'technology' value of technical asset 'Some Asset': some-technology",
+	}, messages)
+
+	technologies, _ := ResolveTechnologies(WithTechnologies(modelInput, map[string]input.Technology{
+		"well-defined-tool": {Parent: "cli"},
+		"build-tool":        {Parent: "well-defined-tool"},
+	}))
+	assert.Equal(t, types.Tool, technologies["build-tool"].BuiltIn) // the model wins over the config
+	assert.True(t, technologies["build-tool"].Has(types.DevelopmentRelevantTrait))
+}
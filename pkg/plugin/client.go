@@ -0,0 +1,223 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+var ErrClosed = errors.New("plugin connection closed")
+
+// Client talks to a single long-lived plugin process
+type Client struct {
+	Filename string
+
+	command *exec.Cmd
+	stdin   io.WriteCloser
+	stderr  syncBuffer
+
+	writeMutex sync.Mutex
+	mutex      sync.Mutex
+	nextId     int64
+	pending    map[int64]chan Response
+	closed     chan struct{}
+	readError  error
+}
+
+// Start runs the plugin in serve mode and begins reading its responses
+func Start(filename string) (*Client, error) {
+	client := &Client{
+		Filename: filename,
+		pending:  make(map[int64]chan Response),
+		closed:   make(chan struct{}),
+	}
+
+	client.command = exec.Command(filename, ServeParameter) // #nosec G204
+	client.command.Stderr = &client.stderr
+
+	stdin, stdinError := client.command.StdinPipe()
+	if stdinError != nil {
+		return nil, stdinError
+	}
+	client.stdin = stdin
+
+	stdout, stdoutError := client.command.StdoutPipe()
+	if stdoutError != nil {
+		return nil, stdoutError
+	}
+
+	startError := client.command.Start()
+	if startError != nil {
+		return nil, startError
+	}
+
+	go client.readResponses(bufio.NewReader(stdout))
+
+	return client, nil
+}
+
+// Call sends a request and waits for its response, the context's deadline or cancellation, or the plugin to exit
+func (c *Client) Call(ctx context.Context, method string, params any, result any) error {
+	c.mutex.Lock()
+	if c.readError != nil {
+		c.mutex.Unlock()
+		return c.closedError()
+	}
+	c.nextId++
+	id := c.nextId
+	responseChannel := make(chan Response, 1)
+	c.pending[id] = responseChannel
+	c.mutex.Unlock()
+
+	defer func() {
+		c.mutex.Lock()
+		delete(c.pending, id)
+		c.mutex.Unlock()
+	}()
+
+	request, requestError := newRequest(&id, method, params)
+	if requestError != nil {
+		return requestError
+	}
+
+	writeError := c.write(request)
+	if writeError != nil {
+		return fmt.Errorf("failed to send %q request to plugin %q: %w", method, c.Filename, writeError)
+	}
+
+	select {
+	case response := <-responseChannel:
+		if response.Error != nil {
+			return response.Error
+		}
+		if result == nil || len(response.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(response.Result, result)
+
+	case <-ctx.Done():
+		_ = c.Notify(CancelMethod, CancelParams{Id: id})
+		return fmt.Errorf("%q request to plugin %q aborted: %w", method, c.Filename, ctx.Err())
+
+	case <-c.closed:
+		return c.closedError()
+	}
+}
+
+// Notify sends a request without waiting for any response
+func (c *Client) Notify(method string, params any) error {
+	request, requestError := newRequest(nil, method, params)
+	if requestError != nil {
+		return requestError
+	}
+
+	return c.write(request)
+}
+
+// Close asks the plugin to shut down and kills it if it does not exit in time
+func (c *Client) Close(timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	_ = c.Notify(ShutdownMethod, nil) // plugins not speaking the protocol still see the end of their input
+	_ = c.stdin.Close()
+
+	select {
+	case <-c.closed:
+		return c.command.Wait()
+
+	case <-timer.C:
+		_ = c.command.Process.Kill()
+		<-c.closed
+		_ = c.command.Wait()
+		return fmt.Errorf("plugin %q killed after not shutting down in time", c.Filename)
+	}
+}
+
+// ErrorOutput returns what the plugin has written to stderr so far
+func (c *Client) ErrorOutput() string {
+	return c.stderr.String()
+}
+
+func (c *Client) write(request Request) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	return WriteMessage(c.stdin, request)
+}
+
+func (c *Client) readResponses(reader *bufio.Reader) {
+	for {
+		data, readError := ReadMessage(reader)
+		if readError != nil {
+			c.mutex.Lock()
+			c.readError = readError
+			c.mutex.Unlock()
+			close(c.closed)
+			return
+		}
+
+		var response Response
+		if json.Unmarshal(data, &response) != nil || response.Id == nil {
+			continue
+		}
+
+		c.mutex.Lock()
+		responseChannel, ok := c.pending[*response.Id]
+		c.mutex.Unlock()
+		if ok {
+			responseChannel <- response
+		}
+	}
+}
+
+func (c *Client) closedError() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if errors.Is(c.readError, io.EOF) || c.readError == nil {
+		return fmt.Errorf("%w: plugin %q exited: %v", ErrClosed, c.Filename, c.stderr.String())
+	}
+	return fmt.Errorf("%w: plugin %q: %v", ErrClosed, c.Filename, c.readError)
+}
+
+func newRequest(id *int64, method string, params any) (Request, error) {
+	request := Request{
+		JsonRpc: jsonRpcVersion,
+		Id:      id,
+		Method:  method,
+	}
+
+	if params != nil {
+		data, marshalError := json.Marshal(params)
+		if marshalError != nil {
+			return request, marshalError
+		}
+		request.Params = data
+	}
+
+	return request, nil
+}
+
+// syncBuffer collects the plugin's stderr, which is written by the exec package concurrently to our reads
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(data []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(data)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.String()
+}
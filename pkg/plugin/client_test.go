@@ -0,0 +1,136 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the test binary acts as plugin when started with this environment variable set to one of the test plugin modes
+const testPluginEnvironmentVariable = "THREAGILE_TEST_PLUGIN"
+
+const (
+	servingTestPlugin = "serve" // speaks the protocol
+	waitingTestPlugin = "wait"  // ignores the serve parameter and waits for the end of its input, like old plugins
+	exitingTestPlugin = "exit"  // fails on the unknown serve parameter
+	cancelledMessage  = "cancelled"
+)
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(testPluginEnvironmentVariable); len(mode) > 0 {
+		os.Exit(runTestPlugin(mode))
+	}
+	os.Exit(m.Run())
+}
+
+func runTestPlugin(mode string) int {
+	switch mode {
+	case servingTestPlugin:
+		serveError := Serve(os.Stdin, os.Stdout, func(ctx context.Context, method string, params json.RawMessage) (any, error) {
+			switch method {
+			case GetInfoMethod:
+				return map[string]string{"ID": "demo"}, nil
+			case "block":
+				<-ctx.Done()
+				_, _ = fmt.Fprintln(os.Stderr, cancelledMessage)
+				return nil, ctx.Err()
+			case "fail":
+				return nil, NewError(PluginFailedCode+1, "no risks for %v", string(params))
+			}
+			return nil, NewError(MethodNotFoundCode, "unknown method %q", method)
+		})
+		if serveError != nil {
+			return 2
+		}
+		return 0
+
+	case waitingTestPlugin:
+		_, _ = io.ReadAll(os.Stdin)
+		return 0
+	}
+
+	_, _ = fmt.Fprintln(os.Stderr, "unknown parameters:", strings.Join(os.Args[1:], " "))
+	return 2
+}
+
+func startTestPlugin(t *testing.T, mode string) *Client {
+	t.Setenv(testPluginEnvironmentVariable, mode)
+	client, startError := Start(os.Args[0])
+	if startError != nil {
+		t.Fatalf("unable to start test plugin: %v", startError)
+	}
+	return client
+}
+
+func TestClientCallsPlugin(t *testing.T) {
+	client := startTestPlugin(t, servingTestPlugin)
+
+	assert.NoError(t, client.Call(context.Background(), HandshakeMethod, nil, nil))
+	var info map[string]string
+	assert.NoError(t, client.Call(context.Background(), GetInfoMethod, nil, &info))
+	assert.Equal(t, map[string]string{"ID": "demo"}, info)
+
+	// errors reported by the plugin keep their code and message
+	var rpcError *Error
+	callError := client.Call(context.Background(), "fail", "model", nil)
+	assert.True(t, errors.As(callError, &rpcError))
+	assert.Equal(t, PluginFailedCode+1, rpcError.Code)
+	assert.Equal(t, `no risks for "model"`, rpcError.Message)
+	callError = client.Call(context.Background(), "unknown", nil, nil)
+	assert.True(t, errors.As(callError, &rpcError))
+	assert.Equal(t, MethodNotFoundCode, rpcError.Code)
+
+	assert.NoError(t, client.Close(5*time.Second))
+	assert.ErrorIs(t, client.Call(context.Background(), GetInfoMethod, nil, nil), ErrClosed)
+}
+
+func TestClientCallTimeout(t *testing.T) {
+	client := startTestPlugin(t, servingTestPlugin)
+	defer func() { _ = client.Close(5 * time.Second) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, client.Call(ctx, "block", nil, nil), context.DeadlineExceeded)
+
+	// the plugin is told to cancel the request and keeps serving
+	assert.Eventually(t, func() bool { return strings.Contains(client.ErrorOutput(), cancelledMessage) }, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, client.Call(context.Background(), GetInfoMethod, nil, nil))
+}
+
+func TestClientCallCancellation(t *testing.T) {
+	client := startTestPlugin(t, servingTestPlugin)
+	defer func() { _ = client.Close(5 * time.Second) }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	assert.ErrorIs(t, client.Call(ctx, "block", nil, nil), context.Canceled)
+
+	assert.Eventually(t, func() bool { return strings.Contains(client.ErrorOutput(), cancelledMessage) }, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, client.Call(context.Background(), GetInfoMethod, nil, nil))
+}
+
+func TestClientOfPluginNotSpeakingTheProtocol(t *testing.T) {
+	client := startTestPlugin(t, exitingTestPlugin)
+	callError := client.Call(context.Background(), HandshakeMethod, nil, nil)
+	assert.ErrorIs(t, callError, ErrClosed)
+	assert.Error(t, client.Close(5*time.Second)) // the exit code of the plugin
+	assert.Contains(t, client.ErrorOutput(), "unknown parameters: "+ServeParameter)
+
+	client = startTestPlugin(t, waitingTestPlugin)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, client.Call(ctx, HandshakeMethod, nil, nil), context.DeadlineExceeded)
+
+	// closing ends the input the plugin waits for, so it exits without being killed
+	started := time.Now()
+	assert.NoError(t, client.Close(30*time.Second))
+	assert.Less(t, time.Since(started), 30*time.Second)
+}
@@ -0,0 +1,130 @@
+/*
+Package plugin implements the long-lived plugin protocol: JSON-RPC 2.0 messages framed with a Content-Length header
+(like the language server protocol) exchanged over the stdin and stdout of a plugin process.
+*/
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	ServeParameter = "-serve"
+
+	HandshakeMethod     = "handshake" // answered by Serve itself, so that clients detect the protocol without waiting for the plugin
+	GetInfoMethod       = "get-info"
+	GenerateRisksMethod = "generate-risks"
+	ShutdownMethod      = "shutdown"
+	CancelMethod        = "$/cancelRequest"
+
+	jsonRpcVersion      = "2.0"
+	contentLengthHeader = "Content-Length"
+)
+
+const (
+	ParseErrorCode       = -32700
+	InvalidRequestCode   = -32600
+	MethodNotFoundCode   = -32601
+	InvalidParamsCode    = -32602
+	InternalErrorCode    = -32603
+	RequestCancelledCode = -32800
+	PluginFailedCode     = -32000
+)
+
+type Request struct {
+	JsonRpc string          `json:"jsonrpc"`
+	Id      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type Response struct {
+	JsonRpc string          `json:"jsonrpc"`
+	Id      *int64          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type CancelParams struct {
+	Id int64 `json:"id"`
+}
+
+// Error is a structured JSON-RPC error as reported by a plugin
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("plugin error %d: %v (%v)", e.Code, e.Message, string(e.Data))
+	}
+	return fmt.Sprintf("plugin error %d: %v", e.Code, e.Message)
+}
+
+func NewError(code int, format string, a ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+// WriteMessage writes a single message with its Content-Length header
+func WriteMessage(writer io.Writer, message any) error {
+	data, marshalError := json.Marshal(message)
+	if marshalError != nil {
+		return marshalError
+	}
+
+	_, writeError := fmt.Fprintf(writer, "%v: %d\r\n\r\n", contentLengthHeader, len(data))
+	if writeError != nil {
+		return writeError
+	}
+
+	_, writeError = writer.Write(data)
+	return writeError
+}
+
+// ReadMessage reads the headers and the body of a single message
+func ReadMessage(reader *bufio.Reader) ([]byte, error) {
+	contentLength := -1
+	for {
+		line, readError := reader.ReadString('\n')
+		if readError != nil {
+			return nil, readError
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			break
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid message header %q", line)
+		}
+
+		if strings.EqualFold(strings.TrimSpace(name), contentLengthHeader) {
+			length, parseError := strconv.Atoi(strings.TrimSpace(value))
+			if parseError != nil || length < 0 {
+				return nil, fmt.Errorf("invalid content length %q", value)
+			}
+			contentLength = length
+		}
+	}
+
+	if contentLength < 0 {
+		return nil, fmt.Errorf("missing %v header", contentLengthHeader)
+	}
+
+	data := make([]byte, contentLength)
+	_, readError := io.ReadFull(reader, data)
+	if readError != nil {
+		return nil, readError
+	}
+
+	return data, nil
+}
@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Handler answers a single request of a plugin; returning an *Error reports it unchanged, any other error is
+// reported as PluginFailedCode
+type Handler func(ctx context.Context, method string, params json.RawMessage) (any, error)
+
+// Serve reads requests from in and writes responses to out until the shutdown request or the end of input.
+// Each request runs with its own context, which is cancelled on a matching cancel notification.
+func Serve(in io.Reader, out io.Writer, handler Handler) error {
+	reader := bufio.NewReader(in)
+
+	var writeMutex sync.Mutex
+	respond := func(response Response) {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+		_ = WriteMessage(out, response)
+	}
+
+	var mutex sync.Mutex
+	running := make(map[int64]context.CancelFunc)
+	var waitGroup sync.WaitGroup
+	defer waitGroup.Wait()
+
+	for {
+		data, readError := ReadMessage(reader)
+		if errors.Is(readError, io.EOF) {
+			return nil
+		}
+		if readError != nil {
+			return readError
+		}
+
+		var request Request
+		unmarshalError := json.Unmarshal(data, &request)
+		if unmarshalError != nil {
+			respond(Response{JsonRpc: jsonRpcVersion, Error: NewError(ParseErrorCode, "failed to parse request: %v", unmarshalError)})
+			continue
+		}
+
+		switch request.Method {
+		case CancelMethod:
+			var params CancelParams
+			if json.Unmarshal(request.Params, &params) == nil {
+				mutex.Lock()
+				if cancel, ok := running[params.Id]; ok {
+					cancel()
+				}
+				mutex.Unlock()
+			}
+			continue
+
+		case HandshakeMethod:
+			if request.Id != nil {
+				respond(Response{JsonRpc: jsonRpcVersion, Id: request.Id, Result: json.RawMessage("null")})
+			}
+			continue
+
+		case ShutdownMethod:
+			waitGroup.Wait()
+			if request.Id != nil {
+				respond(Response{JsonRpc: jsonRpcVersion, Id: request.Id, Result: json.RawMessage("null")})
+			}
+			return nil
+		}
+
+		if request.Id == nil { // unknown notification
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		mutex.Lock()
+		running[*request.Id] = cancel
+		mutex.Unlock()
+
+		waitGroup.Add(1)
+		go func(request Request) {
+			defer waitGroup.Done()
+			defer func() {
+				mutex.Lock()
+				delete(running, *request.Id)
+				mutex.Unlock()
+				cancel()
+			}()
+
+			respond(handle(ctx, handler, request))
+		}(request)
+	}
+}
+
+func handle(ctx context.Context, handler Handler, request Request) (response Response) {
+	response = Response{JsonRpc: jsonRpcVersion, Id: request.Id}
+	defer func() {
+		if r := recover(); r != nil {
+			response.Result = nil
+			response.Error = NewError(InternalErrorCode, "plugin panicked: %v", r)
+		}
+	}()
+
+	result, handleError := handler(ctx, request.Method, request.Params)
+	if ctx.Err() != nil {
+		response.Error = NewError(RequestCancelledCode, "request cancelled")
+		return response
+	}
+
+	if handleError != nil {
+		var rpcError *Error
+		if errors.As(handleError, &rpcError) {
+			response.Error = rpcError
+		} else {
+			response.Error = NewError(PluginFailedCode, "%v", handleError)
+		}
+		return response
+	}
+
+	data, marshalError := json.Marshal(result)
+	if marshalError != nil {
+		response.Error = NewError(InternalErrorCode, "failed to marshal result: %v", marshalError)
+		return response
+	}
+
+	response.Result = data
+	return response
+}
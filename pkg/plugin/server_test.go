@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe_AnswersRequestsAndHonoursCancellation(t *testing.T) {
+	requestReader, requestWriter := io.Pipe()
+	responseReader, responseWriter := io.Pipe()
+
+	started := make(chan struct{})
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(requestReader, responseWriter, func(ctx context.Context, method string, params json.RawMessage) (any, error) {
+			switch method {
+			case GetInfoMethod:
+				return map[string]string{"ID": "demo"}, nil
+			case "block":
+				close(started)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			case "fail":
+				return nil, errors.New("boom")
+			}
+			return nil, NewError(MethodNotFoundCode, "unknown method %q", method)
+		})
+		_ = responseWriter.Close()
+	}()
+
+	responses := bufio.NewReader(responseReader)
+	readResponse := func() Response {
+		data, readError := ReadMessage(responses)
+		assert.NoError(t, readError)
+		var response Response
+		assert.NoError(t, json.Unmarshal(data, &response))
+		return response
+	}
+
+	send := func(request Request) {
+		go func() { _ = WriteMessage(requestWriter, request) }()
+	}
+	id := func(value int64) *int64 { return &value }
+
+	send(Request{JsonRpc: jsonRpcVersion, Id: id(1), Method: GetInfoMethod})
+	response := readResponse()
+	assert.Nil(t, response.Error)
+	assert.JSONEq(t, `{"ID":"demo"}`, string(response.Result))
+
+	send(Request{JsonRpc: jsonRpcVersion, Id: id(2), Method: "fail"})
+	response = readResponse()
+	assert.Equal(t, PluginFailedCode, response.Error.Code)
+
+	send(Request{JsonRpc: jsonRpcVersion, Id: id(3), Method: "unknown"})
+	response = readResponse()
+	assert.Equal(t, MethodNotFoundCode, response.Error.Code)
+
+	send(Request{JsonRpc: jsonRpcVersion, Id: id(4), Method: "block"})
+	<-started
+	cancelParams, _ := json.Marshal(CancelParams{Id: 4})
+	send(Request{JsonRpc: jsonRpcVersion, Method: CancelMethod, Params: cancelParams})
+	response = readResponse()
+	assert.Equal(t, int64(4), *response.Id)
+	assert.Equal(t, RequestCancelledCode, response.Error.Code)
+
+	send(Request{JsonRpc: jsonRpcVersion, Id: id(5), Method: ShutdownMethod})
+	response = readResponse()
+	assert.Nil(t, response.Error)
+	assert.NoError(t, <-served)
+}
@@ -0,0 +1,163 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	DiffFormatText     = "text"
+	DiffFormatJSON     = "json"
+	DiffFormatMarkdown = "markdown"
+)
+
+func WriteModelDiff(writer io.Writer, diff *model.ModelDiff, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case DiffFormatText, "":
+		return WriteModelDiffText(writer, diff)
+	case DiffFormatJSON:
+		return WriteModelDiffJSON(writer, diff)
+	case DiffFormatMarkdown, "md":
+		return WriteModelDiffMarkdown(writer, diff)
+	default:
+		return fmt.Errorf("unknown diff format %q (supported: %v, %v, %v)", format, DiffFormatText, DiffFormatJSON, DiffFormatMarkdown)
+	}
+}
+
+func WriteModelDiffJSON(writer io.Writer, diff *model.ModelDiff) error {
+	jsonBytes, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal model diff to JSON: %w", err)
+	}
+	_, err = writer.Write(append(jsonBytes, '\n'))
+	return err
+}
+
+func WriteModelDiffText(writer io.Writer, diff *model.ModelDiff) error {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Model diff: %v -> %v\n", diff.OldModel, diff.NewModel))
+	if diff.IsEmpty() {
+		text.WriteString("\nNo changes.\n")
+	}
+	writeElementDiffText(&text, "Technical assets", diff.TechnicalAssets)
+	writeElementDiffText(&text, "Communication links", diff.CommunicationLinks)
+	writeElementDiffText(&text, "Data assets", diff.DataAssets)
+	writeElementDiffText(&text, "Trust boundaries", diff.TrustBoundaries)
+	writeRisksText(&text, "New risks", "+", diff.NewRisks)
+	writeRisksText(&text, "Resolved risks", "-", diff.ResolvedRisks)
+	writeRiskChangesText(&text, "Changed risks", diff.ChangedRisks)
+
+	_, err := io.WriteString(writer, text.String())
+	return err
+}
+
+func WriteModelDiffMarkdown(writer io.Writer, diff *model.ModelDiff) error {
+	var text strings.Builder
+	text.WriteString("# Threat Model Diff\n\n")
+	text.WriteString(fmt.Sprintf("`%v` → `%v`\n", diff.OldModel, diff.NewModel))
+	if diff.IsEmpty() {
+		text.WriteString("\nNo changes.\n")
+	}
+	writeElementDiffMarkdown(&text, "Technical Assets", diff.TechnicalAssets)
+	writeElementDiffMarkdown(&text, "Communication Links", diff.CommunicationLinks)
+	writeElementDiffMarkdown(&text, "Data Assets", diff.DataAssets)
+	writeElementDiffMarkdown(&text, "Trust Boundaries", diff.TrustBoundaries)
+	writeRisksMarkdown(&text, "New Risks", diff.NewRisks)
+	writeRisksMarkdown(&text, "Resolved Risks", diff.ResolvedRisks)
+	writeRiskChangesMarkdown(&text, "Changed Risks", diff.ChangedRisks)
+
+	_, err := io.WriteString(writer, text.String())
+	return err
+}
+
+func writeElementDiffText(text *strings.Builder, title string, diff model.ElementDiff) {
+	if diff.IsEmpty() {
+		return
+	}
+	text.WriteString(fmt.Sprintf("\n%v (%d added, %d removed, %d changed):\n", title, len(diff.Added), len(diff.Removed), len(diff.Changed)))
+	for _, id := range diff.Added {
+		text.WriteString(fmt.Sprintf("  + %v\n", id))
+	}
+	for _, id := range diff.Removed {
+		text.WriteString(fmt.Sprintf("  - %v\n", id))
+	}
+	for _, change := range diff.Changed {
+		text.WriteString(fmt.Sprintf("  ~ %v (%v)\n", change.Id, strings.Join(change.Fields, ", ")))
+	}
+}
+
+func writeRisksText(text *strings.Builder, title string, marker string, risks []types.Risk) {
+	if len(risks) == 0 {
+		return
+	}
+	text.WriteString(fmt.Sprintf("\n%v (%d):\n", title, len(risks)))
+	for _, risk := range risks {
+		text.WriteString(fmt.Sprintf("  %v [%v] %v: %v\n", marker, risk.Severity.String(), risk.SyntheticId, removeFormattingTags(risk.Title)))
+	}
+}
+
+func writeRiskChangesText(text *strings.Builder, title string, changes []model.RiskChange) {
+	if len(changes) == 0 {
+		return
+	}
+	text.WriteString(fmt.Sprintf("\n%v (%d):\n", title, len(changes)))
+	for _, change := range changes {
+		marker := "~"
+		if change.IsEscalation() {
+			marker = "!"
+		}
+		text.WriteString(fmt.Sprintf("  %v [%v -> %v] [%v -> %v] %v: %v\n", marker, change.OldSeverity.String(), change.Risk.Severity.String(),
+			change.OldStatus.String(), change.NewStatus.String(), change.Risk.SyntheticId, removeFormattingTags(change.Risk.Title)))
+	}
+}
+
+func writeElementDiffMarkdown(text *strings.Builder, title string, diff model.ElementDiff) {
+	if diff.IsEmpty() {
+		return
+	}
+	text.WriteString(fmt.Sprintf("\n## %v\n\n", title))
+	text.WriteString("| Change | ID | Fields |\n|---|---|---|\n")
+	for _, id := range diff.Added {
+		text.WriteString(fmt.Sprintf("| added | `%v` | |\n", id))
+	}
+	for _, id := range diff.Removed {
+		text.WriteString(fmt.Sprintf("| removed | `%v` | |\n", id))
+	}
+	for _, change := range diff.Changed {
+		text.WriteString(fmt.Sprintf("| changed | `%v` | %v |\n", change.Id, strings.Join(change.Fields, ", ")))
+	}
+}
+
+func writeRisksMarkdown(text *strings.Builder, title string, risks []types.Risk) {
+	if len(risks) == 0 {
+		return
+	}
+	text.WriteString(fmt.Sprintf("\n## %v (%d)\n\n", title, len(risks)))
+	text.WriteString("| Severity | Synthetic ID | Title |\n|---|---|---|\n")
+	for _, risk := range risks {
+		title := strings.ReplaceAll(fmt.Sprint(removeFormattingTags(risk.Title)), "|", "\\|")
+		text.WriteString(fmt.Sprintf("| %v | `%v` | %v |\n", risk.Severity.Title(), risk.SyntheticId, title))
+	}
+}
+
+func writeRiskChangesMarkdown(text *strings.Builder, title string, changes []model.RiskChange) {
+	if len(changes) == 0 {
+		return
+	}
+	text.WriteString(fmt.Sprintf("\n## %v (%d)\n\n", title, len(changes)))
+	text.WriteString("| Severity | Status | Escalation | Synthetic ID | Title |\n|---|---|---|---|---|\n")
+	for _, change := range changes {
+		escalation := ""
+		if change.IsEscalation() {
+			escalation = "yes"
+		}
+		title := strings.ReplaceAll(fmt.Sprint(removeFormattingTags(change.Risk.Title)), "|", "\\|")
+		text.WriteString(fmt.Sprintf("| %v → %v | %v → %v | %v | `%v` | %v |\n", change.OldSeverity.Title(), change.Risk.Severity.Title(),
+			change.OldStatus.Title(), change.NewStatus.Title(), escalation, change.Risk.SyntheticId, title))
+	}
+}
@@ -75,6 +75,9 @@ func WriteRisksExcelToFile(parsedModel *types.ParsedModel, filename string) erro
 		{"R1", "Date"},
 		{"S1", "Checked by"},
 		{"T1", "Ticket"},
+		{"U1", "Owner"},
+		{"V1", "Expires"},
+		{"W1", "Review by"},
 	})
 	if err != nil {
 		return fmt.Errorf("unable to set cell value: %w", err)
@@ -101,6 +104,9 @@ func WriteRisksExcelToFile(parsedModel *types.ParsedModel, filename string) erro
 		{"R", 18},
 		{"S", 20},
 		{"T", 20},
+		{"U", 20},
+		{"V", 18},
+		{"W", 18},
 	})
 	if err != nil {
 		return fmt.Errorf("unable to set column width: %w", err)
@@ -166,6 +172,29 @@ func WriteRisksExcelToFile(parsedModel *types.ParsedModel, filename string) erro
 					return fmt.Errorf("unable to set cell value: %w", err)
 				}
 			}
+			if risk.IsRiskTracked(parsedModel) {
+				riskTracking := risk.GetRiskTracking(parsedModel)
+				err = excel.SetCellValue(sheetName, "U"+strconv.Itoa(excelRow), riskTracking.Owner)
+				if err != nil {
+					return fmt.Errorf("unable to set cell value: %w", err)
+				}
+				if !riskTracking.Expires.IsZero() {
+					expires := riskTracking.Expires.Format("2006-01-02")
+					if riskTracking.Expired {
+						expires += " (expired)"
+					}
+					err = excel.SetCellValue(sheetName, "V"+strconv.Itoa(excelRow), expires)
+					if err != nil {
+						return fmt.Errorf("unable to set cell value: %w", err)
+					}
+				}
+				if !riskTracking.ReviewBy.IsZero() {
+					err = excel.SetCellValue(sheetName, "W"+strconv.Itoa(excelRow), riskTracking.ReviewBy.Format("2006-01-02"))
+					if err != nil {
+						return fmt.Errorf("unable to set cell value: %w", err)
+					}
+				}
+			}
 			// styles
 			leftCellsStyle, rightCellStyles := fromSeverityToExcelStyle(riskTrackingStatus, risk.Severity, cellStyles)
 			err = setCellStyle(excel, sheetName, []setCellStyleCommand{
@@ -182,6 +211,8 @@ func WriteRisksExcelToFile(parsedModel *types.ParsedModel, filename string) erro
 				{"R" + strconv.Itoa(excelRow), "R" + strconv.Itoa(excelRow), cellStyles.blackCenter},
 				{"S" + strconv.Itoa(excelRow), "S" + strconv.Itoa(excelRow), cellStyles.blackCenter},
 				{"T" + strconv.Itoa(excelRow), "T" + strconv.Itoa(excelRow), cellStyles.blackLeft},
+				{"U" + strconv.Itoa(excelRow), "U" + strconv.Itoa(excelRow), cellStyles.blackCenter},
+				{"V" + strconv.Itoa(excelRow), "W" + strconv.Itoa(excelRow), cellStyles.blackCenter},
 			})
 			if err != nil {
 				return fmt.Errorf("unable to set cell style: %w", err)
@@ -189,11 +220,16 @@ func WriteRisksExcelToFile(parsedModel *types.ParsedModel, filename string) erro
 		}
 	}
 
-	err = excel.SetCellStyle(sheetName, "A1", "T1", cellStyles.headCenterBoldItalic)
+	err = excel.SetCellStyle(sheetName, "A1", "W1", cellStyles.headCenterBoldItalic)
 	if err != nil {
 		return fmt.Errorf("unable to set cell style: %w", err)
 	}
 
+	err = writeOverdueRiskReviewsSheet(excel, parsedModel, cellStyles)
+	if err != nil {
+		return err
+	}
+
 	excel.SetActiveSheet(sheetIndex)
 	err = excel.SaveAs(filename)
 	if err != nil {
@@ -202,6 +238,77 @@ func WriteRisksExcelToFile(parsedModel *types.ParsedModel, filename string) erro
 	return nil
 }
 
+// writeOverdueRiskReviewsSheet lists the risks with an expired risk tracking or an overdue review on a separate sheet
+func writeOverdueRiskReviewsSheet(excel *excelize.File, parsedModel *types.ParsedModel, cellStyles *cellStyles) error {
+	sheetName := "Overdue Risk Reviews"
+	_, err := excel.NewSheet(sheetName)
+	if err != nil {
+		return fmt.Errorf("unable to create sheet: %w", err)
+	}
+
+	err = setCellValue(excel, sheetName, []setCellValueCommand{
+		{"A1", "Severity"},
+		{"B1", "Identified Risk"},
+		{"C1", "ID"},
+		{"D1", "Status"},
+		{"E1", "Reason"},
+		{"F1", "Due Date"},
+		{"G1", "Owner"},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to set cell value: %w", err)
+	}
+
+	err = setColumnWidth(excel, sheetName, []setColumnWidthCommand{
+		{"A", 12},
+		{"B", 75},
+		{"C", 50},
+		{"D", 18},
+		{"E", 40},
+		{"F", 18},
+		{"G", 20},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to set column width: %w", err)
+	}
+
+	excelRow := 1 // as we have a header line
+	for _, review := range types.OverdueRiskReviews(parsedModel, types.Today()) {
+		excelRow++
+		err = setCellValue(excel, sheetName, []setCellValueCommand{
+			{"A" + strconv.Itoa(excelRow), review.Severity.Title()},
+			{"B" + strconv.Itoa(excelRow), removeFormattingTags(review.Title)},
+			{"C" + strconv.Itoa(excelRow), review.SyntheticRiskId},
+			{"D" + strconv.Itoa(excelRow), review.Status.Title()},
+			{"E" + strconv.Itoa(excelRow), review.Reason()},
+			{"F" + strconv.Itoa(excelRow), review.DueDate.Format("2006-01-02")},
+			{"G" + strconv.Itoa(excelRow), review.Owner},
+		})
+		if err != nil {
+			return err
+		}
+
+		leftCellsStyle, _ := fromSeverityToExcelStyle(review.Status, review.Severity, cellStyles)
+		err = setCellStyle(excel, sheetName, []setCellStyleCommand{
+			{"A" + strconv.Itoa(excelRow), "A" + strconv.Itoa(excelRow), leftCellsStyle},
+			{"B" + strconv.Itoa(excelRow), "B" + strconv.Itoa(excelRow), cellStyles.blackSmall},
+			{"C" + strconv.Itoa(excelRow), "C" + strconv.Itoa(excelRow), cellStyles.graySmall},
+			{"D" + strconv.Itoa(excelRow), "D" + strconv.Itoa(excelRow), fromRiskTrackingToExcelStyle(review.Status, cellStyles)},
+			{"E" + strconv.Itoa(excelRow), "E" + strconv.Itoa(excelRow), cellStyles.blackLeft},
+			{"F" + strconv.Itoa(excelRow), "G" + strconv.Itoa(excelRow), cellStyles.blackCenter},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to set cell style: %w", err)
+		}
+	}
+
+	err = excel.SetCellStyle(sheetName, "A1", "G1", cellStyles.headCenterBoldItalic)
+	if err != nil {
+		return fmt.Errorf("unable to set cell style: %w", err)
+	}
+	return nil
+}
+
 type cellStyles struct {
 	severityCriticalBold   int
 	severityCriticalCenter int
@@ -10,6 +10,7 @@ import (
 
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
 )
 
 type GenerateCommands struct {
@@ -18,9 +19,13 @@ type GenerateCommands struct {
 	RisksJSON           bool
 	TechnicalAssetsJSON bool
 	StatsJSON           bool
+	RisksSARIF          bool
+	AttackPathsJSON     bool
 	RisksExcel          bool
 	TagsExcel           bool
 	ReportPDF           bool
+	ReportHTML          bool
+	SubDiagrams         bool
 }
 
 func (c *GenerateCommands) Defaults() *GenerateCommands {
@@ -30,9 +35,13 @@ func (c *GenerateCommands) Defaults() *GenerateCommands {
 		RisksJSON:           true,
 		TechnicalAssetsJSON: true,
 		StatsJSON:           true,
+		RisksSARIF:          true,
+		AttackPathsJSON:     true,
 		RisksExcel:          true,
 		TagsExcel:           true,
 		ReportPDF:           true,
+		ReportHTML:          true,
+		SubDiagrams:         true,
 	}
 	return c
 }
@@ -40,17 +49,22 @@ func (c *GenerateCommands) Defaults() *GenerateCommands {
 func Generate(config *common.Config, readResult *model.ReadResult, commands *GenerateCommands, progressReporter progressReporter) error {
 	generateDataFlowDiagram := commands.DataFlowDiagram
 	generateDataAssetsDiagram := commands.DataAssetDiagram
-	if commands.ReportPDF { // as the PDF report includes both diagrams
+	if commands.ReportPDF || commands.ReportHTML { // as the PDF and HTML reports include both diagrams
 		generateDataFlowDiagram = true
 		generateDataAssetsDiagram = true
 	}
+	var dataFlowDiagramDOT, dataAssetDiagramDOT *os.File
 
-	diagramDPI := config.DiagramDPI
-	if diagramDPI < common.MinGraphvizDPI {
-		diagramDPI = common.MinGraphvizDPI
-	} else if diagramDPI > common.MaxGraphvizDPI {
-		diagramDPI = common.MaxGraphvizDPI
+	diagramFormats, err := CheckDiagramFormats(config.DiagramFormats)
+	if err != nil {
+		return err
 	}
+	if commands.ReportPDF && !contains(diagramFormats, common.DiagramFormatPNG) { // as the PDF report embeds the PNG diagrams
+		diagramFormats = append(diagramFormats, common.DiagramFormatPNG)
+	}
+
+	diagramDPI := ClampDiagramDPI(config.DiagramDPI)
+	dataFlowDiagramRendered := false
 	// Data-flow Diagram rendering
 	if generateDataFlowDiagram {
 		gvFile := filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenameDOT)
@@ -66,11 +80,14 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		if err != nil {
 			return fmt.Errorf("error while generating data flow diagram: %s", err)
 		}
+		dataFlowDiagramDOT = dotFile
 
 		err = GenerateDataFlowDiagramGraphvizImage(dotFile, config.OutputFolder,
-			config.TempFolder, config.BinFolder, config.DataFlowDiagramFilenamePNG, progressReporter)
+			config.TempFolder, config.BinFolder, config.DataFlowDiagramFilenamePNG, diagramFormats, progressReporter)
 		if err != nil {
 			progressReporter.Warn(err)
+		} else {
+			dataFlowDiagramRendered = true
 		}
 	}
 	// Data Asset Diagram rendering
@@ -88,8 +105,9 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		if err != nil {
 			return fmt.Errorf("error while generating data asset diagram: %s", err)
 		}
+		dataAssetDiagramDOT = dotFile
 		err = GenerateDataAssetDiagramGraphvizImage(dotFile, config.OutputFolder,
-			config.TempFolder, config.BinFolder, config.DataAssetDiagramFilenamePNG, progressReporter)
+			config.TempFolder, config.BinFolder, config.DataAssetDiagramFilenamePNG, diagramFormats, progressReporter)
 		if err != nil {
 			progressReporter.Warn(err)
 		}
@@ -122,6 +140,33 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		}
 	}
 
+	// portfolio stats json
+	if commands.StatsJSON && readResult.Portfolio != nil {
+		progressReporter.Info("Writing portfolio stats json")
+		err := WritePortfolioJSON(readResult.Portfolio, filepath.Join(config.OutputFolder, config.JsonPortfolioFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing portfolio stats json: %s", err)
+		}
+	}
+
+	// risks as SARIF
+	if commands.RisksSARIF {
+		progressReporter.Info("Writing risks sarif")
+		err := WriteRisksSARIF(readResult.ParsedModel, config.InputFile, filepath.Join(config.OutputFolder, config.SarifRisksFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing risks sarif: %s", err)
+		}
+	}
+
+	// attack paths json
+	if commands.AttackPathsJSON {
+		progressReporter.Info("Writing attack paths json")
+		err := WriteAttackPathsJSON(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.JsonAttackPathsFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing attack paths json: %s", err)
+		}
+	}
+
 	// risks Excel
 	if commands.RisksExcel {
 		progressReporter.Info("Writing risks excel")
@@ -140,7 +185,8 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		}
 	}
 
-	if commands.ReportPDF {
+	modelHash := ""
+	if commands.ReportPDF || commands.ReportHTML {
 		// hash the YAML input file
 		f, err := os.Open(config.InputFile)
 		if err != nil {
@@ -151,12 +197,32 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		if _, err := io.Copy(hasher, f); err != nil {
 			return err
 		}
-		modelHash := hex.EncodeToString(hasher.Sum(nil))
+		modelHash = hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	if commands.ReportPDF {
 		// report PDF
 		progressReporter.Info("Writing report pdf")
 
-		pdfReporter := pdfReporter{}
-		err = pdfReporter.WriteReportPDF(filepath.Join(config.OutputFolder, config.ReportFilename),
+		// the focused sub-diagrams are only rendered when graphviz managed to render the full diagram
+		subDiagramFilenamesPNG := make(map[string]string)
+		if commands.SubDiagrams && dataFlowDiagramRendered {
+			progressReporter.Info("Rendering sub-diagrams for report pdf")
+			for _, scope := range reportSubDiagramScopes(readResult.ParsedModel) {
+				filenamePNG := SubDiagramFilename(filepath.Join(config.TempFolder, config.DataFlowDiagramFilenamePNG), scope)
+				err := GenerateSubDiagram(readResult.ParsedModel, scope, SubDiagramFilename(config.DataFlowDiagramFilenameDOT, scope), filenamePNG,
+					config.TempFolder, false, diagramDPI, []string{common.DiagramFormatPNG}, progressReporter)
+				if err != nil {
+					progressReporter.Warn(err)
+					continue
+				}
+				subDiagramFilenamesPNG[scope.Name] = filenamePNG
+				defer func() { _ = os.Remove(filenamePNG) }()
+			}
+		}
+
+		pdfReporter := pdfReporter{subDiagramFilenamesPNG: subDiagramFilenamesPNG, portfolio: readResult.Portfolio}
+		err := pdfReporter.WriteReportPDF(filepath.Join(config.OutputFolder, config.ReportFilename),
 			filepath.Join(config.AppFolder, config.TemplateFilename),
 			filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenamePNG),
 			filepath.Join(config.OutputFolder, config.DataAssetDiagramFilenamePNG),
@@ -173,9 +239,78 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		}
 	}
 
+	if commands.ReportHTML {
+		// the diagrams are optional: without graphviz the report is written without them
+		var dataFlowDiagramSVG, dataAssetDiagramSVG []byte
+		if dataFlowDiagramDOT != nil {
+			svg, err := RenderGraphvizSVG(dataFlowDiagramDOT, progressReporter)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
+			dataFlowDiagramSVG = svg
+		}
+		if dataAssetDiagramDOT != nil {
+			svg, err := RenderGraphvizSVG(dataAssetDiagramDOT, progressReporter)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
+			dataAssetDiagramSVG = svg
+		}
+
+		progressReporter.Info("Writing report html")
+		err := WriteReportHTML(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.ReportHTMLFilename),
+			dataFlowDiagramSVG, dataAssetDiagramSVG, config.BuildTimestamp, modelHash)
+		if err != nil {
+			return fmt.Errorf("error while writing report html: %s", err)
+		}
+	}
+
 	return nil
 }
 
+// CheckDiagramFormats returns the formats without duplicates or an error for unknown formats
+func CheckDiagramFormats(formats []string) ([]string, error) {
+	diagramFormats := make([]string, 0)
+	for _, format := range formats {
+		if !contains([]string{common.DiagramFormatPNG, common.DiagramFormatSVG, common.DiagramFormatPDF}, format) {
+			return nil, fmt.Errorf("unknown diagram format %q (%s, %s, %s)", format, common.DiagramFormatPNG, common.DiagramFormatSVG, common.DiagramFormatPDF)
+		}
+		if !contains(diagramFormats, format) {
+			diagramFormats = append(diagramFormats, format)
+		}
+	}
+	return diagramFormats, nil
+}
+
+// ClampDiagramDPI limits the DPI to the range supported for rendering
+func ClampDiagramDPI(dpi int) int {
+	if dpi < common.MinGraphvizDPI {
+		return common.MinGraphvizDPI
+	} else if dpi > common.MaxGraphvizDPI {
+		return common.MaxGraphvizDPI
+	}
+	return dpi
+}
+
+// reportSubDiagramScopes lists the scopes embedded in the report: the neighbourhood of each technical asset
+// and each trust boundary containing technical assets
+func reportSubDiagramScopes(parsedModel *types.ParsedModel) []*DiagramScope {
+	scopes := make([]*DiagramScope, 0)
+	for _, technicalAsset := range sortedTechnicalAssetsByTitle(parsedModel) {
+		scope, err := NewTechnicalAssetDiagramScope(parsedModel, technicalAsset.Id, common.DefaultSubDiagramHops)
+		if err == nil {
+			scopes = append(scopes, scope)
+		}
+	}
+	for _, trustBoundary := range sortedTrustBoundariesByTitle(parsedModel) {
+		scope, err := NewTrustBoundaryDiagramScope(parsedModel, trustBoundary.Id)
+		if err == nil { // trust boundaries without technical assets have no diagram
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
 type progressReporter interface {
 	Info(a ...any)
 	Warn(a ...any)
@@ -20,6 +20,25 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.ParsedModel,
 	progressReporter progressReporter) (*os.File, error) {
 	progressReporter.Info("Writing data flow diagram input")
 
+	title := ""
+	if addModelTitle {
+		title = parsedModel.Title
+	}
+	return writeDataFlowDiagramGraphvizDOT(parsedModel, nil, diagramFilenameDOT, dpi, title)
+}
+
+// WriteScopedDataFlowDiagramGraphvizDOT writes a data flow diagram focused on the technical assets of the scope
+func WriteScopedDataFlowDiagramGraphvizDOT(parsedModel *types.ParsedModel, scope *DiagramScope,
+	diagramFilenameDOT string, dpi int,
+	progressReporter progressReporter) (*os.File, error) {
+	progressReporter.Info("Writing data flow diagram input for " + scope.Title)
+
+	return writeDataFlowDiagramGraphvizDOT(parsedModel, scope, diagramFilenameDOT, dpi, scope.Title)
+}
+
+func writeDataFlowDiagramGraphvizDOT(parsedModel *types.ParsedModel, scope *DiagramScope,
+	diagramFilenameDOT string, dpi int, title string) (*os.File, error) {
+
 	var dotContent strings.Builder
 	dotContent.WriteString("digraph generatedModel { concentrate=false \n")
 
@@ -60,8 +79,8 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.ParsedModel,
 		rankdir = "LR"
 	}
 	modelTitle := ""
-	if addModelTitle {
-		modelTitle = `label="` + parsedModel.Title + `"`
+	if len(title) > 0 {
+		modelTitle = `label="` + escapeDOT(title) + `"`
 	}
 	dotContent.WriteString(`	graph [ ` + modelTitle + `
 		labelloc=t
@@ -84,6 +103,9 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.ParsedModel,
 	];
 `)
 
+	// technical assets outside the scope which communicate with it, collapsed per trust boundary
+	placeholders := scope.placeholders(parsedModel)
+
 	// Trust Boundaries ===============================================================================
 	var subgraphSnippetsById = make(map[string]string)
 	// first create them in memory (see the link replacement below for nested trust boundaries) - otherwise in Go ranging over map is random order
@@ -96,7 +118,7 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.ParsedModel,
 	for _, key := range keys {
 		trustBoundary := parsedModel.TrustBoundaries[key]
 		var snippet strings.Builder
-		if len(trustBoundary.TechnicalAssetsInside) > 0 || len(trustBoundary.TrustBoundariesNested) > 0 {
+		if (len(trustBoundary.TechnicalAssetsInside) > 0 || len(trustBoundary.TrustBoundariesNested) > 0) && scope.containsTrustBoundary(parsedModel, trustBoundary) {
 			if drawSpaceLinesForLayoutUnfortunatelyFurtherSeparatesAllRanks {
 				// see https://stackoverflow.com/questions/17247455/how-do-i-add-extra-space-between-clusters?noredirect=1&lq=1
 				snippet.WriteString("\n subgraph cluster_space_boundary_for_layout_only_1" + hash(trustBoundary.Id) + " {\n")
@@ -148,14 +170,24 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.ParsedModel,
 			for _, technicalAssetInside := range keys {
 				//log.Println("About to add technical asset link to trust boundary: ", technicalAssetInside)
 				technicalAsset := parsedModel.TechnicalAssets[technicalAssetInside]
+				if !scope.Contains(technicalAsset.Id) {
+					continue
+				}
 				snippet.WriteString(hash(technicalAsset.Id))
 				snippet.WriteString(";\n")
 			}
+			if _, ok := placeholders[trustBoundary.Id]; ok {
+				snippet.WriteString(placeholderNode(trustBoundary.Id))
+				snippet.WriteString(";\n")
+			}
 			keys = trustBoundary.TrustBoundariesNested
 			sort.Strings(keys)
 			for _, trustBoundaryNested := range keys {
 				//log.Println("About to add nested trust boundary to trust boundary: ", trustBoundaryNested)
 				trustBoundaryNested := parsedModel.TrustBoundaries[trustBoundaryNested]
+				if !scope.containsTrustBoundary(parsedModel, trustBoundaryNested) {
+					continue
+				}
 				snippet.WriteString("LINK-NEEDS-REPLACED-BY-cluster_" + hash(trustBoundaryNested.Id))
 				snippet.WriteString(";\n")
 			}
@@ -201,15 +233,30 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.ParsedModel,
 	}
 	sort.Sort(types.ByOrderAndIdSort(techAssets))
 	for _, technicalAsset := range techAssets {
+		if !scope.Contains(technicalAsset.Id) {
+			continue
+		}
 		dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, false))
 		dotContent.WriteString("\n")
 	}
+	placeholderKeys := make([]string, 0)
+	for k := range placeholders {
+		placeholderKeys = append(placeholderKeys, k)
+	}
+	sort.Strings(placeholderKeys)
+	for _, key := range placeholderKeys {
+		dotContent.WriteString(makePlaceholderNode(parsedModel, key, placeholders[key]))
+		dotContent.WriteString("\n")
+	}
 
 	// Data Flows (Technical Communication Links) ===============================================================================
 	for _, technicalAsset := range techAssets {
 		for _, dataFlow := range technicalAsset.CommunicationLinks {
 			sourceId := technicalAsset.Id
 			targetId := dataFlow.TargetId
+			if !scope.Contains(sourceId) && !scope.Contains(targetId) {
+				continue
+			}
 			//log.Println("About to add link from", sourceId, "to", targetId, "with id", dataFlow.Id)
 			var arrowStyle, arrowColor, readOrWriteHead, readOrWriteTail string
 			if dataFlow.Readonly {
@@ -233,8 +280,10 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.ParsedModel,
 			}
 
 			dotContent.WriteString("\n")
-			dotContent.WriteString("  " + hash(sourceId) + " -> " + hash(targetId) +
-				` [` + arrowColor + ` ` + arrowStyle + tweaks + ` constraint=` + strconv.FormatBool(dataFlow.DiagramTweakConstraint) + ` `)
+			dotContent.WriteString("  " + scope.nodeOf(parsedModel, sourceId) + " -> " + scope.nodeOf(parsedModel, targetId) +
+				` [` + arrowColor + ` ` + arrowStyle + tweaks + ` constraint=` + strconv.FormatBool(dataFlow.DiagramTweakConstraint) + ` ` +
+				makeDiagramIdAndTooltip(parsedModel, "communication-link", dataFlow.Id, dataFlow.Title+" ("+dataFlow.Protocol.String()+")",
+					filterRisks(parsedModel, func(risk types.Risk) bool { return risk.MostRelevantCommunicationLinkId == dataFlow.Id })) + ` `)
 			if !parsedModel.DiagramTweakSuppressEdgeLabels {
 				dotContent.WriteString(` xlabel="` + encode(dataFlow.Protocol.String()) + `" fontcolor="` + determineLabelColor(dataFlow, parsedModel) + `" `)
 			}
@@ -242,17 +291,20 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.ParsedModel,
 		}
 	}
 
-	diagramInvisibleConnectionsTweaks, err := makeDiagramInvisibleConnectionsTweaks(parsedModel)
-	if err != nil {
-		return nil, fmt.Errorf("error while making diagram invisible connections tweaks: %s", err)
-	}
-	dotContent.WriteString(diagramInvisibleConnectionsTweaks)
+	// the layout tweaks refer to technical assets which might be outside the scope, so they only apply to the full diagram
+	if scope == nil {
+		diagramInvisibleConnectionsTweaks, err := makeDiagramInvisibleConnectionsTweaks(parsedModel)
+		if err != nil {
+			return nil, fmt.Errorf("error while making diagram invisible connections tweaks: %s", err)
+		}
+		dotContent.WriteString(diagramInvisibleConnectionsTweaks)
 
-	diagramSameRankNodeTweaks, err := makeDiagramSameRankNodeTweaks(parsedModel)
-	if err != nil {
-		return nil, fmt.Errorf("error while making diagram same-rank node tweaks: %s", err)
+		diagramSameRankNodeTweaks, err := makeDiagramSameRankNodeTweaks(parsedModel)
+		if err != nil {
+			return nil, fmt.Errorf("error while making diagram same-rank node tweaks: %s", err)
+		}
+		dotContent.WriteString(diagramSameRankNodeTweaks)
 	}
-	dotContent.WriteString(diagramSameRankNodeTweaks)
 
 	dotContent.WriteString("}")
 
@@ -394,49 +446,16 @@ func determineArrowColor(cl types.CommunicationLink, parsedModel *types.ParsedMo
 	*/
 }
 
+// GenerateDataFlowDiagramGraphvizImage renders the diagram in all given formats, each file is named like the PNG file
+// with the format as extension
 func GenerateDataFlowDiagramGraphvizImage(dotFile *os.File, targetDir string,
-	tempFolder, binFolder, dataFlowDiagramFilenamePNG string, progressReporter progressReporter) error {
+	tempFolder, binFolder, dataFlowDiagramFilenamePNG string, formats []string, progressReporter progressReporter) error {
 	progressReporter.Info("Rendering data flow diagram input")
-	// tmp files
-	tmpFileDOT, err := os.CreateTemp(tempFolder, "diagram-*-.gv")
-	if err != nil {
-		return fmt.Errorf("Error creating temp file: %v", err)
-	}
-	defer func() { _ = os.Remove(tmpFileDOT.Name()) }()
-
-	tmpFilePNG, err := os.CreateTemp(tempFolder, "diagram-*-.png")
-	if err != nil {
-		return fmt.Errorf("Error creating temp file: %v", err)
-	}
-	defer func() { _ = os.Remove(tmpFilePNG.Name()) }()
-
-	// copy into tmp file as input
-	inputDOT, err := os.ReadFile(dotFile.Name())
-	if err != nil {
-		return fmt.Errorf("Error reading %s: %v", dotFile.Name(), err)
-	}
-	err = os.WriteFile(tmpFileDOT.Name(), inputDOT, 0600)
-	if err != nil {
-		return fmt.Errorf("Error creating %s: %v", tmpFileDOT.Name(), err)
-	}
-
-	// exec
-
-	cmd := exec.Command("dot", "-Tpng", tmpFileDOT.Name(), "-o", tmpFilePNG.Name()) // #nosec G204
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
-	if err != nil {
-		return errors.New("graph rendering call failed with error: " + err.Error())
-	}
-	// copy into resulting file
-	inputPNG, err := os.ReadFile(tmpFilePNG.Name())
-	if err != nil {
-		return fmt.Errorf("Error copying into resulting file %s: %v", tmpFilePNG.Name(), err)
-	}
-	err = os.WriteFile(filepath.Join(targetDir, dataFlowDiagramFilenamePNG), inputPNG, 0600)
-	if err != nil {
-		return fmt.Errorf("Error creating %s: %v", filepath.Join(targetDir, dataFlowDiagramFilenamePNG), err)
+	for _, format := range formats {
+		err := renderGraphvizImage(dotFile, tempFolder, format, filepath.Join(targetDir, diagramFilename(dataFlowDiagramFilenamePNG, format)))
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -552,7 +571,7 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.ParsedModel, diagramFil
 			targetId := technicalAsset.Id
 			dotContent.WriteString("\n")
 			dotContent.WriteString(hash(sourceId) + " -> " + hash(targetId) +
-				` [ color="blue" style="solid" ];`)
+				` [ color="blue" style="solid" ` + makeDiagramDataUsageIdAndTooltip(parsedModel, sourceId, targetId, "stored") + ` ];`)
 			dotContent.WriteString("\n")
 		}
 		for _, sourceId := range technicalAsset.DataAssetsProcessed {
@@ -560,7 +579,7 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.ParsedModel, diagramFil
 				targetId := technicalAsset.Id
 				dotContent.WriteString("\n")
 				dotContent.WriteString(hash(sourceId) + " -> " + hash(targetId) +
-					` [ color="#666666" style="dashed" ];`)
+					` [ color="#666666" style="dashed" ` + makeDiagramDataUsageIdAndTooltip(parsedModel, sourceId, targetId, "processed") + ` ];`)
 				dotContent.WriteString("\n")
 			}
 		}
@@ -596,7 +615,8 @@ func makeDataAssetNode(parsedModel *types.ParsedModel, dataAsset types.DataAsset
 	if !dataAsset.IsDataBreachPotentialStillAtRisk(parsedModel) {
 		color = "#444444" // since black is too dark here as fill color
 	}
-	return "  " + hash(dataAsset.Id) + ` [ label=<<b>` + encode(dataAsset.Title) + `</b>> penwidth="3.0" style="filled" fillcolor="` + color + `" color="` + color + "\"\n  ]; "
+	return "  " + hash(dataAsset.Id) + ` [ label=<<b>` + encode(dataAsset.Title) + `</b>> penwidth="3.0" style="filled" fillcolor="` + color + `" color="` + color + `" ` +
+		makeDiagramIdAndTooltip(parsedModel, "data-asset", dataAsset.Id, dataAsset.Title, dataAsset.IdentifiedDataBreachProbabilityRisks(parsedModel)) + "\n  ]; "
 }
 
 func makeTechAssetNode(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset, simplified bool) string {
@@ -623,28 +643,13 @@ func makeTechAssetNode(parsedModel *types.ParsedModel, technicalAsset types.Tech
 			}
 		}
 		return "  " + hash(technicalAsset.Id) + ` [ shape="box" style="filled" fillcolor="` + color + `"
-				label=<<b>` + encode(technicalAsset.Title) + `</b>> penwidth="3.0" color="` + color + `" ];
+				label=<<b>` + encode(technicalAsset.Title) + `</b>> penwidth="3.0" color="` + color + `" ` + makeTechAssetIdAndTooltip(parsedModel, technicalAsset) + ` ];
 				`
 	} else {
-		var shape, title string
+		shape, title := determineShape(technicalAsset), technicalAsset.Title
 		var lineBreak = ""
-		switch technicalAsset.Type {
-		case types.ExternalEntity:
-			shape = "box"
-			title = technicalAsset.Title
-		case types.Process:
-			shape = "ellipse"
-			title = technicalAsset.Title
-		case types.Datastore:
-			shape = "cylinder"
-			title = technicalAsset.Title
-			if technicalAsset.Redundant {
-				lineBreak = "<br/>"
-			}
-		}
-
-		if technicalAsset.UsedAsClientByHuman {
-			shape = "octagon"
+		if technicalAsset.Type == types.Datastore && technicalAsset.Redundant {
+			lineBreak = "<br/>"
 		}
 
 		// RAA = Relative Attacker Attractiveness
@@ -662,11 +667,43 @@ func makeTechAssetNode(parsedModel *types.ParsedModel, technicalAsset types.Tech
 		}
 
 		return "  " + hash(technicalAsset.Id) + ` [
-	label=<<table border="0" cellborder="` + compartmentBorder + `" cellpadding="2" cellspacing="0"><tr><td><font point-size="15" color="` + DarkBlue + `">` + lineBreak + technicalAsset.Technology.String() + `</font><br/><font point-size="15" color="` + LightGray + `">` + technicalAsset.Size.String() + `</font></td></tr><tr><td><b><font color="` + determineTechnicalAssetLabelColor(technicalAsset, parsedModel) + `">` + encode(title) + `</font></b><br/></td></tr><tr><td>` + attackerAttractivenessLabel + `</td></tr></table>>
+	label=<<table border="0" cellborder="` + compartmentBorder + `" cellpadding="2" cellspacing="0"><tr><td><font point-size="15" color="` + DarkBlue + `">` + lineBreak + encode(technicalAsset.TechnologyName()) + `</font><br/><font point-size="15" color="` + LightGray + `">` + technicalAsset.Size.String() + `</font></td></tr><tr><td><b><font color="` + determineTechnicalAssetLabelColor(technicalAsset, parsedModel) + `">` + encode(title) + `</font></b><br/></td></tr><tr><td>` + attackerAttractivenessLabel + `</td></tr></table>>
 	shape=` + shape + ` style="` + determineShapeBorderLineStyle(technicalAsset) + `,` + determineShapeStyle(technicalAsset) + `" penwidth="` + determineShapeBorderPenWidth(technicalAsset, parsedModel) + `" fillcolor="` + determineShapeFillColor(technicalAsset, parsedModel) + `"
 	peripheries=` + strconv.Itoa(determineShapePeripheries(technicalAsset)) + `
-	color="` + determineShapeBorderColor(technicalAsset, parsedModel) + "\"\n  ]; "
+	color="` + determineShapeBorderColor(technicalAsset, parsedModel) + `"
+	` + makeTechAssetIdAndTooltip(parsedModel, technicalAsset) + "\n  ]; "
+	}
+}
+
+func makeTechAssetIdAndTooltip(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset) string {
+	return makeDiagramIdAndTooltip(parsedModel, "technical-asset", technicalAsset.Id, technicalAsset.Title,
+		filterRisks(parsedModel, func(risk types.Risk) bool { return risk.MostRelevantTechnicalAssetId == technicalAsset.Id }))
+}
+
+func filterRisks(parsedModel *types.ParsedModel, isRelevant func(types.Risk) bool) []types.Risk {
+	result := make([]types.Risk, 0)
+	for _, risk := range types.AllRisks(parsedModel) {
+		if isRelevant(risk) {
+			result = append(result, risk)
+		}
+	}
+	return result
+}
+
+// makeDiagramIdAndTooltip returns the id and tooltip attributes of a diagram element, which graphviz only writes
+// into SVG output: the id is prefixed by the kind of element as IDs are only unique per kind
+func makeDiagramIdAndTooltip(parsedModel *types.ParsedModel, kind string, id string, title string, risks []types.Risk) string {
+	plural := "s"
+	if len(risks) == 1 {
+		plural = ""
 	}
+	tooltip := fmt.Sprintf("%s [%s]: %d risk%s, %d still at risk", title, id, len(risks), plural, len(types.ReduceToOnlyStillAtRisk(parsedModel, risks)))
+	return `id="` + escapeDOT(kind+":"+id) + `" tooltip="` + escapeDOT(tooltip) + `"`
+}
+
+func makeDiagramDataUsageIdAndTooltip(parsedModel *types.ParsedModel, dataAssetId string, technicalAssetId string, usage string) string {
+	tooltip := parsedModel.DataAssets[dataAssetId].Title + " " + usage + " by " + parsedModel.TechnicalAssets[technicalAssetId].Title
+	return `id="` + escapeDOT("data-asset:"+dataAssetId+"@technical-asset:"+technicalAssetId) + `" tooltip="` + escapeDOT(tooltip) + `"`
 }
 
 func determineShapeStyle(ta types.TechnicalAsset) string {
@@ -757,6 +794,24 @@ func determineShapeBorderColor(ta types.TechnicalAsset, parsedModel *types.Parse
 	*/
 }
 
+// octagon when used as client (by humans or by the declared traits of its technology),
+// hexagon when a process only forwarding traffic (like load balancers and reverse proxies)
+func determineShape(ta types.TechnicalAsset) string {
+	if ta.UsedAsClientByHuman || ta.HasTrait(types.ClientTrait) {
+		return "octagon"
+	}
+	switch ta.Type {
+	case types.ExternalEntity:
+		return "box"
+	case types.Datastore:
+		return "cylinder"
+	}
+	if ta.HasTrait(types.TrafficForwardingTrait) {
+		return "hexagon"
+	}
+	return "ellipse"
+}
+
 func determineShapePeripheries(ta types.TechnicalAsset) int {
 	if ta.Redundant {
 		return 2
@@ -829,9 +884,21 @@ func determineTechnicalAssetLabelColor(ta types.TechnicalAsset, model *types.Par
 	*/
 }
 
+// GenerateDataAssetDiagramGraphvizImage renders the diagram in all given formats, each file is named like the PNG file
+// with the format as extension
 func GenerateDataAssetDiagramGraphvizImage(dotFile *os.File, targetDir string,
-	tempFolder, binFolder, dataAssetDiagramFilenamePNG string, progressReporter progressReporter) error { // TODO dedupe with other render...() method here
+	tempFolder, binFolder, dataAssetDiagramFilenamePNG string, formats []string, progressReporter progressReporter) error {
 	progressReporter.Info("Rendering data asset diagram input")
+	for _, format := range formats {
+		err := renderGraphvizImage(dotFile, tempFolder, format, filepath.Join(targetDir, diagramFilename(dataAssetDiagramFilenamePNG, format)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderGraphvizImage(dotFile *os.File, tempFolder string, format string, targetFile string) error {
 	// tmp files
 	tmpFileDOT, err := os.CreateTemp(tempFolder, "diagram-*-.gv")
 	if err != nil {
@@ -839,11 +906,11 @@ func GenerateDataAssetDiagramGraphvizImage(dotFile *os.File, targetDir string,
 	}
 	defer func() { _ = os.Remove(tmpFileDOT.Name()) }()
 
-	tmpFilePNG, err := os.CreateTemp(tempFolder, "diagram-*-.png")
+	tmpFileImage, err := os.CreateTemp(tempFolder, "diagram-*-."+format)
 	if err != nil {
 		return fmt.Errorf("Error creating temp file: %v", err)
 	}
-	defer func() { _ = os.Remove(tmpFilePNG.Name()) }()
+	defer func() { _ = os.Remove(tmpFileImage.Name()) }()
 
 	// copy into tmp file as input
 	inputDOT, err := os.ReadFile(dotFile.Name())
@@ -856,7 +923,7 @@ func GenerateDataAssetDiagramGraphvizImage(dotFile *os.File, targetDir string,
 	}
 
 	// exec
-	cmd := exec.Command("dot", "-Tpng", tmpFileDOT.Name(), "-o", tmpFilePNG.Name()) // #nosec G204
+	cmd := exec.Command("dot", "-T"+format, tmpFileDOT.Name(), "-o", tmpFileImage.Name()) // #nosec G204
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	err = cmd.Run()
@@ -864,23 +931,49 @@ func GenerateDataAssetDiagramGraphvizImage(dotFile *os.File, targetDir string,
 		return errors.New("graph rendering call failed with error: " + err.Error())
 	}
 	// copy into resulting file
-	inputPNG, err := os.ReadFile(tmpFilePNG.Name())
+	inputImage, err := os.ReadFile(tmpFileImage.Name())
 	if err != nil {
-		return fmt.Errorf("Error copying into resulting file %s: %v", tmpFilePNG.Name(), err)
+		return fmt.Errorf("Error copying into resulting file %s: %v", tmpFileImage.Name(), err)
 	}
-	err = os.WriteFile(filepath.Join(targetDir, dataAssetDiagramFilenamePNG), inputPNG, 0600)
+	err = os.WriteFile(targetFile, inputImage, 0600)
 	if err != nil {
-		return fmt.Errorf("Error creating %s: %v", filepath.Join(targetDir, dataAssetDiagramFilenamePNG), err)
+		return fmt.Errorf("Error creating %s: %v", targetFile, err)
 	}
 	return nil
 }
 
+// diagramFilename replaces the extension of the PNG filename by the format
+func diagramFilename(filenamePNG string, format string) string {
+	return strings.TrimSuffix(filenamePNG, filepath.Ext(filenamePNG)) + "." + format
+}
+
+// RenderGraphvizSVG renders the diagram as inline SVG (without the XML prolog) for embedding into HTML
+func RenderGraphvizSVG(dotFile *os.File, progressReporter progressReporter) ([]byte, error) {
+	progressReporter.Info("Rendering diagram as svg: " + filepath.Base(dotFile.Name()))
+	cmd := exec.Command("dot", "-Tsvg", dotFile.Name()) // #nosec G204
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.New("graph rendering call failed with error: " + err.Error())
+	}
+	start := strings.Index(string(output), "<svg")
+	if start < 0 {
+		return nil, fmt.Errorf("graph rendering returned no svg for %s", dotFile.Name())
+	}
+	return output[start:], nil
+}
+
 func hash(s string) string {
 	h := fnv.New32a()
 	_, _ = h.Write([]byte(s))
 	return fmt.Sprintf("%v", h.Sum32())
 }
 
+// escapeDOT escapes a value for a double-quoted DOT attribute
+func escapeDOT(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(value)
+}
+
 func encode(value string) string {
 	return strings.ReplaceAll(value, "&", "&amp;")
 }
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestDetermineShape(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		asset types.TechnicalAsset
+		shape string
+	}{
+		{"process", types.TechnicalAsset{Type: types.Process, Technology: types.WebServer}, "ellipse"},
+		{"external entity", types.TechnicalAsset{Type: types.ExternalEntity, Technology: types.WebServer}, "box"},
+		{"datastore", types.TechnicalAsset{Type: types.Datastore, Technology: types.Database}, "cylinder"},
+		{"used as client by humans", types.TechnicalAsset{Type: types.Process, Technology: types.WebServer, UsedAsClientByHuman: true}, "octagon"},
+		{"built-in client technology", types.TechnicalAsset{Type: types.ExternalEntity, Technology: types.Browser}, "octagon"},
+		{"built-in traffic forwarding technology", types.TechnicalAsset{Type: types.Process, Technology: types.LoadBalancer}, "hexagon"},
+		{"traffic forwarding datastore", types.TechnicalAsset{Type: types.Datastore, Technology: types.LoadBalancer}, "cylinder"},
+		{"custom client technology", types.TechnicalAsset{Type: types.Process, Technology: types.WebServer, CustomTechnology: "kiosk",
+			TechnologyTraits: map[string]bool{types.ClientTrait.String(): true}}, "octagon"},
+		{"custom traffic forwarding technology", types.TechnicalAsset{Type: types.Process, Technology: types.WebServer, CustomTechnology: "api-management",
+			TechnologyTraits: map[string]bool{types.TrafficForwardingTrait.String(): true}}, "hexagon"},
+		{"custom technology without the traits of its parent", types.TechnicalAsset{Type: types.Process, Technology: types.LoadBalancer, CustomTechnology: "feature-store",
+			TechnologyTraits: map[string]bool{types.TrafficForwardingTrait.String(): false}}, "ellipse"},
+	} {
+		assert.Equal(t, test.shape, determineShape(test.asset), test.name)
+	}
+}
+
+func TestMakeTechAssetNodeEncodesTechnologyName(t *testing.T) {
+	technicalAsset := types.TechnicalAsset{Id: "gateway", Title: "Gateway", Type: types.Process, Technology: types.WebServer,
+		CustomTechnology: "api-management & gateway", TechnologyTraits: map[string]bool{types.TrafficForwardingTrait.String(): true}}
+	parsedModel := &types.ParsedModel{
+		TechnicalAssets: map[string]types.TechnicalAsset{technicalAsset.Id: technicalAsset},
+	}
+
+	node := makeTechAssetNode(parsedModel, technicalAsset, false)
+	assert.Contains(t, node, ">api-management &amp; gateway</font>")
+	assert.NotContains(t, node, "api-management & gateway")
+	assert.Contains(t, node, "shape=hexagon ")
+}
+
+// createDiagramTooltipModel creates a web server with quotes and a backslash in its title, calling a database over a
+// link with a quote in its title, with two risks of the database (one of them mitigated) and one of the link
+func createDiagramTooltipModel() *types.ParsedModel {
+	link := types.CommunicationLink{Id: "web>query", SourceId: "web", TargetId: "db", Title: `"Query"`, Protocol: types.JDBC}
+	parsedModel := &types.ParsedModel{
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web": {Id: "web", Title: `Web "Shop" \ Frontend`, Type: types.Process, Technology: types.WebServer,
+				DataAssetsProcessed: []string{"orders"}, CommunicationLinks: []types.CommunicationLink{link}},
+			"db": {Id: "db", Title: "Database", Type: types.Datastore, Technology: types.Database, DataAssetsStored: []string{"orders"}},
+		},
+		DataAssets: map[string]types.DataAsset{
+			"orders": {Id: "orders", Title: `Orders "2024"`},
+		},
+		BuiltInRiskCategories: map[string]types.RiskCategory{
+			"sql-nosql-injection": {Id: "sql-nosql-injection", Title: "SQL/NoSQL-Injection"},
+			"unencrypted-asset":   {Id: "unencrypted-asset", Title: "Unencrypted Technical Assets"},
+		},
+		GeneratedRisksByCategory: map[string][]types.Risk{
+			"sql-nosql-injection": {{CategoryId: "sql-nosql-injection", Severity: types.CriticalSeverity, SyntheticId: "sql-nosql-injection@db@web>query",
+				MostRelevantTechnicalAssetId: "db", MostRelevantCommunicationLinkId: "web>query"}},
+			"unencrypted-asset": {{CategoryId: "unencrypted-asset", Severity: types.MediumSeverity, SyntheticId: "unencrypted-asset@db",
+				MostRelevantTechnicalAssetId: "db"}},
+		},
+		RiskTracking: map[string]types.RiskTracking{
+			"unencrypted-asset@db": {Status: types.Mitigated},
+		},
+		IncomingTechnicalCommunicationLinksMappedByTargetId: map[string][]types.CommunicationLink{"db": {link}},
+	}
+	return parsedModel
+}
+
+func readDOT(t *testing.T, write func(filename string) (*os.File, error)) string {
+	filename := filepath.Join(t.TempDir(), "diagram.gv")
+	file, err := write(filename)
+	assert.NoError(t, err)
+	if file != nil {
+		_ = file.Close()
+	}
+	data, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestDataFlowDiagramCarriesIdsAndTooltips(t *testing.T) {
+	parsedModel := createDiagramTooltipModel()
+	dot := readDOT(t, func(filename string) (*os.File, error) {
+		return WriteDataFlowDiagramGraphvizDOT(parsedModel, filename, 120, false, common.DefaultProgressReporter{SuppressError: true})
+	})
+
+	// nodes
+	assert.Contains(t, dot, `id="technical-asset:web" tooltip="Web \"Shop\" \\ Frontend [web]: 0 risks, 0 still at risk"`)
+	assert.Contains(t, dot, `id="technical-asset:db" tooltip="Database [db]: 2 risks, 1 still at risk"`)
+	// edges
+	assert.Regexp(t, regexp.QuoteMeta(hash("web")+" -> "+hash("db"))+`[^;]*`+
+		regexp.QuoteMeta(`id="communication-link:web>query" tooltip="\"Query\" (jdbc) [web>query]: 1 risk, 1 still at risk"`), dot)
+	assert.NotContains(t, dot, `tooltip="Web "Shop"`)
+}
+
+func TestDataAssetDiagramCarriesIdsAndTooltips(t *testing.T) {
+	parsedModel := createDiagramTooltipModel()
+	dot := readDOT(t, func(filename string) (*os.File, error) {
+		return WriteDataAssetDiagramGraphvizDOT(parsedModel, filename, 120, common.DefaultProgressReporter{SuppressError: true})
+	})
+
+	// nodes
+	assert.Contains(t, dot, `id="data-asset:orders" tooltip="Orders \"2024\" [orders]: `)
+	assert.Contains(t, dot, `id="technical-asset:web" tooltip="Web \"Shop\" \\ Frontend [web]: 0 risks, 0 still at risk"`)
+	// edges
+	assert.Regexp(t, regexp.QuoteMeta(hash("orders")+" -> "+hash("db"))+`[^;]*`+
+		regexp.QuoteMeta(`id="data-asset:orders@technical-asset:db" tooltip="Orders \"2024\" stored by Database"`), dot)
+	assert.Regexp(t, regexp.QuoteMeta(hash("orders")+" -> "+hash("web"))+`[^;]*`+
+		regexp.QuoteMeta(`id="data-asset:orders@technical-asset:web" tooltip="Orders \"2024\" processed by Web \"Shop\" \\ Frontend"`), dot)
+}
+
+func TestEscapeDOT(t *testing.T) {
+	assert.Equal(t, `say \"hi\" \\ bye`, escapeDOT(`say "hi" \ bye`))
+	assert.Equal(t, `two lines`, escapeDOT("two\nlines"))
+	assert.Equal(t, `\\\"`, escapeDOT(`\"`)) // the backslash is not taken as escaping the quote
+}
@@ -0,0 +1,403 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+//go:embed report.html.tmpl
+var htmlReportTemplate string
+
+const (
+	dataFlowDiagramElementId  = "data-flow-diagram"
+	dataAssetDiagramElementId = "data-asset-diagram"
+)
+
+type htmlReport struct {
+	Title                    string
+	Author                   string
+	Date                     string
+	BuildTimestamp           string
+	ModelHash                string
+	ManagementSummaryComment template.HTML
+	BusinessOverview         template.HTML
+	TechnicalOverview        template.HTML
+	BusinessCriticality      string
+	TotalRisks               int
+	Severities               []htmlCount
+	Statuses                 []htmlCount
+	RiskCategories           []htmlRiskCategory
+	TechnicalAssets          []htmlTechnicalAsset
+	DataAssets               []htmlDataAsset
+	STRIDE                   []htmlSTRIDE
+	RAA                      []htmlTechnicalAsset
+	Tags                     []htmlTag
+	DataFlowDiagram          template.HTML
+	DataAssetDiagram         template.HTML
+	DiagramLinks             map[string]map[string]string // per diagram: graphviz node name -> anchor
+	Style                    template.CSS
+}
+
+type htmlCount struct {
+	Value string
+	Title string
+	Count int
+}
+
+type htmlRisk struct {
+	SyntheticId        string
+	Title              template.HTML
+	Category           string
+	Severity           string
+	Likelihood         string
+	Impact             string
+	Status             string
+	StatusTitle        string
+	StillAtRisk        bool
+	Justification      string
+	TechnicalAssetIds  string // space separated for filtering
+	TechnicalAssetLink string
+	TechnicalAsset     string
+}
+
+type htmlRiskCategory struct {
+	Category types.RiskCategory
+	Function string
+	STRIDE   string
+	Severity string
+	Open     int
+	Risks    []htmlRisk
+}
+
+type htmlTechnicalAsset struct {
+	Asset           types.TechnicalAsset
+	RAA             string
+	Severity        string
+	Open            int
+	DataProcessed   []types.DataAsset
+	DataStored      []types.DataAsset
+	OutgoingLinks   []types.CommunicationLink
+	IncomingLinks   []types.CommunicationLink
+	TrustBoundary   string
+	Risks           []htmlRisk
+	Confidentiality string
+	Integrity       string
+	Availability    string
+}
+
+type htmlDataAsset struct {
+	Asset             types.DataAsset
+	BreachProbability string
+	ProcessedBy       []types.TechnicalAsset
+	StoredBy          []types.TechnicalAsset
+	Risks             []htmlRisk
+}
+
+type htmlSTRIDE struct {
+	Title      string
+	Categories []htmlRiskCategory
+}
+
+type htmlTag struct {
+	Tag             string
+	TechnicalAssets []types.TechnicalAsset
+	Links           []types.CommunicationLink
+	DataAssets      []types.DataAsset
+	TrustBoundaries []types.TrustBoundary
+	SharedRuntimes  []types.SharedRuntime
+}
+
+// WriteReportHTML writes a single self-contained HTML file: diagrams are embedded as inline SVG and all styles and
+// scripts are inlined, so the report works offline without any external resources
+func WriteReportHTML(parsedModel *types.ParsedModel, reportFilename string, dataFlowDiagramSVG []byte, dataAssetDiagramSVG []byte,
+	buildTimestamp string, modelHash string) error {
+	reportTemplate, err := template.New("report").Funcs(template.FuncMap{
+		"anchor": htmlAnchor,
+		"text":   htmlText,
+	}).Parse(htmlReportTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse html report template: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Clean(reportFilename), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create html report: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	err = reportTemplate.Execute(file, newHTMLReport(parsedModel, dataFlowDiagramSVG, dataAssetDiagramSVG, buildTimestamp, modelHash))
+	if err != nil {
+		return fmt.Errorf("failed to write html report: %w", err)
+	}
+	return nil
+}
+
+func newHTMLReport(parsedModel *types.ParsedModel, dataFlowDiagramSVG []byte, dataAssetDiagramSVG []byte, buildTimestamp string, modelHash string) htmlReport {
+	report := htmlReport{
+		Title:                    parsedModel.Title,
+		Author:                   parsedModel.Author.Name,
+		BuildTimestamp:           buildTimestamp,
+		ModelHash:                modelHash,
+		ManagementSummaryComment: htmlText(parsedModel.ManagementSummaryComment),
+		BusinessOverview:         htmlText(parsedModel.BusinessOverview.Description),
+		TechnicalOverview:        htmlText(parsedModel.TechnicalOverview.Description),
+		BusinessCriticality:      parsedModel.BusinessCriticality.String(),
+		TotalRisks:               types.TotalRiskCount(parsedModel),
+		DataFlowDiagram:          template.HTML(dataFlowDiagramSVG),  // #nosec G203 // generated by graphviz from escaped labels
+		DataAssetDiagram:         template.HTML(dataAssetDiagramSVG), // #nosec G203 // generated by graphviz from escaped labels
+		DiagramLinks: map[string]map[string]string{
+			dataFlowDiagramElementId:  make(map[string]string),
+			dataAssetDiagramElementId: make(map[string]string),
+		},
+		Style: htmlStyle(),
+	}
+	if !parsedModel.Date.IsZero() {
+		report.Date = parsedModel.Date.Format("2006-01-02")
+	}
+
+	report.Severities = []htmlCount{
+		{types.CriticalSeverity.String(), types.CriticalSeverity.Title(), len(types.FilteredByOnlyCriticalRisks(parsedModel))},
+		{types.HighSeverity.String(), types.HighSeverity.Title(), len(types.FilteredByOnlyHighRisks(parsedModel))},
+		{types.ElevatedSeverity.String(), types.ElevatedSeverity.Title(), len(types.FilteredByOnlyElevatedRisks(parsedModel))},
+		{types.MediumSeverity.String(), types.MediumSeverity.Title(), len(types.FilteredByOnlyMediumRisks(parsedModel))},
+		{types.LowSeverity.String(), types.LowSeverity.Title(), len(types.FilteredByOnlyLowRisks(parsedModel))},
+	}
+	report.Statuses = []htmlCount{
+		{types.Unchecked.String(), types.Unchecked.Title(), len(types.FilteredByRiskTrackingUnchecked(parsedModel))},
+		{types.InDiscussion.String(), types.InDiscussion.Title(), len(types.FilteredByRiskTrackingInDiscussion(parsedModel))},
+		{types.Accepted.String(), types.Accepted.Title(), len(types.FilteredByRiskTrackingAccepted(parsedModel))},
+		{types.InProgress.String(), types.InProgress.Title(), len(types.FilteredByRiskTrackingInProgress(parsedModel))},
+		{types.Mitigated.String(), types.Mitigated.Title(), len(types.FilteredByRiskTrackingMitigated(parsedModel))},
+		{types.FalsePositive.String(), types.FalsePositive.Title(), len(types.FilteredByRiskTrackingFalsePositive(parsedModel))},
+	}
+
+	categoriesById := make(map[string]htmlRiskCategory)
+	for _, category := range types.SortedRiskCategories(parsedModel) {
+		risks := types.SortedRisksOfCategory(parsedModel, category)
+		htmlCategory := htmlRiskCategory{
+			Category: category,
+			Function: category.Function.Title(),
+			STRIDE:   category.STRIDE.Title(),
+			Severity: htmlSeverity(parsedModel, risks),
+			Open:     len(types.ReduceToOnlyStillAtRisk(parsedModel, risks)),
+			Risks:    newHTMLRisks(parsedModel, risks),
+		}
+		categoriesById[category.Id] = htmlCategory
+		report.RiskCategories = append(report.RiskCategories, htmlCategory)
+	}
+
+	for _, stride := range []struct {
+		title string
+		risks map[string][]types.Risk
+	}{
+		{types.Spoofing.Title(), types.RisksOfOnlySTRIDESpoofing(parsedModel, parsedModel.GeneratedRisksByCategory)},
+		{types.Tampering.Title(), types.RisksOfOnlySTRIDETampering(parsedModel, parsedModel.GeneratedRisksByCategory)},
+		{types.Repudiation.Title(), types.RisksOfOnlySTRIDERepudiation(parsedModel, parsedModel.GeneratedRisksByCategory)},
+		{types.InformationDisclosure.Title(), types.RisksOfOnlySTRIDEInformationDisclosure(parsedModel, parsedModel.GeneratedRisksByCategory)},
+		{types.DenialOfService.Title(), types.RisksOfOnlySTRIDEDenialOfService(parsedModel, parsedModel.GeneratedRisksByCategory)},
+		{types.ElevationOfPrivilege.Title(), types.RisksOfOnlySTRIDEElevationOfPrivilege(parsedModel, parsedModel.GeneratedRisksByCategory)},
+	} {
+		htmlStride := htmlSTRIDE{Title: stride.title}
+		for _, category := range report.RiskCategories {
+			if _, ok := stride.risks[category.Category.Id]; ok {
+				htmlStride.Categories = append(htmlStride.Categories, categoriesById[category.Category.Id])
+			}
+		}
+		report.STRIDE = append(report.STRIDE, htmlStride)
+	}
+
+	for _, technicalAsset := range sortedTechnicalAssetsByRiskSeverityAndTitle(parsedModel) {
+		report.TechnicalAssets = append(report.TechnicalAssets, newHTMLTechnicalAsset(parsedModel, technicalAsset))
+		report.DiagramLinks[dataFlowDiagramElementId][hash(technicalAsset.Id)] = htmlAnchor("technical-asset", technicalAsset.Id)
+		report.DiagramLinks[dataAssetDiagramElementId][hash(technicalAsset.Id)] = htmlAnchor("technical-asset", technicalAsset.Id)
+	}
+	for _, technicalAsset := range sortedTechnicalAssetsByRAAAndTitle(parsedModel) {
+		if !technicalAsset.OutOfScope {
+			report.RAA = append(report.RAA, newHTMLTechnicalAsset(parsedModel, technicalAsset))
+		}
+	}
+
+	for _, dataAsset := range sortedDataAssetsByDataBreachProbabilityAndTitle(parsedModel) {
+		report.DataAssets = append(report.DataAssets, newHTMLDataAsset(parsedModel, dataAsset))
+		report.DiagramLinks[dataAssetDiagramElementId][hash(dataAsset.Id)] = htmlAnchor("data-asset", dataAsset.Id)
+	}
+
+	tags := append(make([]string, 0), parsedModel.TagsAvailable...)
+	sort.Strings(tags)
+	for _, tag := range tags {
+		htmlTag := htmlTag{Tag: tag}
+		for _, technicalAsset := range sortedTechnicalAssetsByTitle(parsedModel) {
+			if contains(technicalAsset.Tags, tag) {
+				htmlTag.TechnicalAssets = append(htmlTag.TechnicalAssets, technicalAsset)
+			}
+			for _, link := range technicalAsset.CommunicationLinksSorted() {
+				if contains(link.Tags, tag) {
+					htmlTag.Links = append(htmlTag.Links, link)
+				}
+			}
+		}
+		for _, dataAsset := range sortedDataAssetsByTitle(parsedModel) {
+			if contains(dataAsset.Tags, tag) {
+				htmlTag.DataAssets = append(htmlTag.DataAssets, dataAsset)
+			}
+		}
+		for _, trustBoundary := range sortedTrustBoundariesByTitle(parsedModel) {
+			if contains(trustBoundary.Tags, tag) {
+				htmlTag.TrustBoundaries = append(htmlTag.TrustBoundaries, trustBoundary)
+			}
+		}
+		for _, sharedRuntime := range sortedSharedRuntimesByTitle(parsedModel) {
+			if contains(sharedRuntime.Tags, tag) {
+				htmlTag.SharedRuntimes = append(htmlTag.SharedRuntimes, sharedRuntime)
+			}
+		}
+		report.Tags = append(report.Tags, htmlTag)
+	}
+
+	return report
+}
+
+func newHTMLRisks(parsedModel *types.ParsedModel, risks []types.Risk) []htmlRisk {
+	result := make([]htmlRisk, 0)
+	for _, risk := range risks {
+		status := risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel)
+		assetIds := make([]string, 0)
+		if len(risk.MostRelevantTechnicalAssetId) > 0 {
+			assetIds = append(assetIds, risk.MostRelevantTechnicalAssetId)
+		}
+		for _, id := range risk.DataBreachTechnicalAssetIDs {
+			if !contains(assetIds, id) {
+				assetIds = append(assetIds, id)
+			}
+		}
+
+		htmlRisk := htmlRisk{
+			SyntheticId:       risk.SyntheticId,
+			Title:             htmlRiskTitle(risk.Title),
+			Category:          risk.CategoryId,
+			Severity:          risk.Severity.String(),
+			Likelihood:        risk.ExploitationLikelihood.Title(),
+			Impact:            risk.ExploitationImpact.Title(),
+			Status:            status.String(),
+			StatusTitle:       status.Title(),
+			StillAtRisk:       status.IsStillAtRisk(),
+			Justification:     risk.GetRiskTracking(parsedModel).Justification,
+			TechnicalAssetIds: strings.Join(assetIds, " "),
+		}
+		if technicalAsset, ok := parsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]; ok {
+			htmlRisk.TechnicalAsset = technicalAsset.Title
+			htmlRisk.TechnicalAssetLink = htmlAnchor("technical-asset", technicalAsset.Id)
+		}
+		result = append(result, htmlRisk)
+	}
+	return result
+}
+
+func newHTMLTechnicalAsset(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset) htmlTechnicalAsset {
+	risks := technicalAsset.GeneratedRisks(parsedModel)
+	htmlAsset := htmlTechnicalAsset{
+		Asset:           technicalAsset,
+		RAA:             fmt.Sprintf("%.0f", technicalAsset.RAA),
+		Severity:        htmlSeverity(parsedModel, risks),
+		Open:            len(types.ReduceToOnlyStillAtRisk(parsedModel, risks)),
+		DataProcessed:   technicalAsset.DataAssetsProcessedSorted(parsedModel),
+		DataStored:      technicalAsset.DataAssetsStoredSorted(parsedModel),
+		OutgoingLinks:   technicalAsset.CommunicationLinksSorted(),
+		IncomingLinks:   parsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId[technicalAsset.Id],
+		Risks:           newHTMLRisks(parsedModel, risks),
+		Confidentiality: technicalAsset.Confidentiality.String(),
+		Integrity:       technicalAsset.Integrity.String(),
+		Availability:    technicalAsset.Availability.String(),
+	}
+	if technicalAsset.OutOfScope {
+		htmlAsset.Severity = "out-of-scope"
+	}
+	if trustBoundary, ok := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[technicalAsset.Id]; ok {
+		htmlAsset.TrustBoundary = trustBoundary.Title
+	}
+	return htmlAsset
+}
+
+func newHTMLDataAsset(parsedModel *types.ParsedModel, dataAsset types.DataAsset) htmlDataAsset {
+	risks := dataAsset.IdentifiedDataBreachProbabilityRisks(parsedModel)
+	types.SortByDataBreachProbability(risks, parsedModel)
+	htmlAsset := htmlDataAsset{
+		Asset:             dataAsset,
+		BreachProbability: dataAsset.IdentifiedDataBreachProbabilityStillAtRisk(parsedModel).String(),
+		ProcessedBy:       dataAsset.ProcessedByTechnicalAssetsSorted(parsedModel),
+		StoredBy:          dataAsset.StoredByTechnicalAssetsSorted(parsedModel),
+		Risks:             newHTMLRisks(parsedModel, risks),
+	}
+	if len(risks) == 0 {
+		htmlAsset.BreachProbability = ""
+	}
+	return htmlAsset
+}
+
+// htmlSeverity is the css class of the highest severity still at risk, or empty if there is no risk left
+func htmlSeverity(parsedModel *types.ParsedModel, risks []types.Risk) string {
+	if len(types.ReduceToOnlyStillAtRisk(parsedModel, risks)) == 0 {
+		return ""
+	}
+	return types.HighestSeverityStillAtRisk(parsedModel, risks).String()
+}
+
+// htmlRiskTitle escapes the risk title but keeps the bold markup used by all risk rules
+func htmlRiskTitle(title string) template.HTML {
+	escaped := template.HTMLEscapeString(title)
+	return template.HTML(strings.NewReplacer("&lt;b&gt;", "<b>", "&lt;/b&gt;", "</b>").Replace(escaped)) // #nosec G203 // escaped above
+}
+
+// htmlText escapes the text and keeps its line breaks
+func htmlText(text string) template.HTML {
+	escaped := template.HTMLEscapeString(strings.TrimSpace(text))
+	return template.HTML(strings.ReplaceAll(escaped, "\n", "<br>")) // #nosec G203 // escaped above
+}
+
+// htmlAnchor is the element id of a model element, ids with other characters than letters, digits, '-' and '_'
+// get the hash of the id appended, so that e.g. "a.b" and "a_b" don't share an anchor
+func htmlAnchor(kind string, id string) string {
+	replaced := false
+	anchor := kind + "-" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		replaced = true
+		return '_'
+	}, id)
+	if replaced {
+		anchor += "-" + hash(id)
+	}
+	return anchor
+}
+
+func htmlStyle() template.CSS {
+	var style strings.Builder
+	for _, color := range []struct {
+		class string
+		color string
+	}{
+		{"severity-" + types.CriticalSeverity.String(), rgbHexColorCriticalRisk()},
+		{"severity-" + types.HighSeverity.String(), rgbHexColorHighRisk()},
+		{"severity-" + types.ElevatedSeverity.String(), rgbHexColorElevatedRisk()},
+		{"severity-" + types.MediumSeverity.String(), rgbHexColorMediumRisk()},
+		{"severity-" + types.LowSeverity.String(), rgbHexColorLowRisk()},
+		{"severity-out-of-scope", rgbHexColorOutOfScope()},
+		{"status-" + types.Unchecked.String(), RgbHexColorRiskStatusUnchecked()},
+		{"status-" + types.InDiscussion.String(), rgbHexColorRiskStatusInDiscussion()},
+		{"status-" + types.Accepted.String(), rgbHexColorRiskStatusAccepted()},
+		{"status-" + types.InProgress.String(), rgbHexColorRiskStatusInProgress()},
+		{"status-" + types.Mitigated.String(), rgbHexColorRiskStatusMitigated()},
+		{"status-" + types.FalsePositive.String(), rgbHexColorRiskStatusFalsePositive()},
+	} {
+		style.WriteString("." + color.class + " { color: " + color.color + "; }\n")
+	}
+	return template.CSS(style.String()) // #nosec G203 // built from constants
+}
@@ -0,0 +1,115 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// createHTMLReportModel creates a web app (with a dot in its id) and a web service (with an underscore instead)
+// calling a database, with risks of different severities and statuses
+func createHTMLReportModel() *types.ParsedModel {
+	link := types.CommunicationLink{Id: "web.app>query", SourceId: "web.app", TargetId: "database", Title: "Query", Protocol: types.JDBC}
+	parsedModel := &types.ParsedModel{
+		Title: "Shop",
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web.app":  {Id: "web.app", Title: "Web App", Type: types.Process, CommunicationLinks: []types.CommunicationLink{link}},
+			"web_app":  {Id: "web_app", Title: "Web Service", Type: types.Process},
+			"database": {Id: "database", Title: "Database", Type: types.Datastore, DataAssetsStored: []string{"customers"}},
+		},
+		DataAssets: map[string]types.DataAsset{
+			"customers": {Id: "customers", Title: "Customers"},
+		},
+		BuiltInRiskCategories: map[string]types.RiskCategory{
+			"sql-nosql-injection": {Id: "sql-nosql-injection", Title: "SQL/NoSQL-Injection", STRIDE: types.Tampering, Function: types.Development,
+				CheatSheet: "https://cheatsheetseries.owasp.org/cheatsheets/SQL_Injection_Prevention_Cheat_Sheet.html"},
+			"unencrypted-asset": {Id: "unencrypted-asset", Title: "Unencrypted Technical Assets", STRIDE: types.InformationDisclosure, Function: types.Operations},
+		},
+		GeneratedRisksByCategory: map[string][]types.Risk{
+			"sql-nosql-injection": {{
+				CategoryId:                      "sql-nosql-injection",
+				Severity:                        types.CriticalSeverity,
+				Title:                           "<b>SQL Injection</b> at <b>Database</b>",
+				SyntheticId:                     "sql-nosql-injection@database@web.app>query",
+				MostRelevantTechnicalAssetId:    "database",
+				MostRelevantCommunicationLinkId: "web.app>query",
+				DataBreachTechnicalAssetIDs:     []string{"database"},
+			}},
+			"unencrypted-asset": {{
+				CategoryId:                   "unencrypted-asset",
+				Severity:                     types.MediumSeverity,
+				Title:                        "<b>Unencrypted Technical Asset</b> named <b>Web Service</b>",
+				SyntheticId:                  "unencrypted-asset@web_app",
+				MostRelevantTechnicalAssetId: "web_app",
+			}},
+		},
+		RiskTracking: map[string]types.RiskTracking{
+			"unencrypted-asset@web_app": {Status: types.Mitigated, Justification: "Encrypted volume"},
+		},
+		IncomingTechnicalCommunicationLinksMappedByTargetId: map[string][]types.CommunicationLink{"database": {link}},
+	}
+	return parsedModel
+}
+
+func writeHTMLReportForTest(t *testing.T, parsedModel *types.ParsedModel, dataFlowDiagramSVG string) string {
+	filename := filepath.Join(t.TempDir(), "report.html")
+	assert.NoError(t, WriteReportHTML(parsedModel, filename, []byte(dataFlowDiagramSVG), nil, "20240101", "abc"))
+	data, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestWriteReportHTML(t *testing.T) {
+	parsedModel := createHTMLReportModel()
+	dataFlowDiagramSVG := `<svg><g id="node1" class="node"><title>` + hash("web.app") + `</title></g></svg>`
+	report := writeHTMLReportForTest(t, parsedModel, dataFlowDiagramSVG)
+
+	for _, chapter := range []string{"management-summary", "data-flow-diagram-section", "risk-categories", "technical-assets", "data-assets", "stride", "raa", "tags"} {
+		assert.Contains(t, report, `<section id="`+chapter+`">`)
+		assert.Contains(t, report, `<a href="#`+chapter+`">`)
+	}
+
+	// the filters and the attributes of the risks they filter by
+	for _, filter := range []string{"filter-severity", "filter-status", "filter-asset"} {
+		assert.Contains(t, report, `<select id="`+filter+`">`)
+	}
+	assert.Contains(t, report, `<option value="critical">`)
+	assert.Contains(t, report, `<option value="mitigated">`)
+	assert.Contains(t, report, `<option value="web_app">Web Service</option>`)
+	assert.Contains(t, report, `data-category="risk-category-sql-nosql-injection" data-severity="critical" data-status="unchecked" data-still-at-risk="true" data-assets="database"`)
+	assert.Contains(t, report, `data-category="risk-category-unencrypted-asset" data-severity="medium" data-status="mitigated" data-still-at-risk="false" data-assets="web_app"`)
+
+	// the diagram is embedded and its nodes link to the sections of their assets
+	assert.Contains(t, report, dataFlowDiagramSVG)
+	webAppAnchor := htmlAnchor("technical-asset", "web.app")
+	assert.Contains(t, report, `<div class="section filterable" id="`+webAppAnchor+`">`)
+	assert.Regexp(t, `"`+hash("web.app")+`":\s*"`+webAppAnchor+`"`, report)
+	assert.Contains(t, report, `<a href="#`+webAppAnchor+`">web.app</a>`)
+
+	// nothing is loaded or linked from elsewhere, so the report works offline
+	assert.NotRegexp(t, regexp.MustCompile(`(?i)(src|href)\s*=\s*["']?\s*(https?:)?//`), report)
+	assert.Contains(t, report, "cheat sheet: https://cheatsheetseries.owasp.org/")
+}
+
+func TestHTMLAnchorsAreUnique(t *testing.T) {
+	assert.Equal(t, "technical-asset-web_app", htmlAnchor("technical-asset", "web_app"))
+	assert.Equal(t, "technical-asset-web-app", htmlAnchor("technical-asset", "web-app"))
+	assert.NotEqual(t, htmlAnchor("technical-asset", "web_app"), htmlAnchor("technical-asset", "web.app"))
+	assert.NotEqual(t, htmlAnchor("technical-asset", "web.app"), htmlAnchor("technical-asset", "web app"))
+	assert.Regexp(t, `^technical-asset-web_app-[0-9]+$`, htmlAnchor("technical-asset", "web.app"))
+
+	report := writeHTMLReportForTest(t, createHTMLReportModel(), "")
+	ids := make(map[string]int)
+	for _, match := range regexp.MustCompile(`\sid="([^"]+)"`).FindAllStringSubmatch(report, -1) {
+		ids[match[1]]++
+	}
+	for id, count := range ids {
+		assert.Equal(t, 1, count, id)
+	}
+	assert.Contains(t, ids, htmlAnchor("technical-asset", "web.app"))
+	assert.Contains(t, ids, htmlAnchor("technical-asset", "web_app"))
+}
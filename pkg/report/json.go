@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
@@ -43,6 +44,18 @@ func WriteTechnicalAssetsJSON(parsedModel *types.ParsedModel, filename string) e
 	return nil
 }
 
+func WriteAttackPathsJSON(parsedModel *types.ParsedModel, filename string) error {
+	jsonBytes, err := json.Marshal(parsedModel.AttackPaths)
+	if err != nil {
+		return fmt.Errorf("failed to marshal attack paths to JSON: %w", err)
+	}
+	err = os.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write attack paths to JSON file: %w", err)
+	}
+	return nil
+}
+
 func WriteStatsJSON(parsedModel *types.ParsedModel, filename string) error {
 	jsonBytes, err := json.Marshal(types.OverallRiskStatistics(parsedModel))
 	if err != nil {
@@ -54,3 +67,15 @@ func WriteStatsJSON(parsedModel *types.ParsedModel, filename string) error {
 	}
 	return nil
 }
+
+func WritePortfolioJSON(portfolio *model.PortfolioStatistics, filename string) error {
+	jsonBytes, err := json.Marshal(portfolio)
+	if err != nil {
+		return fmt.Errorf("failed to marshal portfolio stats to JSON: %w", err)
+	}
+	err = os.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write portfolio stats to JSON file: %w", err)
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/threagile/threagile/pkg/docs"
 	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/risks/builtin"
 	"github.com/threagile/threagile/pkg/security/types"
 	"github.com/wcharczuk/go-chart"
 	"github.com/wcharczuk/go-chart/drawing"
@@ -37,6 +38,8 @@ type pdfReporter struct {
 	tocLinkIdByAssetId            map[string]int
 	homeLink                      int
 	currentChapterTitleBreadcrumb string
+	subDiagramFilenamesPNG        map[string]string          // by diagram scope name, optional
+	portfolio                     *model.PortfolioStatistics // of the models combined into the reported one, optional
 }
 
 func (r *pdfReporter) initReport() {
@@ -77,6 +80,9 @@ func (r *pdfReporter) WriteReportPDF(reportFilename string,
 	if err != nil {
 		return fmt.Errorf("error creating management summary: %w", err)
 	}
+	if r.portfolio != nil {
+		r.createPortfolio()
+	}
 	r.createImpactInitialRisks(model)
 	err = r.createRiskMitigationStatus(model, tempFolder)
 	if err != nil {
@@ -95,6 +101,8 @@ func (r *pdfReporter) WriteReportPDF(reportFilename string,
 	r.createAssignmentByFunction(model)
 	r.createRAA(model, introTextRAA)
 	r.embedDataRiskMapping(dataAssetDiagramFilenamePNG, tempFolder)
+	r.createAttackPaths(model)
+	r.createOverdueRiskReviews(model)
 	//createDataRiskQuickWins()
 	r.createOutOfScopeAssets(model)
 	r.createModelFailures(model)
@@ -224,6 +232,14 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.ParsedModel) {
 	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
 	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
 
+	if r.portfolio != nil {
+		y += 6
+		r.pdf.Text(11, y, "    "+"Portfolio of "+strconv.Itoa(len(r.portfolio.Models))+" Models")
+		r.pdf.Text(175, y, "{portfolio}")
+		r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+		r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+	}
+
 	risksStr := "Risks"
 	catStr := "Categories"
 	count, catCount := types.TotalRiskCount(parsedModel), len(parsedModel.GeneratedRisksByCategory)
@@ -314,6 +330,32 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.ParsedModel) {
 	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
 	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
 
+	y += 6
+	paths := "Paths"
+	count = len(parsedModel.AttackPaths)
+	if count == 1 {
+		paths = "Path"
+	}
+	r.pdf.Text(11, y, "    "+"Attack Paths: "+strconv.Itoa(count)+" "+paths)
+	r.pdf.Text(175, y, "{attack-paths}")
+	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+
+	y += 6
+	reviews := "Reviews"
+	count = len(types.OverdueRiskReviews(parsedModel, types.Today()))
+	if count == 1 {
+		reviews = "Review"
+	}
+	if count > 0 {
+		colorModelFailure(r.pdf)
+	}
+	r.pdf.Text(11, y, "    "+"Overdue Risk Reviews: "+strconv.Itoa(count)+" "+reviews)
+	r.pdf.Text(175, y, "{overdue-risk-reviews}")
+	r.pdfColorBlack()
+	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+
 	/*
 		y += 6
 		assets := "assets"
@@ -1536,6 +1578,261 @@ func (r *pdfReporter) createRAA(parsedModel *types.ParsedModel, introTextRAA str
 	r.pdf.SetDashPattern([]float64{}, 0)
 }
 
+func (r *pdfReporter) createAttackPaths(parsedModel *types.ParsedModel) {
+	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	r.pdf.SetTextColor(0, 0, 0)
+	attackPaths := parsedModel.AttackPaths
+	paths := "Paths"
+	if len(attackPaths) == 1 {
+		paths = "Path"
+	}
+	chapTitle := "Attack Paths: " + strconv.Itoa(len(attackPaths)) + " " + paths
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{attack-paths}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	html := r.pdf.HTMLBasicNew()
+	var strBuilder strings.Builder
+	strBuilder.WriteString("This chapter lists the most likely multi-hop attack paths from internet-facing technical assets " +
+		"to the technical assets holding data assets rated as <b>" + types.StrictlyConfidential.String() + "</b> or <b>" +
+		types.MissionCritical.String() + "</b> (up to " + strconv.Itoa(parsedModel.MaxAttackPaths) + " per data asset). " +
+		"Each communication link along a path costs the attacker more when it is authenticated, encrypted, " +
+		"protected via VPN or IP filtering, crosses a network trust boundary or leads to a technical asset with a lower RAA value. " +
+		"The cheaper the path the more likely it is. Each path is also reported as a risk of the category <b>" +
+		uni(builtin.NewAttackPathRule().Category().Title) + "</b>.<br>")
+	html.Write(5, strBuilder.String())
+	strBuilder.Reset()
+	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdfColorGray()
+	html.Write(5, "Attack path paragraphs are clickable and link to the targeted technical asset.")
+	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+
+	categoryId := builtin.NewAttackPathRule().Category().Id
+	dataAssetId := ""
+	for _, path := range attackPaths {
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		} else {
+			strBuilder.WriteString("<br><br>")
+		}
+		if path.DataAssetId != dataAssetId {
+			dataAssetId = path.DataAssetId
+			r.pdfColorBlack()
+			strBuilder.WriteString("<b><u>")
+			strBuilder.WriteString(uni(parsedModel.DataAssets[dataAssetId].Title))
+			strBuilder.WriteString("</u></b><br><br>")
+		}
+		html.Write(5, strBuilder.String())
+		strBuilder.Reset()
+
+		risk, riskFound := parsedModel.GeneratedRisksBySyntheticId[strings.ToLower(categoryId+"@"+path.Key())]
+		if riskFound && risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel).IsStillAtRisk() {
+			switch risk.Severity {
+			case types.CriticalSeverity:
+				colorCriticalRisk(r.pdf)
+			case types.HighSeverity:
+				colorHighRisk(r.pdf)
+			case types.ElevatedSeverity:
+				colorElevatedRisk(r.pdf)
+			case types.MediumSeverity:
+				colorMediumRisk(r.pdf)
+			case types.LowSeverity:
+				colorLowRisk(r.pdf)
+			default:
+				r.pdfColorBlack()
+			}
+		} else {
+			r.pdfColorBlack()
+		}
+
+		posY := r.pdf.GetY()
+		for index, technicalAssetId := range path.TechnicalAssetIds {
+			if index > 0 {
+				strBuilder.WriteString(" -> ")
+			}
+			strBuilder.WriteString("<b>")
+			strBuilder.WriteString(uni(parsedModel.TechnicalAssets[technicalAssetId].Title))
+			strBuilder.WriteString("</b>")
+		}
+		strBuilder.WriteString("<br>")
+		html.Write(5, strBuilder.String())
+		strBuilder.Reset()
+		r.pdf.SetTextColor(0, 0, 0)
+		strBuilder.WriteString(fmt.Sprintf("Cost %.1f via %d communication link", path.Cost, len(path.CommunicationLinkIds)))
+		if len(path.CommunicationLinkIds) != 1 {
+			strBuilder.WriteString("s")
+		}
+		strBuilder.WriteString(": " + path.Likelihood.Title() + " likelihood, " + path.Impact.Title() + " impact")
+		if riskFound {
+			strBuilder.WriteString(", " + risk.Severity.Title() + " severity (" + risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel).Title() + ")")
+		}
+		html.Write(5, strBuilder.String())
+		strBuilder.Reset()
+		r.pdf.Link(9, posY, 190, r.pdf.GetY()-posY+4, r.tocLinkIdByAssetId[path.TargetId()])
+	}
+
+	if len(attackPaths) == 0 {
+		r.pdfColorGray()
+		html.Write(5, "<br><br>No attack paths from internet-facing technical assets to highly sensitive data assets have been identified.")
+	}
+
+	r.pdf.SetDrawColor(0, 0, 0)
+	r.pdf.SetDashPattern([]float64{}, 0)
+}
+
+func (r *pdfReporter) createPortfolio() {
+	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	r.pdf.SetTextColor(0, 0, 0)
+	chapTitle := "Portfolio of " + strconv.Itoa(len(r.portfolio.Models)) + " Models"
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{portfolio}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "This report covers the portfolio \""+uni(r.portfolio.Name)+"\" of the following models, analyzed as one "+
+		"combined model with the communication links and trust boundary memberships between them resolved. The titles of "+
+		"their elements are suffixed with the name of their model, their ids are prefixed with it (like <i>model#id</i>). "+
+		"Each risk is counted for the model of its most relevant element, the overall counts include the risks of all models. "+
+		"The last column counts the risks still at risk, i.e. not mitigated or false positive:<br><br>")
+
+	r.pdf.SetFont("Helvetica", "B", fontSizeSmall)
+	r.pdf.CellFormat(58, 6, "Model", "B", 0, "", false, 0, "")
+	r.pdf.CellFormat(16, 6, "Assets", "B", 0, "R", false, 0, "")
+	r.pdf.CellFormat(14, 6, "Links", "B", 0, "R", false, 0, "")
+	r.pdf.CellFormat(16, 6, "Cross-M.", "B", 0, "R", false, 0, "")
+	colorCriticalRisk(r.pdf)
+	r.pdf.CellFormat(14, 6, "Critical", "B", 0, "R", false, 0, "")
+	colorHighRisk(r.pdf)
+	r.pdf.CellFormat(12, 6, "High", "B", 0, "R", false, 0, "")
+	colorElevatedRisk(r.pdf)
+	r.pdf.CellFormat(16, 6, "Elevated", "B", 0, "R", false, 0, "")
+	colorMediumRisk(r.pdf)
+	r.pdf.CellFormat(14, 6, "Medium", "B", 0, "R", false, 0, "")
+	colorLowRisk(r.pdf)
+	r.pdf.CellFormat(12, 6, "Low", "B", 0, "R", false, 0, "")
+	r.pdfColorBlack()
+	r.pdf.CellFormat(16, 6, "Open", "B", 0, "R", false, 0, "")
+	r.pdf.Ln(-1)
+
+	severities := []types.RiskSeverity{types.CriticalSeverity, types.HighSeverity, types.ElevatedSeverity, types.MediumSeverity, types.LowSeverity}
+	severityWidths := []float64{14, 12, 16, 14, 12} // of the columns of the header
+	row := func(statistics model.ModelStatistics, title string) {
+		r.pdf.CellFormat(58, 6, uni(title), "0", 0, "", false, 0, "")
+		r.pdf.CellFormat(16, 6, strconv.Itoa(statistics.TechnicalAssets), "0", 0, "R", false, 0, "")
+		r.pdf.CellFormat(14, 6, strconv.Itoa(statistics.CommunicationLinks), "0", 0, "R", false, 0, "")
+		r.pdf.CellFormat(16, 6, strconv.Itoa(statistics.CrossModelCommunication), "0", 0, "R", false, 0, "")
+		stillAtRisk := 0
+		for i, severity := range severities {
+			count := 0
+			for _, status := range types.RiskStatusValues() {
+				count += statistics.Risks[severity.String()][status.String()]
+				if status.(types.RiskStatus).IsStillAtRisk() {
+					stillAtRisk += statistics.Risks[severity.String()][status.String()]
+				}
+			}
+			r.pdf.CellFormat(severityWidths[i], 6, strconv.Itoa(count), "0", 0, "R", false, 0, "")
+		}
+		r.pdf.CellFormat(16, 6, strconv.Itoa(stillAtRisk), "0", 0, "R", false, 0, "")
+		r.pdf.Ln(-1)
+	}
+	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	for _, statistics := range r.portfolio.Models {
+		if r.pdf.GetY() > 260 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		}
+		row(statistics, statistics.Title+" ("+statistics.Name+")")
+	}
+	r.pdf.SetFont("Helvetica", "B", fontSizeSmall)
+	row(r.portfolio.Overall, "Overall")
+	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+}
+
+func (r *pdfReporter) createOverdueRiskReviews(parsedModel *types.ParsedModel) {
+	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	r.pdf.SetTextColor(0, 0, 0)
+	overdueRiskReviews := types.OverdueRiskReviews(parsedModel, types.Today())
+	reviews := "Reviews"
+	if len(overdueRiskReviews) == 1 {
+		reviews = "Review"
+	}
+	chapTitle := "Overdue Risk Reviews: " + strconv.Itoa(len(overdueRiskReviews)) + " " + reviews
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{overdue-risk-reviews}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	html := r.pdf.HTMLBasicNew()
+	var strBuilder strings.Builder
+	strBuilder.WriteString("This chapter lists all risks whose risk tracking has expired or whose review date has passed " +
+		"as of " + types.Today().Format("2006-01-02") + ", the longest overdue ones first. " +
+		"An expired risk tracking no longer applies: its risk is treated as <b>" + types.Unchecked.Title() + "</b> again " +
+		"until the risk tracking has been reviewed and renewed in the model:<br>")
+	html.Write(5, strBuilder.String())
+	strBuilder.Reset()
+	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdfColorGray()
+	html.Write(5, "Risk paragraphs are clickable and link to the corresponding chapter.")
+	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+
+	for _, review := range overdueRiskReviews {
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		} else {
+			strBuilder.WriteString("<br><br>")
+		}
+		html.Write(5, strBuilder.String())
+		strBuilder.Reset()
+		switch review.Severity {
+		case types.CriticalSeverity:
+			colorCriticalRisk(r.pdf)
+		case types.HighSeverity:
+			colorHighRisk(r.pdf)
+		case types.ElevatedSeverity:
+			colorElevatedRisk(r.pdf)
+		case types.MediumSeverity:
+			colorMediumRisk(r.pdf)
+		case types.LowSeverity:
+			colorLowRisk(r.pdf)
+		default:
+			r.pdfColorBlack()
+		}
+		if !review.Status.IsStillAtRisk() {
+			r.pdfColorBlack()
+		}
+
+		posY := r.pdf.GetY()
+		strBuilder.WriteString(uni(review.Title))
+		strBuilder.WriteString("<br>")
+		html.Write(5, strBuilder.String())
+		strBuilder.Reset()
+		r.pdf.SetTextColor(0, 0, 0)
+		strBuilder.WriteString(review.Reason() + " on <b>" + review.DueDate.Format("2006-01-02") + "</b>: " +
+			review.Severity.Title() + " severity (" + review.Status.Title() + ")")
+		if len(review.Owner) > 0 {
+			strBuilder.WriteString(", owned by " + uni(review.Owner))
+		}
+		html.Write(5, strBuilder.String())
+		strBuilder.Reset()
+		r.pdf.SetFont("Helvetica", "", fontSizeVerySmall)
+		r.pdfColorGray()
+		html.Write(5, "<br>"+uni(review.SyntheticRiskId))
+		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdfColorBlack()
+		risk := parsedModel.GeneratedRisksBySyntheticId[review.SyntheticRiskId]
+		r.pdf.Link(9, posY, 190, r.pdf.GetY()-posY+4, r.tocLinkIdByAssetId[risk.CategoryId])
+	}
+
+	if len(overdueRiskReviews) == 0 {
+		r.pdfColorGray()
+		html.Write(5, "<br><br>No risk trackings have expired and no risk reviews are overdue.")
+	}
+
+	r.pdf.SetDrawColor(0, 0, 0)
+	r.pdf.SetDashPattern([]float64{}, 0)
+}
+
 /*
 func createDataRiskQuickWins() {
 	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
@@ -2553,6 +2850,12 @@ func (r *pdfReporter) writeRiskTrackingStatus(parsedModel *types.ParsedModel, ri
 		r.pdf.CellFormat(10, 4, "", "0", 0, "", false, 0, "")
 		r.pdf.MultiCell(170, 4, uni(justificationStr), "0", "0", false)
 		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	} else if tracking.Expired {
+		r.pdfColorGray()
+		r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+		r.pdf.CellFormat(90, 4, tracking.ExpiredStatus.Title()+" until "+tracking.Expires.Format("2006-01-02")+" (expired)", "0", 0, "B", false, 0, "")
+		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+		r.pdf.Ln(-1)
 	} else {
 		r.pdf.Ln(-1)
 	}
@@ -2807,7 +3110,7 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.ParsedModel) {
 		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
 		r.pdf.CellFormat(40, 6, "Technology:", "0", 0, "", false, 0, "")
 		r.pdfColorBlack()
-		r.pdf.MultiCell(145, 6, technicalAsset.Technology.String(), "0", "0", false)
+		r.pdf.MultiCell(145, 6, technicalAsset.TechnologyName(), "0", "0", false)
 		if r.pdf.GetY() > 270 {
 			r.pageBreak()
 			r.pdf.SetY(36)
@@ -3368,6 +3671,7 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.ParsedModel) {
 				r.pdf.Ln(-1)
 			}
 		}
+		r.embedSubDiagram(technicalAssetDiagramScopeName(technicalAsset.Id))
 	}
 }
 
@@ -3908,6 +4212,7 @@ func (r *pdfReporter) createTrustBoundaries(parsedModel *types.ParsedModel) {
 			boundariesNestedText = "none"
 		}
 		r.pdf.MultiCell(145, 6, uni(boundariesNestedText), "0", "0", false)
+		r.embedSubDiagram(trustBoundaryDiagramScopeName(trustBoundary.Id))
 	}
 }
 
@@ -4398,6 +4703,38 @@ func (r *pdfReporter) embedDataFlowDiagram(diagramFilenamePNG string, tempFolder
 	}
 }
 
+// embedSubDiagram embeds the focused data-flow diagram (if rendered) scaled down to fit below the current chapter
+func (r *pdfReporter) embedSubDiagram(scopeName string) {
+	diagramFilenamePNG, ok := r.subDiagramFilenamesPNG[scopeName]
+	if !ok {
+		return
+	}
+	/* #nosec diagramFilenamePNG is not tainted */
+	imageFile, err := os.Open(diagramFilenamePNG)
+	if err != nil {
+		return
+	}
+	defer func() { _ = imageFile.Close() }()
+	imageConfig, _, err := image.DecodeConfig(imageFile)
+	if err != nil || imageConfig.Width == 0 || imageConfig.Height == 0 {
+		return
+	}
+
+	maxWidth, maxHeight := 180.0, 120.0
+	embedWidth, embedHeight := maxWidth, maxWidth*float64(imageConfig.Height)/float64(imageConfig.Width)
+	if embedHeight > maxHeight {
+		embedWidth, embedHeight = maxHeight*float64(imageConfig.Width)/float64(imageConfig.Height), maxHeight
+	}
+	r.pdf.Ln(5)
+	if r.pdf.GetY()+embedHeight > 270 {
+		r.pageBreak()
+		r.pdf.SetY(36)
+	}
+	var options gofpdf.ImageOptions
+	r.pdf.RegisterImage(diagramFilenamePNG, "")
+	r.pdf.ImageOptions(diagramFilenamePNG, 15+(maxWidth-embedWidth)/2, r.pdf.GetY(), embedWidth, embedHeight, true, options, 0, "")
+}
+
 func sortedKeysOfIndividualRiskCategories(parsedModel *types.ParsedModel) []string {
 	keys := make([]string, 0)
 	for k := range parsedModel.IndividualRiskCategories {
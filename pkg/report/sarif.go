@@ -0,0 +1,279 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name,omitempty"`
+	ShortDescription     sarifMessage           `json:"shortDescription"`
+	FullDescription      sarifMessage           `json:"fullDescription,omitempty"`
+	HelpURI              string                 `json:"helpUri,omitempty"`
+	Help                 sarifMessage           `json:"help,omitempty"`
+	DefaultConfiguration sarifRuleConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	Suppressions        []sarifSuppression     `json:"suppressions,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name,omitempty"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status,omitempty"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// WriteRisksSARIF writes all generated risks as a SARIF 2.1.0 log, one rule per risk category and one result per risk
+func WriteRisksSARIF(parsedModel *types.ParsedModel, modelFilename string, filename string) error {
+	jsonBytes, err := json.MarshalIndent(createSarifLog(parsedModel, modelFilename), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal risks to SARIF: %w", err)
+	}
+	err = os.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write risks to SARIF file: %w", err)
+	}
+	return nil
+}
+
+func createSarifLog(parsedModel *types.ParsedModel, modelFilename string) sarifLog {
+	rules := make([]sarifRule, 0)
+	results := make([]sarifResult, 0)
+	for ruleIndex, category := range types.SortedRiskCategories(parsedModel) {
+		risks := types.SortedRisksOfCategory(parsedModel, category)
+		rules = append(rules, createSarifRule(category, types.HighestSeverity(risks)))
+		for _, risk := range risks {
+			results = append(results, createSarifResult(parsedModel, risk, ruleIndex, modelFilename))
+		}
+	}
+
+	return sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{
+				Driver: sarifDriver{
+					Name:           "Threagile",
+					Version:        docs.ThreagileVersion,
+					InformationURI: "https://threagile.io",
+					Rules:          rules,
+				},
+			},
+			Results: results,
+		}},
+	}
+}
+
+func createSarifRule(category types.RiskCategory, highestSeverity types.RiskSeverity) sarifRule {
+	tags := []string{"security", category.STRIDE.Title(), category.Function.Title()}
+	if category.CWE > 0 {
+		tags = append(tags, "external/cwe/cwe-"+strconv.Itoa(category.CWE))
+	}
+
+	help := category.Mitigation
+	if len(category.Check) > 0 {
+		help += "\n\nCheck: " + category.Check
+	}
+	if len(category.ASVS) > 0 {
+		help += "\n\nASVS: " + category.ASVS
+	}
+	if len(category.CheatSheet) > 0 {
+		help += "\n\nCheat Sheet: " + category.CheatSheet
+	}
+
+	properties := map[string]interface{}{
+		"tags":              tags,
+		"stride":            category.STRIDE.String(),
+		"function":          category.Function.String(),
+		"action":            category.Action,
+		"mitigation":        category.Mitigation,
+		"security-severity": sarifSecuritySeverity(highestSeverity),
+	}
+	if category.CWE > 0 {
+		properties["cwe"] = "CWE-" + strconv.Itoa(category.CWE)
+	}
+
+	return sarifRule{
+		ID:                   category.Id,
+		Name:                 category.Title,
+		ShortDescription:     sarifMessage{Text: category.Title},
+		FullDescription:      sarifMessage{Text: removeFormattingTags(category.Description).(string)},
+		HelpURI:              category.CheatSheet,
+		Help:                 sarifMessage{Text: removeFormattingTags(help).(string)},
+		DefaultConfiguration: sarifRuleConfiguration{Level: sarifLevel(highestSeverity)},
+		Properties:           properties,
+	}
+}
+
+func createSarifResult(parsedModel *types.ParsedModel, risk types.Risk, ruleIndex int, modelFilename string) sarifResult {
+	riskTracking := risk.GetRiskTracking(parsedModel)
+	riskStatus := risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel)
+
+	result := sarifResult{
+		RuleID:    risk.CategoryId,
+		RuleIndex: ruleIndex,
+		Level:     sarifLevel(risk.Severity),
+		Message:   sarifMessage{Text: removeFormattingTags(risk.Title).(string)},
+		Locations: []sarifLocation{{
+			PhysicalLocation: &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(modelFilename)},
+			},
+			LogicalLocations: sarifLogicalLocations(risk),
+		}},
+		PartialFingerprints: map[string]string{
+			"syntheticId": risk.SyntheticId,
+		},
+		Properties: map[string]interface{}{
+			"synthetic_id":            risk.SyntheticId,
+			"severity":                risk.Severity.String(),
+			"exploitation_likelihood": risk.ExploitationLikelihood.String(),
+			"exploitation_impact":     risk.ExploitationImpact.String(),
+			"data_breach_probability": risk.DataBreachProbability.String(),
+			"risk_status":             riskStatus.String(),
+			"security-severity":       sarifSecuritySeverity(risk.Severity),
+		},
+	}
+	if len(riskTracking.Ticket) > 0 {
+		result.Properties["ticket"] = riskTracking.Ticket
+	}
+
+	suppressionStatus := sarifSuppressionStatus(riskStatus)
+	if len(suppressionStatus) > 0 {
+		result.Suppressions = []sarifSuppression{{
+			Kind:          "external",
+			Status:        suppressionStatus,
+			Justification: riskTracking.Justification,
+		}}
+	}
+
+	return result
+}
+
+func sarifLogicalLocations(risk types.Risk) []sarifLogicalLocation {
+	locations := make([]sarifLogicalLocation, 0)
+	if len(risk.MostRelevantTechnicalAssetId) > 0 {
+		locations = append(locations, sarifLogicalLocation{Name: risk.MostRelevantTechnicalAssetId, FullyQualifiedName: "technical_assets/" + risk.MostRelevantTechnicalAssetId, Kind: "technical-asset"})
+	}
+	if len(risk.MostRelevantCommunicationLinkId) > 0 {
+		locations = append(locations, sarifLogicalLocation{Name: risk.MostRelevantCommunicationLinkId, FullyQualifiedName: "communication_links/" + risk.MostRelevantCommunicationLinkId, Kind: "communication-link"})
+	}
+	if len(risk.MostRelevantDataAssetId) > 0 {
+		locations = append(locations, sarifLogicalLocation{Name: risk.MostRelevantDataAssetId, FullyQualifiedName: "data_assets/" + risk.MostRelevantDataAssetId, Kind: "data-asset"})
+	}
+	if len(risk.MostRelevantTrustBoundaryId) > 0 {
+		locations = append(locations, sarifLogicalLocation{Name: risk.MostRelevantTrustBoundaryId, FullyQualifiedName: "trust_boundaries/" + risk.MostRelevantTrustBoundaryId, Kind: "trust-boundary"})
+	}
+	if len(risk.MostRelevantSharedRuntimeId) > 0 {
+		locations = append(locations, sarifLogicalLocation{Name: risk.MostRelevantSharedRuntimeId, FullyQualifiedName: "shared_runtimes/" + risk.MostRelevantSharedRuntimeId, Kind: "shared-runtime"})
+	}
+	return locations
+}
+
+func sarifLevel(severity types.RiskSeverity) string {
+	switch severity {
+	case types.CriticalSeverity, types.HighSeverity:
+		return "error"
+	case types.ElevatedSeverity, types.MediumSeverity:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// security-severity is the CVSS-like score code scanning dashboards use for ranking
+func sarifSecuritySeverity(severity types.RiskSeverity) string {
+	switch severity {
+	case types.CriticalSeverity:
+		return "9.5"
+	case types.HighSeverity:
+		return "8.0"
+	case types.ElevatedSeverity:
+		return "6.5"
+	case types.MediumSeverity:
+		return "5.0"
+	default:
+		return "2.0"
+	}
+}
+
+// risks no longer at risk are suppressed, risks still being worked on are flagged as under review
+func sarifSuppressionStatus(status types.RiskStatus) string {
+	switch status {
+	case types.Mitigated, types.FalsePositive:
+		return "accepted"
+	case types.Accepted, types.InDiscussion, types.InProgress:
+		return "underReview"
+	default:
+		return ""
+	}
+}
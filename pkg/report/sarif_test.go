@@ -0,0 +1,91 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestWriteRisksSARIF(t *testing.T) {
+	parsedModel := &types.ParsedModel{
+		BuiltInRiskCategories: map[string]types.RiskCategory{
+			"sql-nosql-injection":       {Id: "sql-nosql-injection", Title: "SQL/NoSQL-Injection", Description: "<b>Injection</b>", CWE: 89, STRIDE: types.Tampering, Function: types.Development},
+			"unencrypted-asset":         {Id: "unencrypted-asset", Title: "Unencrypted Technical Assets", STRIDE: types.InformationDisclosure, Function: types.Operations},
+			"unencrypted-communication": {Id: "unencrypted-communication", Title: "Unencrypted Communication", STRIDE: types.InformationDisclosure, Function: types.Operations},
+		},
+		GeneratedRisksByCategory: map[string][]types.Risk{
+			"sql-nosql-injection": {{
+				CategoryId:                      "sql-nosql-injection",
+				Severity:                        types.CriticalSeverity,
+				Title:                           "<b>SQL Injection</b> at <b>Database</b>",
+				SyntheticId:                     "sql-nosql-injection@database@web-app>query",
+				MostRelevantTechnicalAssetId:    "database",
+				MostRelevantCommunicationLinkId: "web-app>query",
+			}},
+			"unencrypted-asset": {{
+				CategoryId:                   "unencrypted-asset",
+				Severity:                     types.MediumSeverity,
+				Title:                        "<b>Unencrypted Technical Asset</b> named <b>Database</b>",
+				SyntheticId:                  "unencrypted-asset@database",
+				MostRelevantTechnicalAssetId: "database",
+			}},
+			"unencrypted-communication": {{
+				CategoryId:                      "unencrypted-communication",
+				Severity:                        types.LowSeverity,
+				Title:                           "<b>Unencrypted Communication</b>",
+				SyntheticId:                     "unencrypted-communication@web-app>query",
+				MostRelevantCommunicationLinkId: "web-app>query",
+			}},
+		},
+		RiskTracking: map[string]types.RiskTracking{
+			"unencrypted-asset@database": {Status: types.Mitigated, Justification: "Encrypted volume"},
+		},
+	}
+
+	filename := filepath.Join(t.TempDir(), "risks.sarif")
+	assert.NoError(t, WriteRisksSARIF(parsedModel, filepath.Join("models", "threagile.yaml"), filename))
+	data, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(data, &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Equal(t, sarifSchemaURI, log.Schema)
+	assert.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "Threagile", run.Tool.Driver.Name)
+
+	ruleIds := make([]string, 0)
+	for _, rule := range run.Tool.Driver.Rules {
+		ruleIds = append(ruleIds, rule.ID)
+	}
+	assert.Equal(t, []string{"sql-nosql-injection", "unencrypted-communication", "unencrypted-asset"}, ruleIds) // by severity still at risk
+	assert.Equal(t, "error", run.Tool.Driver.Rules[0].DefaultConfiguration.Level)
+	assert.Equal(t, "Injection", run.Tool.Driver.Rules[0].FullDescription.Text)
+	assert.Equal(t, "CWE-89", run.Tool.Driver.Rules[0].Properties["cwe"])
+
+	levels := make(map[string]string)
+	for _, result := range run.Results {
+		assert.Equal(t, result.RuleID, run.Tool.Driver.Rules[result.RuleIndex].ID)
+		assert.NotEmpty(t, result.Message.Text)
+		levels[result.RuleID] = result.Level
+		assert.Equal(t, "models/threagile.yaml", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+	assert.Equal(t, map[string]string{"sql-nosql-injection": "error", "unencrypted-asset": "warning", "unencrypted-communication": "note"}, levels)
+
+	injection := run.Results[0]
+	assert.Equal(t, "SQL Injection at Database", injection.Message.Text)
+	assert.Equal(t, []sarifLogicalLocation{
+		{Name: "database", FullyQualifiedName: "technical_assets/database", Kind: "technical-asset"},
+		{Name: "web-app>query", FullyQualifiedName: "communication_links/web-app>query", Kind: "communication-link"},
+	}, injection.Locations[0].LogicalLocations)
+	assert.Equal(t, "sql-nosql-injection@database@web-app>query", injection.PartialFingerprints["syntheticId"])
+	assert.Empty(t, injection.Suppressions)
+
+	mitigated := run.Results[2]
+	assert.Equal(t, []sarifSuppression{{Kind: "external", Status: "accepted", Justification: "Encrypted volume"}}, mitigated.Suppressions)
+}
@@ -0,0 +1,223 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// DiagramScope selects the technical assets of a focused data-flow diagram: technical assets outside the scope
+// which are connected to it are collapsed into one placeholder node per trust boundary, all others are omitted
+type DiagramScope struct {
+	Name              string // used as filename suffix
+	Title             string
+	TechnicalAssetIds map[string]bool
+}
+
+func NewTrustBoundaryDiagramScope(parsedModel *types.ParsedModel, trustBoundaryId string) (*DiagramScope, error) {
+	trustBoundary, ok := parsedModel.TrustBoundaries[trustBoundaryId]
+	if !ok {
+		return nil, fmt.Errorf("unknown trust boundary %q", trustBoundaryId)
+	}
+	return newDiagramScope(trustBoundaryDiagramScopeName(trustBoundary.Id), trustBoundary.Title, trustBoundary.RecursivelyAllTechnicalAssetIDsInside(parsedModel))
+}
+
+// NewTechnicalAssetDiagramScope selects the technical asset and all technical assets reachable within the given
+// number of hops via communication links in either direction
+func NewTechnicalAssetDiagramScope(parsedModel *types.ParsedModel, technicalAssetId string, hops int) (*DiagramScope, error) {
+	technicalAsset, ok := parsedModel.TechnicalAssets[technicalAssetId]
+	if !ok {
+		return nil, fmt.Errorf("unknown technical asset %q", technicalAssetId)
+	}
+	if hops < 0 {
+		return nil, fmt.Errorf("invalid number of hops: %d", hops)
+	}
+
+	ids := []string{technicalAsset.Id}
+	visited := map[string]bool{technicalAsset.Id: true}
+	frontier := []string{technicalAsset.Id}
+	for hop := 0; hop < hops && len(frontier) > 0; hop++ {
+		next := make([]string, 0)
+		for _, id := range frontier {
+			neighbours := make([]string, 0)
+			for _, link := range parsedModel.TechnicalAssets[id].CommunicationLinks {
+				neighbours = append(neighbours, link.TargetId)
+			}
+			for _, link := range parsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId[id] {
+				neighbours = append(neighbours, link.SourceId)
+			}
+			for _, neighbour := range neighbours {
+				if _, exists := parsedModel.TechnicalAssets[neighbour]; exists && !visited[neighbour] {
+					visited[neighbour] = true
+					ids = append(ids, neighbour)
+					next = append(next, neighbour)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	title := technicalAsset.Title
+	if hops > 0 {
+		title += " (" + strconv.Itoa(hops) + "-hop neighbourhood)"
+	}
+	return newDiagramScope(technicalAssetDiagramScopeName(technicalAsset.Id), title, ids)
+}
+
+// NewTagsDiagramScope selects all technical assets tagged with any of the tags
+func NewTagsDiagramScope(parsedModel *types.ParsedModel, tags []string) (*DiagramScope, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tags given")
+	}
+	ids := make([]string, 0)
+	for _, technicalAsset := range parsedModel.TechnicalAssets {
+		if technicalAsset.IsTaggedWithAny(tags...) {
+			ids = append(ids, technicalAsset.Id)
+		}
+	}
+	return newDiagramScope("tags-"+strings.Join(tags, "-"), "Tagged with "+strings.Join(tags, ", "), ids)
+}
+
+func trustBoundaryDiagramScopeName(trustBoundaryId string) string {
+	return "trust-boundary-" + trustBoundaryId
+}
+
+func technicalAssetDiagramScopeName(technicalAssetId string) string {
+	return "technical-asset-" + technicalAssetId
+}
+
+func newDiagramScope(name string, title string, technicalAssetIds []string) (*DiagramScope, error) {
+	if len(technicalAssetIds) == 0 {
+		return nil, fmt.Errorf("no technical assets in scope of diagram %q", title)
+	}
+	scope := &DiagramScope{Name: name, Title: title, TechnicalAssetIds: make(map[string]bool)}
+	for _, id := range technicalAssetIds {
+		scope.TechnicalAssetIds[id] = true
+	}
+	return scope, nil
+}
+
+// Contains tells whether the technical asset is drawn, an unscoped diagram contains all technical assets
+func (what *DiagramScope) Contains(technicalAssetId string) bool {
+	return what == nil || what.TechnicalAssetIds[technicalAssetId]
+}
+
+func (what *DiagramScope) containsTrustBoundary(parsedModel *types.ParsedModel, trustBoundary types.TrustBoundary) bool {
+	if what == nil {
+		return true
+	}
+	for _, id := range trustBoundary.RecursivelyAllTechnicalAssetIDsInside(parsedModel) {
+		if what.TechnicalAssetIds[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholders groups the technical assets outside the scope, which communicate with technical assets inside,
+// by the trust boundary directly containing them (an empty trust boundary ID for assets outside of any boundary)
+func (what *DiagramScope) placeholders(parsedModel *types.ParsedModel) map[string][]string {
+	result := make(map[string][]string)
+	if what == nil {
+		return result
+	}
+
+	outside := make(map[string]bool)
+	for _, technicalAsset := range parsedModel.TechnicalAssets {
+		for _, link := range technicalAsset.CommunicationLinks {
+			if what.Contains(link.SourceId) != what.Contains(link.TargetId) {
+				if what.Contains(link.SourceId) {
+					outside[link.TargetId] = true
+				} else {
+					outside[link.SourceId] = true
+				}
+			}
+		}
+	}
+
+	for id := range outside {
+		trustBoundaryId := parsedModel.TechnicalAssets[id].GetTrustBoundaryId(parsedModel)
+		result[trustBoundaryId] = append(result[trustBoundaryId], id)
+	}
+	for _, ids := range result {
+		sort.Strings(ids)
+	}
+	return result
+}
+
+// nodeOf returns the node drawing the technical asset: either its own or the placeholder of its trust boundary
+func (what *DiagramScope) nodeOf(parsedModel *types.ParsedModel, technicalAssetId string) string {
+	if what.Contains(technicalAssetId) {
+		return hash(technicalAssetId)
+	}
+	return placeholderNode(parsedModel.TechnicalAssets[technicalAssetId].GetTrustBoundaryId(parsedModel))
+}
+
+func placeholderNode(trustBoundaryId string) string {
+	return hash("placeholder:" + trustBoundaryId)
+}
+
+func makePlaceholderNode(parsedModel *types.ParsedModel, trustBoundaryId string, technicalAssetIds []string) string {
+	titles := make([]string, 0)
+	for _, id := range technicalAssetIds {
+		titles = append(titles, parsedModel.TechnicalAssets[id].Title)
+	}
+	location := "outside of trust boundaries"
+	if trustBoundary, ok := parsedModel.TrustBoundaries[trustBoundaryId]; ok {
+		location = "in " + trustBoundary.Title
+	}
+	assets := "technical assets"
+	if len(technicalAssetIds) == 1 {
+		assets = "technical asset"
+	}
+	return "  " + placeholderNode(trustBoundaryId) + ` [ shape="box" style="dashed,rounded" color="` + MiddleLightGray + `" fontcolor="` + LightGray + `"
+	label=<<b>` + strconv.Itoa(len(technicalAssetIds)) + ` more ` + assets + `</b><br/><font point-size="15">` + encode(location) + `</font>>
+	id="` + escapeDOT("placeholder:"+trustBoundaryId) + `" tooltip="` + escapeDOT(strings.Join(titles, ", ")) + `"
+  ];
+`
+}
+
+var subDiagramFilenameCleanup = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// SubDiagramFilename derives the filename of the scoped diagram from the filename of the full diagram,
+// e.g. data-flow-diagram.png becomes data-flow-diagram-trust-boundary-web-dmz.png
+func SubDiagramFilename(filename string, scope *DiagramScope) string {
+	extension := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, extension) + "-" + subDiagramFilenameCleanup.ReplaceAllString(scope.Name, "-") + extension
+}
+
+// GenerateSubDiagram writes the scoped data flow diagram and renders it in all given formats, each file is named
+// like the PNG file with the format as extension
+func GenerateSubDiagram(parsedModel *types.ParsedModel, scope *DiagramScope,
+	diagramFilenameDOT string, diagramFilenamePNG string, tempFolder string, keepDiagramSourceFile bool, dpi int, formats []string,
+	progressReporter progressReporter) error {
+	gvFile := diagramFilenameDOT
+	if !keepDiagramSourceFile {
+		tmpFileGV, err := os.CreateTemp(tempFolder, filepath.Base(diagramFilenameDOT))
+		if err != nil {
+			return err
+		}
+		_ = tmpFileGV.Close()
+		gvFile = tmpFileGV.Name()
+		defer func() { _ = os.Remove(gvFile) }()
+	}
+	dotFile, err := WriteScopedDataFlowDiagramGraphvizDOT(parsedModel, scope, gvFile, dpi, progressReporter)
+	if err != nil {
+		return fmt.Errorf("error while generating data flow diagram for %s: %s", scope.Title, err)
+	}
+
+	progressReporter.Info("Rendering data flow diagram input for " + scope.Title)
+	for _, format := range formats {
+		err = renderGraphvizImage(dotFile, tempFolder, format, diagramFilename(diagramFilenamePNG, format))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// createSubDiagramModel creates a browser calling a web server in the dmz, which calls an app server in the
+// nested app zone, which calls a database and a cache in the backend
+func createSubDiagramModel() *types.ParsedModel {
+	link := func(sourceId string, targetId string) types.CommunicationLink {
+		return types.CommunicationLink{Id: sourceId + ">" + targetId, SourceId: sourceId, TargetId: targetId, Title: targetId}
+	}
+	parsedModel := &types.ParsedModel{
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"browser": {Id: "browser", Title: "Browser", CommunicationLinks: []types.CommunicationLink{link("browser", "web")}},
+			"web":     {Id: "web", Title: "Web Server", Tags: []string{"frontend"}, CommunicationLinks: []types.CommunicationLink{link("web", "app")}},
+			"app":     {Id: "app", Title: "App Server", Tags: []string{"backend"}, CommunicationLinks: []types.CommunicationLink{link("app", "db"), link("app", "cache")}},
+			"db":      {Id: "db", Title: "Database", Tags: []string{"backend", "storage"}},
+			"cache":   {Id: "cache", Title: "Cache", Tags: []string{"storage"}},
+		},
+		TrustBoundaries: map[string]types.TrustBoundary{
+			"dmz":      {Id: "dmz", Title: "DMZ", TechnicalAssetsInside: []string{"web"}, TrustBoundariesNested: []string{"app-zone"}},
+			"app-zone": {Id: "app-zone", Title: "App Zone", TechnicalAssetsInside: []string{"app"}},
+			"backend":  {Id: "backend", Title: "Backend", TechnicalAssetsInside: []string{"db", "cache"}},
+		},
+		IncomingTechnicalCommunicationLinksMappedByTargetId: make(map[string][]types.CommunicationLink),
+	}
+	for _, technicalAsset := range parsedModel.TechnicalAssets {
+		for _, commLink := range technicalAsset.CommunicationLinks {
+			parsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId[commLink.TargetId] = append(parsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId[commLink.TargetId], commLink)
+		}
+	}
+	return parsedModel
+}
+
+func scopedIds(scope *DiagramScope) []string {
+	ids := make([]string, 0)
+	for id := range scope.TechnicalAssetIds {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestNewTrustBoundaryDiagramScope(t *testing.T) {
+	parsedModel := createSubDiagramModel()
+
+	scope, err := NewTrustBoundaryDiagramScope(parsedModel, "dmz")
+	assert.NoError(t, err)
+	assert.Equal(t, "trust-boundary-dmz", scope.Name)
+	assert.Equal(t, "DMZ", scope.Title)
+	assert.Equal(t, []string{"app", "web"}, scopedIds(scope)) // including the nested trust boundary
+
+	_, err = NewTrustBoundaryDiagramScope(parsedModel, "unknown")
+	assert.Error(t, err)
+}
+
+func TestNewTechnicalAssetDiagramScope(t *testing.T) {
+	parsedModel := createSubDiagramModel()
+
+	for _, test := range []struct {
+		hops  int
+		ids   []string
+		title string
+	}{
+		{0, []string{"web"}, "Web Server"},
+		{1, []string{"app", "browser", "web"}, "Web Server (1-hop neighbourhood)"},
+		{2, []string{"app", "browser", "cache", "db", "web"}, "Web Server (2-hop neighbourhood)"},
+		{5, []string{"app", "browser", "cache", "db", "web"}, "Web Server (5-hop neighbourhood)"},
+	} {
+		scope, err := NewTechnicalAssetDiagramScope(parsedModel, "web", test.hops)
+		assert.NoError(t, err)
+		assert.Equal(t, "technical-asset-web", scope.Name)
+		assert.Equal(t, test.title, scope.Title)
+		assert.Equal(t, test.ids, scopedIds(scope), "hops: %d", test.hops)
+	}
+
+	scope, err := NewTechnicalAssetDiagramScope(parsedModel, "db", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app", "db"}, scopedIds(scope)) // following incoming links too
+
+	_, err = NewTechnicalAssetDiagramScope(parsedModel, "web", -1)
+	assert.Error(t, err)
+	_, err = NewTechnicalAssetDiagramScope(parsedModel, "unknown", 1)
+	assert.Error(t, err)
+}
+
+func TestNewTagsDiagramScope(t *testing.T) {
+	parsedModel := createSubDiagramModel()
+
+	scope, err := NewTagsDiagramScope(parsedModel, []string{"backend", "STORAGE"})
+	assert.NoError(t, err)
+	assert.Equal(t, "tags-backend-STORAGE", scope.Name)
+	assert.Equal(t, "Tagged with backend, STORAGE", scope.Title)
+	assert.Equal(t, []string{"app", "cache", "db"}, scopedIds(scope))
+
+	_, err = NewTagsDiagramScope(parsedModel, []string{})
+	assert.Error(t, err)
+	_, err = NewTagsDiagramScope(parsedModel, []string{"unused"})
+	assert.Error(t, err)
+}
+
+func TestDiagramScopeContains(t *testing.T) {
+	parsedModel := createSubDiagramModel()
+
+	var unscoped *DiagramScope
+	assert.True(t, unscoped.Contains("browser"))
+	assert.True(t, unscoped.containsTrustBoundary(parsedModel, parsedModel.TrustBoundaries["backend"]))
+	assert.Empty(t, unscoped.placeholders(parsedModel))
+
+	scope, err := NewTechnicalAssetDiagramScope(parsedModel, "app", 0)
+	assert.NoError(t, err)
+	assert.True(t, scope.Contains("app"))
+	assert.False(t, scope.Contains("web"))
+	assert.True(t, scope.containsTrustBoundary(parsedModel, parsedModel.TrustBoundaries["dmz"])) // via the nested one
+	assert.True(t, scope.containsTrustBoundary(parsedModel, parsedModel.TrustBoundaries["app-zone"]))
+	assert.False(t, scope.containsTrustBoundary(parsedModel, parsedModel.TrustBoundaries["backend"]))
+}
+
+func TestDiagramScopePlaceholders(t *testing.T) {
+	parsedModel := createSubDiagramModel()
+
+	scope, err := NewTechnicalAssetDiagramScope(parsedModel, "app", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"dmz":     {"web"},
+		"backend": {"cache", "db"},
+	}, scope.placeholders(parsedModel)) // the browser is not connected to the app server
+
+	scope, err = NewTrustBoundaryDiagramScope(parsedModel, "dmz")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"":        {"browser"},
+		"backend": {"cache", "db"},
+	}, scope.placeholders(parsedModel))
+
+	assert.Equal(t, hash("web"), scope.nodeOf(parsedModel, "web"))
+	assert.Equal(t, placeholderNode("backend"), scope.nodeOf(parsedModel, "db"))
+	assert.Equal(t, scope.nodeOf(parsedModel, "db"), scope.nodeOf(parsedModel, "cache"))
+	assert.Equal(t, placeholderNode(""), scope.nodeOf(parsedModel, "browser"))
+	assert.NotEqual(t, placeholderNode(""), placeholderNode("backend"))
+
+	node := makePlaceholderNode(parsedModel, "backend", []string{"cache", "db"})
+	assert.Contains(t, node, "<b>2 more technical assets</b>")
+	assert.Contains(t, node, "in Backend")
+	assert.Contains(t, node, `tooltip="Cache, Database"`)
+	node = makePlaceholderNode(parsedModel, "", []string{"browser"})
+	assert.Contains(t, node, "<b>1 more technical asset</b>")
+	assert.Contains(t, node, "outside of trust boundaries")
+}
+
+func TestSubDiagramFilename(t *testing.T) {
+	scope := &DiagramScope{Name: "tags-some tag/other"}
+	assert.Equal(t, filepath.Join("out", "data-flow-diagram-tags-some-tag-other.png"),
+		SubDiagramFilename(filepath.Join("out", "data-flow-diagram.png"), scope))
+	assert.Equal(t, "data-flow-diagram-tags-some-tag-other.gv", SubDiagramFilename("data-flow-diagram.gv", scope))
+}
+
+func TestWriteScopedDataFlowDiagramGraphvizDOT(t *testing.T) {
+	parsedModel := createSubDiagramModel()
+	scope, err := NewTechnicalAssetDiagramScope(parsedModel, "app", 0)
+	assert.NoError(t, err)
+
+	filename := filepath.Join(t.TempDir(), "data-flow-diagram.gv")
+	file, err := WriteScopedDataFlowDiagramGraphvizDOT(parsedModel, scope, filename, 120, common.DefaultProgressReporter{SuppressError: true})
+	assert.NoError(t, err)
+	_ = file.Close()
+	data, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	dot := string(data)
+
+	assert.Contains(t, dot, hash("app"))
+	assert.NotContains(t, dot, hash("web"))
+	assert.NotContains(t, dot, hash("db"))
+	assert.NotContains(t, dot, placeholderNode("")) // the browser is not connected to the app server
+	assert.Contains(t, dot, placeholderNode("dmz")+" -> "+hash("app"))
+	assert.Contains(t, dot, hash("app")+" -> "+placeholderNode("backend"))
+}
+
+func TestWriteScopedDataFlowDiagramGraphvizDOTEscapesTitle(t *testing.T) {
+	parsedModel := createSubDiagramModel()
+	app := parsedModel.TechnicalAssets["app"]
+	app.Title = `App "Core" Server \ Worker`
+	parsedModel.TechnicalAssets["app"] = app
+	scope, err := NewTechnicalAssetDiagramScope(parsedModel, "app", 0)
+	assert.NoError(t, err)
+
+	filename := filepath.Join(t.TempDir(), "data-flow-diagram.gv")
+	file, err := WriteScopedDataFlowDiagramGraphvizDOT(parsedModel, scope, filename, 120, common.DefaultProgressReporter{SuppressError: true})
+	assert.NoError(t, err)
+	_ = file.Close()
+	data, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `graph [ label="App \"Core\" Server \\ Worker"`)
+}
+
+func TestSubDiagramsAreGeneratedByDefault(t *testing.T) {
+	assert.True(t, new(GenerateCommands).Defaults().SubDiagrams) // embedded into the report pdf unless turned off
+}
@@ -0,0 +1,290 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	dialect = "http://json-schema.org/draft-07/schema#"
+	id      = "https://threagile.io/schema.json"
+
+	typeString  = "string"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeBoolean = "boolean"
+	typeArray   = "array"
+	typeObject  = "object"
+	typeNull    = "null"
+
+	formatDate = "date"
+)
+
+// enums are the values referenced by "enum=<name>" in the schema tags of the input types
+var enums = map[string][]types.TypeEnum{
+	"authentication":               types.AuthenticationValues(),
+	"authorization":                types.AuthorizationValues(),
+	"confidentiality":              types.ConfidentialityValues(),
+	"criticality":                  types.CriticalityValues(),
+	"data-breach-probability":      types.DataBreachProbabilityValues(),
+	"data-format":                  types.DataFormatValues(),
+	"encryption-style":             types.EncryptionStyleValues(),
+	"protocol":                     types.ProtocolValues(),
+	"quantity":                     types.QuantityValues(),
+	"risk-exploitation-impact":     types.RiskExploitationImpactValues(),
+	"risk-exploitation-likelihood": types.RiskExploitationLikelihoodValues(),
+	"risk-function":                types.RiskFunctionValues(),
+	"risk-severity":                types.RiskSeverityValues(),
+	"risk-status":                  types.RiskStatusValues(),
+	"stride":                       types.STRIDEValues(),
+	"technical-asset-machine":      types.TechnicalAssetMachineValues(),
+	"technical-asset-size":         types.TechnicalAssetSizeValues(),
+	"technical-asset-technology":   types.TechnicalAssetTechnologyValues(),
+	"technical-asset-type":         types.TechnicalAssetTypeValues(),
+	"trust-boundary-type":          types.TrustBoundaryTypeValues(),
+	"usage":                        types.UsageValues(),
+	"edge-layout": {
+		enumValue{"", "Default layout (ortho)"},
+		enumValue{"spline", "Curved edges avoiding nodes"},
+		enumValue{"polyline", "Straight line segments avoiding nodes"},
+		enumValue{"ortho", "Axis-aligned edges"},
+		enumValue{"curved", "Curved edges"},
+		enumValue{"false", "Straight edges"},
+	},
+	"overlay": {
+		enumValue{input.OverlayAppend, "Extend the matching element or add the element (default)"},
+		enumValue{input.OverlayReplace, "Replace the matching element as a whole or add the element"},
+		enumValue{input.OverlayDelete, "Delete the matching element"},
+	},
+}
+
+// enumValue is a value of an enum without a type of its own in pkg/security/types, like the graphviz splines setting
+// accepted by diagram_tweak_edge_layout
+type enumValue types.TypeDescription
+
+func (what enumValue) String() string {
+	return what.Name
+}
+
+func (what enumValue) Explain() string {
+	return what.Description
+}
+
+// Schema is the part of JSON Schema needed to describe (and validate) Threagile model files
+type Schema struct {
+	Description          string
+	Types                []string
+	Format               string
+	Enum                 []types.TypeEnum
+	Extensible           bool // other values than the ones of the enum are allowed, like declared technologies
+	Properties           []Property
+	Required             []string
+	AdditionalProperties *Schema // schema of the values of a map, nil for structs
+	Items                *Schema
+
+	root bool
+}
+
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Generate returns the schema of Threagile model files, derived from the yaml, description and schema tags of the
+// input types and the values of the referenced enums
+func Generate() *Schema {
+	result := forType(reflect.TypeOf(input.Model{}), false, nil)
+	result.Description = "Agile Threat Modeling"
+	result.root = true
+	return result
+}
+
+// JSON returns the schema of Threagile model files as (indented) schema.json
+func JSON() ([]byte, error) {
+	return json.MarshalIndent(Generate(), "", "  ")
+}
+
+func forType(t reflect.Type, nullable bool, tag schemaTag) *Schema {
+	var result *Schema
+	switch t.Kind() {
+	case reflect.String:
+		if tag.has("scalar") { // any scalar given is read as string, like the values of variables
+			result = &Schema{Types: []string{typeString, typeNumber, typeBoolean}}
+		} else {
+			result = &Schema{Types: []string{typeString}, Enum: enums[tag["enum"]], Extensible: tag.has("extensible")}
+		}
+
+	case reflect.Bool:
+		result = &Schema{Types: []string{typeBoolean}}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		result = &Schema{Types: []string{typeInteger}}
+
+	case reflect.Float32, reflect.Float64:
+		result = &Schema{Types: []string{typeNumber}}
+
+	case reflect.Slice:
+		result = &Schema{Types: []string{typeArray}, Items: forType(t.Elem(), false, tag)}
+
+	case reflect.Map:
+		// maps are keyed by title (or id), only scalar values may be left empty
+		result = &Schema{Types: []string{typeObject}, AdditionalProperties: forType(t.Elem(), t.Elem().Kind() != reflect.Struct, tag)}
+
+	case reflect.Struct:
+		result = &Schema{Types: []string{typeObject}, Properties: make([]Property, 0)}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if len(name) == 0 || name == "-" {
+				continue
+			}
+			tag := parseSchemaTag(field.Tag.Get("schema"))
+			property := forType(field.Type, tag.has("nullable") || !tag.has("required"), tag)
+			property.Description = field.Tag.Get("description")
+			property.Format = tag["format"]
+			result.Properties = append(result.Properties, Property{Name: name, Schema: property})
+			if tag.has("required") {
+				result.Required = append(result.Required, name)
+			}
+		}
+
+	default:
+		panic("unsupported type in model input: " + t.String())
+	}
+
+	if nullable {
+		result.Types = append(result.Types, typeNull)
+	}
+	return result
+}
+
+// schemaTag holds the options of a schema tag like `schema:"required,enum=usage"`, flags are mapped to an empty value
+type schemaTag map[string]string
+
+func parseSchemaTag(tag string) schemaTag {
+	result := make(schemaTag)
+	for _, option := range strings.Split(tag, ",") {
+		if len(option) > 0 {
+			name, value, _ := strings.Cut(option, "=")
+			result[name] = value
+		}
+	}
+	return result
+}
+
+func (what schemaTag) has(option string) bool {
+	_, found := what[option]
+	return found
+}
+
+// Property returns the schema of a property of an object, nil if the object has no such property
+func (what *Schema) Property(name string) *Schema {
+	for _, property := range what.Properties {
+		if property.Name == name {
+			return property.Schema
+		}
+	}
+	return nil
+}
+
+func (what *Schema) allows(typeName string) bool {
+	for _, candidate := range what.Types {
+		if candidate == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON writes the schema members in a fixed order, keeping the properties in the order of the input types
+func (what *Schema) MarshalJSON() ([]byte, error) {
+	members := make([]member, 0)
+	if what.root {
+		members = append(members, member{"$schema", dialect}, member{"$id", id}, member{"title", "Threagile"})
+	}
+	if len(what.Description) > 0 {
+		members = append(members, member{"description", what.Description})
+	}
+	if len(what.Types) == 1 {
+		members = append(members, member{"type", what.Types[0]})
+	} else {
+		members = append(members, member{"type", what.Types})
+	}
+	if len(what.Format) > 0 {
+		members = append(members, member{"format", what.Format})
+	}
+	if len(what.Enum) > 0 {
+		values := make([]any, 0)
+		descriptions := make([]string, 0)
+		for _, value := range what.Enum {
+			values = append(values, value.String())
+			descriptions = append(descriptions, value.Explain())
+		}
+		if what.allows(typeNull) {
+			values = append(values, nil)
+			descriptions = append(descriptions, "")
+		}
+		if what.Extensible { // the values are still suggested by editors
+			members = append(members, member{"anyOf", []object{{member{"enum", values}}, {member{"type", typeString}}}}, member{"enumDescriptions", descriptions})
+		} else {
+			members = append(members, member{"enum", values}, member{"enumDescriptions", descriptions})
+		}
+	}
+	if what.Items != nil {
+		members = append(members, member{"uniqueItems", true}, member{"items", what.Items})
+	}
+	if what.Properties != nil {
+		properties := make([]member, 0)
+		for _, property := range what.Properties {
+			properties = append(properties, member{property.Name, property.Schema})
+		}
+		members = append(members, member{"properties", object(properties)}, member{"additionalProperties", false})
+		if len(what.Required) > 0 {
+			members = append(members, member{"required", what.Required})
+		}
+	}
+	if what.AdditionalProperties != nil {
+		members = append(members, member{"additionalProperties", what.AdditionalProperties})
+	}
+	return object(members).MarshalJSON()
+}
+
+type member struct {
+	name  string
+	value any
+}
+
+// object marshals its members as JSON object in the given order
+type object []member
+
+func (what object) MarshalJSON() ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	buffer.WriteString("{")
+	for i, m := range what {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		name, err := json.Marshal(m.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteString(":")
+		buffer.Write(value)
+	}
+	buffer.WriteString("}")
+	return buffer.Bytes(), nil
+}
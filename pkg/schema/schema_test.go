@@ -0,0 +1,94 @@
+package schema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestGenerate(t *testing.T) {
+	schema := Generate()
+
+	technicalAsset := schema.Property("technical_assets").AdditionalProperties
+	assert.Contains(t, technicalAsset.Required, "technology")
+	assert.Equal(t, types.TechnicalAssetTechnologyValues(), technicalAsset.Property("technology").Enum)
+	assert.True(t, technicalAsset.Property("technology").Extensible) // by declared technologies
+	assert.Equal(t, []string{typeString}, technicalAsset.Property("id").Types)
+	assert.Equal(t, []string{typeString, typeNull}, technicalAsset.Property("justification_out_of_scope").Types)
+	assert.Equal(t, types.DataFormatValues(), technicalAsset.Property("data_formats_accepted").Items.Enum)
+	assert.Equal(t, formatDate, schema.Property("risk_tracking").AdditionalProperties.Property("date").Format)
+
+	data, err := JSON()
+	assert.NoError(t, err)
+	var document map[string]any
+	assert.NoError(t, json.Unmarshal(data, &document))
+	assert.Equal(t, "Threagile", document["title"])
+	assert.Equal(t, false, document["additionalProperties"])
+}
+
+func TestValidate(t *testing.T) {
+	model := `
+title: Some Model
+business_criticality: importnt
+data_assets:
+  Some Data:
+    id: some-data
+    description: Some data
+    usage: business
+    quantity: few
+    confidentiality: internal
+    integrity: critical
+risk_tracking:
+  some-risk@some-asset:
+    status: accepted
+    justification:
+    ticket:
+    date: 2024-02-30
+    checked_by:
+    checked: true
+`
+	_, errs := Validate(Generate(), "model.yaml", []byte(model), nil)
+	messages := make([]string, 0)
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		`model.yaml:3:23: business_criticality: unknown value "importnt", expected one of: archive, operational, important, critical, mission-critical`,
+		`model.yaml:6:5: data_assets.Some Data: missing required property "availability"`,
+		`model.yaml:17:11: risk_tracking.some-risk@some-asset.date: invalid date "2024-02-30" (expected format: 2006-01-02)`,
+		`model.yaml:19:5: risk_tracking.some-risk@some-asset: unknown property "checked"`,
+	}, messages)
+}
+
+func TestValidateModelFile(t *testing.T) {
+	assert.Empty(t, ValidateModelFile(filepath.Join("..", "..", "demo", "example", "threagile.yaml"), nil))
+	assert.Empty(t, ValidateModelFile(filepath.Join("..", "..", "test", "main.yaml"), nil))
+}
+
+func TestValidateModelFileWithOverlay(t *testing.T) {
+	dir := t.TempDir()
+	main, err := os.ReadFile(filepath.Join("..", "..", "demo", "example", "threagile.yaml"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.yaml"), append(main, []byte("\noverlays:\n  - ${env}.yaml\n")...), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "prod.yaml"), []byte(`
+technical_assets:
+  Some Asset:
+    redundant: true
+    tags: null
+  other-asset:
+    id: other-asset
+    overlay: remove
+`), 0600))
+
+	errs := ValidateModelFile(filepath.Join(dir, "main.yaml"), map[string]string{"env": "prod"})
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], `prod.yaml:8:14: technical_assets.other-asset.overlay: unknown value "remove", expected one of: append, replace, delete`)
+
+	errs = ValidateModelFile(filepath.Join(dir, "main.yaml"), nil)
+	assert.NotEmpty(t, errs)
+	assert.ErrorContains(t, errs[0], `undefined variable "env"`)
+}
@@ -0,0 +1,343 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+// ValidationError is a schema violation at a position of a model file
+type ValidationError struct {
+	File    string
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+func (what *ValidationError) Error() string {
+	if len(what.Path) == 0 {
+		return fmt.Sprintf("%v:%d:%d: %v", what.File, what.Line, what.Column, what.Message)
+	}
+	return fmt.Sprintf("%v:%d:%d: %v: %v", what.File, what.Line, what.Column, what.Path, what.Message)
+}
+
+// ValidateModelFile validates a model file and all files included or overlaid by it (recursively) against the schema,
+// with the given values of the variables, returning all violations found instead of stopping at the first one
+func ValidateModelFile(filename string, values map[string]string) []error {
+	schema := Generate()
+	filename = filepath.Clean(filename)
+	keys := make(map[string]bool)
+	v := validator{values: make(map[string]string)}
+	for name, value := range values {
+		v.values[name] = value
+	}
+	errs := v.validateModelFile(schema, filename, keys, make(map[string]bool))
+	if len(errs) > 0 && !isValidationError(errs[0]) {
+		return errs // main model file unreadable
+	}
+
+	// the required top-level properties may also be defined by included files
+	for _, name := range schema.Required {
+		if !keys[name] {
+			errs = append(errs, &ValidationError{File: filename, Line: 1, Column: 1, Message: fmt.Sprintf("missing required property %q", name)})
+		}
+	}
+	return errs
+}
+
+func (what *validator) validateModelFile(schema *Schema, filename string, keys map[string]bool, visited map[string]bool) []error {
+	if visited[filename] {
+		return nil
+	}
+	visited[filename] = true
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return []error{fmt.Errorf("unable to read model file: %w", err)}
+	}
+	root, errs := Validate(schema, filename, data, what.values)
+	if root == nil {
+		return errs
+	}
+	for _, key := range mergedKeys(root) {
+		keys[key] = true
+	}
+
+	// includes and overlays are relative to the including file, the elements of overlays need not be complete
+	for _, name := range []string{"includes", "overlays"} {
+		if files := mappingValue(root, name); files != nil && files.Kind == yaml.SequenceNode {
+			for _, file := range files.Content {
+				if file.Kind != yaml.ScalarNode || len(file.Value) == 0 {
+					continue
+				}
+				filename := filepath.Join(filepath.Dir(filename), file.Value)
+				if name == "overlays" {
+					errs = append(errs, what.validateOverlayFile(schema, filename, visited)...)
+				} else {
+					errs = append(errs, what.validateModelFile(schema, filename, keys, visited)...)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func (what *validator) validateOverlayFile(schema *Schema, filename string, visited map[string]bool) []error {
+	if visited[filename] {
+		return nil
+	}
+	visited[filename] = true
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return []error{fmt.Errorf("unable to read overlay file: %w", err)}
+	}
+	document, errs := parse(filename, data, what.values)
+	if document == nil {
+		return errs
+	}
+	v := validator{filename: filename, overlay: true}
+	v.validate(document, schema, "", false)
+	return append(errs, v.errs...)
+}
+
+// Validate validates the yaml data of a model file against the schema, after replacing the variables referenced by
+// their values, and returns its root node (nil if the data is no valid yaml at all), the required top-level properties
+// are not checked as they may be defined by included files (see ValidateModelFile)
+func Validate(schema *Schema, filename string, data []byte, values map[string]string) (*yaml.Node, []error) {
+	root, errs := parse(filename, data, values)
+	if root == nil {
+		return nil, errs
+	}
+	v := validator{filename: filename}
+	v.validate(root, schema, "", false)
+	return root, append(errs, v.errs...)
+}
+
+// parse returns the root node of the yaml data of a model file with the variables replaced, undefined variables are
+// reported as violations
+func parse(filename string, data []byte, values map[string]string) (*yaml.Node, []error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, []error{fmt.Errorf("%v: unable to parse model yaml: %w", filename, err)}
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: 1, Column: 1}
+	if document.Kind == yaml.DocumentNode && len(document.Content) > 0 {
+		root = document.Content[0]
+	}
+
+	if values == nil {
+		values = make(map[string]string)
+	}
+	errs := make([]error, 0)
+	for _, err := range input.SplitErrors(input.ResolveVariables(filename, root, values)) {
+		var positionError *input.PositionError
+		if errors.As(err, &positionError) {
+			err = &ValidationError{File: positionError.Position.File, Line: positionError.Position.Line, Column: positionError.Position.Column, Message: positionError.Err.Error()}
+		}
+		errs = append(errs, err)
+	}
+	return root, errs
+}
+
+func isValidationError(err error) bool {
+	var validationError *ValidationError
+	return errors.As(err, &validationError)
+}
+
+type validator struct {
+	filename string
+	overlay  bool              // elements of overlay files need not have the required properties
+	values   map[string]string // of the variables, shared by all files of a model
+	errs     []error
+}
+
+func (what *validator) fail(node *yaml.Node, path string, format string, a ...any) {
+	what.errs = append(what.errs, &ValidationError{
+		File:    what.filename,
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (what *validator) validate(node *yaml.Node, schema *Schema, path string, checkRequired bool) {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	nodeType := typeOf(node)
+	if nodeType == typeNull && what.overlay {
+		return // removes the value from the model
+	}
+	if !schema.allows(nodeType) && !(nodeType == typeInteger && schema.allows(typeNumber)) {
+		if nodeType == typeNull {
+			what.fail(node, path, "must not be empty")
+		} else {
+			what.fail(node, path, "expected %v but found %v", strings.Join(schema.Types, " or "), nodeType)
+		}
+		return
+	}
+
+	switch nodeType {
+	case typeString:
+		what.validateString(node, schema, path)
+
+	case typeArray:
+		seen := make(map[string]bool)
+		for i, item := range node.Content {
+			what.validate(item, schema.Items, path+"["+strconv.Itoa(i)+"]", true)
+			if item.Kind == yaml.ScalarNode {
+				if seen[item.Value] {
+					what.fail(item, path, "duplicate item %q", item.Value)
+				}
+				seen[item.Value] = true
+			}
+		}
+
+	case typeObject:
+		what.validateObject(node, schema, path, checkRequired)
+	}
+}
+
+func (what *validator) validateString(node *yaml.Node, schema *Schema, path string) {
+	if len(schema.Enum) > 0 && !schema.Extensible { // otherwise checked against the declared values, see model.CheckReferences
+		names := make([]string, 0)
+		for _, value := range schema.Enum {
+			if value.String() == node.Value {
+				return
+			}
+			names = append(names, value.String())
+		}
+		if len(names) > 10 {
+			what.fail(node, path, "unknown value %q (see list-types for the possible values)", node.Value)
+		} else {
+			what.fail(node, path, "unknown value %q, expected one of: %v", node.Value, strings.Join(names, ", "))
+		}
+		return
+	}
+
+	if schema.Format == formatDate && len(node.Value) > 0 {
+		if _, err := time.Parse("2006-01-02", node.Value); err != nil {
+			what.fail(node, path, "invalid date %q (expected format: 2006-01-02)", node.Value)
+		}
+	}
+}
+
+func (what *validator) validateObject(node *yaml.Node, schema *Schema, path string, checkRequired bool) {
+	seen := make(map[string]bool)
+	merged := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value == "<<" { // yaml merge key, the merged values are validated where they are defined
+			for _, mergedKey := range mergedKeys(value) {
+				merged[mergedKey] = true
+			}
+			continue
+		}
+		if seen[key.Value] {
+			what.fail(key, path, "duplicate key %q", key.Value)
+		}
+		seen[key.Value] = true
+
+		valuePath := key.Value
+		if len(path) > 0 {
+			valuePath = path + "." + key.Value
+		}
+		if schema.AdditionalProperties != nil {
+			what.validate(value, schema.AdditionalProperties, valuePath, true)
+			continue
+		}
+		property := schema.Property(key.Value)
+		if property == nil {
+			what.fail(key, path, "unknown property %q", key.Value)
+			continue
+		}
+		what.validate(value, property, valuePath, true)
+	}
+
+	if checkRequired && !what.overlay {
+		for _, name := range schema.Required {
+			if !seen[name] && !merged[name] {
+				what.fail(node, path, "missing required property %q", name)
+			}
+		}
+	}
+}
+
+func typeOf(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return typeObject
+
+	case yaml.SequenceNode:
+		return typeArray
+	}
+
+	switch node.ShortTag() {
+	case "!!null":
+		return typeNull
+
+	case "!!bool":
+		return typeBoolean
+
+	case "!!int":
+		return typeInteger
+
+	case "!!float":
+		return typeNumber
+	}
+	return typeString // including !!timestamp, as dates are given as plain yaml scalars
+}
+
+// mergedKeys returns the keys merged into a mapping via the yaml merge key, which references a mapping or a sequence
+// of mappings
+func mergedKeys(node *yaml.Node) []string {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	keys := make([]string, 0)
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "<<" {
+				keys = append(keys, mergedKeys(node.Content[i+1])...)
+			} else {
+				keys = append(keys, node.Content[i].Value)
+			}
+		}
+
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			keys = append(keys, mergedKeys(item)...)
+		}
+	}
+	return keys
+}
+
+// mappingValue returns the value of a key of a yaml mapping, nil if there is no such key
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+/*
+Package attackpath finds multi-hop attack paths from internet-facing technical assets to the technical assets holding
+highly sensitive data assets. Technical assets are the nodes and communication links the (directed) edges of the graph.
+Each link has a cost for the attacker based on its authentication, encryption, network protection, trust boundary
+crossing and the RAA of its target, so the cheapest paths are the most likely ones.
+*/
+package attackpath
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	virtualSource = "\x00source"
+	virtualSink   = "\x00sink"
+)
+
+type Path = types.AttackPath
+
+// Apply computes the attack paths of a model once per analysis (after the RAA calculation, as the costs depend on it),
+// for the attack path risk rule and the reports
+func Apply(parsedModel *types.ParsedModel, maxPaths int) {
+	parsedModel.AttackPaths = Compute(parsedModel, maxPaths)
+	parsedModel.MaxAttackPaths = maxPaths
+}
+
+// IsSensitive tells whether paths to the data asset are searched
+func IsSensitive(dataAsset types.DataAsset) bool {
+	return dataAsset.Confidentiality == types.StrictlyConfidential ||
+		dataAsset.Integrity == types.MissionCritical || dataAsset.Availability == types.MissionCritical
+}
+
+// LinkCost is the effort for an attacker controlling the source of the link to compromise its target
+func LinkCost(parsedModel *types.ParsedModel, link types.CommunicationLink) float64 {
+	cost := 1.0
+
+	switch link.Authentication {
+	case types.NoneAuthentication:
+	case types.ClientCertificate, types.TwoFactor:
+		cost += 2
+	default:
+		cost++
+	}
+
+	if link.Protocol.IsEncrypted() {
+		cost += 0.5
+	}
+	if link.VPN {
+		cost += 0.5
+	}
+	if link.IpFiltered {
+		cost += 0.5
+	}
+	if link.IsAcrossTrustBoundaryNetworkOnly(parsedModel) {
+		cost++
+	}
+
+	// attractive targets (high RAA) are attacked first
+	raa := parsedModel.TechnicalAssets[link.TargetId].RAA
+	if raa < 0 {
+		raa = 0
+	} else if raa > 100 {
+		raa = 100
+	}
+	cost += (100 - raa) / 100
+
+	return cost
+}
+
+// Likelihood maps the total cost of a path to an exploitation likelihood
+func Likelihood(cost float64) types.RiskExploitationLikelihood {
+	switch {
+	case cost <= 3:
+		return types.VeryLikely
+	case cost <= 6:
+		return types.Likely
+	}
+	return types.Unlikely
+}
+
+// Compute returns up to maxPaths cheapest paths per sensitive data asset, ordered by data asset and cost
+func Compute(parsedModel *types.ParsedModel, maxPaths int) []Path {
+	paths := make([]Path, 0)
+	if maxPaths <= 0 {
+		return paths
+	}
+
+	entries := make([]string, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		if parsedModel.TechnicalAssets[id].Internet {
+			entries = append(entries, id)
+		}
+	}
+	if len(entries) == 0 {
+		return paths
+	}
+
+	dataAssetIds := make([]string, 0)
+	for id, dataAsset := range parsedModel.DataAssets {
+		if IsSensitive(dataAsset) {
+			dataAssetIds = append(dataAssetIds, id)
+		}
+	}
+	sort.Strings(dataAssetIds)
+
+	for _, dataAssetId := range dataAssetIds {
+		dataAsset := parsedModel.DataAssets[dataAssetId]
+		targets := targetsOf(parsedModel, dataAssetId)
+		if len(targets) == 0 {
+			continue
+		}
+
+		impact := types.HighImpact
+		if dataAsset.Confidentiality == types.StrictlyConfidential && dataAsset.Integrity == types.MissionCritical {
+			impact = types.VeryHighImpact
+		}
+
+		g := newGraph(parsedModel, entries, targets)
+		for _, found := range g.cheapestRoutes(maxPaths) {
+			path := Path{
+				DataAssetId:          dataAssetId,
+				TechnicalAssetIds:    found.nodes[1 : len(found.nodes)-1],
+				CommunicationLinkIds: make([]string, 0),
+				Cost:                 found.cost,
+				Likelihood:           Likelihood(found.cost),
+				Impact:               impact,
+			}
+			for _, step := range found.edges[1 : len(found.edges)-1] {
+				path.CommunicationLinkIds = append(path.CommunicationLinkIds, step.linkId)
+			}
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// targetsOf returns the technical assets storing the data asset (or processing it if none stores it),
+// internet-facing assets are entries and no targets
+func targetsOf(parsedModel *types.ParsedModel, dataAssetId string) []string {
+	storing, processing := make([]string, 0), make([]string, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		if technicalAsset.Internet {
+			continue
+		}
+		for _, stored := range technicalAsset.DataAssetsStored {
+			if stored == dataAssetId {
+				storing = append(storing, id)
+				break
+			}
+		}
+		if technicalAsset.ProcessesOrStoresDataAsset(dataAssetId) {
+			processing = append(processing, id)
+		}
+	}
+
+	if len(storing) > 0 {
+		return storing
+	}
+	return processing
+}
+
+type edge struct {
+	from   string
+	to     string
+	linkId string
+	cost   float64
+}
+
+func (what edge) key() string {
+	return what.from + "\x00" + what.to + "\x00" + what.linkId
+}
+
+type route struct {
+	nodes []string
+	edges []edge
+	cost  float64
+}
+
+func (what route) key() string {
+	return strings.Join(what.nodes, ">")
+}
+
+type graph struct {
+	edges map[string][]edge
+}
+
+func newGraph(parsedModel *types.ParsedModel, entries []string, targets []string) *graph {
+	g := &graph{edges: make(map[string][]edge)}
+
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		for _, link := range parsedModel.TechnicalAssets[id].CommunicationLinks {
+			if _, ok := parsedModel.TechnicalAssets[link.TargetId]; ok {
+				g.edges[id] = append(g.edges[id], edge{from: id, to: link.TargetId, linkId: link.Id, cost: LinkCost(parsedModel, link)})
+			}
+		}
+		sort.Slice(g.edges[id], func(i, j int) bool { return g.edges[id][i].linkId < g.edges[id][j].linkId })
+	}
+
+	for _, entry := range entries {
+		g.edges[virtualSource] = append(g.edges[virtualSource], edge{from: virtualSource, to: entry})
+	}
+	for _, target := range targets {
+		g.edges[target] = append(g.edges[target], edge{from: target, to: virtualSink})
+	}
+
+	return g
+}
+
+// cheapestRoutes implements Yen's algorithm for the k cheapest loopless routes from the virtual source to the virtual sink
+func (g *graph) cheapestRoutes(k int) []route {
+	first, found := g.cheapestRoute(virtualSource, nil, nil)
+	if !found {
+		return nil
+	}
+
+	routes := []route{first}
+	known := map[string]bool{first.key(): true}
+	candidates := make([]route, 0)
+
+	for len(routes) < k {
+		previous := routes[len(routes)-1]
+		for index := 0; index < len(previous.nodes)-1; index++ {
+			spurNode := previous.nodes[index]
+			rootNodes := previous.nodes[:index+1]
+			rootEdges := previous.edges[:index]
+
+			blockedEdges := make(map[string]bool)
+			for _, existing := range routes {
+				if len(existing.nodes) > index && slices.Equal(existing.nodes[:index+1], rootNodes) {
+					blockedEdges[existing.edges[index].key()] = true
+				}
+			}
+
+			blockedNodes := make(map[string]bool)
+			for _, node := range rootNodes[:index] {
+				blockedNodes[node] = true
+			}
+
+			spur, spurFound := g.cheapestRoute(spurNode, blockedNodes, blockedEdges)
+			if !spurFound {
+				continue
+			}
+
+			candidate := route{
+				nodes: append(append(make([]string, 0), rootNodes[:index]...), spur.nodes...),
+				edges: append(append(make([]edge, 0), rootEdges...), spur.edges...),
+			}
+			for _, step := range candidate.edges {
+				candidate.cost += step.cost
+			}
+
+			if !known[candidate.key()] {
+				known[candidate.key()] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].cost != candidates[j].cost {
+				return candidates[i].cost < candidates[j].cost
+			}
+			return candidates[i].key() < candidates[j].key()
+		})
+		routes = append(routes, candidates[0])
+		candidates = candidates[1:]
+	}
+
+	return routes
+}
+
+// cheapestRoute runs Dijkstra's algorithm from the given node to the virtual sink, ties are broken by node id
+// to keep the results stable
+func (g *graph) cheapestRoute(from string, blockedNodes map[string]bool, blockedEdges map[string]bool) (route, bool) {
+	costs := map[string]float64{from: 0}
+	previous := make(map[string]edge)
+	done := make(map[string]bool)
+
+	for {
+		current, found := "", false
+		for node, cost := range costs {
+			if done[node] {
+				continue
+			}
+			if !found || cost < costs[current] || (cost == costs[current] && node < current) {
+				current, found = node, true
+			}
+		}
+
+		if !found {
+			return route{}, false
+		}
+		if current == virtualSink {
+			break
+		}
+		done[current] = true
+
+		for _, next := range g.edges[current] {
+			if blockedNodes[next.to] || blockedEdges[next.key()] || done[next.to] {
+				continue
+			}
+			cost := costs[current] + next.cost
+			if existing, ok := costs[next.to]; !ok || cost < existing {
+				costs[next.to] = cost
+				previous[next.to] = next
+			}
+		}
+	}
+
+	result := route{nodes: []string{virtualSink}, cost: costs[virtualSink]}
+	for node := virtualSink; node != from; {
+		step := previous[node]
+		result.nodes = append([]string{step.from}, result.nodes...)
+		result.edges = append([]edge{step}, result.edges...)
+		node = step.from
+	}
+
+	return result, true
+}
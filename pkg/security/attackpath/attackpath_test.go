@@ -0,0 +1,58 @@
+package attackpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestComputeOrdersPathsByCost(t *testing.T) {
+	parsedModel := createParsedModel()
+
+	paths := Compute(parsedModel, 3)
+	assert.Len(t, paths, 2)
+
+	assert.Equal(t, []string{"web", "app", "database"}, paths[0].TechnicalAssetIds)
+	assert.Equal(t, []string{"web>app", "app>database"}, paths[0].CommunicationLinkIds)
+	assert.Equal(t, 3.0, paths[0].Cost)
+	assert.Equal(t, types.VeryLikely, paths[0].Likelihood)
+	assert.Equal(t, types.VeryHighImpact, paths[0].Impact)
+	assert.Equal(t, "customer-data@web>app>database", paths[0].Key())
+
+	assert.Equal(t, []string{"web", "database"}, paths[1].TechnicalAssetIds)
+	assert.Equal(t, 3.5, paths[1].Cost)
+
+	assert.Len(t, Compute(parsedModel, 1), 1)
+	assert.Empty(t, Compute(parsedModel, 0))
+}
+
+func createParsedModel() *types.ParsedModel {
+	webToApp := types.CommunicationLink{Id: "web>app", SourceId: "web", TargetId: "app", Protocol: types.HTTP}
+	appToDatabase := types.CommunicationLink{Id: "app>database", SourceId: "app", TargetId: "database", Protocol: types.JDBC, Authentication: types.Credentials}
+	webToDatabase := types.CommunicationLink{Id: "web>database", SourceId: "web", TargetId: "database", Protocol: types.JdbcEncrypted, Authentication: types.ClientCertificate}
+
+	return &types.ParsedModel{
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web":      {Id: "web", Internet: true, RAA: 50, CommunicationLinks: []types.CommunicationLink{webToApp, webToDatabase}},
+			"app":      {Id: "app", RAA: 100, CommunicationLinks: []types.CommunicationLink{appToDatabase}},
+			"database": {Id: "database", RAA: 100, DataAssetsStored: []string{"customer-data"}},
+		},
+		DataAssets: map[string]types.DataAsset{
+			"customer-data": {Id: "customer-data", Confidentiality: types.StrictlyConfidential, Integrity: types.MissionCritical},
+			"public-data":   {Id: "public-data", Confidentiality: types.Public},
+		},
+		CommunicationLinks: map[string]types.CommunicationLink{
+			webToApp.Id: webToApp, appToDatabase.Id: appToDatabase, webToDatabase.Id: webToDatabase,
+		},
+	}
+}
+
+func TestApplyStoresPathsInModel(t *testing.T) {
+	parsedModel := createParsedModel()
+
+	Apply(parsedModel, 1)
+	assert.Len(t, parsedModel.AttackPaths, 1)
+	assert.Equal(t, 1, parsedModel.MaxAttackPaths)
+}
@@ -0,0 +1,78 @@
+package builtin
+
+import (
+	"strconv"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/attackpath"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type AttackPathRule struct{}
+
+func NewAttackPathRule() *AttackPathRule {
+	return &AttackPathRule{}
+}
+
+func (*AttackPathRule) Category() types.RiskCategory {
+	return types.RiskCategory{
+		Id:    "attack-path",
+		Title: "Attack Path to Highly Sensitive Data",
+		Description: "Internet-facing technical assets reach technical assets holding highly sensitive data assets via a chain of " +
+			"communication links. An attacker compromising the entry asset can move along such a path hop by hop.",
+		Impact: "If this risk is unmitigated, attackers might be able to reach and compromise highly sensitive data assets " +
+			"by chaining the weaknesses of the technical assets along the path.",
+		ASVS:       "V1 - Architecture, Design and Threat Modeling Requirements",
+		CheatSheet: "https://cheatsheetseries.owasp.org/cheatsheets/Attack_Surface_Analysis_Cheat_Sheet.html",
+		Action:     "Defense in Depth",
+		Mitigation: "Break or harden the path: add strong authentication to the communication links, isolate the assets " +
+			"holding the sensitive data in their own network trust boundary and restrict the links via VPN or IP filtering.",
+		Check:    "Are the communication links along the path authenticated, encrypted and filtered?",
+		Function: types.Architecture,
+		STRIDE:   types.ElevationOfPrivilege,
+		DetectionLogic: "The cheapest paths (" + strconv.Itoa(common.DefaultMaxAttackPaths) + " by default, see the MaxAttackPaths config) from internet-facing technical assets to the technical assets storing (or, " +
+			"if none stores it, processing) each data asset rated as " + types.StrictlyConfidential.String() + " or " + types.MissionCritical.String() + ". " +
+			"Each communication link costs the attacker more with authentication, encryption, VPN, IP filtering, network trust boundary crossings " +
+			"and lower RAA values of its target.",
+		RiskAssessment: "The likelihood depends on the total cost of the path, the impact is high or (for data assets rated both " +
+			types.StrictlyConfidential.String() + " and " + types.MissionCritical.String() + ") very high.",
+		FalsePositives: "Paths along communication links which can not be used by an attacker to compromise the target " +
+			"(e.g. because the target only returns static content) can be considered as false positives after individual review.",
+		ModelFailurePossibleReason: false,
+		CWE:                        1008,
+	}
+}
+
+func (*AttackPathRule) SupportedTags() []string {
+	return []string{}
+}
+
+func (r *AttackPathRule) GenerateRisks(input *types.ParsedModel) []types.Risk {
+	risks := make([]types.Risk, 0)
+	for _, path := range input.AttackPaths { // computed once per analysis, see attackpath.Apply
+		risks = append(risks, r.createRisk(input, path))
+	}
+	return risks
+}
+
+func (r *AttackPathRule) createRisk(input *types.ParsedModel, path attackpath.Path) types.Risk {
+	title := "<b>Attack Path</b> to <b>" + input.DataAssets[path.DataAssetId].Title + "</b> at <b>" +
+		input.TechnicalAssets[path.TargetId()].Title + "</b> from <b>" + input.TechnicalAssets[path.EntryId()].Title + "</b>"
+	for _, id := range path.TechnicalAssetIds[1 : len(path.TechnicalAssetIds)-1] {
+		title += " via <b>" + input.TechnicalAssets[id].Title + "</b>"
+	}
+	risk := types.Risk{
+		CategoryId:                      r.Category().Id,
+		Severity:                        types.CalculateSeverity(path.Likelihood, path.Impact),
+		ExploitationLikelihood:          path.Likelihood,
+		ExploitationImpact:              path.Impact,
+		Title:                           title,
+		MostRelevantDataAssetId:         path.DataAssetId,
+		MostRelevantTechnicalAssetId:    path.TargetId(),
+		MostRelevantCommunicationLinkId: path.CommunicationLinkIds[len(path.CommunicationLinkIds)-1],
+		DataBreachProbability:           types.Probable,
+		DataBreachTechnicalAssetIDs:     []string{path.TargetId()},
+	}
+	risk.SyntheticId = risk.CategoryId + "@" + path.Key()
+	return risk
+}
@@ -50,7 +50,7 @@ func (r *CodeBackdooringRule) GenerateRisks(parsedModel *types.ParsedModel) []ty
 	risks := make([]types.Risk, 0)
 	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
 		technicalAsset := parsedModel.TechnicalAssets[id]
-		if !technicalAsset.OutOfScope && technicalAsset.Technology.IsDevelopmentRelevant() {
+		if !technicalAsset.OutOfScope && technicalAsset.HasTrait(types.DevelopmentRelevantTrait) {
 			if technicalAsset.Internet {
 				risks = append(risks, r.createRisk(parsedModel, technicalAsset, true))
 				continue
@@ -55,7 +55,7 @@ func (r *MissingAuthenticationRule) GenerateRisks(input *types.ParsedModel) []ty
 			commLinks := input.IncomingTechnicalCommunicationLinksMappedByTargetId[technicalAsset.Id]
 			for _, commLink := range commLinks {
 				caller := input.TechnicalAssets[commLink.SourceId]
-				if caller.Technology.IsUnprotectedCommunicationsTolerated() || caller.Type == types.Datastore {
+				if caller.HasTrait(types.UnprotectedCommunicationsToleratedTrait) || caller.Type == types.Datastore {
 					continue
 				}
 				highRisk := commLink.HighestConfidentiality(input) == types.StrictlyConfidential ||
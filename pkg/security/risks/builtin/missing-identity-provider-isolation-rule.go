@@ -44,7 +44,7 @@ func (*MissingIdentityProviderIsolationRule) SupportedTags() []string {
 func (r *MissingIdentityProviderIsolationRule) GenerateRisks(input *types.ParsedModel) []types.Risk {
 	risks := make([]types.Risk, 0)
 	for _, technicalAsset := range input.TechnicalAssets {
-		if !technicalAsset.OutOfScope && technicalAsset.Technology.IsIdentityRelated() {
+		if !technicalAsset.OutOfScope && technicalAsset.HasTrait(types.IdentityRelatedTrait) {
 			moreImpact := technicalAsset.Confidentiality == types.StrictlyConfidential ||
 				technicalAsset.Integrity == types.MissionCritical ||
 				technicalAsset.Availability == types.MissionCritical
@@ -54,7 +54,7 @@ func (r *MissingIdentityProviderIsolationRule) GenerateRisks(input *types.Parsed
 			for sparringAssetCandidateId := range input.TechnicalAssets { // so inner loop again over all assets
 				if technicalAsset.Id != sparringAssetCandidateId {
 					sparringAssetCandidate := input.TechnicalAssets[sparringAssetCandidateId]
-					if !sparringAssetCandidate.Technology.IsIdentityRelated() && !sparringAssetCandidate.Technology.IsCloseToHighValueTargetsTolerated() {
+					if !sparringAssetCandidate.HasTrait(types.IdentityRelatedTrait) && !sparringAssetCandidate.HasTrait(types.CloseToHighValueTargetsToleratedTrait) {
 						if technicalAsset.IsSameExecutionEnvironment(input, sparringAssetCandidateId) {
 							createRiskEntry = true
 							sameExecutionEnv = true
@@ -66,10 +66,10 @@ func (r *MissingNetworkSegmentationRule) GenerateRisks(input *types.ParsedModel)
 				for _, sparringAssetCandidateId := range keys { // so inner loop again over all assets
 					if technicalAsset.Id != sparringAssetCandidateId {
 						sparringAssetCandidate := input.TechnicalAssets[sparringAssetCandidateId]
-						if sparringAssetCandidate.Technology.IsLessProtectedType() &&
+						if sparringAssetCandidate.HasTrait(types.LessProtectedTypeTrait) &&
 							technicalAsset.IsSameTrustBoundaryNetworkOnly(input, sparringAssetCandidateId) &&
 							!technicalAsset.HasDirectConnection(input, sparringAssetCandidateId) &&
-							!sparringAssetCandidate.Technology.IsCloseToHighValueTargetsTolerated() {
+							!sparringAssetCandidate.HasTrait(types.CloseToHighValueTargetsToleratedTrait) {
 							highRisk := technicalAsset.Confidentiality == types.StrictlyConfidential ||
 								technicalAsset.Integrity == types.MissionCritical || technicalAsset.Availability == types.MissionCritical
 							risks = append(risks, r.createRisk(technicalAsset, highRisk))
@@ -43,7 +43,7 @@ func (r *MissingWafRule) GenerateRisks(input *types.ParsedModel) []types.Risk {
 	risks := make([]types.Risk, 0)
 	for _, technicalAsset := range input.TechnicalAssets {
 		if !technicalAsset.OutOfScope &&
-			(technicalAsset.Technology.IsWebApplication() || technicalAsset.Technology.IsWebService()) {
+			(technicalAsset.HasTrait(types.WebApplicationTrait) || technicalAsset.HasTrait(types.WebServiceTrait)) {
 			for _, incomingAccess := range input.IncomingTechnicalCommunicationLinksMappedByTargetId[technicalAsset.Id] {
 				if incomingAccess.IsAcrossTrustBoundaryNetworkOnly(input) &&
 					incomingAccess.Protocol.IsPotentialWebAccessProtocol() &&
@@ -49,7 +49,7 @@ func (r *PushInsteadPullDeploymentRule) GenerateRisks(input *types.ParsedModel)
 			for _, deploymentLink := range buildPipeline.CommunicationLinks {
 				targetAsset := input.TechnicalAssets[deploymentLink.TargetId]
 				if !deploymentLink.Readonly && deploymentLink.Usage == types.DevOps &&
-					!targetAsset.OutOfScope && !targetAsset.Technology.IsDevelopmentRelevant() && targetAsset.Usage == types.Business {
+					!targetAsset.OutOfScope && !targetAsset.HasTrait(types.DevelopmentRelevantTrait) && targetAsset.Usage == types.Business {
 					if targetAsset.HighestConfidentiality(input) >= types.Confidential ||
 						targetAsset.HighestIntegrity(input) >= types.Critical ||
 						targetAsset.HighestAvailability(input) >= types.Critical {
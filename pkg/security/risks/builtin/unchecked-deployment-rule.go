@@ -44,7 +44,7 @@ func (*UncheckedDeploymentRule) SupportedTags() []string {
 func (r *UncheckedDeploymentRule) GenerateRisks(input *types.ParsedModel) []types.Risk {
 	risks := make([]types.Risk, 0)
 	for _, technicalAsset := range input.TechnicalAssets {
-		if technicalAsset.Technology.IsDevelopmentRelevant() {
+		if technicalAsset.HasTrait(types.DevelopmentRelevantTrait) {
 			risks = append(risks, r.createRisk(input, technicalAsset))
 		}
 	}
@@ -53,7 +53,7 @@ func (r *UnencryptedAssetRule) GenerateRisks(input *types.ParsedModel) []types.R
 				technicalAsset.HighestIntegrity(input) >= types.Critical) {
 			verySensitive := technicalAsset.HighestConfidentiality(input) == types.StrictlyConfidential ||
 				technicalAsset.HighestIntegrity(input) == types.MissionCritical
-			requiresEndUserKey := verySensitive && technicalAsset.Technology.IsUsuallyStoringEndUserData()
+			requiresEndUserKey := verySensitive && technicalAsset.HasTrait(types.UsuallyStoringEndUserDataTrait)
 			if technicalAsset.Encryption == types.NoneEncryption {
 				impact := types.MediumImpact
 				if verySensitive {
@@ -75,7 +75,7 @@ func (r *UnencryptedAssetRule) GenerateRisks(input *types.ParsedModel) []types.R
 func isEncryptionWaiver(asset types.TechnicalAsset) bool {
 	return asset.Technology == types.ReverseProxy || asset.Technology == types.LoadBalancer ||
 		asset.Technology == types.WAF || asset.Technology == types.IDS || asset.Technology == types.IPS ||
-		asset.Technology.IsEmbeddedComponent()
+		asset.HasTrait(types.EmbeddedComponentTrait)
 }
 
 func (r *UnencryptedAssetRule) createRisk(technicalAsset types.TechnicalAsset, impact types.RiskExploitationImpact, requiresEndUserKey bool) types.Risk {
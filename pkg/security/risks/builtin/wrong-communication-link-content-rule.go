@@ -57,11 +57,11 @@ func (r *WrongCommunicationLinkContentRule) GenerateRisks(input *types.ParsedMod
 			targetAsset := input.TechnicalAssets[commLink.TargetId]
 			if commLink.Protocol == types.InProcessLibraryCall && targetAsset.Technology != types.Library {
 				risks = append(risks, r.createRisk(techAsset, commLink,
-					"(protocol type \""+types.InProcessLibraryCall.String()+"\" does not match target technology type \""+targetAsset.Technology.String()+"\": expected \""+types.Library.String()+"\")"))
+					"(protocol type \""+types.InProcessLibraryCall.String()+"\" does not match target technology type \""+targetAsset.TechnologyName()+"\": expected \""+types.Library.String()+"\")"))
 			}
 			if commLink.Protocol == types.LocalFileAccess && targetAsset.Technology != types.LocalFileSystem {
 				risks = append(risks, r.createRisk(techAsset, commLink,
-					"(protocol type \""+types.LocalFileAccess.String()+"\" does not match target technology type \""+targetAsset.Technology.String()+"\": expected \""+types.LocalFileSystem.String()+"\")"))
+					"(protocol type \""+types.LocalFileAccess.String()+"\" does not match target technology type \""+targetAsset.TechnologyName()+"\": expected \""+types.LocalFileSystem.String()+"\")"))
 			}
 			if commLink.Protocol == types.ContainerSpawning && targetAsset.Machine != types.Container {
 				risks = append(risks, r.createRisk(techAsset, commLink,
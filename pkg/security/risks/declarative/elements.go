@@ -0,0 +1,263 @@
+package declarative
+
+import (
+	"sort"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	TechnicalAssetKind    = "technical-asset"
+	CommunicationLinkKind = "communication-link"
+	DataAssetKind         = "data-asset"
+	TrustBoundaryKind     = "trust-boundary"
+)
+
+// element is a model element as seen by expressions; attributes are resolved lazily so relations can be followed
+// in any direction without building the whole graph up front
+type element struct {
+	kind  string
+	id    string
+	model *types.ParsedModel
+}
+
+type attribute struct {
+	kind string // kind of the referenced element(s) for relations, empty for plain values
+	get  func(model *types.ParsedModel, id string) any
+}
+
+var attributes map[string]map[string]attribute
+
+func init() {
+	attributes = map[string]map[string]attribute{
+		TechnicalAssetKind: {
+			"id":                      asset(func(what types.TechnicalAsset) any { return what.Id }),
+			"title":                   asset(func(what types.TechnicalAsset) any { return what.Title }),
+			"description":             asset(func(what types.TechnicalAsset) any { return what.Description }),
+			"usage":                   asset(func(what types.TechnicalAsset) any { return what.Usage.String() }),
+			"type":                    asset(func(what types.TechnicalAsset) any { return what.Type.String() }),
+			"size":                    asset(func(what types.TechnicalAsset) any { return what.Size.String() }),
+			"technology":              asset(func(what types.TechnicalAsset) any { return what.Technology.String() }),
+			"custom_technology":       asset(func(what types.TechnicalAsset) any { return what.CustomTechnology }),
+			"machine":                 asset(func(what types.TechnicalAsset) any { return what.Machine.String() }),
+			"internet":                asset(func(what types.TechnicalAsset) any { return what.Internet }),
+			"multi_tenant":            asset(func(what types.TechnicalAsset) any { return what.MultiTenant }),
+			"redundant":               asset(func(what types.TechnicalAsset) any { return what.Redundant }),
+			"custom_developed_parts":  asset(func(what types.TechnicalAsset) any { return what.CustomDevelopedParts }),
+			"out_of_scope":            asset(func(what types.TechnicalAsset) any { return what.OutOfScope }),
+			"used_as_client_by_human": asset(func(what types.TechnicalAsset) any { return what.UsedAsClientByHuman }),
+			"encryption":              asset(func(what types.TechnicalAsset) any { return what.Encryption.String() }),
+			"owner":                   asset(func(what types.TechnicalAsset) any { return what.Owner }),
+			"confidentiality":         asset(func(what types.TechnicalAsset) any { return what.Confidentiality.String() }),
+			"integrity":               asset(func(what types.TechnicalAsset) any { return what.Integrity.String() }),
+			"availability":            asset(func(what types.TechnicalAsset) any { return what.Availability.String() }),
+			"tags":                    asset(func(what types.TechnicalAsset) any { return values(what.Tags) }),
+			"raa":                     asset(func(what types.TechnicalAsset) any { return what.RAA }),
+			"technology_traits": asset(func(what types.TechnicalAsset) any {
+				result := make([]any, 0)
+				for _, trait := range types.TechnologyTraitValues() {
+					if what.HasTrait(trait.(types.TechnologyTrait)) {
+						result = append(result, trait.String())
+					}
+				}
+				return result
+			}),
+			"data_formats_accepted": asset(func(what types.TechnicalAsset) any {
+				result := make([]any, 0)
+				for _, format := range what.DataFormatsAccepted {
+					result = append(result, format.String())
+				}
+				return result
+			}),
+			"highest_confidentiality": {get: func(model *types.ParsedModel, id string) any {
+				return model.TechnicalAssets[id].HighestConfidentiality(model).String()
+			}},
+			"highest_integrity": {get: func(model *types.ParsedModel, id string) any {
+				return model.TechnicalAssets[id].HighestIntegrity(model).String()
+			}},
+			"highest_availability": {get: func(model *types.ParsedModel, id string) any {
+				return model.TechnicalAssets[id].HighestAvailability(model).String()
+			}},
+			"data_assets_processed": {kind: DataAssetKind, get: func(model *types.ParsedModel, id string) any {
+				return elements(DataAssetKind, model, model.TechnicalAssets[id].DataAssetsProcessed)
+			}},
+			"data_assets_stored": {kind: DataAssetKind, get: func(model *types.ParsedModel, id string) any {
+				return elements(DataAssetKind, model, model.TechnicalAssets[id].DataAssetsStored)
+			}},
+			"outgoing_links": {kind: CommunicationLinkKind, get: func(model *types.ParsedModel, id string) any {
+				return linkElements(model, model.TechnicalAssets[id].CommunicationLinks)
+			}},
+			"incoming_links": {kind: CommunicationLinkKind, get: func(model *types.ParsedModel, id string) any {
+				return linkElements(model, model.IncomingTechnicalCommunicationLinksMappedByTargetId[id])
+			}},
+			"trust_boundary": {kind: TrustBoundaryKind, get: func(model *types.ParsedModel, id string) any {
+				return optionalElement(TrustBoundaryKind, model, model.DirectContainingTrustBoundaryMappedByTechnicalAssetId[id].Id)
+			}},
+		},
+
+		CommunicationLinkKind: {
+			"id":             link(func(what types.CommunicationLink) any { return what.Id }),
+			"title":          link(func(what types.CommunicationLink) any { return what.Title }),
+			"description":    link(func(what types.CommunicationLink) any { return what.Description }),
+			"protocol":       link(func(what types.CommunicationLink) any { return what.Protocol.String() }),
+			"tags":           link(func(what types.CommunicationLink) any { return values(what.Tags) }),
+			"vpn":            link(func(what types.CommunicationLink) any { return what.VPN }),
+			"ip_filtered":    link(func(what types.CommunicationLink) any { return what.IpFiltered }),
+			"readonly":       link(func(what types.CommunicationLink) any { return what.Readonly }),
+			"authentication": link(func(what types.CommunicationLink) any { return what.Authentication.String() }),
+			"authorization":  link(func(what types.CommunicationLink) any { return what.Authorization.String() }),
+			"usage":          link(func(what types.CommunicationLink) any { return what.Usage.String() }),
+			"encrypted":      link(func(what types.CommunicationLink) any { return what.Protocol.IsEncrypted() }),
+			"process_local":  link(func(what types.CommunicationLink) any { return what.Protocol.IsProcessLocal() }),
+			"bidirectional":  link(func(what types.CommunicationLink) any { return what.IsBidirectional() }),
+			"across_trust_boundary": {get: func(model *types.ParsedModel, id string) any {
+				return model.CommunicationLinks[id].IsAcrossTrustBoundary(model)
+			}},
+			"source": {kind: TechnicalAssetKind, get: func(model *types.ParsedModel, id string) any {
+				return optionalElement(TechnicalAssetKind, model, model.CommunicationLinks[id].SourceId)
+			}},
+			"target": {kind: TechnicalAssetKind, get: func(model *types.ParsedModel, id string) any {
+				return optionalElement(TechnicalAssetKind, model, model.CommunicationLinks[id].TargetId)
+			}},
+			"data_assets_sent": {kind: DataAssetKind, get: func(model *types.ParsedModel, id string) any {
+				return elements(DataAssetKind, model, model.CommunicationLinks[id].DataAssetsSent)
+			}},
+			"data_assets_received": {kind: DataAssetKind, get: func(model *types.ParsedModel, id string) any {
+				return elements(DataAssetKind, model, model.CommunicationLinks[id].DataAssetsReceived)
+			}},
+		},
+
+		DataAssetKind: {
+			"id":              data(func(what types.DataAsset) any { return what.Id }),
+			"title":           data(func(what types.DataAsset) any { return what.Title }),
+			"description":     data(func(what types.DataAsset) any { return what.Description }),
+			"usage":           data(func(what types.DataAsset) any { return what.Usage.String() }),
+			"tags":            data(func(what types.DataAsset) any { return values(what.Tags) }),
+			"origin":          data(func(what types.DataAsset) any { return what.Origin }),
+			"owner":           data(func(what types.DataAsset) any { return what.Owner }),
+			"quantity":        data(func(what types.DataAsset) any { return what.Quantity.String() }),
+			"confidentiality": data(func(what types.DataAsset) any { return what.Confidentiality.String() }),
+			"integrity":       data(func(what types.DataAsset) any { return what.Integrity.String() }),
+			"availability":    data(func(what types.DataAsset) any { return what.Availability.String() }),
+			"processed_by": {kind: TechnicalAssetKind, get: func(model *types.ParsedModel, id string) any {
+				return assetElements(model, model.DataAssets[id].ProcessedByTechnicalAssetsSorted(model))
+			}},
+			"stored_by": {kind: TechnicalAssetKind, get: func(model *types.ParsedModel, id string) any {
+				return assetElements(model, model.DataAssets[id].StoredByTechnicalAssetsSorted(model))
+			}},
+			"sent_via": {kind: CommunicationLinkKind, get: func(model *types.ParsedModel, id string) any {
+				return linkElements(model, model.DataAssets[id].SentViaCommLinksSorted(model))
+			}},
+			"received_via": {kind: CommunicationLinkKind, get: func(model *types.ParsedModel, id string) any {
+				return linkElements(model, model.DataAssets[id].ReceivedViaCommLinksSorted(model))
+			}},
+		},
+
+		TrustBoundaryKind: {
+			"id":               boundary(func(what types.TrustBoundary) any { return what.Id }),
+			"title":            boundary(func(what types.TrustBoundary) any { return what.Title }),
+			"description":      boundary(func(what types.TrustBoundary) any { return what.Description }),
+			"type":             boundary(func(what types.TrustBoundary) any { return what.Type.String() }),
+			"tags":             boundary(func(what types.TrustBoundary) any { return values(what.Tags) }),
+			"network_boundary": boundary(func(what types.TrustBoundary) any { return what.Type.IsNetworkBoundary() }),
+			"technical_assets_inside": {kind: TechnicalAssetKind, get: func(model *types.ParsedModel, id string) any {
+				return elements(TechnicalAssetKind, model, model.TrustBoundaries[id].TechnicalAssetsInside)
+			}},
+			"all_technical_assets_inside": {kind: TechnicalAssetKind, get: func(model *types.ParsedModel, id string) any {
+				return elements(TechnicalAssetKind, model, model.TrustBoundaries[id].RecursivelyAllTechnicalAssetIDsInside(model))
+			}},
+			"trust_boundaries_nested": {kind: TrustBoundaryKind, get: func(model *types.ParsedModel, id string) any {
+				return elements(TrustBoundaryKind, model, model.TrustBoundaries[id].TrustBoundariesNested)
+			}},
+			"parent": {kind: TrustBoundaryKind, get: func(model *types.ParsedModel, id string) any {
+				return optionalElement(TrustBoundaryKind, model, model.TrustBoundaries[id].ParentTrustBoundaryID(model))
+			}},
+		},
+	}
+}
+
+func (what *element) get(name string) (any, bool) {
+	found, ok := attributes[what.kind][name]
+	if !ok {
+		return nil, false
+	}
+	return found.get(what.model, what.id), true
+}
+
+// sortedIDs returns the IDs of all elements of the given kind in a stable order
+func sortedIDs(kind string, model *types.ParsedModel) []string {
+	ids := make([]string, 0)
+	switch kind {
+	case TechnicalAssetKind:
+		return model.SortedTechnicalAssetIDs()
+	case CommunicationLinkKind:
+		for id := range model.CommunicationLinks {
+			ids = append(ids, id)
+		}
+	case DataAssetKind:
+		for id := range model.DataAssets {
+			ids = append(ids, id)
+		}
+	case TrustBoundaryKind:
+		for id := range model.TrustBoundaries {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func asset(get func(what types.TechnicalAsset) any) attribute {
+	return attribute{get: func(model *types.ParsedModel, id string) any { return get(model.TechnicalAssets[id]) }}
+}
+
+func link(get func(what types.CommunicationLink) any) attribute {
+	return attribute{get: func(model *types.ParsedModel, id string) any { return get(model.CommunicationLinks[id]) }}
+}
+
+func data(get func(what types.DataAsset) any) attribute {
+	return attribute{get: func(model *types.ParsedModel, id string) any { return get(model.DataAssets[id]) }}
+}
+
+func boundary(get func(what types.TrustBoundary) any) attribute {
+	return attribute{get: func(model *types.ParsedModel, id string) any { return get(model.TrustBoundaries[id]) }}
+}
+
+func values(texts []string) []any {
+	result := make([]any, 0, len(texts))
+	for _, text := range texts {
+		result = append(result, text)
+	}
+	return result
+}
+
+func elements(kind string, model *types.ParsedModel, ids []string) []any {
+	result := make([]any, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, &element{kind: kind, id: id, model: model})
+	}
+	return result
+}
+
+func optionalElement(kind string, model *types.ParsedModel, id string) any {
+	if len(id) == 0 {
+		return nil
+	}
+	return &element{kind: kind, id: id, model: model}
+}
+
+func assetElements(model *types.ParsedModel, assets []types.TechnicalAsset) []any {
+	result := make([]any, 0, len(assets))
+	for _, what := range assets {
+		result = append(result, &element{kind: TechnicalAssetKind, id: what.Id, model: model})
+	}
+	return result
+}
+
+func linkElements(model *types.ParsedModel, links []types.CommunicationLink) []any {
+	result := make([]any, 0, len(links))
+	for _, what := range links {
+		result = append(result, &element{kind: CommunicationLinkKind, id: what.Id, model: model})
+	}
+	return result
+}
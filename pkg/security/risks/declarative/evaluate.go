@@ -0,0 +1,540 @@
+package declarative
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Expression is a parsed and checked condition of a declarative rule
+type Expression struct {
+	Source string
+	root   node
+}
+
+// ParseExpression parses the source and checks function calls and attribute names against the given element kind
+func ParseExpression(source string, kind string) (*Expression, error) {
+	root, parseError := parse(source)
+	if parseError != nil {
+		return nil, fmt.Errorf("invalid expression %q: %v", source, parseError)
+	}
+
+	_, checkError := check(root, []string{kind})
+	if checkError != nil {
+		return nil, fmt.Errorf("invalid expression %q: %v", source, checkError)
+	}
+
+	return &Expression{Source: source, root: root}, nil
+}
+
+// Matches evaluates the expression for the given element and requires a boolean result (null counts as false)
+func (what *Expression) Matches(current *element) (bool, error) {
+	value, evaluateError := evaluate(what.root, &scope{current: current})
+	if evaluateError != nil {
+		return false, fmt.Errorf("failed to evaluate %q for %v %q: %v", what.Source, current.kind, current.id, evaluateError)
+	}
+
+	result, truthError := truth(value)
+	if truthError != nil {
+		return false, fmt.Errorf("failed to evaluate %q for %v %q: %v", what.Source, current.kind, current.id, truthError)
+	}
+
+	return result, nil
+}
+
+type scope struct {
+	current any
+	parent  *scope
+}
+
+const (
+	plainKind   = ""
+	unknownKind = "?"
+)
+
+type function struct {
+	minArguments int
+	maxArguments int
+	predicate    bool // the second argument is evaluated for each item of the list given as first argument
+	call         func(current *scope, arguments []any) (any, error)
+}
+
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"any":   {minArguments: 2, maxArguments: 2, predicate: true},
+		"all":   {minArguments: 2, maxArguments: 2, predicate: true},
+		"count": {minArguments: 1, maxArguments: 2, predicate: true},
+		"has_tag": {minArguments: 1, maxArguments: -1, call: func(current *scope, arguments []any) (any, error) {
+			currentElement, ok := current.current.(*element)
+			if !ok {
+				return nil, fmt.Errorf("has_tag() can only be used on elements")
+			}
+			tags, _ := currentElement.get("tags")
+			return hasTag(tags, arguments)
+		}},
+		"len": {minArguments: 1, maxArguments: 1, call: func(_ *scope, arguments []any) (any, error) {
+			switch value := arguments[0].(type) {
+			case nil:
+				return float64(0), nil
+			case string:
+				return float64(len(value)), nil
+			case []any:
+				return float64(len(value)), nil
+			}
+			return nil, fmt.Errorf("len() expects a list or a string, got %v", describe(arguments[0]))
+		}},
+		"lower": {minArguments: 1, maxArguments: 1, call: func(_ *scope, arguments []any) (any, error) {
+			text, ok := arguments[0].(string)
+			if !ok {
+				return nil, fmt.Errorf("lower() expects a string, got %v", describe(arguments[0]))
+			}
+			return strings.ToLower(text), nil
+		}},
+		"contains": {minArguments: 2, maxArguments: 2, call: func(_ *scope, arguments []any) (any, error) {
+			return contains(arguments[1], arguments[0])
+		}},
+		"starts_with": {minArguments: 2, maxArguments: 2, call: stringFunction("starts_with", strings.HasPrefix)},
+		"ends_with":   {minArguments: 2, maxArguments: 2, call: stringFunction("ends_with", strings.HasSuffix)},
+		"matches": {minArguments: 2, maxArguments: 2, call: func(_ *scope, arguments []any) (any, error) {
+			text, textOk := arguments[0].(string)
+			switch pattern := arguments[1].(type) {
+			case *regexp.Regexp:
+				if textOk {
+					return pattern.MatchString(text), nil
+				}
+			case string:
+				if textOk {
+					return regexp.MatchString(pattern, text)
+				}
+			}
+			return nil, fmt.Errorf("matches() expects strings, got %v and %v", describe(arguments[0]), describe(arguments[1]))
+		}},
+	}
+}
+
+// check validates function calls and attribute names statically; kinds holds the element kind of each nested scope
+// (innermost last) and it returns the element kind the node evaluates to
+func check(current node, kinds []string) (string, error) {
+	switch what := current.(type) {
+	case *literalNode:
+		return plainKind, nil
+
+	case *identifierNode:
+		if what.name == "it" {
+			return kinds[len(kinds)-1], nil
+		}
+		for index := len(kinds) - 1; index >= 0; index-- {
+			if kinds[index] == unknownKind {
+				return unknownKind, nil
+			}
+			if found, ok := attributes[kinds[index]][what.name]; ok {
+				return found.kind, nil
+			}
+		}
+		return "", fmt.Errorf("unknown attribute %q at position %d", what.name, what.position+1)
+
+	case *memberNode:
+		objectKind, checkError := check(what.object, kinds)
+		if checkError != nil {
+			return "", checkError
+		}
+		if objectKind == unknownKind {
+			return unknownKind, nil
+		}
+		found, ok := attributes[objectKind][what.name]
+		if !ok {
+			return "", fmt.Errorf("unknown attribute %q at position %d", what.name, what.position+1)
+		}
+		return found.kind, nil
+
+	case *notNode:
+		_, checkError := check(what.operand, kinds)
+		return plainKind, checkError
+
+	case *binaryNode:
+		_, checkError := check(what.left, kinds)
+		if checkError != nil {
+			return "", checkError
+		}
+		_, checkError = check(what.right, kinds)
+		return plainKind, checkError
+
+	case *listNode:
+		for _, item := range what.items {
+			_, checkError := check(item, kinds)
+			if checkError != nil {
+				return "", checkError
+			}
+		}
+		return plainKind, nil
+
+	case *callNode:
+		called, ok := functions[what.name]
+		if !ok {
+			return "", fmt.Errorf("unknown function %q at position %d", what.name, what.position+1)
+		}
+		if len(what.arguments) < called.minArguments || (called.maxArguments >= 0 && len(what.arguments) > called.maxArguments) {
+			return "", fmt.Errorf("wrong number of arguments for %v() at position %d", what.name, what.position+1)
+		}
+
+		itemKind := plainKind
+		for index, argument := range what.arguments {
+			argumentKinds := kinds
+			if called.predicate && index == 1 {
+				argumentKinds = append(append(make([]string, 0, len(kinds)+1), kinds...), itemKind)
+			}
+
+			argumentKind, checkError := check(argument, argumentKinds)
+			if checkError != nil {
+				return "", checkError
+			}
+			if index == 0 {
+				itemKind = argumentKind
+			}
+		}
+
+		if what.name == "matches" {
+			if literal, ok := what.arguments[1].(*literalNode); ok {
+				if pattern, ok := literal.value.(string); ok {
+					compiled, compileError := regexp.Compile(pattern)
+					if compileError != nil {
+						return "", fmt.Errorf("invalid pattern %q of matches() at position %d: %v", pattern, what.position+1, compileError)
+					}
+					what.pattern = compiled
+				}
+			}
+		}
+		return plainKind, nil
+	}
+
+	return "", fmt.Errorf("unsupported expression")
+}
+
+func evaluate(current node, context *scope) (any, error) {
+	switch what := current.(type) {
+	case *literalNode:
+		return what.value, nil
+
+	case *identifierNode:
+		if what.name == "it" {
+			return context.current, nil
+		}
+		for candidate := context; candidate != nil; candidate = candidate.parent {
+			if currentElement, ok := candidate.current.(*element); ok {
+				if value, found := currentElement.get(what.name); found {
+					return value, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("unknown attribute %q", what.name)
+
+	case *memberNode:
+		object, evaluateError := evaluate(what.object, context)
+		if evaluateError != nil {
+			return nil, evaluateError
+		}
+		if object == nil { // missing relations like the trust boundary of an asset outside any boundary
+			return nil, nil
+		}
+		objectElement, ok := object.(*element)
+		if !ok {
+			return nil, fmt.Errorf("cannot access %q of %v", what.name, describe(object))
+		}
+		value, found := objectElement.get(what.name)
+		if !found {
+			return nil, fmt.Errorf("unknown attribute %q of %v", what.name, objectElement.kind)
+		}
+		return value, nil
+
+	case *notNode:
+		operand, evaluateError := evaluate(what.operand, context)
+		if evaluateError != nil {
+			return nil, evaluateError
+		}
+		result, truthError := truth(operand)
+		return !result, truthError
+
+	case *binaryNode:
+		return evaluateBinary(what, context)
+
+	case *listNode:
+		result := make([]any, 0, len(what.items))
+		for _, item := range what.items {
+			value, evaluateError := evaluate(item, context)
+			if evaluateError != nil {
+				return nil, evaluateError
+			}
+			result = append(result, value)
+		}
+		return result, nil
+
+	case *callNode:
+		return evaluateCall(what, context)
+	}
+
+	return nil, fmt.Errorf("unsupported expression")
+}
+
+func evaluateBinary(what *binaryNode, context *scope) (any, error) {
+	left, leftError := evaluate(what.left, context)
+	if leftError != nil {
+		return nil, leftError
+	}
+
+	if what.operator == "&&" || what.operator == "||" {
+		leftTruth, truthError := truth(left)
+		if truthError != nil {
+			return nil, truthError
+		}
+		if leftTruth == (what.operator == "||") {
+			return leftTruth, nil
+		}
+
+		right, rightError := evaluate(what.right, context)
+		if rightError != nil {
+			return nil, rightError
+		}
+		return truth(right)
+	}
+
+	right, rightError := evaluate(what.right, context)
+	if rightError != nil {
+		return nil, rightError
+	}
+
+	switch what.operator {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "in":
+		return contains(right, left)
+	}
+
+	order, compareError := compare(left, right)
+	if compareError != nil {
+		return nil, fmt.Errorf("%v at position %d", compareError, what.position+1)
+	}
+
+	switch what.operator {
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	case ">=":
+		return order >= 0, nil
+	}
+
+	return nil, fmt.Errorf("unsupported operator %q", what.operator)
+}
+
+func evaluateCall(what *callNode, context *scope) (any, error) {
+	called := functions[what.name]
+	if !called.predicate {
+		arguments := make([]any, 0, len(what.arguments))
+		for _, argument := range what.arguments {
+			value, evaluateError := evaluate(argument, context)
+			if evaluateError != nil {
+				return nil, evaluateError
+			}
+			arguments = append(arguments, value)
+		}
+		if what.pattern != nil {
+			arguments[1] = what.pattern
+		}
+		return called.call(context, arguments)
+	}
+
+	listValue, evaluateError := evaluate(what.arguments[0], context)
+	if evaluateError != nil {
+		return nil, evaluateError
+	}
+
+	var items []any
+	switch value := listValue.(type) {
+	case nil:
+	case []any:
+		items = value
+	default:
+		return nil, fmt.Errorf("%v() expects a list, got %v", what.name, describe(listValue))
+	}
+
+	matching := 0
+	for _, item := range items {
+		matches := true
+		if len(what.arguments) > 1 {
+			value, itemError := evaluate(what.arguments[1], &scope{current: item, parent: context})
+			if itemError != nil {
+				return nil, itemError
+			}
+			var truthError error
+			matches, truthError = truth(value)
+			if truthError != nil {
+				return nil, truthError
+			}
+		}
+
+		if matches {
+			matching++
+		}
+	}
+
+	switch what.name {
+	case "any":
+		return matching > 0, nil
+	case "all":
+		return matching == len(items), nil
+	}
+	return float64(matching), nil
+}
+
+func truth(value any) (bool, error) {
+	switch what := value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return what, nil
+	}
+	return false, fmt.Errorf("expected a boolean, got %v", describe(value))
+}
+
+func equal(left any, right any) bool {
+	leftElement, leftIsElement := left.(*element)
+	rightElement, rightIsElement := right.(*element)
+	if leftIsElement || rightIsElement {
+		return leftIsElement && rightIsElement && leftElement.kind == rightElement.kind && leftElement.id == rightElement.id
+	}
+
+	switch left.(type) {
+	case nil, bool, float64, string:
+		switch right.(type) {
+		case nil, bool, float64, string:
+			return left == right
+		}
+	}
+
+	return false
+}
+
+func contains(container any, value any) (bool, error) {
+	switch what := container.(type) {
+	case nil:
+		return false, nil
+	case []any:
+		for _, item := range what {
+			if equal(item, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case string:
+		text, ok := value.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot search %v in a string", describe(value))
+		}
+		return strings.Contains(what, text), nil
+	}
+	return false, fmt.Errorf("cannot search in %v", describe(container))
+}
+
+// orderedValues lists the enum values which are compared by their rank instead of alphabetically
+var orderedValues = [][]types.TypeEnum{
+	types.ConfidentialityValues(),
+	types.CriticalityValues(),
+	types.QuantityValues(),
+	types.DataBreachProbabilityValues(),
+	types.RiskExploitationLikelihoodValues(),
+	types.RiskExploitationImpactValues(),
+	types.RiskSeverityValues(),
+	types.TechnicalAssetSizeValues(),
+}
+
+func compare(left any, right any) (int, error) {
+	switch leftValue := left.(type) {
+	case float64:
+		if rightValue, ok := right.(float64); ok {
+			switch {
+			case leftValue < rightValue:
+				return -1, nil
+			case leftValue > rightValue:
+				return 1, nil
+			}
+			return 0, nil
+		}
+
+	case string:
+		if rightValue, ok := right.(string); ok {
+			for _, enumValues := range orderedValues {
+				leftRank, rightRank := rank(enumValues, leftValue), rank(enumValues, rightValue)
+				if leftRank >= 0 && rightRank >= 0 {
+					return leftRank - rightRank, nil
+				}
+			}
+			return strings.Compare(leftValue, rightValue), nil
+		}
+	}
+
+	return 0, fmt.Errorf("cannot compare %v with %v", describe(left), describe(right))
+}
+
+func rank(enumValues []types.TypeEnum, value string) int {
+	for index, candidate := range enumValues {
+		if candidate.String() == value {
+			return index
+		}
+	}
+	return -1
+}
+
+func hasTag(tags any, arguments []any) (bool, error) {
+	tagList, _ := tags.([]any)
+	for _, argument := range arguments {
+		wanted, ok := argument.(string)
+		if !ok {
+			return false, fmt.Errorf("has_tag() expects strings, got %v", describe(argument))
+		}
+
+		for _, tag := range tagList {
+			text, _ := tag.(string)
+			if types.IsTaggedWithBaseTag([]string{text}, wanted) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func stringFunction(name string, test func(text string, argument string) bool) func(*scope, []any) (any, error) {
+	return func(_ *scope, arguments []any) (any, error) {
+		text, textOk := arguments[0].(string)
+		argument, argumentOk := arguments[1].(string)
+		if !textOk || !argumentOk {
+			return nil, fmt.Errorf("%v() expects strings, got %v and %v", name, describe(arguments[0]), describe(arguments[1]))
+		}
+		return test(text, argument), nil
+	}
+}
+
+func describe(value any) string {
+	switch what := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return fmt.Sprintf("boolean %v", what)
+	case float64:
+		return fmt.Sprintf("number %v", what)
+	case string:
+		return fmt.Sprintf("string %q", what)
+	case []any:
+		return "a list"
+	case *element:
+		return fmt.Sprintf("%v %q", what.kind, what.id)
+	}
+	return fmt.Sprintf("%T", value)
+}
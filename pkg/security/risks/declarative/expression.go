@@ -0,0 +1,381 @@
+package declarative
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*
+The expression language is deliberately small:
+
+	confidentiality >= "confidential" && !has_tag("encrypted")
+	any(outgoing_links, target.internet and protocol == "http")
+	count(data_assets_stored, confidentiality == "strictly-confidential") > 2
+	technology in ["database", "file-server"]
+
+Identifiers refer to attributes of the selected element. Inside the predicate of any(), all() and count() they refer
+to the attributes of the current list item first and then to the outer elements; the item itself is available as "it".
+Ordered enum values like confidentiality, criticality and quantity are compared by rank, not alphabetically.
+*/
+
+type node interface{}
+
+type literalNode struct {
+	value any
+}
+
+type identifierNode struct {
+	name     string
+	position int
+}
+
+type memberNode struct {
+	object   node
+	name     string
+	position int
+}
+
+type notNode struct {
+	operand node
+}
+
+type binaryNode struct {
+	operator string
+	left     node
+	right    node
+	position int
+}
+
+type listNode struct {
+	items []node
+}
+
+type callNode struct {
+	name      string
+	arguments []node
+	position  int
+	pattern   *regexp.Regexp // the literal pattern of matches(), compiled while checking the expression
+}
+
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	identifierToken
+	stringToken
+	numberToken
+	operatorToken
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+func tokenize(source string) ([]token, error) {
+	tokens := make([]token, 0)
+	for position := 0; position < len(source); {
+		char := rune(source[position])
+		switch {
+		case unicode.IsSpace(char):
+			position++
+
+		case char == '"' || char == '\'':
+			end := position + 1
+			var text strings.Builder
+			for ; end < len(source) && rune(source[end]) != char; end++ {
+				if source[end] == '\\' && end+1 < len(source) {
+					end++
+				}
+				text.WriteByte(source[end])
+			}
+			if end >= len(source) {
+				return nil, fmt.Errorf("unterminated string at position %d", position+1)
+			}
+			tokens = append(tokens, token{kind: stringToken, text: text.String(), position: position})
+			position = end + 1
+
+		case unicode.IsDigit(char):
+			end := position
+			for end < len(source) && (unicode.IsDigit(rune(source[end])) || source[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: numberToken, text: source[position:end], position: position})
+			position = end
+
+		case unicode.IsLetter(char) || char == '_':
+			end := position
+			for end < len(source) && (unicode.IsLetter(rune(source[end])) || unicode.IsDigit(rune(source[end])) || source[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, token{kind: identifierToken, text: source[position:end], position: position})
+			position = end
+
+		default:
+			found := false
+			for _, operator := range operators {
+				if strings.HasPrefix(source[position:], operator) {
+					tokens = append(tokens, token{kind: operatorToken, text: operator, position: position})
+					position += len(operator)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected character %q at position %d", char, position+1)
+			}
+		}
+	}
+
+	return append(tokens, token{kind: endToken, position: len(source)}), nil
+}
+
+type parser struct {
+	tokens []token
+	index  int
+}
+
+func parse(source string) (node, error) {
+	tokens, tokenizeError := tokenize(source)
+	if tokenizeError != nil {
+		return nil, tokenizeError
+	}
+
+	p := &parser{tokens: tokens}
+	root, parseError := p.parseOr()
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	if p.peek().kind != endToken {
+		return nil, p.unexpected()
+	}
+
+	return root, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	current := p.tokens[p.index]
+	if current.kind != endToken {
+		p.index++
+	}
+	return current
+}
+
+// accept consumes the next token if it is one of the given operators or keywords
+func (p *parser) accept(texts ...string) (token, bool) {
+	current := p.peek()
+	if current.kind != operatorToken && current.kind != identifierToken {
+		return current, false
+	}
+
+	for _, text := range texts {
+		if current.text == text {
+			return p.next(), true
+		}
+	}
+
+	return current, false
+}
+
+func (p *parser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		return fmt.Errorf("expected %q at position %d", text, p.peek().position+1)
+	}
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	current := p.peek()
+	if current.kind == endToken {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected %q at position %d", current.text, current.position+1)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, parseError := p.parseAnd()
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	for {
+		operator, ok := p.accept("||", "or")
+		if !ok {
+			return left, nil
+		}
+
+		right, rightError := p.parseAnd()
+		if rightError != nil {
+			return nil, rightError
+		}
+		left = &binaryNode{operator: "||", left: left, right: right, position: operator.position}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, parseError := p.parseNot()
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	for {
+		operator, ok := p.accept("&&", "and")
+		if !ok {
+			return left, nil
+		}
+
+		right, rightError := p.parseNot()
+		if rightError != nil {
+			return nil, rightError
+		}
+		left = &binaryNode{operator: "&&", left: left, right: right, position: operator.position}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		operand, parseError := p.parseNot()
+		if parseError != nil {
+			return nil, parseError
+		}
+		return &notNode{operand: operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, parseError := p.parseMember()
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	operator, ok := p.accept("==", "!=", "<=", ">=", "<", ">", "in")
+	if !ok {
+		return left, nil
+	}
+
+	right, rightError := p.parseMember()
+	if rightError != nil {
+		return nil, rightError
+	}
+
+	return &binaryNode{operator: operator.text, left: left, right: right, position: operator.position}, nil
+}
+
+func (p *parser) parseMember() (node, error) {
+	object, parseError := p.parsePrimary()
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	for {
+		dot, ok := p.accept(".")
+		if !ok {
+			return object, nil
+		}
+
+		name := p.next()
+		if name.kind != identifierToken {
+			return nil, fmt.Errorf("expected attribute name after '.' at position %d", dot.position+1)
+		}
+		object = &memberNode{object: object, name: name.text, position: name.position}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.peek().kind == endToken {
+		return nil, p.unexpected()
+	}
+
+	current := p.next()
+	switch current.kind {
+	case stringToken:
+		return &literalNode{value: current.text}, nil
+
+	case numberToken:
+		number, parseError := strconv.ParseFloat(current.text, 64)
+		if parseError != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", current.text, current.position+1)
+		}
+		return &literalNode{value: number}, nil
+
+	case identifierToken:
+		switch current.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		case "and", "or", "not", "in":
+			p.index--
+			return nil, p.unexpected()
+		}
+
+		if _, ok := p.accept("("); !ok {
+			return &identifierNode{name: current.text, position: current.position}, nil
+		}
+
+		call := &callNode{name: current.text, position: current.position}
+		if _, ok := p.accept(")"); ok {
+			return call, nil
+		}
+
+		for {
+			argument, parseError := p.parseOr()
+			if parseError != nil {
+				return nil, parseError
+			}
+			call.arguments = append(call.arguments, argument)
+
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		return call, p.expect(")")
+
+	case operatorToken:
+		switch current.text {
+		case "(":
+			inner, parseError := p.parseOr()
+			if parseError != nil {
+				return nil, parseError
+			}
+			return inner, p.expect(")")
+
+		case "[":
+			list := &listNode{}
+			if _, ok := p.accept("]"); ok {
+				return list, nil
+			}
+
+			for {
+				item, parseError := p.parseOr()
+				if parseError != nil {
+					return nil, parseError
+				}
+				list.items = append(list.items, item)
+
+				if _, ok := p.accept(","); !ok {
+					break
+				}
+			}
+			return list, p.expect("]")
+		}
+	}
+
+	p.index--
+	return nil, p.unexpected()
+}
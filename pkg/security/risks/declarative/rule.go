@@ -0,0 +1,383 @@
+/*
+Package declarative implements custom risk rules written in YAML instead of Go. A rule selects model elements of one
+kind with an expression and turns each match into a risk of the rule's category:
+
+	risk_rules:
+	  - category:
+	      id: unencrypted-customer-database
+	      title: Unencrypted Customer Database
+	      description: Customer data must be stored encrypted.
+	      function: operations
+	      stride: information-disclosure
+	      cwe: 311
+	    supported_tags:
+	      - customer-data
+	    select: technical-asset
+	    where: technology == "database" && encryption == "none" && any(data_assets_stored, has_tag("customer-data"))
+	    risk:
+	      synthetic_id: "{{.category.id}}@{{.id}}"
+	      title: "<b>Unencrypted Customer Database</b> named <b>{{.title}}</b>"
+	      exploitation_likelihood: likely
+	      exploitation_impact:
+	        - when: highest_confidentiality == "strictly-confidential"
+	          value: high
+	        - value: medium
+	      data_breach_probability: probable
+*/
+package declarative
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const defaultSyntheticId = "{{.category.id}}@{{.id}}"
+
+// Rule is a loaded and validated declarative risk rule
+type Rule struct {
+	Filename string
+
+	category              types.RiskCategory
+	supportedTags         []string
+	kind                  string
+	where                 *Expression
+	syntheticId           *template.Template
+	title                 *template.Template
+	likelihood            []choice[types.RiskExploitationLikelihood]
+	impact                []choice[types.RiskExploitationImpact]
+	dataBreachProbability []choice[types.DataBreachProbability]
+}
+
+type ruleFile struct {
+	RiskRules []ruleDefinition `yaml:"risk_rules"`
+}
+
+type ruleDefinition struct {
+	Category      types.RiskCategory `yaml:"category"`
+	SupportedTags []string           `yaml:"supported_tags"`
+	Select        string             `yaml:"select"`
+	Where         string             `yaml:"where"`
+	Risk          riskDefinition     `yaml:"risk"`
+}
+
+type riskDefinition struct {
+	SyntheticId           string           `yaml:"synthetic_id"`
+	Title                 string           `yaml:"title"`
+	Likelihood            conditionalValue `yaml:"exploitation_likelihood"`
+	Impact                conditionalValue `yaml:"exploitation_impact"`
+	DataBreachProbability conditionalValue `yaml:"data_breach_probability"`
+}
+
+// conditionalValue is either a plain value or a list of values with conditions, where the first matching one wins
+type conditionalValue struct {
+	Cases []conditionalCase
+}
+
+type conditionalCase struct {
+	When  string `yaml:"when"`
+	Value string `yaml:"value"`
+}
+
+func (what *conditionalValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		what.Cases = []conditionalCase{{Value: node.Value}}
+		return nil
+	}
+	return node.Decode(&what.Cases)
+}
+
+type choice[T any] struct {
+	when  *Expression
+	value T
+}
+
+// IsRuleFile tells declarative rule files apart from custom risk rule plugin executables
+func IsRuleFile(filename string) bool {
+	extension := strings.ToLower(filepath.Ext(filename))
+	return extension == ".yaml" || extension == ".yml"
+}
+
+// LoadRules reads and validates all rules of a declarative rule file
+func LoadRules(filename string) ([]*Rule, error) {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, readError
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var file ruleFile
+	decodeError := decoder.Decode(&file)
+	if decodeError != nil {
+		return nil, fmt.Errorf("failed to parse %q: %v", filename, decodeError)
+	}
+
+	if len(file.RiskRules) == 0 {
+		return nil, fmt.Errorf("no risk rules found in %q", filename)
+	}
+
+	rules := make([]*Rule, 0)
+	ids := make(map[string]bool)
+	for index, definition := range file.RiskRules {
+		rule, ruleError := newRule(filename, definition)
+		if ruleError != nil {
+			return nil, fmt.Errorf("invalid risk rule #%d (%q) in %q: %v", index+1, definition.Category.Id, filename, ruleError)
+		}
+
+		if ids[rule.category.Id] {
+			return nil, fmt.Errorf("duplicate risk rule %q in %q", rule.category.Id, filename)
+		}
+		ids[rule.category.Id] = true
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func newRule(filename string, definition ruleDefinition) (*Rule, error) {
+	if len(strings.TrimSpace(definition.Category.Id)) == 0 {
+		return nil, fmt.Errorf("missing category id")
+	}
+
+	if len(strings.TrimSpace(definition.Category.Title)) == 0 {
+		return nil, fmt.Errorf("missing category title")
+	}
+
+	if _, ok := attributes[definition.Select]; !ok {
+		return nil, fmt.Errorf("unknown element kind %q to select, expected one of %v, %v, %v or %v", definition.Select,
+			TechnicalAssetKind, CommunicationLinkKind, DataAssetKind, TrustBoundaryKind)
+	}
+
+	rule := &Rule{
+		Filename:      filename,
+		category:      definition.Category,
+		supportedTags: definition.SupportedTags,
+		kind:          definition.Select,
+	}
+
+	if rule.supportedTags == nil {
+		rule.supportedTags = make([]string, 0)
+	}
+
+	if len(strings.TrimSpace(definition.Where)) > 0 {
+		where, parseError := ParseExpression(definition.Where, rule.kind)
+		if parseError != nil {
+			return nil, parseError
+		}
+		rule.where = where
+	}
+
+	var templateError error
+	syntheticId := definition.Risk.SyntheticId
+	if len(syntheticId) == 0 {
+		syntheticId = defaultSyntheticId
+	}
+	rule.syntheticId, templateError = template.New("synthetic_id").Option("missingkey=error").Parse(syntheticId)
+	if templateError != nil {
+		return nil, fmt.Errorf("invalid synthetic id template: %v", templateError)
+	}
+
+	title := definition.Risk.Title
+	if len(title) == 0 {
+		title = "<b>" + definition.Category.Title + "</b> at <b>{{.title}}</b>"
+	}
+	rule.title, templateError = template.New("title").Option("missingkey=error").Parse(title)
+	if templateError != nil {
+		return nil, fmt.Errorf("invalid title template: %v", templateError)
+	}
+
+	var choiceError error
+	rule.likelihood, choiceError = newChoices(definition.Risk.Likelihood, rule.kind, types.ParseRiskExploitationLikelihood)
+	if choiceError != nil {
+		return nil, fmt.Errorf("invalid exploitation likelihood: %v", choiceError)
+	}
+
+	rule.impact, choiceError = newChoices(definition.Risk.Impact, rule.kind, types.ParseRiskExploitationImpact)
+	if choiceError != nil {
+		return nil, fmt.Errorf("invalid exploitation impact: %v", choiceError)
+	}
+
+	rule.dataBreachProbability, choiceError = newChoices(definition.Risk.DataBreachProbability, rule.kind, types.ParseDataBreachProbability)
+	if choiceError != nil {
+		return nil, fmt.Errorf("invalid data breach probability: %v", choiceError)
+	}
+
+	return rule, nil
+}
+
+func newChoices[T any](value conditionalValue, kind string, parseValue func(string) (T, error)) ([]choice[T], error) {
+	choices := make([]choice[T], 0)
+	for _, current := range value.Cases {
+		parsed, parseError := parseValue(current.Value)
+		if parseError != nil {
+			return nil, parseError
+		}
+
+		next := choice[T]{value: parsed}
+		if len(strings.TrimSpace(current.When)) > 0 {
+			when, whenError := ParseExpression(current.When, kind)
+			if whenError != nil {
+				return nil, whenError
+			}
+			next.when = when
+		}
+
+		choices = append(choices, next)
+	}
+
+	if len(choices) == 0 || choices[len(choices)-1].when != nil { // fall back to the type's default
+		fallback, _ := parseValue("")
+		choices = append(choices, choice[T]{value: fallback})
+	}
+
+	return choices, nil
+}
+
+func (r *Rule) Category() types.RiskCategory {
+	return r.category
+}
+
+func (r *Rule) SupportedTags() []string {
+	return r.supportedTags
+}
+
+func (r *Rule) GenerateRisks(parsedModel *types.ParsedModel) ([]types.Risk, error) {
+	risks := make([]types.Risk, 0)
+	for _, id := range sortedIDs(r.kind, parsedModel) {
+		current := &element{kind: r.kind, id: id, model: parsedModel}
+		if r.where != nil {
+			matches, matchError := r.where.Matches(current)
+			if matchError != nil {
+				return nil, matchError
+			}
+			if !matches {
+				continue
+			}
+		}
+
+		risk, riskError := r.createRisk(current)
+		if riskError != nil {
+			return nil, riskError
+		}
+		risks = append(risks, risk)
+	}
+
+	return risks, nil
+}
+
+func (r *Rule) createRisk(current *element) (types.Risk, error) {
+	likelihood, likelihoodError := choose(r.likelihood, current)
+	if likelihoodError != nil {
+		return types.Risk{}, likelihoodError
+	}
+
+	impact, impactError := choose(r.impact, current)
+	if impactError != nil {
+		return types.Risk{}, impactError
+	}
+
+	dataBreachProbability, probabilityError := choose(r.dataBreachProbability, current)
+	if probabilityError != nil {
+		return types.Risk{}, probabilityError
+	}
+
+	data := templateValues(current, 1)
+	data["category"] = map[string]any{"id": r.category.Id, "title": r.category.Title}
+
+	title, titleError := execute(r.title, data)
+	if titleError != nil {
+		return types.Risk{}, fmt.Errorf("failed to create risk title for %v %q: %v", current.kind, current.id, titleError)
+	}
+
+	syntheticId, syntheticIdError := execute(r.syntheticId, data)
+	if syntheticIdError != nil {
+		return types.Risk{}, fmt.Errorf("failed to create synthetic id for %v %q: %v", current.kind, current.id, syntheticIdError)
+	}
+
+	risk := types.Risk{
+		CategoryId:             r.category.Id,
+		Severity:               types.CalculateSeverity(likelihood, impact),
+		ExploitationLikelihood: likelihood,
+		ExploitationImpact:     impact,
+		Title:                  title,
+		SyntheticId:            syntheticId,
+		DataBreachProbability:  dataBreachProbability,
+	}
+
+	model := current.model
+	switch current.kind {
+	case TechnicalAssetKind:
+		risk.MostRelevantTechnicalAssetId = current.id
+		risk.DataBreachTechnicalAssetIDs = []string{current.id}
+	case CommunicationLinkKind:
+		link := model.CommunicationLinks[current.id]
+		risk.MostRelevantCommunicationLinkId = current.id
+		risk.MostRelevantTechnicalAssetId = link.SourceId
+		risk.DataBreachTechnicalAssetIDs = []string{link.TargetId}
+	case DataAssetKind:
+		risk.MostRelevantDataAssetId = current.id
+		risk.DataBreachTechnicalAssetIDs = make([]string, 0)
+		for _, storing := range model.DataAssets[current.id].StoredByTechnicalAssetsSorted(model) {
+			risk.DataBreachTechnicalAssetIDs = append(risk.DataBreachTechnicalAssetIDs, storing.Id)
+		}
+	case TrustBoundaryKind:
+		risk.MostRelevantTrustBoundaryId = current.id
+	}
+
+	return risk, nil
+}
+
+func choose[T any](choices []choice[T], current *element) (T, error) {
+	for _, candidate := range choices {
+		if candidate.when == nil {
+			return candidate.value, nil
+		}
+
+		matches, matchError := candidate.when.Matches(current)
+		if matchError != nil {
+			var empty T
+			return empty, matchError
+		}
+		if matches {
+			return candidate.value, nil
+		}
+	}
+
+	var empty T
+	return empty, nil
+}
+
+// templateValues makes the plain attributes of an element available to templates, single related elements
+// (like the source of a link) are included up to the given depth
+func templateValues(current *element, depth int) map[string]any {
+	result := make(map[string]any)
+	for name, found := range attributes[current.kind] {
+		if len(found.kind) == 0 {
+			result[name] = found.get(current.model, current.id)
+			continue
+		}
+
+		if depth > 0 {
+			if related, ok := found.get(current.model, current.id).(*element); ok {
+				result[name] = templateValues(related, depth-1)
+			}
+		}
+	}
+	return result
+}
+
+func execute(current *template.Template, data map[string]any) (string, error) {
+	var result strings.Builder
+	executeError := current.Execute(&result, data)
+	return result.String(), executeError
+}
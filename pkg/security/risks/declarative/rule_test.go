@@ -0,0 +1,167 @@
+package declarative
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestParseExpressionRejectsInvalidExpressions(t *testing.T) {
+	for _, source := range []string{
+		`technology ==`,
+		`unknown_attribute == "x"`,
+		`any(outgoing_links, target.unknown_attribute)`,
+		`unknown_function(title)`,
+		`any(outgoing_links)`,
+		`title == "unterminated`,
+		`matches(title, "data[base")`,
+	} {
+		_, parseError := ParseExpression(source, TechnicalAssetKind)
+		assert.Error(t, parseError, source)
+	}
+}
+
+func TestExpressionMatches(t *testing.T) {
+	parsedModel := createParsedModel()
+	database := &element{kind: TechnicalAssetKind, id: "database", model: parsedModel}
+
+	for source, expected := range map[string]bool{
+		`technology == "database" && encryption == "none"`:                       true,
+		`confidentiality >= "confidential"`:                                      false,
+		`highest_confidentiality >= "confidential"`:                              true,
+		`any(data_assets_stored, confidentiality == "strictly-confidential")`:    true,
+		`any(incoming_links, source.internet and !encrypted)`:                    true,
+		`count(incoming_links) == 1 && len(outgoing_links) == 0`:                 true,
+		`has_tag("aws") && !has_tag("azure")`:                                    true,
+		`trust_boundary.type == "network-cloud-provider"`:                        true,
+		`trust_boundary.parent == null`:                                          true,
+		`technology in ["file-server", "database"]`:                              true,
+		`all(data_assets_stored, quantity > "many") || starts_with(title, "DB")`: false,
+		`matches(title, "^Data(base)?$")`:                                        true,
+		`any(data_assets_stored, matches(id, "^customer-"))`:                     true,
+		`matches(title, lower(title))`:                                           false, // a pattern only known when evaluating
+	} {
+		expression, parseError := ParseExpression(source, TechnicalAssetKind)
+		assert.NoError(t, parseError, source)
+
+		matches, matchError := expression.Matches(database)
+		assert.NoError(t, matchError, source)
+		assert.Equal(t, expected, matches, source)
+	}
+}
+
+func TestLoadRulesAndGenerateRisks(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "rules.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte(`
+risk_rules:
+  - category:
+      id: plaintext-to-database
+      title: Plaintext to Database
+      function: operations
+      stride: information-disclosure
+    select: communication-link
+    where: target.technology == "database" && !encrypted
+    risk:
+      synthetic_id: "{{.category.id}}@{{.source.id}}>{{.target.id}}"
+      title: "<b>Plaintext</b> from <b>{{.source.title}}</b>"
+      exploitation_likelihood: likely
+      exploitation_impact:
+        - when: any(data_assets_sent, confidentiality == "strictly-confidential")
+          value: high
+        - value: low
+`), 0600))
+
+	rules, loadError := LoadRules(filename)
+	assert.NoError(t, loadError)
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "plaintext-to-database", rules[0].Category().Id)
+	assert.Equal(t, types.Operations, rules[0].Category().Function)
+
+	generatedRisks, generateError := rules[0].GenerateRisks(createParsedModel())
+	assert.NoError(t, generateError)
+	assert.Len(t, generatedRisks, 1)
+	assert.Equal(t, "plaintext-to-database@web>database", generatedRisks[0].SyntheticId)
+	assert.Equal(t, "<b>Plaintext</b> from <b>Web Server</b>", generatedRisks[0].Title)
+	assert.Equal(t, types.HighImpact, generatedRisks[0].ExploitationImpact)
+	assert.Equal(t, types.CalculateSeverity(types.Likely, types.HighImpact), generatedRisks[0].Severity)
+	assert.Equal(t, "web>database", generatedRisks[0].MostRelevantCommunicationLinkId)
+	assert.Equal(t, []string{"database"}, generatedRisks[0].DataBreachTechnicalAssetIDs)
+}
+
+func TestLoadRulesRejectsInvalidRules(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown field":   "risk_rules:\n  - category: {id: a, title: A}\n    select: technical-asset\n    unknown: x\n",
+		"missing id":      "risk_rules:\n  - category: {title: A}\n    select: technical-asset\n",
+		"unknown kind":    "risk_rules:\n  - category: {id: a, title: A}\n    select: shared-runtime\n",
+		"invalid impact":  "risk_rules:\n  - category: {id: a, title: A}\n    select: data-asset\n    risk: {exploitation_impact: huge}\n",
+		"invalid title":   "risk_rules:\n  - category: {id: a, title: A}\n    select: data-asset\n    risk: {title: '{{.title'}\n",
+		"invalid pattern": "risk_rules:\n  - category: {id: a, title: A}\n    select: data-asset\n    where: matches(title, \"(\")\n",
+		"duplicate rules": "risk_rules:\n  - category: {id: a, title: A}\n    select: data-asset\n  - category: {id: a, title: B}\n    select: data-asset\n",
+	} {
+		filename := filepath.Join(t.TempDir(), "rules.yaml")
+		assert.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+
+		_, loadError := LoadRules(filename)
+		assert.Error(t, loadError, name)
+	}
+}
+
+func createParsedModel() *types.ParsedModel {
+	link := types.CommunicationLink{
+		Id:             "web>database",
+		SourceId:       "web",
+		TargetId:       "database",
+		Title:          "Database Access",
+		Protocol:       types.JDBC,
+		DataAssetsSent: []string{"customer-data"},
+	}
+
+	parsedModel := &types.ParsedModel{
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web": {
+				Id:                 "web",
+				Title:              "Web Server",
+				Technology:         types.WebServer,
+				Internet:           true,
+				CommunicationLinks: []types.CommunicationLink{link},
+			},
+			"database": {
+				Id:                  "database",
+				Title:               "Database",
+				Technology:          types.Database,
+				Encryption:          types.NoneEncryption,
+				Confidentiality:     types.Internal,
+				Tags:                []string{"aws:rds"},
+				DataAssetsProcessed: []string{"customer-data"},
+				DataAssetsStored:    []string{"customer-data"},
+			},
+		},
+		DataAssets: map[string]types.DataAsset{
+			"customer-data": {
+				Id:              "customer-data",
+				Title:           "Customer Data",
+				Quantity:        types.Many,
+				Confidentiality: types.StrictlyConfidential,
+			},
+		},
+		CommunicationLinks: map[string]types.CommunicationLink{link.Id: link},
+		TrustBoundaries: map[string]types.TrustBoundary{
+			"cloud": {
+				Id:                    "cloud",
+				Title:                 "Cloud",
+				Type:                  types.NetworkCloudProvider,
+				TechnicalAssetsInside: []string{"database"},
+			},
+		},
+		IncomingTechnicalCommunicationLinksMappedByTargetId: map[string][]types.CommunicationLink{"database": {link}},
+	}
+	parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId = map[string]types.TrustBoundary{
+		"database": parsedModel.TrustBoundaries["cloud"],
+	}
+
+	return parsedModel
+}
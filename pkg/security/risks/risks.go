@@ -14,6 +14,7 @@ type RiskRule interface {
 func GetBuiltInRiskRules() []RiskRule {
 	return []RiskRule{
 		builtin.NewAccidentalSecretLeakRule(),
+		builtin.NewAttackPathRule(),
 		builtin.NewCodeBackdooringRule(),
 		builtin.NewContainerBaseImageBackdooringRule(),
 		builtin.NewContainerPlatformEscapeRule(),
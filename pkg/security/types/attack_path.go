@@ -0,0 +1,27 @@
+package types
+
+import "strings"
+
+// AttackPath is a chain of communication links from an internet-facing technical asset to a technical asset holding a
+// highly sensitive data asset, see package attackpath
+type AttackPath struct {
+	DataAssetId          string                     `json:"data_asset" yaml:"data_asset"`
+	TechnicalAssetIds    []string                   `json:"technical_assets" yaml:"technical_assets"` // from the internet-facing entry to the target
+	CommunicationLinkIds []string                   `json:"communication_links" yaml:"communication_links"`
+	Cost                 float64                    `json:"cost" yaml:"cost"`
+	Likelihood           RiskExploitationLikelihood `json:"likelihood" yaml:"likelihood"`
+	Impact               RiskExploitationImpact     `json:"impact" yaml:"impact"`
+}
+
+func (what AttackPath) EntryId() string {
+	return what.TechnicalAssetIds[0]
+}
+
+func (what AttackPath) TargetId() string {
+	return what.TechnicalAssetIds[len(what.TechnicalAssetIds)-1]
+}
+
+// Key identifies the path by its data asset and the chain of technical assets
+func (what AttackPath) Key() string {
+	return what.DataAssetId + "@" + strings.Join(what.TechnicalAssetIds, ">")
+}
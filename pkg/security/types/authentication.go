@@ -57,7 +57,6 @@ func ParseAuthentication(value string) (authentication Authentication, err error
 }
 
 func (what Authentication) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	//return [...]string{"none", "credentials", "session-id", "token", "client-certificate", "two-factor", "externalized"}[what]
 	return AuthenticationTypeDescription[what].Name
 }
@@ -51,7 +51,6 @@ var ConfidentialityTypeDescription = [...]TypeDescription{
 }
 
 func (what Confidentiality) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return ConfidentialityTypeDescription[what].Name
 }
 
@@ -49,7 +49,6 @@ func ParseDataBreachProbability(value string) (dataBreachProbability DataBreachP
 }
 
 func (what DataBreachProbability) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return DataBreachProbabilityTypeDescription[what].Name
 }
 
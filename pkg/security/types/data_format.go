@@ -51,7 +51,6 @@ func ParseDataFormat(value string) (dataFormat DataFormat, err error) {
 }
 
 func (what DataFormat) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return DataFormatTypeDescription[what].Name
 }
 
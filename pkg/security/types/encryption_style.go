@@ -51,7 +51,6 @@ var EncryptionStyleTypeDescription = [...]TypeDescription{
 }
 
 func (what EncryptionStyle) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return EncryptionStyleTypeDescription[what].Name
 }
 
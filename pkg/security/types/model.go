@@ -11,6 +11,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/threagile/threagile/pkg/input"
 )
@@ -56,6 +57,8 @@ type ParsedModel struct {
 	DirectContainingTrustBoundaryMappedByTechnicalAssetId map[string]TrustBoundary       `json:"direct_containing_trust_boundary_mapped_by_technical_asset_id,omitempty" yaml:"direct_containing_trust_boundary_mapped_by_technical_asset_id,omitempty"`
 	GeneratedRisksByCategory                              map[string][]Risk              `json:"generated_risks_by_category,omitempty" yaml:"generated_risks_by_category,omitempty"`
 	GeneratedRisksBySyntheticId                           map[string]Risk                `json:"generated_risks_by_synthetic_id,omitempty" yaml:"generated_risks_by_synthetic_id,omitempty"`
+	AttackPaths                                           []AttackPath                   `json:"attack_paths,omitempty" yaml:"attack_paths,omitempty"`
+	MaxAttackPaths                                        int                            `json:"max_attack_paths,omitempty" yaml:"max_attack_paths,omitempty"` // per sensitive data asset
 }
 
 func (parsedModel *ParsedModel) AddToListOfSupportedTags(tags []string) {
@@ -82,6 +85,26 @@ func (parsedModel *ParsedModel) HasNotYetAnyDirectNonWildcardRiskTracking(synthe
 	return true
 }
 
+// MatchingSyntheticRiskIds returns the sorted ids of the generated risks matching the synthetic risk id pattern, where
+// each "*" matches one part of the id between the "@" separators, a pattern without "*" only matches the very same id
+func (parsedModel *ParsedModel) MatchingSyntheticRiskIds(syntheticRiskIdPattern string) []string {
+	syntheticRiskIds := make([]string, 0)
+	if !strings.Contains(syntheticRiskIdPattern, "*") {
+		if _, ok := parsedModel.GeneratedRisksBySyntheticId[syntheticRiskIdPattern]; ok {
+			syntheticRiskIds = append(syntheticRiskIds, syntheticRiskIdPattern)
+		}
+		return syntheticRiskIds
+	}
+	var matchingRiskIdExpression = regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(syntheticRiskIdPattern), `\*`, `[^@]+`) + "$")
+	for syntheticRiskId := range parsedModel.GeneratedRisksBySyntheticId {
+		if matchingRiskIdExpression.MatchString(syntheticRiskId) {
+			syntheticRiskIds = append(syntheticRiskIds, syntheticRiskId)
+		}
+	}
+	sort.Strings(syntheticRiskIds)
+	return syntheticRiskIds
+}
+
 func (parsedModel *ParsedModel) CheckTags(tags []string, where string) ([]string, error) {
 	var tagsUsed = make([]string, 0)
 	if tags != nil {
@@ -104,17 +127,19 @@ func (parsedModel *ParsedModel) ApplyWildcardRiskTrackingEvaluation(ignoreOrphan
 		progressReporter.Info("Applying wildcard risk tracking for risk id: " + syntheticRiskIdPattern)
 
 		foundSome := false
-		var matchingRiskIdExpression = regexp.MustCompile(strings.ReplaceAll(regexp.QuoteMeta(syntheticRiskIdPattern), `\*`, `[^@]+`))
-		for syntheticRiskId := range parsedModel.GeneratedRisksBySyntheticId {
-			if matchingRiskIdExpression.Match([]byte(syntheticRiskId)) && parsedModel.HasNotYetAnyDirectNonWildcardRiskTracking(syntheticRiskId) {
+		for _, syntheticRiskId := range parsedModel.MatchingSyntheticRiskIds(syntheticRiskIdPattern) {
+			if parsedModel.HasNotYetAnyDirectNonWildcardRiskTracking(syntheticRiskId) {
 				foundSome = true
 				parsedModel.RiskTracking[syntheticRiskId] = RiskTracking{
 					SyntheticRiskId: strings.TrimSpace(syntheticRiskId),
 					Justification:   riskTracking.Justification,
 					CheckedBy:       riskTracking.CheckedBy,
 					Ticket:          riskTracking.Ticket,
+					Owner:           riskTracking.Owner,
 					Status:          riskTracking.Status,
 					Date:            riskTracking.Date,
+					Expires:         riskTracking.Expires,
+					ReviewBy:        riskTracking.ReviewBy,
 				}
 			}
 		}
@@ -130,6 +155,21 @@ func (parsedModel *ParsedModel) ApplyWildcardRiskTrackingEvaluation(ignoreOrphan
 	return nil
 }
 
+// applyRiskTrackingExpiry reverts accepted risks whose tracking expired before the given day to unchecked, so that
+// they count as still at risk again (an expiry date has no effect on risks in any other status, like mitigated ones)
+func (parsedModel *ParsedModel) applyRiskTrackingExpiry(today time.Time, progressReporter progressReporter) {
+	for syntheticRiskId, tracking := range parsedModel.RiskTracking {
+		if tracking.Expired || tracking.Status != Accepted || !tracking.HasExpired(today) {
+			continue
+		}
+		progressReporter.Info("Risk tracking expired on " + tracking.Expires.Format("2006-01-02") + " (was " + tracking.Status.String() + "): " + syntheticRiskId)
+		tracking.Expired = true
+		tracking.ExpiredStatus = tracking.Status
+		tracking.Status = Unchecked
+		parsedModel.RiskTracking[syntheticRiskId] = tracking
+	}
+}
+
 func (parsedModel *ParsedModel) CheckRiskTracking(ignoreOrphanedRiskTracking bool, progressReporter progressReporter) error {
 	progressReporter.Info("Checking risk tracking")
 	for _, tracking := range parsedModel.RiskTracking {
@@ -150,6 +190,8 @@ func (parsedModel *ParsedModel) CheckRiskTracking(ignoreOrphanedRiskTracking boo
 		}
 	}
 
+	parsedModel.applyRiskTrackingExpiry(Today(), progressReporter)
+
 	// save also the risk-category-id and risk-status directly in the risk for better JSON marshalling
 	for category := range parsedModel.GeneratedRisksByCategory {
 		for i := range parsedModel.GeneratedRisksByCategory[category] {
@@ -177,7 +177,6 @@ func ParseProtocol(value string) (protocol Protocol, err error) {
 }
 
 func (what Protocol) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return ProtocolTypeDescription[what].Name
 }
 
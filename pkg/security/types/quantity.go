@@ -48,7 +48,6 @@ var QuantityTypeDescription = [...]TypeDescription{
 }
 
 func (what Quantity) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return QuantityTypeDescription[what].Name
 }
 
@@ -1,10 +1,106 @@
 package types
 
+import (
+	"sort"
+	"time"
+)
+
 type RiskTracking struct {
 	SyntheticRiskId string     `json:"synthetic_risk_id,omitempty" yaml:"synthetic_risk_id,omitempty"`
 	Justification   string     `json:"justification,omitempty" yaml:"justification,omitempty"`
 	Ticket          string     `json:"ticket,omitempty" yaml:"ticket,omitempty"`
 	CheckedBy       string     `json:"checked_by,omitempty" yaml:"checked_by,omitempty"`
+	Owner           string     `json:"owner,omitempty" yaml:"owner,omitempty"`
 	Status          RiskStatus `json:"status,omitempty" yaml:"status,omitempty"`
 	Date            Date       `json:"date,omitempty" yaml:"date,omitempty"`
+	Expires         Date       `json:"expires,omitempty" yaml:"expires,omitempty"`
+	ReviewBy        Date       `json:"review_by,omitempty" yaml:"review_by,omitempty"`
+
+	// set when the tracking of an accepted risk has expired: the status was reverted to unchecked, the original one is kept here
+	Expired       bool       `json:"expired,omitempty" yaml:"expired,omitempty"`
+	ExpiredStatus RiskStatus `json:"expired_status,omitempty" yaml:"expired_status,omitempty"`
+}
+
+// HasExpired tells whether the expiry date of the tracking lies before the given day
+func (what RiskTracking) HasExpired(today time.Time) bool {
+	return !what.Expires.IsZero() && what.Expires.Before(today)
+}
+
+// IsReviewOverdue tells whether the review date of the tracking lies before the given day
+func (what RiskTracking) IsReviewOverdue(today time.Time) bool {
+	return !what.ReviewBy.IsZero() && what.ReviewBy.Before(today)
+}
+
+type OverdueRiskReview struct {
+	SyntheticRiskId string       `json:"synthetic_risk_id" yaml:"synthetic_risk_id"`
+	Title           string       `json:"title" yaml:"title"`
+	Severity        RiskSeverity `json:"severity" yaml:"severity"`
+	Status          RiskStatus   `json:"status" yaml:"status"`
+	ExpiredStatus   RiskStatus   `json:"expired_status,omitempty" yaml:"expired_status,omitempty"`
+	Owner           string       `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Expired         bool         `json:"expired" yaml:"expired"`
+	DueDate         Date         `json:"due_date" yaml:"due_date"`
+}
+
+// Reason explains why the review is overdue
+func (what OverdueRiskReview) Reason() string {
+	if what.Expired {
+		return "Risk tracking (" + what.ExpiredStatus.Title() + ") expired"
+	}
+	return "Review overdue"
+}
+
+// Today returns the current day in the same form as the dates parsed from the model
+func Today() time.Time {
+	year, month, day := time.Now().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// OverdueRiskReviews lists the risks with an expired risk tracking or a review date before the given day,
+// the longest overdue ones first
+func OverdueRiskReviews(parsedModel *ParsedModel, today time.Time) []OverdueRiskReview {
+	result := make([]OverdueRiskReview, 0)
+	for syntheticRiskId, tracking := range parsedModel.RiskTracking {
+		risk, ok := parsedModel.GeneratedRisksBySyntheticId[syntheticRiskId]
+		if !ok {
+			continue
+		}
+		review := OverdueRiskReview{
+			SyntheticRiskId: risk.SyntheticId,
+			Title:           risk.Title,
+			Severity:        risk.Severity,
+			Status:          tracking.Status,
+			Owner:           tracking.Owner,
+		}
+		switch {
+		case tracking.Expired:
+			review.Expired = true
+			review.ExpiredStatus = tracking.ExpiredStatus
+			review.DueDate = tracking.Expires
+		case tracking.IsReviewOverdue(today):
+			review.DueDate = tracking.ReviewBy
+		default:
+			continue
+		}
+		result = append(result, review)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DueDate.Equal(result[j].DueDate.Time) {
+			return result[i].DueDate.Before(result[j].DueDate.Time)
+		}
+		return result[i].SyntheticRiskId < result[j].SyntheticRiskId
+	})
+	return result
+}
+
+// ExpiredRiskTrackings lists the synthetic risk IDs of all risk trackings reverted to unchecked due to their expiry
+func ExpiredRiskTrackings(parsedModel *ParsedModel) []string {
+	result := make([]string, 0)
+	for syntheticRiskId, tracking := range parsedModel.RiskTracking {
+		if _, ok := parsedModel.GeneratedRisksBySyntheticId[syntheticRiskId]; ok && tracking.Expired {
+			result = append(result, syntheticRiskId)
+		}
+	}
+	sort.Strings(result)
+	return result
 }
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nullProgressReporter struct{}
+
+func (nullProgressReporter) Info(a ...any)  {}
+func (nullProgressReporter) Warn(a ...any)  {}
+func (nullProgressReporter) Error(a ...any) {}
+
+func TestRiskTrackingExpiryAndOverdueReviews(t *testing.T) {
+	day := func(value string) Date {
+		date, _ := time.Parse("2006-01-02", value)
+		return Date{Time: date}
+	}
+	parsedModel := &ParsedModel{
+		GeneratedRisksBySyntheticId: map[string]Risk{
+			"some-category@a": {SyntheticId: "some-category@a", Severity: HighSeverity},
+			"some-category@b": {SyntheticId: "some-category@b", Severity: MediumSeverity},
+			"some-category@c": {SyntheticId: "some-category@c", Severity: LowSeverity},
+			"some-category@d": {SyntheticId: "some-category@d", Severity: LowSeverity},
+			"some-category@e": {SyntheticId: "some-category@e", Severity: LowSeverity},
+		},
+		RiskTracking: map[string]RiskTracking{
+			"some-category@a": {SyntheticRiskId: "some-category@a", Status: Accepted, Expires: day("2024-03-01"), Owner: "Jane"},
+			"some-category@b": {SyntheticRiskId: "some-category@b", Status: Accepted, Expires: day("2024-03-02"), ReviewBy: day("2024-02-01")},
+			"some-category@c": {SyntheticRiskId: "some-category@c", Status: Mitigated, ReviewBy: day("2024-03-02")},
+			"some-category@d": {SyntheticRiskId: "some-category@d", Status: Mitigated, Expires: day("2024-03-01")},
+			"some-category@e": {SyntheticRiskId: "some-category@e", Status: FalsePositive, Expires: day("2024-03-01")},
+		},
+	}
+
+	today := day("2024-03-02").Time
+	parsedModel.applyRiskTrackingExpiry(today, nullProgressReporter{})
+
+	expired := parsedModel.RiskTracking["some-category@a"]
+	assert.True(t, expired.Expired)
+	assert.Equal(t, Unchecked, expired.Status)
+	assert.Equal(t, Accepted, expired.ExpiredStatus)
+	assert.False(t, parsedModel.RiskTracking["some-category@b"].Expired) // expires at the end of the day
+	assert.False(t, parsedModel.RiskTracking["some-category@d"].Expired) // only accepted risks expire
+	assert.Equal(t, Mitigated, parsedModel.RiskTracking["some-category@d"].Status)
+	assert.False(t, parsedModel.RiskTracking["some-category@e"].Expired)
+	assert.Equal(t, FalsePositive, parsedModel.RiskTracking["some-category@e"].Status)
+	assert.Equal(t, []string{"some-category@a"}, ExpiredRiskTrackings(parsedModel))
+
+	reviews := OverdueRiskReviews(parsedModel, today)
+	assert.Len(t, reviews, 2)
+	assert.Equal(t, "some-category@b", reviews[0].SyntheticRiskId)
+	assert.False(t, reviews[0].Expired)
+	assert.Equal(t, "some-category@a", reviews[1].SyntheticRiskId)
+	assert.Equal(t, "Jane", reviews[1].Owner)
+	assert.Equal(t, "Risk tracking (Accepted) expired", reviews[1].Reason())
+}
+
+func TestMatchingSyntheticRiskIds(t *testing.T) {
+	parsedModel := &ParsedModel{
+		GeneratedRisksBySyntheticId: map[string]Risk{
+			"some-category@b":       {},
+			"some-category@a":       {},
+			"some-category@a@link":  {},
+			"other-category@a":      {},
+			"other-category@a@link": {},
+		},
+	}
+
+	assert.Equal(t, []string{"some-category@a", "some-category@b"}, parsedModel.MatchingSyntheticRiskIds("some-category@*"))
+	assert.Equal(t, []string{"some-category@a@link"}, parsedModel.MatchingSyntheticRiskIds("some-category@*@*"))
+	assert.Equal(t, []string{"other-category@a@link", "some-category@a@link"}, parsedModel.MatchingSyntheticRiskIds("*@a@link"))
+	assert.Empty(t, parsedModel.MatchingSyntheticRiskIds("*@a@lin"))
+	assert.Empty(t, parsedModel.MatchingSyntheticRiskIds("unknown@*"))
+
+	assert.Equal(t, []string{"some-category@a"}, parsedModel.MatchingSyntheticRiskIds("some-category@a"))
+	assert.Empty(t, parsedModel.MatchingSyntheticRiskIds("some-category@")) // a prefix only
+	assert.Empty(t, parsedModel.MatchingSyntheticRiskIds("category@a"))     // a suffix only
+	assert.Empty(t, parsedModel.MatchingSyntheticRiskIds("some-category@a.link"))
+}
@@ -54,7 +54,6 @@ func ParseRiskSeverity(value string) (riskSeverity RiskSeverity, err error) {
 }
 
 func (what RiskSeverity) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return RiskSeverityTypeDescription[what].Name
 }
 
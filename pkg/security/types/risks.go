@@ -122,7 +122,8 @@ func SortByRiskCategoryHighestContainingRiskSeveritySortStillAtRisk(parsedModel
 
 type RiskStatistics struct {
 	// TODO add also some more like before / after (i.e. with mitigation applied)
-	Risks map[string]map[string]int `yaml:"risks" json:"risks"`
+	Risks              map[string]map[string]int `yaml:"risks" json:"risks"`
+	OverdueRiskReviews []OverdueRiskReview       `yaml:"overdue_risk_reviews" json:"overdue_risk_reviews"`
 }
 
 func SortByRiskSeverity(risks []Risk, parsedModel *ParsedModel) {
@@ -781,46 +782,51 @@ func FilteredByStillAtRisk(parsedModel *ParsedModel) []Risk {
 
 func OverallRiskStatistics(parsedModel *ParsedModel) RiskStatistics {
 	result := RiskStatistics{}
-	result.Risks = make(map[string]map[string]int)
-	result.Risks[CriticalSeverity.String()] = make(map[string]int)
-	result.Risks[CriticalSeverity.String()][Unchecked.String()] = 0
-	result.Risks[CriticalSeverity.String()][InDiscussion.String()] = 0
-	result.Risks[CriticalSeverity.String()][Accepted.String()] = 0
-	result.Risks[CriticalSeverity.String()][InProgress.String()] = 0
-	result.Risks[CriticalSeverity.String()][Mitigated.String()] = 0
-	result.Risks[CriticalSeverity.String()][FalsePositive.String()] = 0
-	result.Risks[HighSeverity.String()] = make(map[string]int)
-	result.Risks[HighSeverity.String()][Unchecked.String()] = 0
-	result.Risks[HighSeverity.String()][InDiscussion.String()] = 0
-	result.Risks[HighSeverity.String()][Accepted.String()] = 0
-	result.Risks[HighSeverity.String()][InProgress.String()] = 0
-	result.Risks[HighSeverity.String()][Mitigated.String()] = 0
-	result.Risks[HighSeverity.String()][FalsePositive.String()] = 0
-	result.Risks[ElevatedSeverity.String()] = make(map[string]int)
-	result.Risks[ElevatedSeverity.String()][Unchecked.String()] = 0
-	result.Risks[ElevatedSeverity.String()][InDiscussion.String()] = 0
-	result.Risks[ElevatedSeverity.String()][Accepted.String()] = 0
-	result.Risks[ElevatedSeverity.String()][InProgress.String()] = 0
-	result.Risks[ElevatedSeverity.String()][Mitigated.String()] = 0
-	result.Risks[ElevatedSeverity.String()][FalsePositive.String()] = 0
-	result.Risks[MediumSeverity.String()] = make(map[string]int)
-	result.Risks[MediumSeverity.String()][Unchecked.String()] = 0
-	result.Risks[MediumSeverity.String()][InDiscussion.String()] = 0
-	result.Risks[MediumSeverity.String()][Accepted.String()] = 0
-	result.Risks[MediumSeverity.String()][InProgress.String()] = 0
-	result.Risks[MediumSeverity.String()][Mitigated.String()] = 0
-	result.Risks[MediumSeverity.String()][FalsePositive.String()] = 0
-	result.Risks[LowSeverity.String()] = make(map[string]int)
-	result.Risks[LowSeverity.String()][Unchecked.String()] = 0
-	result.Risks[LowSeverity.String()][InDiscussion.String()] = 0
-	result.Risks[LowSeverity.String()][Accepted.String()] = 0
-	result.Risks[LowSeverity.String()][InProgress.String()] = 0
-	result.Risks[LowSeverity.String()][Mitigated.String()] = 0
-	result.Risks[LowSeverity.String()][FalsePositive.String()] = 0
-	for _, risks := range parsedModel.GeneratedRisksByCategory {
-		for _, risk := range risks {
-			result.Risks[risk.Severity.String()][risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel).String()]++
-		}
-	}
+	result.Risks = CountRisksBySeverityAndStatus(parsedModel, AllRisks(parsedModel))
+	result.OverdueRiskReviews = OverdueRiskReviews(parsedModel, Today())
 	return result
 }
+
+// CountRisksBySeverityAndStatus counts the given risks by their severity and tracking status (defaulting to unchecked), including zero counts
+func CountRisksBySeverityAndStatus(parsedModel *ParsedModel, risks []Risk) map[string]map[string]int {
+	counts := make(map[string]map[string]int)
+	counts[CriticalSeverity.String()] = make(map[string]int)
+	counts[CriticalSeverity.String()][Unchecked.String()] = 0
+	counts[CriticalSeverity.String()][InDiscussion.String()] = 0
+	counts[CriticalSeverity.String()][Accepted.String()] = 0
+	counts[CriticalSeverity.String()][InProgress.String()] = 0
+	counts[CriticalSeverity.String()][Mitigated.String()] = 0
+	counts[CriticalSeverity.String()][FalsePositive.String()] = 0
+	counts[HighSeverity.String()] = make(map[string]int)
+	counts[HighSeverity.String()][Unchecked.String()] = 0
+	counts[HighSeverity.String()][InDiscussion.String()] = 0
+	counts[HighSeverity.String()][Accepted.String()] = 0
+	counts[HighSeverity.String()][InProgress.String()] = 0
+	counts[HighSeverity.String()][Mitigated.String()] = 0
+	counts[HighSeverity.String()][FalsePositive.String()] = 0
+	counts[ElevatedSeverity.String()] = make(map[string]int)
+	counts[ElevatedSeverity.String()][Unchecked.String()] = 0
+	counts[ElevatedSeverity.String()][InDiscussion.String()] = 0
+	counts[ElevatedSeverity.String()][Accepted.String()] = 0
+	counts[ElevatedSeverity.String()][InProgress.String()] = 0
+	counts[ElevatedSeverity.String()][Mitigated.String()] = 0
+	counts[ElevatedSeverity.String()][FalsePositive.String()] = 0
+	counts[MediumSeverity.String()] = make(map[string]int)
+	counts[MediumSeverity.String()][Unchecked.String()] = 0
+	counts[MediumSeverity.String()][InDiscussion.String()] = 0
+	counts[MediumSeverity.String()][Accepted.String()] = 0
+	counts[MediumSeverity.String()][InProgress.String()] = 0
+	counts[MediumSeverity.String()][Mitigated.String()] = 0
+	counts[MediumSeverity.String()][FalsePositive.String()] = 0
+	counts[LowSeverity.String()] = make(map[string]int)
+	counts[LowSeverity.String()][Unchecked.String()] = 0
+	counts[LowSeverity.String()][InDiscussion.String()] = 0
+	counts[LowSeverity.String()][Accepted.String()] = 0
+	counts[LowSeverity.String()][InProgress.String()] = 0
+	counts[LowSeverity.String()][Mitigated.String()] = 0
+	counts[LowSeverity.String()][FalsePositive.String()] = 0
+	for _, risk := range risks {
+		counts[risk.Severity.String()][risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel).String()]++
+	}
+	return counts
+}
@@ -17,6 +17,8 @@ type TechnicalAsset struct {
 	Type                    TechnicalAssetType       `json:"type,omitempty" yaml:"type,omitempty"`
 	Size                    TechnicalAssetSize       `json:"size,omitempty" yaml:"size,omitempty"`
 	Technology              TechnicalAssetTechnology `json:"technology,omitempty" yaml:"technology,omitempty"`
+	CustomTechnology        string                   `json:"custom_technology,omitempty" yaml:"custom_technology,omitempty"`
+	TechnologyTraits        map[string]bool          `json:"technology_traits,omitempty" yaml:"technology_traits,omitempty"`
 	Machine                 TechnicalAssetMachine    `json:"machine,omitempty" yaml:"machine,omitempty"`
 	Internet                bool                     `json:"internet,omitempty" yaml:"internet,omitempty"`
 	MultiTenant             bool                     `json:"multi_tenant,omitempty" yaml:"multi_tenant,omitempty"`
@@ -41,6 +43,23 @@ type TechnicalAsset struct {
 	RAA float64 `json:"raa,omitempty" yaml:"raa,omitempty"`
 }
 
+// HasTrait tells whether the technology of the asset has a trait, as declared for a custom technology (see
+// CustomTechnology) or else by default for the built-in one
+func (what TechnicalAsset) HasTrait(trait TechnologyTrait) bool {
+	if value, found := what.TechnologyTraits[trait.String()]; found {
+		return value
+	}
+	return what.Technology.Has(trait)
+}
+
+// TechnologyName is the name of the custom technology of the asset, if any, or else of the built-in one
+func (what TechnicalAsset) TechnologyName() string {
+	if len(what.CustomTechnology) > 0 {
+		return what.CustomTechnology
+	}
+	return what.Technology.String()
+}
+
 func (what TechnicalAsset) IsTaggedWithAny(tags ...string) bool {
 	return containsCaseInsensitiveAny(what.Tags, tags...)
 }
@@ -38,7 +38,6 @@ var TechnicalAssetSizeDescription = [...]TypeDescription{
 }
 
 func (what TechnicalAssetSize) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return TechnicalAssetSizeDescription[what].Name
 }
 
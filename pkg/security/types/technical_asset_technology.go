@@ -197,7 +197,6 @@ var TechnicalAssetTechnologyTypeDescription = [...]TypeDescription{
 }
 
 func (what TechnicalAssetTechnology) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return TechnicalAssetTechnologyTypeDescription[what].Name
 }
 
@@ -294,6 +293,47 @@ func (what TechnicalAssetTechnology) IsEmbeddedComponent() bool {
 	return what == Library
 }
 
+// Has tells whether the technology has a trait by default, see TechnicalAsset.HasTrait for declared technologies
+func (what TechnicalAssetTechnology) Has(trait TechnologyTrait) bool {
+	switch trait {
+	case WebApplicationTrait:
+		return what.IsWebApplication()
+	case WebServiceTrait:
+		return what.IsWebService()
+	case IdentityRelatedTrait:
+		return what.IsIdentityRelated()
+	case SecurityControlRelatedTrait:
+		return what.IsSecurityControlRelated()
+	case UnprotectedCommunicationsToleratedTrait:
+		return what.IsUnprotectedCommunicationsTolerated()
+	case UnnecessaryDataToleratedTrait:
+		return what.IsUnnecessaryDataTolerated()
+	case CloseToHighValueTargetsToleratedTrait:
+		return what.IsCloseToHighValueTargetsTolerated()
+	case ClientTrait:
+		return what.IsClient()
+	case UsuallyAbleToPropagateIdentityToOutgoingTargetsTrait:
+		return what.IsUsuallyAbleToPropagateIdentityToOutgoingTargets()
+	case LessProtectedTypeTrait:
+		return what.IsLessProtectedType()
+	case UsuallyProcessingEndUserRequestsTrait:
+		return what.IsUsuallyProcessingEndUserRequests()
+	case UsuallyStoringEndUserDataTrait:
+		return what.IsUsuallyStoringEndUserData()
+	case ExclusivelyFrontendRelatedTrait:
+		return what.IsExclusivelyFrontendRelated()
+	case ExclusivelyBackendRelatedTrait:
+		return what.IsExclusivelyBackendRelated()
+	case DevelopmentRelevantTrait:
+		return what.IsDevelopmentRelevant()
+	case TrafficForwardingTrait:
+		return what.IsTrafficForwarding()
+	case EmbeddedComponentTrait:
+		return what.IsEmbeddedComponent()
+	}
+	return false
+}
+
 func (what TechnicalAssetTechnology) MarshalJSON() ([]byte, error) {
 	return json.Marshal(what.String())
 }
@@ -35,7 +35,6 @@ var TechnicalAssetTypeDescription = [...]TypeDescription{
 }
 
 func (what TechnicalAssetType) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return TechnicalAssetTypeDescription[what].Name
 }
 
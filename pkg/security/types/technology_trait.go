@@ -0,0 +1,139 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+type TechnologyTrait int
+
+const (
+	WebApplicationTrait TechnologyTrait = iota
+	WebServiceTrait
+	IdentityRelatedTrait
+	SecurityControlRelatedTrait
+	UnprotectedCommunicationsToleratedTrait
+	UnnecessaryDataToleratedTrait
+	CloseToHighValueTargetsToleratedTrait
+	ClientTrait
+	UsuallyAbleToPropagateIdentityToOutgoingTargetsTrait
+	LessProtectedTypeTrait
+	UsuallyProcessingEndUserRequestsTrait
+	UsuallyStoringEndUserDataTrait
+	ExclusivelyFrontendRelatedTrait
+	ExclusivelyBackendRelatedTrait
+	DevelopmentRelevantTrait
+	TrafficForwardingTrait
+	EmbeddedComponentTrait
+)
+
+func TechnologyTraitValues() []TypeEnum {
+	return []TypeEnum{
+		WebApplicationTrait,
+		WebServiceTrait,
+		IdentityRelatedTrait,
+		SecurityControlRelatedTrait,
+		UnprotectedCommunicationsToleratedTrait,
+		UnnecessaryDataToleratedTrait,
+		CloseToHighValueTargetsToleratedTrait,
+		ClientTrait,
+		UsuallyAbleToPropagateIdentityToOutgoingTargetsTrait,
+		LessProtectedTypeTrait,
+		UsuallyProcessingEndUserRequestsTrait,
+		UsuallyStoringEndUserDataTrait,
+		ExclusivelyFrontendRelatedTrait,
+		ExclusivelyBackendRelatedTrait,
+		DevelopmentRelevantTrait,
+		TrafficForwardingTrait,
+		EmbeddedComponentTrait,
+	}
+}
+
+func ParseTechnologyTrait(value string) (technologyTrait TechnologyTrait, err error) {
+	value = strings.TrimSpace(value)
+	for _, candidate := range TechnologyTraitValues() {
+		if candidate.String() == value {
+			return candidate.(TechnologyTrait), err
+		}
+	}
+	return technologyTrait, errors.New("Unable to parse into type: " + value)
+}
+
+var TechnologyTraitTypeDescription = [...]TypeDescription{
+	{"web-application", "Web application, like a web server or CMS, checked for web related risks like XSS"},
+	{"web-service", "Web service, like a REST or SOAP service"},
+	{"identity-related", "Identity provider or store, a high-value target"},
+	{"security-control-related", "Security control, like a vault, HSM, WAF or IDS"},
+	{"unprotected-communications-tolerated", "Unencrypted communication is tolerated, like for monitoring"},
+	{"unnecessary-data-tolerated", "Receiving data without processing it is tolerated, like for monitoring"},
+	{"close-to-high-value-targets-tolerated", "Being close to high-value targets is tolerated, like for load balancers"},
+	{"client", "Client, like a browser, desktop or mobile app"},
+	{"usually-able-to-propagate-identity-to-outgoing-targets", "Usually able to propagate the identity of the caller to the targets it calls"},
+	{"less-protected-type", "Usually less protected, thus a likely starting point of attacks"},
+	{"usually-processing-end-user-requests", "Usually processing requests of end users"},
+	{"usually-storing-end-user-data", "Usually storing data of end users, like a database"},
+	{"exclusively-frontend-related", "Only used in the frontend, like a reverse proxy"},
+	{"exclusively-backend-related", "Only used in the backend, like a database or message queue"},
+	{"development-relevant", "Part of the development infrastructure, like a build pipeline"},
+	{"traffic-forwarding", "Forwarding traffic to other assets, like a load balancer"},
+	{"embedded-component", "Embedded into other assets, like a library"},
+}
+
+func (what TechnologyTrait) String() string {
+	return TechnologyTraitTypeDescription[what].Name
+}
+
+func (what TechnologyTrait) Explain() string {
+	return TechnologyTraitTypeDescription[what].Description
+}
+
+func (what TechnologyTrait) MarshalJSON() ([]byte, error) {
+	return json.Marshal(what.String())
+}
+
+func (what *TechnologyTrait) UnmarshalJSON(data []byte) error {
+	var text string
+	unmarshalError := json.Unmarshal(data, &text)
+	if unmarshalError != nil {
+		return unmarshalError
+	}
+
+	value, findError := what.find(text)
+	if findError != nil {
+		return findError
+	}
+
+	*what = value
+	return nil
+}
+
+func (what TechnologyTrait) MarshalYAML() (interface{}, error) {
+	return what.String(), nil
+}
+
+func (what *TechnologyTrait) UnmarshalYAML(node *yaml.Node) error {
+	value, findError := what.find(node.Value)
+	if findError != nil {
+		return findError
+	}
+
+	*what = value
+	return nil
+}
+
+func (what TechnologyTrait) find(value string) (TechnologyTrait, error) {
+	for index, description := range TechnologyTraitTypeDescription {
+		if strings.EqualFold(value, description.Name) {
+			return TechnologyTrait(index), nil
+		}
+	}
+
+	return TechnologyTrait(0), fmt.Errorf("unknown technology trait value %q", value)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ParseTechnologyTraitTest struct {
+	input         string
+	expected      TechnologyTrait
+	expectedError error
+}
+
+func TestParseTechnologyTrait(t *testing.T) {
+	testCases := map[string]ParseTechnologyTraitTest{
+		"web-application": {
+			input:    "web-application",
+			expected: WebApplicationTrait,
+		},
+		"client": {
+			input:    "client",
+			expected: ClientTrait,
+		},
+		"traffic-forwarding": {
+			input:    "traffic-forwarding",
+			expected: TrafficForwardingTrait,
+		},
+		"embedded-component": {
+			input:    "embedded-component",
+			expected: EmbeddedComponentTrait,
+		},
+		"unknown": {
+			input:         "unknown",
+			expectedError: errors.New("Unable to parse into type: unknown"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := ParseTechnologyTrait(testCase.input)
+
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.expectedError, err)
+		})
+	}
+}
+
+func TestTechnicalAssetHasTrait(t *testing.T) {
+	asset := TechnicalAsset{Technology: WebServer}
+	assert.True(t, asset.HasTrait(WebApplicationTrait))
+	assert.False(t, asset.HasTrait(ClientTrait))
+	assert.Equal(t, "web-server", asset.TechnologyName())
+
+	asset.CustomTechnology = "edge-worker"
+	asset.TechnologyTraits = map[string]bool{"web-application": false, "traffic-forwarding": true}
+	assert.False(t, asset.HasTrait(WebApplicationTrait))
+	assert.True(t, asset.HasTrait(TrafficForwardingTrait))
+	assert.True(t, asset.HasTrait(LessProtectedTypeTrait)) // of the web server
+	assert.Equal(t, "edge-worker", asset.TechnologyName())
+}
@@ -56,7 +56,6 @@ func ParseTrustBoundary(value string) (trustBoundary TrustBoundaryType, err erro
 }
 
 func (what TrustBoundaryType) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return TrustBoundaryTypeDescription[what].Name
 }
 
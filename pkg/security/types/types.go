@@ -36,6 +36,7 @@ func GetBuiltinTypeValues() map[string][]TypeEnum {
 		"Technical Asset Size":                         TechnicalAssetSizeValues(),
 		"Technical Asset Technology":                   TechnicalAssetTechnologyValues(),
 		"Technical Asset Type":                         TechnicalAssetTypeValues(),
+		"Technology Trait":                             TechnologyTraitValues(),
 		"Trust Boundary Type":                          TrustBoundaryTypeValues(),
 		"Usage":                                        UsageValues(),
 	}
@@ -0,0 +1,329 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// communication links are a sub-resource of their source technical asset, the "communication-link-id" route parameter
+// is the part of the link id after the ">" (i.e. the normalized title of the link)
+
+type payloadCommunicationLink struct {
+	Title                  string   `yaml:"title" json:"title"`
+	Target                 string   `yaml:"target" json:"target"`
+	Description            string   `yaml:"description" json:"description"`
+	Protocol               string   `yaml:"protocol" json:"protocol"`
+	Authentication         string   `yaml:"authentication" json:"authentication"`
+	Authorization          string   `yaml:"authorization" json:"authorization"`
+	Tags                   []string `yaml:"tags" json:"tags"`
+	VPN                    bool     `yaml:"vpn" json:"vpn"`
+	IpFiltered             bool     `yaml:"ip_filtered" json:"ip_filtered"`
+	Readonly               bool     `yaml:"readonly" json:"readonly"`
+	Usage                  string   `yaml:"usage" json:"usage"`
+	DataAssetsSent         []string `yaml:"data_assets_sent" json:"data_assets_sent"`
+	DataAssetsReceived     []string `yaml:"data_assets_received" json:"data_assets_received"`
+	DiagramTweakWeight     int      `yaml:"diagram_tweak_weight" json:"diagram_tweak_weight"`
+	DiagramTweakConstraint bool     `yaml:"diagram_tweak_constraint" json:"diagram_tweak_constraint"`
+}
+
+func (s *server) getCommunicationLinks(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		techAssetTitle, ok := findTechnicalAsset(ginContext, modelInput)
+		if ok {
+			ginContext.JSON(http.StatusOK, modelInput.TechnicalAssets[techAssetTitle].CommunicationLinks)
+		}
+	}
+}
+
+func (s *server) getCommunicationLink(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		techAssetTitle, ok := findTechnicalAsset(ginContext, modelInput)
+		if !ok {
+			return
+		}
+		techAsset := modelInput.TechnicalAssets[techAssetTitle]
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, commLink := range techAsset.CommunicationLinks {
+			if communicationLinkID(techAsset.ID, title) == communicationLinkID(techAsset.ID, ginContext.Param("communication-link-id")) {
+				ginContext.JSON(http.StatusOK, gin.H{
+					title: commLink,
+				})
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "communication link not found",
+		})
+	}
+}
+
+func (s *server) createNewCommunicationLink(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		techAssetTitle, ok := findTechnicalAsset(ginContext, modelInput)
+		if !ok {
+			return
+		}
+		payload := payloadCommunicationLink{}
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		techAsset := modelInput.TechnicalAssets[techAssetTitle]
+		// the id is derived from the title, so checking the id also covers the title
+		id := communicationLinkID(techAsset.ID, payload.Title)
+		for title := range techAsset.CommunicationLinks {
+			if communicationLinkID(techAsset.ID, title) == id {
+				ginContext.JSON(http.StatusConflict, gin.H{
+					"error": "communication link with this title or id already exists",
+				})
+				return
+			}
+		}
+		commLinkInput, ok := populateCommunicationLink(ginContext, modelInput, payload)
+		if !ok {
+			return
+		}
+		if techAsset.CommunicationLinks == nil {
+			techAsset.CommunicationLinks = make(map[string]input.CommunicationLink)
+		}
+		techAsset.CommunicationLinks[payload.Title] = commLinkInput
+		modelInput.TechnicalAssets[techAssetTitle] = techAsset
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Communication Link Creation")
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "communication link created",
+				"id":      id,
+			})
+		}
+	}
+}
+
+func (s *server) setCommunicationLink(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		techAssetTitle, ok := findTechnicalAsset(ginContext, modelInput)
+		if !ok {
+			return
+		}
+		techAsset := modelInput.TechnicalAssets[techAssetTitle]
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title := range techAsset.CommunicationLinks {
+			oldID := communicationLinkID(techAsset.ID, title)
+			if oldID == communicationLinkID(techAsset.ID, ginContext.Param("communication-link-id")) {
+				payload := payloadCommunicationLink{}
+				err := ginContext.BindJSON(&payload)
+				if err != nil {
+					log.Println(err)
+					ginContext.JSON(http.StatusBadRequest, gin.H{
+						"error": "unable to parse request payload",
+					})
+					return
+				}
+				newID := communicationLinkID(techAsset.ID, payload.Title)
+				for otherTitle := range techAsset.CommunicationLinks {
+					if otherTitle != title && communicationLinkID(techAsset.ID, otherTitle) == newID {
+						ginContext.JSON(http.StatusConflict, gin.H{
+							"error": "communication link with this title or id already exists",
+						})
+						return
+					}
+				}
+				commLinkInput, ok := populateCommunicationLink(ginContext, modelInput, payload)
+				if !ok {
+					return
+				}
+				// in order to also update the title, remove the link from the map and re-insert it (with new key)
+				delete(techAsset.CommunicationLinks, title)
+				techAsset.CommunicationLinks[payload.Title] = commLinkInput
+				idChanged := newID != oldID
+				if idChanged { // ID-CHANGE-PROPAGATION
+					for individualRiskCatTitle, individualRiskCat := range modelInput.IndividualRiskCategories {
+						for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+							if individualRiskInstance.MostRelevantCommunicationLink == oldID { // apply the ID change
+								individualRiskInstance.MostRelevantCommunicationLink = newID
+								modelInput.IndividualRiskCategories[individualRiskCatTitle].RisksIdentified[individualRiskInstanceTitle] = individualRiskInstance
+							}
+						}
+					}
+				}
+				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Communication Link Update")
+				if ok {
+					ginContext.JSON(http.StatusOK, gin.H{
+						"message":    "communication link updated",
+						"id":         newID,
+						"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
+					})
+				}
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "communication link not found",
+		})
+	}
+}
+
+func (s *server) deleteCommunicationLink(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		techAssetTitle, ok := findTechnicalAsset(ginContext, modelInput)
+		if !ok {
+			return
+		}
+		referencesDeleted := false
+		techAsset := modelInput.TechnicalAssets[techAssetTitle]
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title := range techAsset.CommunicationLinks {
+			id := communicationLinkID(techAsset.ID, title)
+			if id == communicationLinkID(techAsset.ID, ginContext.Param("communication-link-id")) {
+				// also remove all usages of this communication link !!
+				for individualRiskCatTitle, individualRiskCat := range modelInput.IndividualRiskCategories {
+					for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+						if individualRiskInstance.MostRelevantCommunicationLink == id { // apply the removal
+							referencesDeleted = true
+							individualRiskInstance.MostRelevantCommunicationLink = ""
+							modelInput.IndividualRiskCategories[individualRiskCatTitle].RisksIdentified[individualRiskInstanceTitle] = individualRiskInstance
+						}
+					}
+				}
+				// remove it itself
+				delete(techAsset.CommunicationLinks, title)
+				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Communication Link Deletion")
+				if ok {
+					ginContext.JSON(http.StatusOK, gin.H{
+						"message":            "communication link deleted",
+						"id":                 id,
+						"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
+					})
+				}
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "communication link not found",
+		})
+	}
+}
+
+func populateCommunicationLink(ginContext *gin.Context, modelInput input.Model, payload payloadCommunicationLink) (commLinkInput input.CommunicationLink, ok bool) {
+	protocol, err := types.ParseProtocol(payload.Protocol)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return commLinkInput, false
+	}
+	authentication, err := types.ParseAuthentication(payload.Authentication)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return commLinkInput, false
+	}
+	authorization, err := types.ParseAuthorization(payload.Authorization)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return commLinkInput, false
+	}
+	usage, err := types.ParseUsage(payload.Usage)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return commLinkInput, false
+	}
+	if !checkTechnicalAssetsExisting(modelInput, []string{payload.Target}) {
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "referenced technical asset does not exist",
+		})
+		return commLinkInput, false
+	}
+	if !checkDataAssetsExisting(modelInput, payload.DataAssetsSent) || !checkDataAssetsExisting(modelInput, payload.DataAssetsReceived) {
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "referenced data asset does not exist",
+		})
+		return commLinkInput, false
+	}
+	commLinkInput = input.CommunicationLink{
+		Target:                 payload.Target,
+		Description:            payload.Description,
+		Protocol:               protocol.String(),
+		Authentication:         authentication.String(),
+		Authorization:          authorization.String(),
+		Tags:                   lowerCaseAndTrim(payload.Tags),
+		VPN:                    payload.VPN,
+		IpFiltered:             payload.IpFiltered,
+		Readonly:               payload.Readonly,
+		Usage:                  usage.String(),
+		DataAssetsSent:         payload.DataAssetsSent,
+		DataAssetsReceived:     payload.DataAssetsReceived,
+		DiagramTweakWeight:     payload.DiagramTweakWeight,
+		DiagramTweakConstraint: payload.DiagramTweakConstraint,
+	}
+	return commLinkInput, true
+}
+
+// findTechnicalAsset returns the title of the technical asset addressed by the "technical-asset-id" route parameter
+func findTechnicalAsset(ginContext *gin.Context, modelInput input.Model) (title string, ok bool) {
+	for title, techAsset := range modelInput.TechnicalAssets {
+		if techAsset.ID == ginContext.Param("technical-asset-id") {
+			return title, true
+		}
+	}
+	ginContext.JSON(http.StatusNotFound, gin.H{
+		"error": "technical asset not found",
+	})
+	return "", false
+}
+
+func communicationLinkID(techAssetID string, title string) string {
+	id, _ := model.CreateDataFlowId(techAssetID, title) // the pattern is constant, so this never fails
+	return id
+}
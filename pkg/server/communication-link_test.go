@@ -0,0 +1,89 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func TestCommunicationLinkCreateUpdateDelete(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createTechnicalAssetTestModel(server, token)
+	modelInput := server.readModel(token, modelID)
+	modelInput.IndividualRiskCategories = map[string]input.IndividualRiskCategory{
+		"Manual Finding": {ID: "manual-finding", RisksIdentified: map[string]input.RiskIdentified{
+			"Backup Leak": {Severity: "medium", MostRelevantCommunicationLink: "web>backup-export"},
+		}},
+	}
+	server.writeModel(token, modelID, &modelInput)
+	path := "/models/" + modelID + "/technical-assets/web/communication-links"
+	communicationLink := func(title string, target string) payloadCommunicationLink {
+		return payloadCommunicationLink{Title: title, Target: target, Protocol: "ssh", Authentication: "credentials", Authorization: "technical-user",
+			Usage: "devops", Tags: []string{" Nightly "}}
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPost, path, communicationLink("Backup Export", "backup"), &created))
+	assert.Equal(t, "web>backup-export", created.ID)
+
+	// conflicts and invalid references are rejected
+	for name, test := range map[string]struct {
+		payload payloadCommunicationLink
+		status  int
+	}{
+		"same title":     {communicationLink("Backup Export", "db"), http.StatusConflict},
+		"same id":        {communicationLink("backup export!", "db"), http.StatusConflict},
+		"unknown target": {communicationLink("Other", "unknown"), http.StatusBadRequest},
+		"unknown data":   {payloadCommunicationLink{Title: "Other", Target: "db", Protocol: "ssh", Authentication: "none", Authorization: "none", Usage: "business", DataAssetsSent: []string{"unknown"}}, http.StatusBadRequest},
+		"unknown value":  {payloadCommunicationLink{Title: "Other", Target: "db", Protocol: "carrier-pigeon", Authentication: "none", Authorization: "none", Usage: "business"}, http.StatusBadRequest},
+	} {
+		assert.Equal(t, test.status, server.withToken(token, http.MethodPost, path, test.payload, nil), name)
+	}
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodPost, "/models/"+modelID+"/technical-assets/unknown/communication-links", communicationLink("Other", "db"), nil))
+
+	var read map[string]input.CommunicationLink
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodGet, path+"/backup-export", nil, &read))
+	assert.Equal(t, "backup", read["Backup Export"].Target)
+	assert.Equal(t, "credentials", read["Backup Export"].Authentication)
+	assert.Equal(t, []string{"nightly"}, read["Backup Export"].Tags)
+	var all map[string]input.CommunicationLink
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodGet, path, nil, &all))
+	assert.Contains(t, all, "db")
+	assert.Contains(t, all, "Backup Export")
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodGet, path+"/unknown", nil, nil))
+
+	// renaming the link updates the individual risks referencing it
+	var updated struct {
+		ID        string `json:"id"`
+		IDChanged bool   `json:"id_changed"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPut, path+"/backup-export", communicationLink("Snapshot Export", "backup"), &updated))
+	assert.Equal(t, "web>snapshot-export", updated.ID)
+	assert.True(t, updated.IDChanged)
+	modelInput = server.readModel(token, modelID)
+	assert.NotContains(t, modelInput.TechnicalAssets["Web Server"].CommunicationLinks, "Backup Export")
+	assert.Equal(t, "backup", modelInput.TechnicalAssets["Web Server"].CommunicationLinks["Snapshot Export"].Target)
+	assert.Equal(t, "web>snapshot-export", modelInput.IndividualRiskCategories["Manual Finding"].RisksIdentified["Backup Leak"].MostRelevantCommunicationLink)
+	assert.Equal(t, http.StatusConflict, server.withToken(token, http.MethodPut, path+"/snapshot-export", communicationLink("DB", "db"), nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodPut, path+"/backup-export", communicationLink("Backup Export", "backup"), nil))
+
+	// deleting the link removes it from the individual risks referencing it
+	var deleted struct {
+		ID                string `json:"id"`
+		ReferencesDeleted bool   `json:"references_deleted"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodDelete, path+"/snapshot-export", nil, &deleted))
+	assert.Equal(t, "web>snapshot-export", deleted.ID)
+	assert.True(t, deleted.ReferencesDeleted)
+	modelInput = server.readModel(token, modelID)
+	assert.NotContains(t, modelInput.TechnicalAssets["Web Server"].CommunicationLinks, "Snapshot Export")
+	assert.Contains(t, modelInput.TechnicalAssets["Web Server"].CommunicationLinks, "db")
+	assert.Empty(t, modelInput.IndividualRiskCategories["Manual Finding"].RisksIdentified["Backup Leak"].MostRelevantCommunicationLink)
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodDelete, path+"/snapshot-export", nil, nil))
+}
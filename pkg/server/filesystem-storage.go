@@ -0,0 +1,595 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const auditLogFilename = "audit.log"
+
+// filesystemStorage keeps a folder per key (named by the hash of the key) containing the audit log and a folder per
+// model (named by its UUID) with the model file and its history, users and tokens are kept as files in separate
+// folders named by the user key hash and token hash respectively
+type filesystemStorage struct {
+	keysFolder   string
+	usersFolder  string
+	tokensFolder string
+	locksFolder  string
+	inputFile    string
+	locks        *storageLocks
+}
+
+type filesystemUser struct {
+	ID         string            `json:"id"`
+	KeyHash    string            `json:"key_hash"`
+	Name       string            `json:"name"`
+	WrappedKey []byte            `json:"wrapped_key"`
+	Roles      map[string]string `json:"roles"`
+	Created    time.Time         `json:"created"`
+}
+
+type filesystemToken struct {
+	KeyHash              string `json:"key_hash"`
+	Role                 string `json:"role,omitempty"`
+	XorRand              []byte `json:"xor_rand"`
+	CreatedNanoTime      int64  `json:"created_nano_time"`
+	LastAccessedNanoTime int64  `json:"last_accessed_nano_time"`
+}
+
+func newFilesystemStorage(serverFolder string, keyFolder string, inputFile string) (*filesystemStorage, error) {
+	storage := &filesystemStorage{
+		keysFolder:   filepath.Join(serverFolder, keyFolder),
+		usersFolder:  filepath.Join(serverFolder, "users"),
+		tokensFolder: filepath.Join(serverFolder, "tokens"),
+		locksFolder:  filepath.Join(serverFolder, "locks"),
+		inputFile:    inputFile,
+		locks:        newStorageLocks(),
+	}
+	for _, folder := range []string{storage.keysFolder, storage.usersFolder, storage.tokensFolder, storage.locksFolder} {
+		if err := os.MkdirAll(folder, 0700); err != nil {
+			return nil, fmt.Errorf("unable to create folder %v: %w", folder, err)
+		}
+	}
+	return storage, nil
+}
+
+func (what *filesystemStorage) CreateKey(keyHash string) error {
+	folder, err := what.folder(keyHash)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(folder, 0700)
+}
+
+func (what *filesystemStorage) KeyExists(keyHash string) (bool, error) {
+	folder, err := what.folder(keyHash)
+	if err != nil {
+		return false, err
+	}
+	return fileExists(folder)
+}
+
+func (what *filesystemStorage) ListKeys() ([]string, error) {
+	keyFolders, err := os.ReadDir(what.keysFolder)
+	if err != nil {
+		return nil, err
+	}
+	keyHashes := make([]string, 0)
+	for _, keyFolder := range keyFolders {
+		if keyFolder.IsDir() {
+			keyHashes = append(keyHashes, keyFolder.Name())
+		}
+	}
+	return keyHashes, nil
+}
+
+func (what *filesystemStorage) DeleteKey(keyHash string) error {
+	folder, err := what.folder(keyHash)
+	if err != nil {
+		return err
+	}
+	if found, err := fileExists(folder); err != nil || !found {
+		if err == nil {
+			err = errNotFound
+		}
+		return err
+	}
+	if err = what.deleteTokensOfKey(keyHash); err != nil {
+		return err
+	}
+	users, err := what.ListUsers(keyHash)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if err = what.DeleteUser(keyHash, user.ID); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(folder)
+}
+
+func (what *filesystemStorage) PutUser(user StoredUser) error {
+	if err := checkName(user.UserKeyHash); err != nil {
+		return err
+	}
+	data, err := json.Marshal(filesystemUser{
+		ID:         user.ID,
+		KeyHash:    user.KeyHash,
+		Name:       user.Name,
+		WrappedKey: user.WrappedKey,
+		Roles:      user.Roles,
+		Created:    user.Created,
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(what.usersFolder, user.UserKeyHash), data, 0600)
+}
+
+func (what *filesystemStorage) GetUser(userKeyHash string) (StoredUser, bool, error) {
+	if err := checkName(userKeyHash); err != nil {
+		return StoredUser{}, false, err
+	}
+	data, err := os.ReadFile(filepath.Join(what.usersFolder, userKeyHash))
+	if os.IsNotExist(err) {
+		return StoredUser{}, false, nil
+	}
+	if err != nil {
+		return StoredUser{}, false, err
+	}
+	user := filesystemUser{}
+	if err = json.Unmarshal(data, &user); err != nil {
+		return StoredUser{}, false, err
+	}
+	return StoredUser{
+		ID:          user.ID,
+		KeyHash:     user.KeyHash,
+		UserKeyHash: userKeyHash,
+		Name:        user.Name,
+		WrappedKey:  user.WrappedKey,
+		Roles:       user.Roles,
+		Created:     user.Created,
+	}, true, nil
+}
+
+func (what *filesystemStorage) ListUsers(keyHash string) ([]StoredUser, error) {
+	files, err := os.ReadDir(what.usersFolder)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]StoredUser, 0)
+	for _, file := range files {
+		user, found, err := what.GetUser(file.Name())
+		if err != nil {
+			return nil, err
+		}
+		if found && user.KeyHash == keyHash {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Created.Before(users[j].Created)
+	})
+	return users, nil
+}
+
+func (what *filesystemStorage) DeleteUser(keyHash string, userID string) error {
+	users, err := what.ListUsers(keyHash)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if user.ID == userID {
+			if err = what.deleteTokensOfKey(user.UserKeyHash); err != nil {
+				return err
+			}
+			return os.Remove(filepath.Join(what.usersFolder, user.UserKeyHash))
+		}
+	}
+	return errNotFound
+}
+
+func (what *filesystemStorage) PutToken(token StoredToken) error {
+	if err := checkName(token.TokenHash); err != nil {
+		return err
+	}
+	if err := what.deleteTokensOfKey(token.KeyHash); err != nil {
+		return err
+	}
+	return what.writeToken(token)
+}
+
+func (what *filesystemStorage) GetToken(tokenHash string) (StoredToken, bool, error) {
+	if err := checkName(tokenHash); err != nil {
+		return StoredToken{}, false, err
+	}
+	data, err := os.ReadFile(filepath.Join(what.tokensFolder, tokenHash))
+	if os.IsNotExist(err) {
+		return StoredToken{}, false, nil
+	}
+	if err != nil {
+		return StoredToken{}, false, err
+	}
+	token := filesystemToken{}
+	if err = json.Unmarshal(data, &token); err != nil {
+		return StoredToken{}, false, err
+	}
+	return StoredToken{
+		TokenHash:            tokenHash,
+		KeyHash:              token.KeyHash,
+		Role:                 token.Role,
+		XorRand:              token.XorRand,
+		CreatedNanoTime:      token.CreatedNanoTime,
+		LastAccessedNanoTime: token.LastAccessedNanoTime,
+	}, true, nil
+}
+
+func (what *filesystemStorage) TouchToken(tokenHash string, lastAccessedNanoTime int64) error {
+	token, found, err := what.GetToken(tokenHash)
+	if err != nil || !found {
+		return err
+	}
+	token.LastAccessedNanoTime = lastAccessedNanoTime
+	return what.writeToken(token)
+}
+
+func (what *filesystemStorage) DeleteToken(tokenHash string) error {
+	if err := checkName(tokenHash); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(what.tokensFolder, tokenHash))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (what *filesystemStorage) DeleteTokensIdleOrCreatedBefore(idleNanoTime int64, createdNanoTime int64) error {
+	return what.deleteTokens(func(token StoredToken) bool {
+		return token.LastAccessedNanoTime < idleNanoTime || token.CreatedNanoTime < createdNanoTime
+	})
+}
+
+func (what *filesystemStorage) ListModels(keyHash string) ([]StoredModel, error) {
+	folder, err := what.folder(keyHash)
+	if err != nil {
+		return nil, err
+	}
+	modelFolders, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]StoredModel, 0)
+	for _, modelFolder := range modelFolders {
+		if !modelFolder.IsDir() {
+			continue
+		}
+		modelStat, err := os.Stat(filepath.Join(folder, modelFolder.Name(), what.inputFile))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		folderInfo, err := modelFolder.Info()
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, StoredModel{
+			ID:       modelFolder.Name(),
+			Created:  folderInfo.ModTime(),
+			Modified: modelStat.ModTime(),
+		})
+	}
+	return models, nil
+}
+
+func (what *filesystemStorage) ModelExists(keyHash string, modelID string) (bool, error) {
+	folder, err := what.folder(keyHash, modelID)
+	if err != nil {
+		return false, err
+	}
+	return fileExists(folder)
+}
+
+func (what *filesystemStorage) ReadModel(keyHash string, modelID string) ([]byte, error) {
+	folder, err := what.folder(keyHash, modelID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(folder, what.inputFile))
+	if os.IsNotExist(err) {
+		return nil, errNotFound
+	}
+	return data, err
+}
+
+func (what *filesystemStorage) WriteModel(keyHash string, model StoredModel, data []byte) error {
+	folder, err := what.folder(keyHash, model.ID)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(folder, 0700); err != nil {
+		return err
+	}
+	filename := filepath.Join(folder, what.inputFile)
+	if err = os.WriteFile(filename, data, 0600); err != nil {
+		return err
+	}
+	if !model.Modified.IsZero() {
+		if err = os.Chtimes(filename, model.Modified, model.Modified); err != nil {
+			return err
+		}
+	}
+	if !model.Created.IsZero() { // the creation time of a model is the modification time of its folder
+		return os.Chtimes(folder, model.Created, model.Created)
+	}
+	return nil
+}
+
+func (what *filesystemStorage) DeleteModel(keyHash string, modelID string) error {
+	folder, err := what.folder(keyHash, modelID)
+	if err != nil {
+		return err
+	}
+	if found, err := fileExists(folder); err != nil || !found {
+		if err == nil {
+			err = errNotFound
+		}
+		return err
+	}
+	return os.RemoveAll(folder)
+}
+
+func (what *filesystemStorage) AddHistory(keyHash string, modelID string, versionID string, data []byte, keep int) error {
+	folder, err := what.folder(keyHash, modelID, historyFolderName)
+	if err != nil {
+		return err
+	}
+	if err = checkName(versionID); err != nil {
+		return err
+	}
+	if err = os.MkdirAll(folder, 0700); err != nil {
+		return err
+	}
+	filename := filepath.Join(folder, versionID+historyFileExtension)
+	if err = os.Remove(filename); err != nil && !os.IsNotExist(err) { // replaces an entry with the same id (being read-only)
+		return err
+	}
+	if err = os.WriteFile(filename, data, 0400); err != nil {
+		return err
+	}
+	versionIDs, err := what.ListHistory(keyHash, modelID)
+	if err != nil {
+		return err
+	}
+	for _, oldVersionID := range oldestToDelete(versionIDs, keep) {
+		if err = os.Remove(filepath.Join(folder, oldVersionID+historyFileExtension)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (what *filesystemStorage) ListHistory(keyHash string, modelID string) ([]string, error) {
+	folder, err := what.folder(keyHash, modelID, historyFolderName)
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(folder)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	versionIDs := make([]string, 0)
+	for _, file := range files {
+		if versionID, found := strings.CutSuffix(file.Name(), historyFileExtension); found && !file.IsDir() {
+			versionIDs = append(versionIDs, versionID)
+		}
+	}
+	sort.Strings(versionIDs)
+	return versionIDs, nil
+}
+
+func (what *filesystemStorage) ReadHistory(keyHash string, modelID string, versionID string) ([]byte, error) {
+	folder, err := what.folder(keyHash, modelID, historyFolderName)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkName(versionID); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(folder, versionID+historyFileExtension))
+	if os.IsNotExist(err) {
+		return nil, errNotFound
+	}
+	return data, err
+}
+
+type filesystemAuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	UserID    string    `json:"user_id,omitempty"`
+	UserName  string    `json:"user_name"`
+	ModelID   string    `json:"model_id,omitempty"`
+	Action    string    `json:"action"`
+}
+
+// AddAuditEntry appends the entry as a line of JSON to the audit log of the key
+func (what *filesystemStorage) AddAuditEntry(keyHash string, entry StoredAuditEntry) error {
+	folder, err := what.folder(keyHash)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(filesystemAuditEntry(entry))
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(folder, auditLogFilename), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(data, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (what *filesystemStorage) ListAuditEntries(keyHash string) ([]StoredAuditEntry, error) {
+	folder, err := what.folder(keyHash)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]StoredAuditEntry, 0)
+	file, err := os.Open(filepath.Join(folder, auditLogFilename))
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := filesystemAuditEntry{}
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, StoredAuditEntry(entry))
+	}
+	return entries, scanner.Err()
+}
+
+// Lock uses a lock file created exclusively, which works across processes (also on network filesystems), the lock file
+// contains the owner token and its modification time is refreshed while the lock is held
+func (what *filesystemStorage) Lock(name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	filename := filepath.Join(what.locksFolder, name+".lock")
+	return what.locks.lock(name, func(owner string, timeout time.Duration) (bool, error) {
+		file, err := os.OpenFile(filename, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, err = file.WriteString(owner)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(filename)
+				return false, err
+			}
+			return true, nil
+		}
+		if !os.IsExist(err) {
+			return false, err
+		}
+		if info, statErr := os.Stat(filename); statErr == nil && time.Since(info.ModTime()) > timeout {
+			_ = os.Remove(filename) // stale lock
+		}
+		return false, nil
+	}, func(owner string) error {
+		if err := checkLockFileOwner(filename, owner); err != nil {
+			return err
+		}
+		now := time.Now()
+		return os.Chtimes(filename, now, now)
+	})
+}
+
+func (what *filesystemStorage) Unlock(name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	filename := filepath.Join(what.locksFolder, name+".lock")
+	return what.locks.unlock(name, func(owner string) error {
+		if err := checkLockFileOwner(filename, owner); err != nil {
+			return err
+		}
+		return os.Remove(filename)
+	})
+}
+
+func checkLockFileOwner(filename string, owner string) error {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) || (err == nil && string(data) != owner) {
+		return errLockTakenOver
+	}
+	return err
+}
+
+func (what *filesystemStorage) Close() error {
+	return nil
+}
+
+func (what *filesystemStorage) writeToken(token StoredToken) error {
+	data, err := json.Marshal(filesystemToken{
+		KeyHash:              token.KeyHash,
+		Role:                 token.Role,
+		XorRand:              token.XorRand,
+		CreatedNanoTime:      token.CreatedNanoTime,
+		LastAccessedNanoTime: token.LastAccessedNanoTime,
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(what.tokensFolder, token.TokenHash), data, 0600)
+}
+
+func (what *filesystemStorage) deleteTokensOfKey(keyHash string) error {
+	return what.deleteTokens(func(token StoredToken) bool {
+		return token.KeyHash == keyHash
+	})
+}
+
+func (what *filesystemStorage) deleteTokens(matches func(token StoredToken) bool) error {
+	files, err := os.ReadDir(what.tokensFolder)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		token, found, err := what.GetToken(file.Name())
+		if err != nil {
+			return err
+		}
+		if found && matches(token) {
+			if err = what.DeleteToken(file.Name()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (what *filesystemStorage) folder(names ...string) (string, error) {
+	for _, name := range names {
+		if err := checkName(name); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(append([]string{what.keysFolder}, names...)...), nil
+}
+
+// checkName ensures that a name taken from a request can't be used to escape the storage folders
+func checkName(name string) error {
+	if len(name) == 0 || name != filepath.Base(name) || name == "." || name == ".." {
+		return fmt.Errorf("invalid name %q", name)
+	}
+	return nil
+}
+
+func fileExists(filename string) (bool, error) {
+	_, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
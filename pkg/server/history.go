@@ -0,0 +1,201 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+// history backups are identified by "<timestamp> <change reason>" and keep the model as it was before that change,
+// the filesystem storage keeps them as "<id>.backup" files in the history folder of the model
+
+const (
+	historyFolderName          = "history"
+	historyFileExtension       = ".backup"
+	historyFileTimestampFormat = "2006-01-02 15:04:05"
+	currentVersionID           = "current"
+)
+
+type payloadModelVersion struct {
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Reason    string    `json:"reason"`
+}
+
+func (s *server) getModelHistory(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToModel(ginContext, roleViewer)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelID, ok := s.checkModel(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	versions, err := s.listModelVersions(folderNameOfKey, modelID)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	ginContext.JSON(http.StatusOK, versions)
+}
+
+func (s *server) getModelVersion(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	yamlBytes, ok := s.readModelVersion(ginContext, ginContext.Param("version-id"), key, folderNameOfKey)
+	if ok {
+		ginContext.Header("Content-Disposition", "attachment; filename="+s.config.InputFile)
+		ginContext.Data(http.StatusOK, "application/x-yaml", yamlBytes)
+	}
+}
+
+// diffs a version against another one given by the "to" query parameter, which defaults to the current model
+func (s *server) diffModelVersions(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	fromID := ginContext.Param("version-id")
+	toID := ginContext.DefaultQuery("to", currentVersionID)
+	fromYAML, ok := s.readModelVersion(ginContext, fromID, key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	toYAML, ok := s.readModelVersion(ginContext, toID, key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(fromYAML)),
+		B:        difflib.SplitLines(string(toYAML)),
+		FromFile: fromID,
+		ToFile:   toID,
+		Context:  3,
+	})
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	ginContext.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(diff))
+}
+
+// rolls the model back to a version, the model as it was before the rollback is kept in the history as well
+func (s *server) rollbackModel(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	versionID := ginContext.Param("version-id")
+	if versionID == currentVersionID {
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "unable to roll back to the current version",
+		})
+		return
+	}
+	yamlBytes, ok := s.readModelVersion(ginContext, versionID, key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	modelInput := new(input.Model).Defaults()
+	err := modelInput.Parse(s.config.InputFile, yamlBytes)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	modelID, ok := s.checkModel(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	ok = s.writeModelYAML(ginContext, string(yamlBytes), key, folderNameOfKey, modelID, "Rollback to "+versionID, false)
+	if ok {
+		ginContext.JSON(http.StatusOK, gin.H{
+			"message": "model rolled back",
+			"id":      versionID,
+		})
+	}
+}
+
+// readModelVersion returns the YAML of a version from the history of the model, or of the current model
+func (s *server) readModelVersion(ginContext *gin.Context, versionID string, key []byte, folderNameOfKey string) (yamlBytes []byte, ok bool) {
+	if versionID == currentVersionID {
+		_, yamlText, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+		return []byte(yamlText), ok
+	}
+	modelID, ok := s.checkModel(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return nil, false
+	}
+	versions, err := s.listModelVersions(folderNameOfKey, modelID)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return nil, false
+	}
+	for _, version := range versions {
+		if version.ID == versionID { // only ids listed are accepted, so the id is safe to use
+			data, err := s.storage.ReadHistory(folderNameOfKey, modelID, version.ID)
+			if err == nil {
+				yamlBytes, err = decryptModel(data, key)
+			}
+			if err != nil {
+				handleErrorInServiceCall(err, ginContext)
+				return nil, false
+			}
+			return yamlBytes, true
+		}
+	}
+	ginContext.JSON(http.StatusNotFound, gin.H{
+		"error": "model version not found",
+	})
+	return nil, false
+}
+
+// listModelVersions returns the versions kept in the history of the model, oldest first
+func (s *server) listModelVersions(folderNameOfKey string, modelID string) ([]payloadModelVersion, error) {
+	versions := make([]payloadModelVersion, 0)
+	versionIDs, err := s.storage.ListHistory(folderNameOfKey, modelID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range versionIDs {
+		if len(id) < len(historyFileTimestampFormat) {
+			continue
+		}
+		timestamp, err := time.ParseInLocation(historyFileTimestampFormat, id[:len(historyFileTimestampFormat)], time.Local)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, payloadModelVersion{
+			ID:        id,
+			Timestamp: timestamp,
+			Reason:    strings.TrimSpace(id[len(historyFileTimestampFormat):]),
+		})
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].ID < versions[j].ID
+	})
+	return versions, nil
+}
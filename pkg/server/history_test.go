@@ -0,0 +1,142 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/docs"
+)
+
+// modelHistory returns the ids of the versions in the history of the model by their change reason
+func (what *testServer) modelHistory(token string, modelID string) map[string]string {
+	var versions []payloadModelVersion
+	assert.Equal(what.t, http.StatusOK, what.withToken(token, http.MethodGet, "/models/"+modelID+"/history", nil, &versions))
+	versionIDs := make(map[string]string)
+	for _, version := range versions {
+		assert.Equal(what.t, version.Timestamp.Format(historyFileTimestampFormat)+" "+version.Reason, version.ID)
+		versionIDs[version.Reason] = version.ID
+	}
+	assert.Len(what.t, versionIDs, len(versions))
+	return versionIDs
+}
+
+// text sends the request with the token and returns the status code and the plain response body
+func (what *testServer) text(token string, method string, path string) (int, string) {
+	request := httptest.NewRequest(method, path, nil)
+	request.Header.Set("token", token)
+	recorder := httptest.NewRecorder()
+	what.router.ServeHTTP(recorder, request)
+	return recorder.Code, recorder.Body.String()
+}
+
+// createHistoryTestModel creates a model with a history of an overview and a cover update
+func createHistoryTestModel(server *testServer, token string) string {
+	modelID := server.createModel(token)
+	assert.Equal(server.t, http.StatusOK, server.withToken(token, http.MethodPut, "/models/"+modelID+"/overview", payloadOverview{
+		ManagementSummaryComment: "Some comment",
+		BusinessCriticality:      "important",
+	}, nil))
+	assert.Equal(server.t, http.StatusOK, server.withToken(token, http.MethodPut, "/models/"+modelID+"/cover", payloadCover{
+		Title: "Changed Title",
+	}, nil))
+	return modelID
+}
+
+func TestModelHistory(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createHistoryTestModel(server, token)
+
+	versionIDs := server.modelHistory(token, modelID)
+	assert.Len(t, versionIDs, 2) // the model creation itself is no change kept in the history
+	assert.Contains(t, versionIDs, "Overview Update")
+	assert.Contains(t, versionIDs, "Cover Update")
+
+	status, newModel := server.text(token, http.MethodGet, "/models/"+modelID+"/history/"+url.PathEscape(versionIDs["Overview Update"]))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, newModel, "title: New Threat Model")
+	assert.NotContains(t, newModel, "Some comment") // the model as it was before the overview update
+	status, beforeCoverUpdate := server.text(token, http.MethodGet, "/models/"+modelID+"/history/"+url.PathEscape(versionIDs["Cover Update"]))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, beforeCoverUpdate, "Some comment")
+	assert.Contains(t, beforeCoverUpdate, "title: New Threat Model")
+	status, current := server.text(token, http.MethodGet, "/models/"+modelID+"/history/current")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, current, "title: Changed Title")
+
+	status, _ = server.text(token, http.MethodGet, "/models/"+modelID+"/history/unknown")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = server.text(token, http.MethodGet, "/models/"+modelID+"/history/..%2Fmodel")
+	assert.Equal(t, http.StatusNotFound, status) // only ids listed in the history are read
+}
+
+func TestDiffModelVersions(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createHistoryTestModel(server, token)
+	versionID := server.modelHistory(token, modelID)["Cover Update"]
+	path := "/models/" + modelID + "/history/" + url.PathEscape(versionID) + "/diff"
+
+	status, diff := server.text(token, http.MethodGet, path)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "--- "+versionID+"\n"+
+		"+++ current\n"+
+		"@@ -1,5 +1,5 @@\n"+
+		" threagile_version: "+docs.ThreagileVersion+"\n"+
+		"-title: New Threat Model\n"+
+		"+title: Changed Title\n"+
+		" business_criticality: important\n"+
+		" management_summary_comment: Some comment\n"+
+		" \n", diff)
+	for i := 0; i < 3; i++ {
+		_, again := server.text(token, http.MethodGet, path)
+		assert.Equal(t, diff, again)
+	}
+	status, explicitlyToCurrent := server.text(token, http.MethodGet, path+"?to=current")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, diff, explicitlyToCurrent)
+
+	status, diff = server.text(token, http.MethodGet, path+"?to="+url.QueryEscape(versionID))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, diff)
+
+	status, _ = server.text(token, http.MethodGet, path+"?to=unknown")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestRollbackModel(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createHistoryTestModel(server, token)
+	versionIDs := server.modelHistory(token, modelID)
+	path := "/models/" + modelID + "/history/"
+
+	assert.Equal(t, http.StatusBadRequest, server.withToken(token, http.MethodPost, path+"current/rollback", nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodPost, path+"unknown/rollback", nil, nil))
+	assert.Len(t, server.modelHistory(token, modelID), 2)
+
+	var response struct {
+		ID string `json:"id"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPost, path+url.PathEscape(versionIDs["Cover Update"])+"/rollback", nil, &response))
+	assert.Equal(t, versionIDs["Cover Update"], response.ID)
+	modelInput := server.readModel(token, modelID)
+	assert.Equal(t, "New Threat Model", modelInput.Title)
+	assert.Equal(t, "Some comment", modelInput.ManagementSummaryComment)
+
+	rolledBackVersionIDs := server.modelHistory(token, modelID)
+	assert.Len(t, rolledBackVersionIDs, 3) // the rollback is a change kept in the history as well
+	rollbackVersionID := rolledBackVersionIDs["Rollback to "+versionIDs["Cover Update"]]
+	assert.NotEmpty(t, rollbackVersionID)
+	status, beforeRollback := server.text(token, http.MethodGet, path+url.PathEscape(rollbackVersionID))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, beforeRollback, "title: Changed Title") // so the rollback can be undone
+
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPost, path+url.PathEscape(rollbackVersionID)+"/rollback", nil, nil))
+	assert.Equal(t, "Changed Title", server.readModel(token, modelID).Title)
+	assert.Len(t, server.modelHistory(token, modelID), 4)
+}
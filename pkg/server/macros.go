@@ -0,0 +1,386 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/macros"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// macroSession keeps the state of a model macro (the answers given so far) between the calls stepping through its questions
+type macroSession struct {
+	macro                                 macros.Macros
+	modelUUID                             string
+	folderNameOfKey                       string
+	principalKeyHash                      string // the user (or owner of the key) who started the session
+	parsedModel                           *types.ParsedModel
+	createdNanoTime, lastAccessedNanoTime int64
+}
+
+type payloadModelMacro struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Custom      bool   `json:"custom"`
+}
+
+type payloadMacroSession struct {
+	MacroID string `json:"macro_id"`
+}
+
+type payloadMacroAnswer struct {
+	QuestionID string   `json:"question_id"`
+	Answer     []string `json:"answer"`
+}
+
+type payloadMacroQuestion struct {
+	ID              string   `json:"id"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	PossibleAnswers []string `json:"possible_answers"`
+	MultiSelect     bool     `json:"multi_select"`
+	DefaultAnswer   string   `json:"default_answer"`
+}
+
+func (s *server) listModelMacros(ginContext *gin.Context) {
+	result := make([]payloadModelMacro, 0)
+	for _, macro := range s.customMacros {
+		details := macro.GetMacroDetails()
+		result = append(result, payloadModelMacro{ID: details.ID, Title: details.Title, Description: details.Description, Custom: true})
+	}
+	for _, macro := range macros.ListBuiltInMacros() {
+		details := macro.GetMacroDetails()
+		result = append(result, payloadModelMacro{ID: details.ID, Title: details.Title, Description: details.Description})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	ginContext.JSON(http.StatusOK, result)
+}
+
+// starts a macro session on the model: the questions are based on the model as analyzed at this point in time
+func (s *server) createMacroSession(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	payload := payloadMacroSession{}
+	err := ginContext.BindJSON(&payload)
+	if err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "unable to parse request payload",
+		})
+		return
+	}
+	macro, err := macros.GetMacroByID(payload.MacroID, s.customMacros)
+	if err != nil {
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "model macro not found",
+		})
+		return
+	}
+
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	parsedModel, ok := s.analyzeModelForMacro(ginContext, modelInput)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	session := &macroSession{
+		macro:                macro,
+		modelUUID:            ginContext.Param("model-id"),
+		folderNameOfKey:      folderNameOfKey,
+		principalKeyHash:     principalOf(ginContext).keyHash,
+		parsedModel:          parsedModel,
+		createdNanoTime:      now,
+		lastAccessedNanoTime: now,
+	}
+	question, ok := nextMacroQuestion(ginContext, session)
+	if !ok {
+		return
+	}
+
+	sessionID := uuid.New().String()
+	s.macroSessionsLock.Lock()
+	s.housekeepingMacroSessions()
+	s.macroSessions[sessionID] = session
+	s.macroSessionsLock.Unlock()
+
+	ginContext.JSON(http.StatusCreated, gin.H{
+		"message":  "model macro session created",
+		"id":       sessionID,
+		"macro_id": macro.GetMacroDetails().ID,
+		"question": question,
+	})
+}
+
+func (s *server) getMacroSession(ginContext *gin.Context) {
+	session, _, ok := s.checkMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, session.folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(session.folderNameOfKey)
+	question, ok := nextMacroQuestion(ginContext, session)
+	if ok {
+		ginContext.JSON(http.StatusOK, gin.H{
+			"macro_id": session.macro.GetMacroDetails().ID,
+			"question": question,
+		})
+	}
+}
+
+func (s *server) answerMacroQuestion(ginContext *gin.Context) {
+	session, _, ok := s.checkMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	payload := payloadMacroAnswer{}
+	err := ginContext.BindJSON(&payload)
+	if err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "unable to parse request payload",
+		})
+		return
+	}
+	if !s.lockFolder(ginContext, session.folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(session.folderNameOfKey)
+
+	current, err := session.macro.GetNextQuestion(session.parsedModel)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	if current.NoMoreQuestions() || current.ID != payload.QuestionID {
+		ginContext.JSON(http.StatusConflict, gin.H{
+			"error": "question is not the current question of the model macro",
+		})
+		return
+	}
+	if !current.MultiSelect && len(payload.Answer) != 1 {
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "question takes exactly one answer",
+		})
+		return
+	}
+	for _, answer := range payload.Answer {
+		if !current.IsMatchingValueConstraint(answer) {
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "answer does not match any allowed value: " + answer,
+			})
+			return
+		}
+	}
+
+	message, validResult, err := session.macro.ApplyAnswer(payload.QuestionID, payload.Answer...)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	s.respondMacroStep(ginContext, session, message, validResult)
+}
+
+func (s *server) goBackInMacroSession(ginContext *gin.Context) {
+	session, _, ok := s.checkMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, session.folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(session.folderNameOfKey)
+	message, validResult, err := session.macro.GoBack()
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	s.respondMacroStep(ginContext, session, message, validResult)
+}
+
+func (s *server) getMacroChangeImpact(ginContext *gin.Context) {
+	session, key, ok := s.checkMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, session.folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(session.folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, session.modelUUID, key, session.folderNameOfKey)
+	if !ok {
+		return
+	}
+	// the model may have changed since the session was started, the changes are based on the model as stored now
+	parsedModel, ok := s.analyzeModelForMacro(ginContext, modelInput)
+	if !ok {
+		return
+	}
+	session.parsedModel = parsedModel
+	changes, message, validResult, err := session.macro.GetFinalChangeImpact(&modelInput, session.parsedModel)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	if changes == nil {
+		changes = make([]string, 0)
+	}
+	ginContext.JSON(http.StatusOK, gin.H{
+		"changes": changes,
+		"message": message,
+		"valid":   validResult,
+	})
+}
+
+// executes the model macro and ends the session, the updated model is written with a history entry
+func (s *server) executeMacroSession(ginContext *gin.Context) {
+	session, key, ok := s.checkMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, session.folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(session.folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, session.modelUUID, key, session.folderNameOfKey)
+	if !ok {
+		return
+	}
+	// the model may have changed since the session was started, the changes are based on the model as stored now
+	parsedModel, ok := s.analyzeModelForMacro(ginContext, modelInput)
+	if !ok {
+		return
+	}
+	session.parsedModel = parsedModel
+	message, validResult, err := session.macro.Execute(&modelInput, session.parsedModel)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	if !validResult {
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return
+	}
+	ok = s.writeModel(ginContext, key, session.folderNameOfKey, &modelInput, "Model Macro "+session.macro.GetMacroDetails().ID)
+	if ok {
+		s.deleteMacroSessionFromMap(ginContext.Param("session-id"))
+		ginContext.JSON(http.StatusOK, gin.H{
+			"message": message,
+		})
+	}
+}
+
+func (s *server) deleteMacroSession(ginContext *gin.Context) {
+	_, _, ok := s.checkMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	s.deleteMacroSessionFromMap(ginContext.Param("session-id"))
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "model macro session deleted",
+	})
+}
+
+func (s *server) respondMacroStep(ginContext *gin.Context, session *macroSession, message string, validResult bool) {
+	question, ok := nextMacroQuestion(ginContext, session)
+	if ok {
+		ginContext.JSON(http.StatusOK, gin.H{
+			"message":  message,
+			"valid":    validResult,
+			"question": question,
+		})
+	}
+}
+
+// returns the next question of the macro or nil when there are no more questions
+func nextMacroQuestion(ginContext *gin.Context, session *macroSession) (question *payloadMacroQuestion, ok bool) {
+	nextQuestion, err := session.macro.GetNextQuestion(session.parsedModel)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return nil, false
+	}
+	if nextQuestion.NoMoreQuestions() {
+		return nil, true
+	}
+	possibleAnswers := nextQuestion.PossibleAnswers
+	if possibleAnswers == nil {
+		possibleAnswers = make([]string, 0)
+	}
+	return &payloadMacroQuestion{
+		ID:              nextQuestion.ID,
+		Title:           nextQuestion.Title,
+		Description:     nextQuestion.Description,
+		PossibleAnswers: possibleAnswers,
+		MultiSelect:     nextQuestion.MultiSelect,
+		DefaultAnswer:   nextQuestion.DefaultAnswer,
+	}, true
+}
+
+// checks the token and that the session belongs to the model of the request and the key of the token,
+// the key itself is never kept in the session
+func (s *server) checkMacroSession(ginContext *gin.Context) (session *macroSession, key []byte, ok bool) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return nil, nil, false
+	}
+	s.macroSessionsLock.Lock()
+	defer s.macroSessionsLock.Unlock()
+	s.housekeepingMacroSessions()
+	session, exists := s.macroSessions[ginContext.Param("session-id")]
+	if !exists || session.folderNameOfKey != folderNameOfKey || session.principalKeyHash != principalOf(ginContext).keyHash || session.modelUUID != ginContext.Param("model-id") {
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "model macro session not found",
+		})
+		return nil, nil, false
+	}
+	session.lastAccessedNanoTime = time.Now().UnixNano()
+	return session, key, true
+}
+
+func (s *server) housekeepingMacroSessions() {
+	now := time.Now().UnixNano()
+	for sessionID, session := range s.macroSessions {
+		// remove all sessions idle for 30 minutes (= 1800000000000 ns) or older than 10 hours (= 36000000000000 ns)
+		if now-session.lastAccessedNanoTime > 1800000000000 || now-session.createdNanoTime > 36000000000000 {
+			delete(s.macroSessions, sessionID)
+		}
+	}
+}
+
+func (s *server) deleteMacroSessionFromMap(sessionID string) {
+	s.macroSessionsLock.Lock()
+	defer s.macroSessionsLock.Unlock()
+	delete(s.macroSessions, sessionID)
+}
+
+func (s *server) analyzeModelForMacro(ginContext *gin.Context, modelInput input.Model) (parsedModel *types.ParsedModel, ok bool) {
+	result, err := model.AnalyzeModelWithRules(*s.config, &modelInput, s.customRiskRules, s.progressReporter())
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return nil, false
+	}
+	return result.ParsedModel, true
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type macroSessionResponse struct {
+	ID       string                `json:"id"`
+	MacroID  string                `json:"macro_id"`
+	Message  string                `json:"message"`
+	Valid    bool                  `json:"valid"`
+	Question *payloadMacroQuestion `json:"question"`
+}
+
+// createMacroTestModel creates the model of createTechnicalAssetTestModel without its risk tracking, as some of it
+// doesn't match any risk, which fails the analysis of the model by macros
+func createMacroTestModel(server *testServer, token string) string {
+	modelID := createTechnicalAssetTestModel(server, token)
+	modelInput := server.readModel(token, modelID)
+	modelInput.RiskTracking = nil
+	server.writeModel(token, modelID, &modelInput)
+	return modelID
+}
+
+// createMacroSession starts a session of the macro on the model and returns the path of the session and its first question
+func (what *testServer) createMacroSession(token string, modelID string, macroID string) (string, *payloadMacroQuestion) {
+	var created macroSessionResponse
+	assert.Equal(what.t, http.StatusCreated, what.withToken(token, http.MethodPost, "/models/"+modelID+"/macro-sessions", payloadMacroSession{MacroID: macroID}, &created))
+	assert.NotEmpty(what.t, created.ID)
+	assert.Equal(what.t, macroID, created.MacroID)
+	return "/models/" + modelID + "/macro-sessions/" + created.ID, created.Question
+}
+
+// answer answers the current question of the session and returns the next question
+func (what *testServer) answer(token string, sessionPath string, questionID string, answer ...string) *payloadMacroQuestion {
+	var response macroSessionResponse
+	assert.Equal(what.t, http.StatusOK, what.withToken(token, http.MethodPost, sessionPath+"/answer", payloadMacroAnswer{QuestionID: questionID, Answer: answer}, &response), questionID)
+	assert.True(what.t, response.Valid, questionID)
+	return response.Question
+}
+
+func TestMacroSession(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createMacroTestModel(server, token)
+
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodPost, "/models/"+modelID+"/macro-sessions", payloadMacroSession{MacroID: "unknown"}, nil))
+	sessionPath, question := server.createMacroSession(token, modelID, "add-vault")
+	assert.Equal(t, "vault-name", question.ID)
+
+	var current macroSessionResponse
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodGet, sessionPath, nil, &current))
+	assert.Equal(t, "add-vault", current.MacroID)
+	assert.Equal(t, "vault-name", current.Question.ID)
+
+	question = server.answer(token, sessionPath, "vault-name", "Test")
+	assert.Equal(t, "storage-type", question.ID)
+	assert.Equal(t, http.StatusConflict, server.withToken(token, http.MethodPost, sessionPath+"/answer", payloadMacroAnswer{QuestionID: "vault-name", Answer: []string{"Other"}}, nil))
+	assert.Equal(t, http.StatusBadRequest, server.withToken(token, http.MethodPost, sessionPath+"/answer", payloadMacroAnswer{QuestionID: "storage-type", Answer: []string{"Tape"}}, nil))
+	assert.Equal(t, http.StatusBadRequest, server.withToken(token, http.MethodPost, sessionPath+"/answer", payloadMacroAnswer{QuestionID: "storage-type", Answer: question.PossibleAnswers[:2]}, nil))
+	question = server.answer(token, sessionPath, "storage-type", "In-Memory (no persistent storage of secrets)")
+	assert.Equal(t, "authentication-type", question.ID)
+
+	// going back asks the previous question again
+	var back macroSessionResponse
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPost, sessionPath+"/back", nil, &back))
+	assert.True(t, back.Valid)
+	assert.Equal(t, "storage-type", back.Question.ID)
+	question = server.answer(token, sessionPath, "storage-type", "In-Memory (no persistent storage of secrets)")
+	assert.Equal(t, "authentication-type", question.ID)
+
+	question = server.answer(token, sessionPath, "authentication-type", "Certificate")
+	assert.Equal(t, "multi-tenant", question.ID)
+	question = server.answer(token, sessionPath, "multi-tenant", "No")
+	assert.Equal(t, "clients", question.ID)
+	assert.Equal(t, []string{"backup", "db", "web"}, question.PossibleAnswers)
+	question = server.answer(token, sessionPath, "clients", "web", "db")
+	assert.Equal(t, "within-trust-boundary", question.ID)
+	assert.Nil(t, server.answer(token, sessionPath, "within-trust-boundary", "No"))
+
+	var changeImpact struct {
+		Changes []string `json:"changes"`
+		Valid   bool     `json:"valid"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodGet, sessionPath+"/changes", nil, &changeImpact))
+	assert.True(t, changeImpact.Valid)
+	assert.NotEmpty(t, changeImpact.Changes)
+	assert.NotContains(t, server.readModel(token, modelID).TechnicalAssets, "Test Vault") // a dry run only
+
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPost, sessionPath+"/execute", nil, nil))
+	modelInput := server.readModel(token, modelID)
+	assert.Equal(t, "test-vault", modelInput.TechnicalAssets["Test Vault"].ID)
+	assert.Contains(t, modelInput.TechnicalAssets["Web Server"].CommunicationLinks, "Vault Access (web)")
+	var versions []payloadModelVersion
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodGet, "/models/"+modelID+"/history", nil, &versions))
+	reasons := make([]string, 0)
+	for _, version := range versions {
+		reasons = append(reasons, version.Reason)
+	}
+	assert.Contains(t, reasons, "Model Macro add-vault")
+
+	// the session ends with the execution
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodGet, sessionPath, nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodPost, sessionPath+"/execute", nil, nil))
+}
+
+func TestDeleteMacroSession(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createMacroTestModel(server, token)
+	historyLength := func() int {
+		var versions []payloadModelVersion
+		assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodGet, "/models/"+modelID+"/history", nil, &versions))
+		return len(versions)
+	}
+	versions := historyLength()
+	sessionPath, _ := server.createMacroSession(token, modelID, "add-vault")
+	server.answer(token, sessionPath, "vault-name", "Test")
+
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodDelete, sessionPath, nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodGet, sessionPath, nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodDelete, sessionPath, nil, nil))
+	assert.Equal(t, versions, historyLength()) // nothing changed
+}
+
+func TestMacroSessionIsBoundToModelAndUser(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createMacroTestModel(server, token)
+	otherModelID := server.createModel(token)
+	_, editorKey := server.createUser(token, "Editor", map[string]string{modelID: roleEditor, otherModelID: roleEditor})
+	editorToken := server.createToken(editorKey, "")
+	_, otherToken := server.createKey()
+	otherKeysModelID := server.createModel(otherToken)
+
+	sessionPath, _ := server.createMacroSession(token, modelID, "add-vault")
+	sessionID := sessionPath[len("/models/"+modelID+"/macro-sessions/"):]
+	for name, request := range map[string]struct{ token, modelID string }{
+		"another model":                   {token, otherModelID},
+		"another user of the key":         {editorToken, modelID},
+		"another key":                     {otherToken, otherKeysModelID},
+		"another key with the model id":   {otherToken, modelID},
+		"another user with another model": {editorToken, otherModelID},
+	} {
+		path := "/models/" + request.modelID + "/macro-sessions/" + sessionID
+		status := server.withToken(request.token, http.MethodGet, path, nil, nil)
+		assert.Equal(t, http.StatusNotFound, status, name)
+		status = server.withToken(request.token, http.MethodPost, path+"/answer", payloadMacroAnswer{QuestionID: "vault-name", Answer: []string{"Test"}}, nil)
+		assert.Equal(t, http.StatusNotFound, status, name)
+		status = server.withToken(request.token, http.MethodPost, path+"/execute", nil, nil)
+		assert.Equal(t, http.StatusNotFound, status, name)
+		status = server.withToken(request.token, http.MethodDelete, path, nil, nil)
+		assert.Equal(t, http.StatusNotFound, status, name)
+	}
+
+	// the session is still usable by its user
+	var current macroSessionResponse
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodGet, sessionPath, nil, &current))
+	assert.Equal(t, "vault-name", current.Question.ID)
+}
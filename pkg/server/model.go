@@ -15,7 +15,6 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,27 +30,22 @@ import (
 	"golang.org/x/crypto/argon2"
 )
 
-// creates a sub-folder (named by a new UUID) inside the token folder
+// creates a model (identified by a new UUID) for the key
 func (s *server) createNewModel(ginContext *gin.Context) {
 	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
-	if !ok {
+	if !ok || !s.checkKeyOwner(ginContext) {
 		return
 	}
 	ok = s.checkObjectCreationThrottler(ginContext, "MODEL")
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 
 	aUuid := uuid.New().String()
-	err := os.Mkdir(folderNameForModel(folderNameOfKey, aUuid), 0700)
-	if err != nil {
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to create model",
-		})
-		return
-	}
 
 	aYaml := `title: New Threat Model
 threagile_version: ` + docs.ThreagileVersion + `
@@ -77,14 +71,14 @@ trust_boundaries: {}
 shared_runtimes: {}
 individual_risk_categories: {}
 risk_tracking: {}
-diagram_tweak_nodesep: ""
-diagram_tweak_ranksep: ""
+diagram_tweak_nodesep: 0
+diagram_tweak_ranksep: 0
 diagram_tweak_edge_layout: ""
 diagram_tweak_suppress_edge_labels: false
 diagram_tweak_invisible_connections_between_assets: []
 diagram_tweak_same_rank_assets: []`
 
-	ok = s.writeModelYAML(ginContext, aYaml, key, folderNameForModel(folderNameOfKey, aUuid), "New Model Creation", true)
+	ok = s.writeModelYAML(ginContext, aYaml, key, folderNameOfKey, aUuid, "New Model Creation", true)
 	if ok {
 		ginContext.JSON(http.StatusCreated, gin.H{
 			"message": "model created",
@@ -98,6 +92,7 @@ type payloadModels struct {
 	Title             string    `yaml:"title" json:"title"`
 	TimestampCreated  time.Time `yaml:"timestamp_created" json:"timestamp_created"`
 	TimestampModified time.Time `yaml:"timestamp_modified" json:"timestamp_modified"`
+	Role              string    `yaml:"role" json:"role"`
 }
 
 func (s *server) listModels(ginContext *gin.Context) { // TODO currently returns error when any model is no longer valid in syntax, so eventually have some fallback to not just bark on an invalid model...
@@ -105,11 +100,13 @@ func (s *server) listModels(ginContext *gin.Context) { // TODO currently returns
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 
 	result := make([]payloadModels, 0)
-	modelFolders, err := os.ReadDir(folderNameOfKey)
+	storedModels, err := s.storage.ListModels(folderNameOfKey)
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusNotFound, gin.H{
@@ -117,61 +114,47 @@ func (s *server) listModels(ginContext *gin.Context) { // TODO currently returns
 		})
 		return
 	}
-	for _, dirEntry := range modelFolders {
-		if dirEntry.IsDir() {
-			modelStat, err := os.Stat(filepath.Join(folderNameOfKey, dirEntry.Name(), s.config.InputFile))
-			if err != nil {
-				log.Println(err)
-				ginContext.JSON(http.StatusNotFound, gin.H{
-					"error": "unable to list model",
-				})
-				return
-			}
-			aModel, _, ok := s.readModel(ginContext, dirEntry.Name(), key, folderNameOfKey)
-			if !ok {
-				return
-			}
-			fileInfo, err := dirEntry.Info()
-			if err != nil {
-				log.Println(err)
-				ginContext.JSON(http.StatusNotFound, gin.H{
-					"error": "unable to get file info",
-				})
-				return
-			}
-			result = append(result, payloadModels{
-				ID:                dirEntry.Name(),
-				Title:             aModel.Title,
-				TimestampCreated:  fileInfo.ModTime(),
-				TimestampModified: modelStat.ModTime(),
-			})
+	p := principalOf(ginContext)
+	for _, storedModel := range storedModels {
+		role := p.role(storedModel.ID)
+		if len(role) == 0 { // not shared with the user
+			continue
 		}
+		aModel, _, ok := s.readModel(ginContext, storedModel.ID, key, folderNameOfKey)
+		if !ok {
+			return
+		}
+		result = append(result, payloadModels{
+			ID:                storedModel.ID,
+			Title:             aModel.Title,
+			TimestampCreated:  storedModel.Created,
+			TimestampModified: storedModel.Modified,
+			Role:              role,
+		})
 	}
 	ginContext.JSON(http.StatusOK, result)
 }
 
 func (s *server) deleteModel(ginContext *gin.Context) {
-	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, _, ok := s.checkTokenToModel(ginContext, roleOwner)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
-	folder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	modelID, ok := s.checkModel(ginContext, ginContext.Param("model-id"), folderNameOfKey)
 	if ok {
-		if folder != filepath.Clean(folder) {
-			ginContext.JSON(http.StatusInternalServerError, gin.H{
-				"error": "model-id is weird",
-			})
-			return
-		}
-		err := os.RemoveAll(folder)
+		err := s.storage.DeleteModel(folderNameOfKey, modelID)
 		if err != nil {
+			log.Println(err)
 			ginContext.JSON(http.StatusNotFound, gin.H{
 				"error": "model not found",
 			})
 			return
 		}
+		s.audit(ginContext, folderNameOfKey, modelID, "Model Deletion")
 		ginContext.JSON(http.StatusOK, gin.H{
 			"message": "model deleted",
 		})
@@ -185,11 +168,13 @@ type payloadCover struct {
 }
 
 func (s *server) setCover(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -217,11 +202,13 @@ func (s *server) setCover(ginContext *gin.Context) {
 }
 
 func (s *server) getCover(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -241,11 +228,13 @@ type payloadOverview struct {
 }
 
 func (s *server) setOverview(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -279,11 +268,13 @@ func (s *server) setOverview(ginContext *gin.Context) {
 }
 
 func (s *server) getOverview(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -299,11 +290,13 @@ func (s *server) getOverview(ginContext *gin.Context) {
 type payloadAbuseCases map[string]string
 
 func (s *server) setAbuseCases(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -327,11 +320,13 @@ func (s *server) setAbuseCases(ginContext *gin.Context) {
 }
 
 func (s *server) getAbuseCases(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -342,11 +337,13 @@ func (s *server) getAbuseCases(ginContext *gin.Context) {
 type payloadSecurityRequirements map[string]string
 
 func (s *server) setSecurityRequirements(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -370,11 +367,13 @@ func (s *server) setSecurityRequirements(ginContext *gin.Context) {
 }
 
 func (s *server) getSecurityRequirements(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -398,11 +397,13 @@ type payloadDataAsset struct {
 }
 
 func (s *server) getDataAssets(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -411,11 +412,13 @@ func (s *server) getDataAssets(ginContext *gin.Context) {
 }
 
 func (s *server) getDataAsset(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -435,11 +438,13 @@ func (s *server) getDataAsset(ginContext *gin.Context) {
 }
 
 func (s *server) deleteDataAsset(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -538,11 +543,13 @@ func (s *server) deleteDataAsset(ginContext *gin.Context) {
 }
 
 func (s *server) setDataAsset(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -632,11 +639,13 @@ func (s *server) setDataAsset(ginContext *gin.Context) {
 }
 
 func (s *server) createNewDataAsset(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -726,11 +735,13 @@ func (s *server) populateDataAsset(ginContext *gin.Context, payload payloadDataA
 }
 
 func (s *server) getTrustBoundaries(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -747,11 +758,13 @@ type payloadSharedRuntime struct {
 }
 
 func (s *server) setSharedRuntime(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -806,11 +819,13 @@ func (s *server) setSharedRuntime(ginContext *gin.Context) {
 }
 
 func (s *server) getSharedRuntime(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -830,11 +845,13 @@ func (s *server) getSharedRuntime(ginContext *gin.Context) {
 }
 
 func (s *server) createNewSharedRuntime(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -914,11 +931,13 @@ func populateSharedRuntime(_ *gin.Context, payload payloadSharedRuntime) (shared
 }
 
 func (s *server) deleteSharedRuntime(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -959,11 +978,13 @@ func (s *server) deleteSharedRuntime(ginContext *gin.Context) {
 }
 
 func (s *server) getSharedRuntimes(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -972,12 +993,11 @@ func (s *server) getSharedRuntimes(ginContext *gin.Context) {
 }
 
 func (s *server) readModel(ginContext *gin.Context, modelUUID string, key []byte, folderNameOfKey string) (modelInputResult input.Model, yamlText string, ok bool) {
-	modelFolder, ok := s.checkModelFolder(ginContext, modelUUID, folderNameOfKey)
+	modelID, ok := s.checkModel(ginContext, modelUUID, folderNameOfKey)
 	if !ok {
 		return modelInputResult, yamlText, false
 	}
-	cryptoKey := generateKeyFromAlreadyStrongRandomInput(key)
-	block, err := aes.NewCipher(cryptoKey)
+	data, err := s.storage.ReadModel(folderNameOfKey, modelID)
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusInternalServerError, gin.H{
@@ -985,7 +1005,7 @@ func (s *server) readModel(ginContext *gin.Context, modelUUID string, key []byte
 		})
 		return modelInputResult, yamlText, false
 	}
-	aesGcm, err := cipher.NewGCM(block)
+	yamlBytes, err := decryptModel(data, key)
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusInternalServerError, gin.H{
@@ -993,8 +1013,8 @@ func (s *server) readModel(ginContext *gin.Context, modelUUID string, key []byte
 		})
 		return modelInputResult, yamlText, false
 	}
-
-	fileBytes, err := os.ReadFile(filepath.Clean(filepath.Join(modelFolder, s.config.InputFile)))
+	modelInput := new(input.Model).Defaults()
+	err = modelInput.Parse(s.config.InputFile, yamlBytes)
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusInternalServerError, gin.H{
@@ -1002,43 +1022,40 @@ func (s *server) readModel(ginContext *gin.Context, modelUUID string, key []byte
 		})
 		return modelInputResult, yamlText, false
 	}
+	return *modelInput, string(yamlBytes), true
+}
 
-	nonce := fileBytes[0:12]
-	ciphertext := fileBytes[12:]
+// decryptModel returns the YAML of an encrypted model, i.e. the model itself or one of its history backups
+func decryptModel(data []byte, key []byte) ([]byte, error) {
+	cryptoKey := generateKeyFromAlreadyStrongRandomInput(key)
+	block, err := aes.NewCipher(cryptoKey)
+	if err != nil {
+		return nil, err
+	}
+	aesGcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 {
+		return nil, fmt.Errorf("invalid encrypted model")
+	}
+	nonce := data[0:12]
+	ciphertext := data[12:]
 	plaintext, err := aesGcm.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
+		return nil, err
 	}
-
 	r, err := gzip.NewReader(bytes.NewReader(plaintext))
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
+		return nil, err
 	}
 	buf := new(bytes.Buffer)
 	_, _ = buf.ReadFrom(r)
-	modelInput := new(input.Model).Defaults()
-	yamlBytes := buf.Bytes()
-	err = yaml.Unmarshal(yamlBytes, &modelInput)
-	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
-	}
-	return *modelInput, string(yamlBytes), true
+	return buf.Bytes(), nil
 }
 
 func (s *server) writeModel(ginContext *gin.Context, key []byte, folderNameOfKey string, modelInput *input.Model, changeReasonForHistory string) (ok bool) {
-	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	modelID, ok := s.checkModel(ginContext, ginContext.Param("model-id"), folderNameOfKey)
 	if ok {
 		modelInput.ThreagileVersion = docs.ThreagileVersion
 		yamlBytes, err := yaml.Marshal(modelInput)
@@ -1052,35 +1069,41 @@ func (s *server) writeModel(ginContext *gin.Context, key []byte, folderNameOfKey
 		/*
 			yamlBytes = model.ReformatYAML(yamlBytes)
 		*/
-		return s.writeModelYAML(ginContext, string(yamlBytes), key, modelFolder, changeReasonForHistory, false)
+		return s.writeModelYAML(ginContext, string(yamlBytes), key, folderNameOfKey, modelID, changeReasonForHistory, false)
 	}
 	return false
 }
 
-func (s *server) checkModelFolder(ginContext *gin.Context, modelUUID string, folderNameOfKey string) (modelFolder string, ok bool) {
+// checkModel returns the normalized id of the model, when the model exists for the key
+func (s *server) checkModel(ginContext *gin.Context, modelUUID string, folderNameOfKey string) (modelID string, ok bool) {
 	uuidParsed, err := uuid.Parse(modelUUID)
 	if err != nil {
 		ginContext.JSON(http.StatusNotFound, gin.H{
 			"error": "model not found",
 		})
-		return modelFolder, false
+		return modelID, false
 	}
-	modelFolder = folderNameForModel(folderNameOfKey, uuidParsed.String())
-	if _, err := os.Stat(modelFolder); os.IsNotExist(err) {
+	modelID = uuidParsed.String()
+	if exists, err := s.storage.ModelExists(folderNameOfKey, modelID); !exists {
+		if err != nil {
+			log.Println(err)
+		}
 		ginContext.JSON(http.StatusNotFound, gin.H{
 			"error": "model not found",
 		})
-		return modelFolder, false
+		return modelID, false
 	}
-	return modelFolder, true
+	return modelID, true
 }
 
 func (s *server) getModel(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 	_, yamlText, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
 	if ok {
@@ -1104,21 +1127,25 @@ func (s *server) getModel(ginContext *gin.Context) {
 
 // fully replaces threagile.yaml in sub-folder given by UUID
 func (s *server) importModel(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer s.unlockFolder(folderNameOfKey)
 
-	aUuid := ginContext.Param("model-id") // UUID is syntactically validated in readModel+checkModelFolder (next line) via uuid.Parse(modelUUID)
-	_, _, ok = s.readModel(ginContext, aUuid, key, folderNameOfKey)
+	aUuid, ok := s.checkModel(ginContext, ginContext.Param("model-id"), folderNameOfKey) // UUID is syntactically validated via uuid.Parse(modelUUID)
+	if ok {
+		_, _, ok = s.readModel(ginContext, aUuid, key, folderNameOfKey)
+	}
 	if ok {
 		// first analyze it simply by executing the full risk process (just discard the result) to ensure that everything would work
 		yamlContent, ok := s.execute(ginContext, true)
 		if ok {
 			// if we're here, then no problem was raised, so ok to proceed
-			ok = s.writeModelYAML(ginContext, string(yamlContent), key, folderNameForModel(folderNameOfKey, aUuid), "Model Import", false)
+			ok = s.writeModelYAML(ginContext, string(yamlContent), key, folderNameOfKey, aUuid, "Model Import", false)
 			if ok {
 				ginContext.JSON(http.StatusCreated, gin.H{
 					"message": "model imported",
@@ -1129,11 +1156,13 @@ func (s *server) importModel(ginContext *gin.Context) {
 }
 
 func (s *server) analyzeModelOnServerDirectly(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer func() {
 		s.unlockFolder(folderNameOfKey)
 		var err error
@@ -1218,9 +1247,9 @@ func (s *server) analyzeModelOnServerDirectly(ginContext *gin.Context) {
 	ginContext.FileAttachment(tmpResultFile.Name(), "threagile-result.zip")
 }
 
-func (s *server) writeModelYAML(ginContext *gin.Context, yaml string, key []byte, modelFolder string, changeReasonForHistory string, skipBackup bool) (ok bool) {
+func (s *server) writeModelYAML(ginContext *gin.Context, yaml string, key []byte, folderNameOfKey string, modelID string, changeReasonForHistory string, skipBackup bool) (ok bool) {
 	if s.config.Verbose {
-		fmt.Println("about to write " + strconv.Itoa(len(yaml)) + " bytes of yaml into model folder: " + modelFolder)
+		fmt.Println("about to write " + strconv.Itoa(len(yaml)) + " bytes of yaml into model: " + modelID)
 	}
 	var b bytes.Buffer
 	w := gzip.NewWriter(&b)
@@ -1255,7 +1284,7 @@ func (s *server) writeModelYAML(ginContext *gin.Context, yaml string, key []byte
 	}
 	ciphertext := aesGcm.Seal(nil, nonce, plaintext, nil)
 	if !skipBackup {
-		err = s.backupModelToHistory(modelFolder, changeReasonForHistory)
+		err = s.backupModelToHistory(folderNameOfKey, modelID, changeReasonForHistory)
 		if err != nil {
 			log.Println(err)
 			ginContext.JSON(http.StatusInternalServerError, gin.H{
@@ -1264,7 +1293,7 @@ func (s *server) writeModelYAML(ginContext *gin.Context, yaml string, key []byte
 			return false
 		}
 	}
-	f, err := os.Create(filepath.Clean(filepath.Join(modelFolder, s.config.InputFile)))
+	err = s.storage.WriteModel(folderNameOfKey, StoredModel{ID: modelID}, append(nonce, ciphertext...))
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusInternalServerError, gin.H{
@@ -1272,75 +1301,54 @@ func (s *server) writeModelYAML(ginContext *gin.Context, yaml string, key []byte
 		})
 		return false
 	}
-	_, _ = f.Write(nonce)
-	_, _ = f.Write(ciphertext)
-	_ = f.Close()
+	s.audit(ginContext, folderNameOfKey, modelID, changeReasonForHistory)
 	return true
 }
 
-func (s *server) lockFolder(folderName string) {
+// lockFolder locks the folder against other requests, also those of other server processes sharing the same storage,
+// and responds with an error when the lock of the storage cannot be acquired
+func (s *server) lockFolder(ginContext *gin.Context, folderName string) (ok bool) {
 	s.globalLock.Lock()
-	defer s.globalLock.Unlock()
 	_, exists := s.locksByFolderName[folderName]
 	if !exists {
 		s.locksByFolderName[folderName] = &sync.Mutex{}
 	}
 	s.locksByFolderName[folderName].Lock()
+	s.globalLock.Unlock()
+	// also lock against other server processes sharing the same storage (without blocking other folders meanwhile)
+	if err := s.storage.Lock(folderName); err != nil {
+		log.Println(err)
+		s.releaseFolder(folderName)
+		ginContext.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "unable to lock storage",
+		})
+		return false
+	}
+	return true
 }
 
 func (s *server) unlockFolder(folderName string) {
+	if err := s.storage.Unlock(folderName); err != nil {
+		log.Println(err)
+	}
+	s.releaseFolder(folderName)
+}
+
+func (s *server) releaseFolder(folderName string) {
 	if _, exists := s.locksByFolderName[folderName]; exists {
 		s.locksByFolderName[folderName].Unlock()
 		delete(s.locksByFolderName, folderName)
 	}
 }
 
-func (s *server) backupModelToHistory(modelFolder string, changeReasonForHistory string) (err error) {
-	historyFolder := filepath.Join(modelFolder, "history")
-	if _, err := os.Stat(historyFolder); os.IsNotExist(err) {
-		err = os.Mkdir(historyFolder, 0700)
-		if err != nil {
-			return err
-		}
-	}
-	inputModel, err := os.ReadFile(filepath.Clean(filepath.Join(modelFolder, s.config.InputFile)))
-	if err != nil {
-		return err
-	}
-	historyFile := filepath.Join(historyFolder, time.Now().Format("2006-01-02 15:04:05")+" "+changeReasonForHistory+".backup")
-	err = os.WriteFile(historyFile, inputModel, 0400)
-	if err != nil {
-		return err
-	}
-	// now delete any old files if over limit to keep
-	files, err := os.ReadDir(historyFolder)
+func (s *server) backupModelToHistory(folderNameOfKey string, modelID string, changeReasonForHistory string) (err error) {
+	inputModel, err := s.storage.ReadModel(folderNameOfKey, modelID)
 	if err != nil {
 		return err
 	}
-	if len(files) > s.config.BackupHistoryFilesToKeep {
-		requiredToDelete := len(files) - s.config.BackupHistoryFilesToKeep
-		sort.Slice(files, func(i, j int) bool {
-			return files[i].Name() < files[j].Name()
-		})
-		for _, file := range files {
-			requiredToDelete--
-			if file.Name() != filepath.Clean(file.Name()) {
-				return fmt.Errorf("weird file name %v", file.Name())
-			}
-			err = os.Remove(filepath.Join(historyFolder, file.Name()))
-			if err != nil {
-				return err
-			}
-			if requiredToDelete <= 0 {
-				break
-			}
-		}
-	}
-	return
-}
-
-func folderNameForModel(folderNameOfKey string, uuid string) string {
-	return filepath.Join(folderNameOfKey, uuid)
+	versionID := time.Now().Format(historyFileTimestampFormat) + " " + changeReasonForHistory
+	// any old entries over the limit to keep are deleted by the storage
+	return s.storage.AddHistory(folderNameOfKey, modelID, versionID, inputModel, s.config.BackupHistoryFilesToKeep)
 }
 
 type argon2Params struct {
@@ -60,11 +60,13 @@ func (s *server) streamStatsJSON(ginContext *gin.Context) {
 }
 
 func (s *server) streamResponse(ginContext *gin.Context, responseType responseType) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
 	if !ok {
 		return
 	}
-	s.lockFolder(folderNameOfKey)
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
 	defer func() {
 		s.unlockFolder(folderNameOfKey)
 		var err error
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type payloadRiskTracking struct {
+	Status        string `yaml:"status" json:"status"`
+	Justification string `yaml:"justification" json:"justification"`
+	Ticket        string `yaml:"ticket" json:"ticket"`
+	Date          string `yaml:"date" json:"date"`
+	CheckedBy     string `yaml:"checked_by" json:"checked_by"`
+	Owner         string `yaml:"owner" json:"owner"`
+	Expires       string `yaml:"expires" json:"expires"`
+	ReviewBy      string `yaml:"review_by" json:"review_by"`
+}
+
+func (s *server) getRiskTrackings(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		ginContext.JSON(http.StatusOK, modelInput.RiskTracking)
+	}
+}
+
+func (s *server) getRiskTracking(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		syntheticRiskId := ginContext.Param("synthetic-id")
+		riskTracking, exists := modelInput.RiskTracking[syntheticRiskId]
+		if !exists {
+			ginContext.JSON(http.StatusNotFound, gin.H{
+				"error": "risk tracking not found",
+			})
+			return
+		}
+		ginContext.JSON(http.StatusOK, gin.H{
+			syntheticRiskId: riskTracking,
+		})
+	}
+}
+
+// sets the risk tracking of a single risk, which must be one of the risks currently generated for the model
+func (s *server) setRiskTracking(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		payload := payloadRiskTracking{}
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		syntheticRiskId := strings.TrimSpace(ginContext.Param("synthetic-id"))
+		if strings.Contains(syntheticRiskId, "*") {
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "wildcard risk ids are only supported by the bulk risk tracking update",
+			})
+			return
+		}
+		riskTrackingInput, ok := populateRiskTracking(ginContext, payload)
+		if !ok {
+			return
+		}
+		parsedModel, ok := s.analyzeModelForRiskTracking(ginContext, modelInput)
+		if !ok {
+			return
+		}
+		if _, exists := parsedModel.GeneratedRisksBySyntheticId[syntheticRiskId]; !exists {
+			ginContext.JSON(http.StatusNotFound, gin.H{
+				"error": "risk not found",
+			})
+			return
+		}
+		if modelInput.RiskTracking == nil {
+			modelInput.RiskTracking = make(map[string]input.RiskTracking)
+		}
+		modelInput.RiskTracking[syntheticRiskId] = riskTrackingInput
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Risk Tracking Update")
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "risk tracking updated",
+				"id":      syntheticRiskId,
+			})
+		}
+	}
+}
+
+// sets the risk tracking of several risks at once, keyed by synthetic risk ids which may contain wildcards ("*"),
+// each of them must match at least one of the risks currently generated for the model
+func (s *server) setRiskTrackings(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		payload := make(map[string]payloadRiskTracking)
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		riskTrackingInputs := make(map[string]input.RiskTracking)
+		for syntheticRiskId, riskTracking := range payload {
+			riskTrackingInput, ok := populateRiskTracking(ginContext, riskTracking)
+			if !ok {
+				return
+			}
+			riskTrackingInputs[strings.TrimSpace(syntheticRiskId)] = riskTrackingInput
+		}
+		parsedModel, ok := s.analyzeModelForRiskTracking(ginContext, modelInput)
+		if !ok {
+			return
+		}
+		matchingRisks := make(map[string][]string)
+		for syntheticRiskId := range riskTrackingInputs {
+			matchingRisks[syntheticRiskId] = parsedModel.MatchingSyntheticRiskIds(syntheticRiskId)
+			if len(matchingRisks[syntheticRiskId]) == 0 {
+				ginContext.JSON(http.StatusBadRequest, gin.H{
+					"error": "risk id does not match any risk: " + syntheticRiskId,
+				})
+				return
+			}
+		}
+		if modelInput.RiskTracking == nil {
+			modelInput.RiskTracking = make(map[string]input.RiskTracking)
+		}
+		for syntheticRiskId, riskTrackingInput := range riskTrackingInputs {
+			modelInput.RiskTracking[syntheticRiskId] = riskTrackingInput
+		}
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Risk Tracking Bulk Update")
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message":        "risk tracking updated",
+				"matching_risks": matchingRisks, // the risks each (wildcard) risk id currently applies to
+			})
+		}
+	}
+}
+
+// deleting is possible for any risk tracking, also those no longer matching a generated risk (orphaned ones)
+func (s *server) deleteRiskTracking(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		syntheticRiskId := ginContext.Param("synthetic-id")
+		if _, exists := modelInput.RiskTracking[syntheticRiskId]; !exists {
+			ginContext.JSON(http.StatusNotFound, gin.H{
+				"error": "risk tracking not found",
+			})
+			return
+		}
+		delete(modelInput.RiskTracking, syntheticRiskId)
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Risk Tracking Deletion")
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "risk tracking deleted",
+				"id":      syntheticRiskId,
+			})
+		}
+	}
+}
+
+// analyzes the model as stored, orphaned risk trackings are ignored here so that they don't prevent triaging the other risks
+func (s *server) analyzeModelForRiskTracking(ginContext *gin.Context, modelInput input.Model) (parsedModel *types.ParsedModel, ok bool) {
+	config := *s.config
+	config.IgnoreOrphanedRiskTracking = true
+	result, err := model.AnalyzeModelWithRules(config, &modelInput, s.customRiskRules, s.progressReporter())
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return nil, false
+	}
+	return result.ParsedModel, true
+}
+
+func populateRiskTracking(ginContext *gin.Context, payload payloadRiskTracking) (riskTrackingInput input.RiskTracking, ok bool) {
+	status, err := types.ParseRiskStatus(payload.Status)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return riskTrackingInput, false
+	}
+	for _, date := range []struct{ name, value string }{{"date", payload.Date}, {"expires", payload.Expires}, {"review_by", payload.ReviewBy}} {
+		if len(date.value) > 0 {
+			if _, err := time.Parse("2006-01-02", date.value); err != nil {
+				ginContext.JSON(http.StatusBadRequest, gin.H{
+					"error": "unable to parse '" + date.name + "' of risk tracking (expected YYYY-MM-DD): " + date.value,
+				})
+				return riskTrackingInput, false
+			}
+		}
+	}
+	riskTrackingInput = input.RiskTracking{
+		Status:        status.String(),
+		Justification: payload.Justification,
+		Ticket:        payload.Ticket,
+		Date:          payload.Date,
+		CheckedBy:     payload.CheckedBy,
+		Owner:         strings.TrimSpace(payload.Owner),
+		Expires:       payload.Expires,
+		ReviewBy:      payload.ReviewBy,
+	}
+	return riskTrackingInput, true
+}
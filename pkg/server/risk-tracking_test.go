@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetRiskTrackingsMatchesWholeRiskIds(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createTechnicalAssetTestModel(server, token)
+	path := "/models/" + modelID + "/risk-tracking"
+
+	var response struct {
+		MatchingRisks map[string][]string `json:"matching_risks"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPut, path, map[string]payloadRiskTracking{
+		"missing-vault@backup":          {Status: "accepted"},
+		"unnecessary-technical-asset@*": {Status: "false-positive"},
+	}, &response))
+	assert.Equal(t, map[string][]string{
+		"missing-vault@backup": {"missing-vault@backup"},
+		"unnecessary-technical-asset@*": {
+			"unnecessary-technical-asset@backup",
+			"unnecessary-technical-asset@db",
+			"unnecessary-technical-asset@web",
+		},
+	}, response.MatchingRisks)
+	modelInput := server.readModel(token, modelID)
+	assert.Equal(t, "accepted", modelInput.RiskTracking["missing-vault@backup"].Status)
+	assert.Equal(t, "false-positive", modelInput.RiskTracking["unnecessary-technical-asset@*"].Status)
+
+	for _, syntheticRiskId := range []string{
+		"missing-vault@back",                // a prefix of a risk id only
+		"vault@backup",                      // a suffix of a risk id only
+		"missing-vault@backup@web",          // longer than the risk id
+		"missing-vault@b.ckup",              // no regular expression
+		"cross-site-scripting@*@*",          // a wildcard for each part of the risk id only
+		"sql-nosql-injection@*@db",          // no partial wildcard match either
+		"unnecessary-communication-link@db", // nor a partial match without wildcard
+	} {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		assert.Equal(t, http.StatusBadRequest, server.withToken(token, http.MethodPut, path, map[string]payloadRiskTracking{
+			syntheticRiskId: {Status: "accepted"},
+		}, &failure), syntheticRiskId)
+		assert.Equal(t, "risk id does not match any risk: "+syntheticRiskId, failure.Error)
+	}
+	assert.NotContains(t, server.readModel(token, modelID).RiskTracking, "missing-vault@back")
+}
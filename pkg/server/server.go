@@ -5,25 +5,36 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/macros"
 	"github.com/threagile/threagile/pkg/model"
 
 	"github.com/gin-gonic/gin"
 	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/schema"
 	"github.com/threagile/threagile/pkg/security/risks"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
+// serverShutdownTimeout bounds waiting for running requests on shutdown, before the plugins and the storage are closed
+const serverShutdownTimeout = 30 * time.Second
+
 type server struct {
 	config                         *common.Config
 	successCount                   int
@@ -31,22 +42,24 @@ type server struct {
 	globalLock                     sync.Mutex
 	throttlerLock                  sync.Mutex
 	createdObjectsThrottler        map[string][]int64
-	mapTokenHashToTimeoutStruct    map[string]timeoutStruct
-	mapFolderNameToTokenHash       map[string]string
+	storage                        Storage
 	extremeShortTimeoutsForTesting bool
 	locksByFolderName              map[string]*sync.Mutex
 	customRiskRules                map[string]*model.CustomRisk
+	customMacros                   []macros.Macros // loaded once, each macro session gets its own instance
+	macroSessionsLock              sync.Mutex
+	macroSessions                  map[string]*macroSession
 }
 
 func RunServer(config *common.Config) {
-	s := &server{
-		config:                         config,
-		createdObjectsThrottler:        make(map[string][]int64),
-		mapTokenHashToTimeoutStruct:    make(map[string]timeoutStruct),
-		mapFolderNameToTokenHash:       make(map[string]string),
-		extremeShortTimeoutsForTesting: false,
-		locksByFolderName:              make(map[string]*sync.Mutex),
+	storage, err := OpenStorage(config.ServerStorage, config)
+	if err != nil {
+		log.Fatalf("unable to open server storage: %v", err)
 	}
+	defer func() { _ = storage.Close() }()
+	shutdown, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	s := newServer(config, storage)
 	router := gin.Default()
 	router.LoadHTMLGlob(filepath.Join(s.config.ServerFolder, "s", "static", "*.html")) // <==
 	router.GET("/", func(c *gin.Context) {
@@ -64,7 +77,7 @@ func RunServer(config *common.Config) {
 	router.StaticFile("/android-chrome-512x512.png", filepath.Join(s.config.ServerFolder, "s", "static", "android-chrome-512x512.png"))
 	router.StaticFile("/android-chrome-192x192.png", filepath.Join(s.config.ServerFolder, "s", "static", "android-chrome-192x192.png"))
 
-	router.StaticFile("/schema.json", filepath.Join(s.config.AppFolder, "schema.json"))
+	router.GET("/schema.json", s.schemaJSON)
 	router.StaticFile("/live-templates.txt", filepath.Join(s.config.AppFolder, "live-templates.txt"))
 	router.StaticFile("/openapi.yaml", filepath.Join(s.config.AppFolder, "openapi.yaml"))
 	router.StaticFile("/swagger-ui/", filepath.Join(s.config.ServerFolder, "s", "static", "swagger-ui/index.html"))
@@ -77,7 +90,41 @@ func RunServer(config *common.Config) {
 
 	router.GET("/threagile-example-model.yaml", s.exampleFile)
 	router.GET("/threagile-stub-model.yaml", s.stubFile)
+	s.addAPIRoutes(router)
 
+	reporter := common.DefaultProgressReporter{Verbose: s.config.Verbose}
+	s.customRiskRules = model.LoadCustomRiskRules(s.config.RiskRulesPlugins, time.Duration(s.config.RiskRulesPluginTimeout)*time.Second, reporter)
+	defer model.CloseCustomRiskRules(s.customRiskRules, reporter) // the plugins keep running for all analyses until shutdown
+	s.customMacros = macros.ListCustomMacros(s.config.ModelMacroPlugins, reporter)
+
+	fmt.Println("Threagile s running...")
+	httpServer := &http.Server{Addr: ":" + strconv.Itoa(s.config.ServerPort), Handler: router, ReadHeaderTimeout: time.Minute} // listen and serve on 0.0.0.0:8080 or whatever port was specified
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("unable to serve: %v", err)
+			stop()
+		}
+	}()
+	<-shutdown.Done()
+	fmt.Println("Threagile s shutting down...")
+	shutdownContext, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancel()
+	_ = httpServer.Shutdown(shutdownContext)
+}
+
+func newServer(config *common.Config, storage Storage) *server {
+	return &server{
+		config:                         config,
+		createdObjectsThrottler:        make(map[string][]int64),
+		extremeShortTimeoutsForTesting: false,
+		storage:                        storage,
+		locksByFolderName:              make(map[string]*sync.Mutex),
+		macroSessions:                  make(map[string]*macroSession),
+	}
+}
+
+// addAPIRoutes adds the routes of the REST API, i.e. all routes not serving static files
+func (s *server) addAPIRoutes(router *gin.Engine) {
 	router.GET("/meta/ping", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"message": "pong",
@@ -116,7 +163,7 @@ func RunServer(config *common.Config) {
 	})
 
 	// TODO router.GET("/meta/risk-rules", listRiskRules)
-	// TODO router.GET("/meta/model-macros", listModelMacros)
+	router.GET("/meta/model-macros", s.listModelMacros)
 
 	router.GET("/meta/stats", s.stats)
 
@@ -128,6 +175,12 @@ func RunServer(config *common.Config) {
 	router.DELETE("/auth/keys", s.deleteKey)
 	router.POST("/auth/tokens", s.createToken)
 	router.DELETE("/auth/tokens", s.deleteToken)
+	router.POST("/auth/users", s.createUser)
+	router.GET("/auth/users", s.listUsers)
+	router.GET("/auth/users/:user-id", s.getUser)
+	router.PUT("/auth/users/:user-id", s.setUser)
+	router.DELETE("/auth/users/:user-id", s.deleteUser)
+	router.GET("/auth/audit-log", s.getAuditLog)
 
 	router.POST("/models", s.createNewModel)
 	router.GET("/models", s.listModels)
@@ -140,9 +193,27 @@ func RunServer(config *common.Config) {
 	router.GET("/models/:model-id/risks-excel", s.streamRisksExcel)
 	router.GET("/models/:model-id/tags-excel", s.streamTagsExcel)
 	router.GET("/models/:model-id/risks", s.streamRisksJSON)
+	router.GET("/models/:model-id/risks/:synthetic-id/tracking", s.getRiskTracking)
+	router.PUT("/models/:model-id/risks/:synthetic-id/tracking", s.setRiskTracking)
+	router.DELETE("/models/:model-id/risks/:synthetic-id/tracking", s.deleteRiskTracking)
+	router.GET("/models/:model-id/risk-tracking", s.getRiskTrackings)
+	router.PUT("/models/:model-id/risk-tracking", s.setRiskTrackings)
 	router.GET("/models/:model-id/technical-assets", s.streamTechnicalAssetsJSON)
 	router.GET("/models/:model-id/stats", s.streamStatsJSON)
 	router.GET("/models/:model-id/analysis", s.analyzeModelOnServerDirectly)
+	router.GET("/models/:model-id/history", s.getModelHistory)
+	router.GET("/models/:model-id/history/:version-id", s.getModelVersion)
+	router.GET("/models/:model-id/history/:version-id/diff", s.diffModelVersions)
+	router.POST("/models/:model-id/history/:version-id/rollback", s.rollbackModel)
+	router.GET("/models/:model-id/audit-log", s.getModelAuditLog)
+
+	router.POST("/models/:model-id/macro-sessions", s.createMacroSession)
+	router.GET("/models/:model-id/macro-sessions/:session-id", s.getMacroSession)
+	router.DELETE("/models/:model-id/macro-sessions/:session-id", s.deleteMacroSession)
+	router.POST("/models/:model-id/macro-sessions/:session-id/answer", s.answerMacroQuestion)
+	router.POST("/models/:model-id/macro-sessions/:session-id/back", s.goBackInMacroSession)
+	router.GET("/models/:model-id/macro-sessions/:session-id/changes", s.getMacroChangeImpact)
+	router.POST("/models/:model-id/macro-sessions/:session-id/execute", s.executeMacroSession)
 
 	router.GET("/models/:model-id/cover", s.getCover)
 	router.PUT("/models/:model-id/cover", s.setCover)
@@ -163,23 +234,33 @@ func RunServer(config *common.Config) {
 	router.PUT("/models/:model-id/data-assets/:data-asset-id", s.setDataAsset)
 	router.DELETE("/models/:model-id/data-assets/:data-asset-id", s.deleteDataAsset)
 
+	router.POST("/models/:model-id/technical-assets", s.createNewTechnicalAsset)
+	router.GET("/models/:model-id/technical-assets/:technical-asset-id", s.getTechnicalAsset)
+	router.PUT("/models/:model-id/technical-assets/:technical-asset-id", s.setTechnicalAsset)
+	router.DELETE("/models/:model-id/technical-assets/:technical-asset-id", s.deleteTechnicalAsset)
+
+	router.GET("/models/:model-id/technical-assets/:technical-asset-id/communication-links", s.getCommunicationLinks)
+	router.POST("/models/:model-id/technical-assets/:technical-asset-id/communication-links", s.createNewCommunicationLink)
+	router.GET("/models/:model-id/technical-assets/:technical-asset-id/communication-links/:communication-link-id", s.getCommunicationLink)
+	router.PUT("/models/:model-id/technical-assets/:technical-asset-id/communication-links/:communication-link-id", s.setCommunicationLink)
+	router.DELETE("/models/:model-id/technical-assets/:technical-asset-id/communication-links/:communication-link-id", s.deleteCommunicationLink)
+
 	router.GET("/models/:model-id/trust-boundaries", s.getTrustBoundaries)
-	//	router.POST("/models/:model-id/trust-boundaries", createNewTrustBoundary)
-	//	router.GET("/models/:model-id/trust-boundaries/:trust-boundary-id", getTrustBoundary)
-	//	router.PUT("/models/:model-id/trust-boundaries/:trust-boundary-id", setTrustBoundary)
-	//	router.DELETE("/models/:model-id/trust-boundaries/:trust-boundary-id", deleteTrustBoundary)
+	router.POST("/models/:model-id/trust-boundaries", s.createNewTrustBoundary)
+	router.GET("/models/:model-id/trust-boundaries/:trust-boundary-id", s.getTrustBoundary)
+	router.PUT("/models/:model-id/trust-boundaries/:trust-boundary-id", s.setTrustBoundary)
+	router.DELETE("/models/:model-id/trust-boundaries/:trust-boundary-id", s.deleteTrustBoundary)
 
 	router.GET("/models/:model-id/shared-runtimes", s.getSharedRuntimes)
 	router.POST("/models/:model-id/shared-runtimes", s.createNewSharedRuntime)
 	router.GET("/models/:model-id/shared-runtimes/:shared-runtime-id", s.getSharedRuntime)
 	router.PUT("/models/:model-id/shared-runtimes/:shared-runtime-id", s.setSharedRuntime)
 	router.DELETE("/models/:model-id/shared-runtimes/:shared-runtime-id", s.deleteSharedRuntime)
+}
 
-	reporter := common.DefaultProgressReporter{Verbose: s.config.Verbose}
-	s.customRiskRules = model.LoadCustomRiskRules(s.config.RiskRulesPlugins, reporter)
-
-	fmt.Println("Threagile s running...")
-	_ = router.Run(":" + strconv.Itoa(s.config.ServerPort)) // listen and serve on 0.0.0.0:8080 or whatever port was specified
+// progressReporter reports to the log of the server while handling a request: errors fail the request, not the server
+func (s *server) progressReporter() common.DefaultProgressReporter {
+	return common.DefaultProgressReporter{Verbose: s.config.Verbose, SuppressError: true}
 }
 
 func (s *server) exampleFile(ginContext *gin.Context) {
@@ -250,7 +331,7 @@ func arrayOfStringValues(values []types.TypeEnum) []string {
 
 func (s *server) stats(ginContext *gin.Context) {
 	keyCount, modelCount := 0, 0
-	keyFolders, err := os.ReadDir(filepath.Join(s.config.ServerFolder, s.config.KeyFolder))
+	keyHashes, err := s.storage.ListKeys()
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusInternalServerError, gin.H{
@@ -258,29 +339,17 @@ func (s *server) stats(ginContext *gin.Context) {
 		})
 		return
 	}
-	for _, keyFolder := range keyFolders {
-		if len(keyFolder.Name()) == 128 { // it's a sha512 token hash probably, so count it as token folder for the stats
-			keyCount++
-			if keyFolder.Name() != filepath.Clean(keyFolder.Name()) {
-				ginContext.JSON(http.StatusInternalServerError, gin.H{
-					"error": "weird file path",
-				})
-				return
-			}
-			modelFolders, err := os.ReadDir(filepath.Join(s.config.ServerFolder, s.config.KeyFolder, keyFolder.Name()))
-			if err != nil {
-				log.Println(err)
-				ginContext.JSON(http.StatusInternalServerError, gin.H{
-					"error": "unable to collect stats",
-				})
-				return
-			}
-			for _, modelFolder := range modelFolders {
-				if len(modelFolder.Name()) == 36 { // it's a uuid model folder probably, so count it as model folder for the stats
-					modelCount++
-				}
-			}
+	for _, keyHash := range keyHashes {
+		keyCount++
+		models, err := s.storage.ListModels(keyHash)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusInternalServerError, gin.H{
+				"error": "unable to collect stats",
+			})
+			return
 		}
+		modelCount += len(models)
 	}
 	// TODO collect and deliver more stats (old model count?) and health info
 	ginContext.JSON(http.StatusOK, gin.H{
@@ -291,9 +360,30 @@ func (s *server) stats(ginContext *gin.Context) {
 	})
 }
 
+// schemaJSON delivers the schema of model files, generated from the model input types
+func (s *server) schemaJSON(ginContext *gin.Context) {
+	data, err := schema.JSON()
+	if err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "unable to create schema",
+		})
+		return
+	}
+	ginContext.Data(http.StatusOK, "application/json", data)
+}
+
 func handleErrorInServiceCall(err error, ginContext *gin.Context) {
 	log.Println(err)
-	ginContext.JSON(http.StatusBadRequest, gin.H{
+	response := gin.H{
 		"error": strings.TrimSpace(err.Error()),
-	})
+	}
+	if errs := input.SplitErrors(err); len(errs) > 1 { // e.g. all errors found while parsing a model, with their positions
+		messages := make([]string, 0)
+		for _, e := range errs {
+			messages = append(messages, strings.TrimSpace(e.Error()))
+		}
+		response["errors"] = messages
+	}
+	ginContext.JSON(http.StatusBadRequest, response)
 }
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+// testServer serves the REST API of a server with a filesystem storage in a temporary folder
+type testServer struct {
+	t      *testing.T
+	server *server
+	router *gin.Engine
+}
+
+func newTestServer(t *testing.T) *testServer {
+	gin.SetMode(gin.TestMode)
+	config := new(common.Config).Defaults("")
+	config.ServerFolder = t.TempDir()
+	storage, err := newFilesystemStorage(config.ServerFolder, config.KeyFolder, config.InputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newServer(config, storage)
+	router := gin.New()
+	s.addAPIRoutes(router)
+	return &testServer{t: t, server: s, router: router}
+}
+
+// request sends the body (a string as is, anything else as JSON) with the given headers and unmarshals the JSON
+// response into the result (unless nil), returning the status code
+func (what *testServer) request(method string, path string, headers map[string]string, body any, result any) int {
+	var reader io.Reader
+	switch value := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewBufferString(value)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			what.t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	request := httptest.NewRequest(method, path, reader)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	recorder := httptest.NewRecorder()
+	what.router.ServeHTTP(recorder, request)
+	if result != nil {
+		assert.NoError(what.t, json.Unmarshal(recorder.Body.Bytes(), result), recorder.Body.String())
+	}
+	return recorder.Code
+}
+
+// withToken sends the request with the token
+func (what *testServer) withToken(token string, method string, path string, body any, result any) int {
+	return what.request(method, path, map[string]string{"token": token}, body, result)
+}
+
+// createKey returns a new key and a token of its owner
+func (what *testServer) createKey() (key string, token string) {
+	var created struct {
+		Key string `json:"key"`
+	}
+	assert.Equal(what.t, http.StatusCreated, what.request(http.MethodPost, "/auth/keys", nil, nil, &created))
+	return created.Key, what.createToken(created.Key, "")
+}
+
+// createToken returns a token of the key or user key, optionally limited to the role
+func (what *testServer) createToken(key string, role string) string {
+	path := "/auth/tokens"
+	if len(role) > 0 {
+		path += "?role=" + role
+	}
+	var created struct {
+		Token string `json:"token"`
+	}
+	assert.Equal(what.t, http.StatusCreated, what.request(http.MethodPost, path, map[string]string{"key": key}, nil, &created))
+	return created.Token
+}
+
+// createModel creates a new model and returns its id
+func (what *testServer) createModel(token string) string {
+	var created struct {
+		ID string `json:"id"`
+	}
+	assert.Equal(what.t, http.StatusCreated, what.withToken(token, http.MethodPost, "/models", nil, &created))
+	return created.ID
+}
+
+// writeModel replaces the model (as the import would, but without analyzing it in a separate process)
+func (what *testServer) writeModel(token string, modelID string, modelInput *input.Model) {
+	folderNameOfKey, key := what.keyOfToken(token)
+	data, err := yaml.Marshal(modelInput)
+	if err != nil {
+		what.t.Fatal(err)
+	}
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.True(what.t, what.server.writeModelYAML(ginContext, string(data), key, folderNameOfKey, modelID, "Model Import", false))
+}
+
+// readModel reads the model as stored
+func (what *testServer) readModel(token string, modelID string) input.Model {
+	folderNameOfKey, key := what.keyOfToken(token)
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	modelInput, _, ok := what.server.readModel(ginContext, modelID, key, folderNameOfKey)
+	assert.True(what.t, ok)
+	return modelInput
+}
+
+func (what *testServer) keyOfToken(token string) (folderNameOfKey string, key []byte) {
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ginContext.Request.Header.Set("token", token)
+	folderNameOfKey, key, ok := what.server.checkTokenToFolderName(ginContext)
+	assert.True(what.t, ok)
+	return folderNameOfKey, key
+}
+
+func TestNewModelIsReadable(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := server.createModel(token)
+
+	modelInput := server.readModel(token, modelID)
+	assert.Equal(t, "New Threat Model", modelInput.Title)
+	assert.Zero(t, modelInput.DiagramTweakNodesep) // i.e. the default
+	assert.Zero(t, modelInput.DiagramTweakRanksep)
+}
@@ -0,0 +1,394 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+var sqlStorageSchema = []string{
+	`CREATE TABLE IF NOT EXISTS keys (key_hash TEXT PRIMARY KEY)`,
+	`CREATE TABLE IF NOT EXISTS users (user_key_hash TEXT PRIMARY KEY, id TEXT NOT NULL UNIQUE, key_hash TEXT NOT NULL, name TEXT NOT NULL, wrapped_key BLOB NOT NULL, roles TEXT NOT NULL, created INTEGER NOT NULL)`,
+	`CREATE TABLE IF NOT EXISTS tokens (token_hash TEXT PRIMARY KEY, key_hash TEXT NOT NULL UNIQUE, role TEXT NOT NULL, xor_rand BLOB NOT NULL, created INTEGER NOT NULL, last_accessed INTEGER NOT NULL)`,
+	`CREATE TABLE IF NOT EXISTS models (key_hash TEXT NOT NULL, model_id TEXT NOT NULL, data BLOB NOT NULL, created INTEGER NOT NULL, modified INTEGER NOT NULL, PRIMARY KEY (key_hash, model_id))`,
+	`CREATE TABLE IF NOT EXISTS history (key_hash TEXT NOT NULL, model_id TEXT NOT NULL, version_id TEXT NOT NULL, data BLOB NOT NULL, PRIMARY KEY (key_hash, model_id, version_id))`,
+	`CREATE TABLE IF NOT EXISTS audit (key_hash TEXT NOT NULL, timestamp INTEGER NOT NULL, user_id TEXT NOT NULL, user_name TEXT NOT NULL, model_id TEXT NOT NULL, action TEXT NOT NULL)`,
+	`CREATE TABLE IF NOT EXISTS locks (name TEXT PRIMARY KEY, owner TEXT NOT NULL, acquired INTEGER NOT NULL)`,
+}
+
+// sqlStorage keeps everything in a database accessed through database/sql, so that server replicas only need to share
+// the database, the locks are rows in the locks table with the owner token and the time the lock was last refreshed
+type sqlStorage struct {
+	db    *sql.DB
+	locks *storageLocks
+}
+
+func newSQLStorage(driverName string, dataSourceName string) (*sqlStorage, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open server storage database: %w", err)
+	}
+	for _, statement := range sqlStorageSchema {
+		if _, err = db.Exec(statement); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("unable to create server storage database schema: %w", err)
+		}
+	}
+	return &sqlStorage{db: db, locks: newStorageLocks()}, nil
+}
+
+func (what *sqlStorage) CreateKey(keyHash string) error {
+	_, err := what.db.Exec(`INSERT INTO keys (key_hash) VALUES (?) ON CONFLICT DO NOTHING`, keyHash)
+	return err
+}
+
+func (what *sqlStorage) KeyExists(keyHash string) (bool, error) {
+	return what.exists(`SELECT 1 FROM keys WHERE key_hash = ?`, keyHash)
+}
+
+func (what *sqlStorage) ListKeys() ([]string, error) {
+	return what.strings(`SELECT key_hash FROM keys ORDER BY key_hash`)
+}
+
+func (what *sqlStorage) DeleteKey(keyHash string) error {
+	return what.transaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM keys WHERE key_hash = ?`, keyHash)
+		if err != nil {
+			return err
+		}
+		if count, err := result.RowsAffected(); err != nil || count == 0 {
+			if err == nil {
+				err = errNotFound
+			}
+			return err
+		}
+		_, err = tx.Exec(`DELETE FROM tokens WHERE key_hash = ? OR key_hash IN (SELECT user_key_hash FROM users WHERE key_hash = ?)`, keyHash, keyHash)
+		if err != nil {
+			return err
+		}
+		for _, statement := range []string{
+			`DELETE FROM users WHERE key_hash = ?`,
+			`DELETE FROM models WHERE key_hash = ?`,
+			`DELETE FROM history WHERE key_hash = ?`,
+			`DELETE FROM audit WHERE key_hash = ?`,
+		} {
+			if _, err = tx.Exec(statement, keyHash); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (what *sqlStorage) PutUser(user StoredUser) error {
+	roles, err := json.Marshal(user.Roles)
+	if err != nil {
+		return err
+	}
+	_, err = what.db.Exec(`INSERT INTO users (user_key_hash, id, key_hash, name, wrapped_key, roles, created) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_key_hash) DO UPDATE SET name = excluded.name, roles = excluded.roles`,
+		user.UserKeyHash, user.ID, user.KeyHash, user.Name, user.WrappedKey, string(roles), user.Created.UnixNano())
+	return err
+}
+
+func (what *sqlStorage) GetUser(userKeyHash string) (StoredUser, bool, error) {
+	users, err := what.users(`WHERE user_key_hash = ?`, userKeyHash)
+	if err != nil || len(users) == 0 {
+		return StoredUser{}, false, err
+	}
+	return users[0], true, nil
+}
+
+func (what *sqlStorage) ListUsers(keyHash string) ([]StoredUser, error) {
+	return what.users(`WHERE key_hash = ? ORDER BY created`, keyHash)
+}
+
+func (what *sqlStorage) DeleteUser(keyHash string, userID string) error {
+	return what.transaction(func(tx *sql.Tx) error {
+		var userKeyHash string
+		err := tx.QueryRow(`SELECT user_key_hash FROM users WHERE key_hash = ? AND id = ?`, keyHash, userID).Scan(&userKeyHash)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(`DELETE FROM tokens WHERE key_hash = ?`, userKeyHash); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`DELETE FROM users WHERE user_key_hash = ?`, userKeyHash)
+		return err
+	})
+}
+
+func (what *sqlStorage) PutToken(token StoredToken) error {
+	return what.transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM tokens WHERE key_hash = ?`, token.KeyHash); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`INSERT INTO tokens (token_hash, key_hash, role, xor_rand, created, last_accessed) VALUES (?, ?, ?, ?, ?, ?)`,
+			token.TokenHash, token.KeyHash, token.Role, token.XorRand, token.CreatedNanoTime, token.LastAccessedNanoTime)
+		return err
+	})
+}
+
+func (what *sqlStorage) GetToken(tokenHash string) (StoredToken, bool, error) {
+	token := StoredToken{TokenHash: tokenHash}
+	err := what.db.QueryRow(`SELECT key_hash, role, xor_rand, created, last_accessed FROM tokens WHERE token_hash = ?`, tokenHash).
+		Scan(&token.KeyHash, &token.Role, &token.XorRand, &token.CreatedNanoTime, &token.LastAccessedNanoTime)
+	if errors.Is(err, sql.ErrNoRows) {
+		return StoredToken{}, false, nil
+	}
+	if err != nil {
+		return StoredToken{}, false, err
+	}
+	return token, true, nil
+}
+
+func (what *sqlStorage) TouchToken(tokenHash string, lastAccessedNanoTime int64) error {
+	_, err := what.db.Exec(`UPDATE tokens SET last_accessed = ? WHERE token_hash = ?`, lastAccessedNanoTime, tokenHash)
+	return err
+}
+
+func (what *sqlStorage) DeleteToken(tokenHash string) error {
+	_, err := what.db.Exec(`DELETE FROM tokens WHERE token_hash = ?`, tokenHash)
+	return err
+}
+
+func (what *sqlStorage) DeleteTokensIdleOrCreatedBefore(idleNanoTime int64, createdNanoTime int64) error {
+	_, err := what.db.Exec(`DELETE FROM tokens WHERE last_accessed < ? OR created < ?`, idleNanoTime, createdNanoTime)
+	return err
+}
+
+func (what *sqlStorage) ListModels(keyHash string) ([]StoredModel, error) {
+	rows, err := what.db.Query(`SELECT model_id, created, modified FROM models WHERE key_hash = ? ORDER BY model_id`, keyHash)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	models := make([]StoredModel, 0)
+	for rows.Next() {
+		var id string
+		var created, modified int64
+		if err = rows.Scan(&id, &created, &modified); err != nil {
+			return nil, err
+		}
+		models = append(models, StoredModel{ID: id, Created: time.Unix(0, created), Modified: time.Unix(0, modified)})
+	}
+	return models, rows.Err()
+}
+
+func (what *sqlStorage) ModelExists(keyHash string, modelID string) (bool, error) {
+	return what.exists(`SELECT 1 FROM models WHERE key_hash = ? AND model_id = ?`, keyHash, modelID)
+}
+
+func (what *sqlStorage) ReadModel(keyHash string, modelID string) ([]byte, error) {
+	var data []byte
+	err := what.db.QueryRow(`SELECT data FROM models WHERE key_hash = ? AND model_id = ?`, keyHash, modelID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound
+	}
+	return data, err
+}
+
+func (what *sqlStorage) WriteModel(keyHash string, model StoredModel, data []byte) error {
+	now := time.Now()
+	modified, created := model.Modified, model.Created
+	if modified.IsZero() {
+		modified = now
+	}
+	if created.IsZero() {
+		created = now
+	}
+	_, err := what.db.Exec(`INSERT INTO models (key_hash, model_id, data, created, modified) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (key_hash, model_id) DO UPDATE SET data = excluded.data, modified = excluded.modified`+keepCreated(model),
+		keyHash, model.ID, data, created.UnixNano(), modified.UnixNano())
+	return err
+}
+
+func (what *sqlStorage) DeleteModel(keyHash string, modelID string) error {
+	return what.transaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM models WHERE key_hash = ? AND model_id = ?`, keyHash, modelID)
+		if err != nil {
+			return err
+		}
+		if count, err := result.RowsAffected(); err != nil || count == 0 {
+			if err == nil {
+				err = errNotFound
+			}
+			return err
+		}
+		_, err = tx.Exec(`DELETE FROM history WHERE key_hash = ? AND model_id = ?`, keyHash, modelID)
+		return err
+	})
+}
+
+func (what *sqlStorage) AddHistory(keyHash string, modelID string, versionID string, data []byte, keep int) error {
+	return what.transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO history (key_hash, model_id, version_id, data) VALUES (?, ?, ?, ?)
+			ON CONFLICT (key_hash, model_id, version_id) DO UPDATE SET data = excluded.data`, keyHash, modelID, versionID, data)
+		if err != nil {
+			return err
+		}
+		if keep < 0 {
+			return nil
+		}
+		_, err = tx.Exec(`DELETE FROM history WHERE key_hash = ? AND model_id = ? AND version_id NOT IN
+			(SELECT version_id FROM history WHERE key_hash = ? AND model_id = ? ORDER BY version_id DESC LIMIT ?)`,
+			keyHash, modelID, keyHash, modelID, keep)
+		return err
+	})
+}
+
+func (what *sqlStorage) ListHistory(keyHash string, modelID string) ([]string, error) {
+	return what.strings(`SELECT version_id FROM history WHERE key_hash = ? AND model_id = ? ORDER BY version_id`, keyHash, modelID)
+}
+
+func (what *sqlStorage) ReadHistory(keyHash string, modelID string, versionID string) ([]byte, error) {
+	var data []byte
+	err := what.db.QueryRow(`SELECT data FROM history WHERE key_hash = ? AND model_id = ? AND version_id = ?`, keyHash, modelID, versionID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound
+	}
+	return data, err
+}
+
+func (what *sqlStorage) AddAuditEntry(keyHash string, entry StoredAuditEntry) error {
+	_, err := what.db.Exec(`INSERT INTO audit (key_hash, timestamp, user_id, user_name, model_id, action) VALUES (?, ?, ?, ?, ?, ?)`,
+		keyHash, entry.Timestamp.UnixNano(), entry.UserID, entry.UserName, entry.ModelID, entry.Action)
+	return err
+}
+
+func (what *sqlStorage) ListAuditEntries(keyHash string) ([]StoredAuditEntry, error) {
+	rows, err := what.db.Query(`SELECT timestamp, user_id, user_name, model_id, action FROM audit WHERE key_hash = ? ORDER BY rowid`, keyHash)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	entries := make([]StoredAuditEntry, 0)
+	for rows.Next() {
+		entry := StoredAuditEntry{}
+		var timestamp int64
+		if err = rows.Scan(&timestamp, &entry.UserID, &entry.UserName, &entry.ModelID, &entry.Action); err != nil {
+			return nil, err
+		}
+		entry.Timestamp = time.Unix(0, timestamp)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// Lock inserts a row for the lock, which fails as long as another process (or goroutine) holds the lock
+func (what *sqlStorage) Lock(name string) error {
+	return what.locks.lock(name, func(owner string, timeout time.Duration) (bool, error) {
+		now := time.Now()
+		_, err := what.db.Exec(`DELETE FROM locks WHERE name = ? AND acquired < ?`, name, now.Add(-timeout).UnixNano()) // stale lock
+		if err != nil {
+			return false, err
+		}
+		result, err := what.db.Exec(`INSERT INTO locks (name, owner, acquired) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`, name, owner, now.UnixNano())
+		if err != nil {
+			return false, err
+		}
+		count, err := result.RowsAffected()
+		return count == 1, err
+	}, func(owner string) error {
+		return what.execOwnLock(`UPDATE locks SET acquired = ? WHERE name = ? AND owner = ?`, time.Now().UnixNano(), name, owner)
+	})
+}
+
+func (what *sqlStorage) Unlock(name string) error {
+	return what.locks.unlock(name, func(owner string) error {
+		return what.execOwnLock(`DELETE FROM locks WHERE name = ? AND owner = ?`, name, owner)
+	})
+}
+
+// execOwnLock executes the statement on the row of a lock, which fails if the row is no longer owned by the owner
+func (what *sqlStorage) execOwnLock(statement string, args ...any) error {
+	result, err := what.db.Exec(statement, args...)
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err == nil && count == 0 {
+		return errLockTakenOver
+	}
+	return err
+}
+
+func (what *sqlStorage) Close() error {
+	return what.db.Close()
+}
+
+func (what *sqlStorage) users(condition string, args ...any) ([]StoredUser, error) {
+	rows, err := what.db.Query(`SELECT user_key_hash, id, key_hash, name, wrapped_key, roles, created FROM users `+condition, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	users := make([]StoredUser, 0)
+	for rows.Next() {
+		user := StoredUser{}
+		var roles string
+		var created int64
+		if err = rows.Scan(&user.UserKeyHash, &user.ID, &user.KeyHash, &user.Name, &user.WrappedKey, &roles, &created); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(roles), &user.Roles); err != nil {
+			return nil, err
+		}
+		user.Created = time.Unix(0, created)
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (what *sqlStorage) exists(query string, args ...any) (bool, error) {
+	var found int
+	err := what.db.QueryRow(query, args...).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (what *sqlStorage) strings(query string, args ...any) ([]string, error) {
+	rows, err := what.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	values := make([]string, 0)
+	for rows.Next() {
+		var value string
+		if err = rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+func (what *sqlStorage) transaction(statements func(tx *sql.Tx) error) error {
+	tx, err := what.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err = statements(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// keepCreated returns the part of the upsert setting the creation time when given explicitly (e.g. on migration)
+func keepCreated(model StoredModel) string {
+	if model.Created.IsZero() {
+		return ""
+	}
+	return ", created = excluded.created"
+}
@@ -0,0 +1,291 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+const (
+	FilesystemStorage   = "filesystem"
+	SQLiteStoragePrefix = "sqlite:"
+)
+
+const (
+	// a lock not refreshed within this time is considered stale (e.g. left by a crashed replica) and is taken over,
+	// locks still held are refreshed several times within this time
+	storageLockTimeout         = time.Minute
+	storageLockRefreshInterval = storageLockTimeout / 4
+	// waiting for a lock fails after this time, which leaves enough time to take over a stale lock
+	storageLockWaitTimeout   = 2 * storageLockTimeout
+	storageLockRetryInterval = 50 * time.Millisecond
+)
+
+var (
+	errNotFound      = errors.New("not found")
+	errLockTakenOver = errors.New("lock was taken over as stale")
+)
+
+// Storage persists the keys, users, tokens, models, model history and audit log of the server, so that several server
+// replicas can share the same storage: keys are only known by the hash of the key (the "folder name" of the key), models
+// and history entries are stored as encrypted by the server, users only with the key encrypted by their own user key and
+// tokens only with the random value xor-ed to the key
+type Storage interface {
+	CreateKey(keyHash string) error
+	KeyExists(keyHash string) (bool, error)
+	ListKeys() ([]string, error)
+	DeleteKey(keyHash string) error // also deletes all users, tokens, models, history and audit entries of the key
+
+	PutUser(user StoredUser) error // creates or replaces the user with the same user key hash
+	GetUser(userKeyHash string) (StoredUser, bool, error)
+	ListUsers(keyHash string) ([]StoredUser, error)
+	DeleteUser(keyHash string, userID string) error // also deletes the tokens of the user
+
+	PutToken(token StoredToken) error // replaces any previous token of the same key
+	GetToken(tokenHash string) (StoredToken, bool, error)
+	TouchToken(tokenHash string, lastAccessedNanoTime int64) error
+	DeleteToken(tokenHash string) error
+	DeleteTokensIdleOrCreatedBefore(idleNanoTime int64, createdNanoTime int64) error
+
+	ListModels(keyHash string) ([]StoredModel, error)
+	ModelExists(keyHash string, modelID string) (bool, error)
+	ReadModel(keyHash string, modelID string) ([]byte, error)
+	WriteModel(keyHash string, model StoredModel, data []byte) error // a zero Created keeps the creation time
+	DeleteModel(keyHash string, modelID string) error
+
+	AddHistory(keyHash string, modelID string, versionID string, data []byte, keep int) error // keeps only the newest entries
+	ListHistory(keyHash string, modelID string) ([]string, error)                             // oldest first
+	ReadHistory(keyHash string, modelID string, versionID string) ([]byte, error)
+
+	AddAuditEntry(keyHash string, entry StoredAuditEntry) error
+	ListAuditEntries(keyHash string) ([]StoredAuditEntry, error) // oldest first
+
+	Lock(name string) error   // blocks until the lock is acquired (or the wait timed out), also across processes sharing the storage
+	Unlock(name string) error // fails if the lock was taken over meanwhile
+	Close() error
+}
+
+// StoredUser is a user sharing the models of a key with the roles given per model id, the user authenticates with its own
+// user key, which decrypts the wrapped key of the models
+type StoredUser struct {
+	ID          string
+	KeyHash     string
+	UserKeyHash string
+	Name        string
+	WrappedKey  []byte
+	Roles       map[string]string
+	Created     time.Time
+}
+
+// StoredToken belongs to the key or user key with the given hash, the role optionally limits the roles of the token
+type StoredToken struct {
+	TokenHash            string
+	KeyHash              string
+	Role                 string
+	XorRand              []byte
+	CreatedNanoTime      int64
+	LastAccessedNanoTime int64
+}
+
+type StoredModel struct {
+	ID       string
+	Created  time.Time
+	Modified time.Time
+}
+
+type StoredAuditEntry struct {
+	Timestamp time.Time
+	UserID    string
+	UserName  string
+	ModelID   string
+	Action    string
+}
+
+// OpenStorage opens the storage described by spec: "filesystem" for the encrypted folders below the server folder or
+// "sqlite:<file>" for an SQLite database (a relative file is located in the server folder), which must be on a local disk
+func OpenStorage(spec string, config *common.Config) (Storage, error) {
+	switch {
+	case spec == "" || spec == FilesystemStorage:
+		return newFilesystemStorage(config.ServerFolder, config.KeyFolder, config.InputFile)
+
+	case strings.HasPrefix(spec, SQLiteStoragePrefix):
+		filename := strings.TrimPrefix(spec, SQLiteStoragePrefix)
+		if len(filename) == 0 {
+			return nil, fmt.Errorf("missing database file in server storage %q", spec)
+		}
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(config.ServerFolder, filename)
+		}
+		return newSQLStorage("sqlite", "file:"+filename+"?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)")
+	}
+	return nil, fmt.Errorf("unknown server storage %q: expected %q or %q", spec, FilesystemStorage, SQLiteStoragePrefix+"<file>")
+}
+
+// MigrateStorage copies all keys, users, models, model history and audit entries from one storage to another, tokens
+// are not copied as they are short-lived anyway (clients simply create new ones)
+func MigrateStorage(from Storage, to Storage, progressReporter progressReporter) error {
+	keyHashes, err := from.ListKeys()
+	if err != nil {
+		return fmt.Errorf("unable to list keys: %w", err)
+	}
+	for _, keyHash := range keyHashes {
+		if err = to.CreateKey(keyHash); err != nil {
+			return fmt.Errorf("unable to create key: %w", err)
+		}
+		users, err := from.ListUsers(keyHash)
+		if err != nil {
+			return fmt.Errorf("unable to list users: %w", err)
+		}
+		for _, user := range users {
+			if err = to.PutUser(user); err != nil {
+				return fmt.Errorf("unable to write user %v: %w", user.ID, err)
+			}
+		}
+		auditEntries, err := from.ListAuditEntries(keyHash)
+		if err != nil {
+			return fmt.Errorf("unable to list audit entries: %w", err)
+		}
+		for _, entry := range auditEntries {
+			if err = to.AddAuditEntry(keyHash, entry); err != nil {
+				return fmt.Errorf("unable to write audit entry: %w", err)
+			}
+		}
+		models, err := from.ListModels(keyHash)
+		if err != nil {
+			return fmt.Errorf("unable to list models: %w", err)
+		}
+		for _, model := range models {
+			versionIDs, err := from.ListHistory(keyHash, model.ID)
+			if err != nil {
+				return fmt.Errorf("unable to list history of model %v: %w", model.ID, err)
+			}
+			for _, versionID := range versionIDs {
+				data, err := from.ReadHistory(keyHash, model.ID, versionID)
+				if err != nil {
+					return fmt.Errorf("unable to read history of model %v: %w", model.ID, err)
+				}
+				if err = to.AddHistory(keyHash, model.ID, versionID, data, len(versionIDs)); err != nil {
+					return fmt.Errorf("unable to write history of model %v: %w", model.ID, err)
+				}
+			}
+			data, err := from.ReadModel(keyHash, model.ID)
+			if err != nil {
+				return fmt.Errorf("unable to read model %v: %w", model.ID, err)
+			}
+			if err = to.WriteModel(keyHash, model, data); err != nil {
+				return fmt.Errorf("unable to write model %v: %w", model.ID, err)
+			}
+			progressReporter.Info(fmt.Sprintf("Migrated model %v with %d history entries", model.ID, len(versionIDs)))
+		}
+	}
+	progressReporter.Info(fmt.Sprintf("Migrated %d keys", len(keyHashes)))
+	return nil
+}
+
+type progressReporter interface {
+	Info(a ...any)
+	Warn(a ...any)
+	Error(a ...any)
+}
+
+// storageLocks keeps the locks held by a storage: each lock is written with a random owner token, so that only its owner
+// releases it, and is refreshed in the background while held, so that it is not taken over as stale
+type storageLocks struct {
+	mutex           sync.Mutex
+	held            map[string]*storageLock
+	timeout         time.Duration
+	refreshInterval time.Duration
+	waitTimeout     time.Duration
+}
+
+type storageLock struct {
+	owner   string
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func newStorageLocks() *storageLocks {
+	return &storageLocks{
+		held:            make(map[string]*storageLock),
+		timeout:         storageLockTimeout,
+		refreshInterval: storageLockRefreshInterval,
+		waitTimeout:     storageLockWaitTimeout,
+	}
+}
+
+// lock retries to acquire the lock until the wait timed out, tryLock takes over stale locks of the given timeout,
+// refresh is called regularly as long as the lock is held
+func (what *storageLocks) lock(name string, tryLock func(owner string, timeout time.Duration) (bool, error), refresh func(owner string) error) error {
+	owner := uuid.New().String()
+	deadline := time.Now().Add(what.waitTimeout)
+	for {
+		acquired, err := tryLock(owner, what.timeout)
+		if err != nil {
+			return fmt.Errorf("unable to acquire lock %v: %w", name, err)
+		}
+		if acquired {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("unable to acquire lock %v within %v", name, what.waitTimeout)
+		}
+		time.Sleep(storageLockRetryInterval)
+	}
+
+	lock := &storageLock{owner: owner, stop: make(chan struct{}), stopped: make(chan struct{})}
+	ticker := time.NewTicker(what.refreshInterval)
+	go func() {
+		defer close(lock.stopped)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lock.stop:
+				return
+			case <-ticker.C:
+				if err := refresh(owner); err != nil {
+					log.Println(fmt.Errorf("unable to refresh lock %v: %w", name, err))
+				}
+			}
+		}
+	}()
+	what.mutex.Lock()
+	what.held[name] = lock
+	what.mutex.Unlock()
+	return nil
+}
+
+// unlock stops refreshing the lock and releases it, release fails if the lock is no longer owned by the given owner
+func (what *storageLocks) unlock(name string, release func(owner string) error) error {
+	what.mutex.Lock()
+	lock, held := what.held[name]
+	delete(what.held, name)
+	what.mutex.Unlock()
+	if !held {
+		return fmt.Errorf("lock %v is not held", name)
+	}
+	close(lock.stop)
+	<-lock.stopped
+	if err := release(lock.owner); err != nil {
+		return fmt.Errorf("unable to release lock %v: %w", name, err)
+	}
+	return nil
+}
+
+// oldestToDelete returns the entries to delete (the oldest ones) in order to keep only the given number of entries
+func oldestToDelete(versionIDs []string, keep int) []string {
+	if keep < 0 || len(versionIDs) <= keep {
+		return nil
+	}
+	sorted := append([]string{}, versionIDs...)
+	sort.Strings(sorted)
+	return sorted[:len(sorted)-keep]
+}
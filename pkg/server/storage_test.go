@@ -0,0 +1,474 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+// storageBackends opens a storage of each backend in the given folder, opening it again in the same folder shares
+// the storage as server replicas do
+var storageBackends = map[string]func(t *testing.T, folder string) Storage{
+	"filesystem": func(t *testing.T, folder string) Storage {
+		return openTestStorage(t, FilesystemStorage, folder)
+	},
+	"sqlite": func(t *testing.T, folder string) Storage {
+		return openTestStorage(t, SQLiteStoragePrefix+"threagile.db", folder)
+	},
+}
+
+func openTestStorage(t *testing.T, spec string, folder string) Storage {
+	config := new(common.Config).Defaults("")
+	config.ServerFolder = folder
+	storage, err := OpenStorage(spec, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = storage.Close() })
+	return storage
+}
+
+func TestStorage(t *testing.T) {
+	for name, test := range map[string]func(t *testing.T, open func() Storage){
+		"keys":    testStorageKeys,
+		"users":   testStorageUsers,
+		"tokens":  testStorageTokens,
+		"models":  testStorageModels,
+		"history": testStorageHistory,
+		"audit":   testStorageAudit,
+		"locks":   testStorageLocks,
+	} {
+		for backend, openStorage := range storageBackends {
+			t.Run(backend+"/"+name, func(t *testing.T) {
+				folder := t.TempDir()
+				test(t, func() Storage { return openStorage(t, folder) })
+			})
+		}
+	}
+}
+
+func testStorageKeys(t *testing.T, open func() Storage) {
+	storage := open()
+	assert.NoError(t, storage.CreateKey("key-a"))
+	assert.NoError(t, storage.CreateKey("key-b"))
+	assert.NoError(t, storage.CreateKey("key-a")) // already existing
+	keyHashes, err := storage.ListKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key-a", "key-b"}, keyHashes)
+	exists, err := storage.KeyExists("key-a")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = storage.KeyExists("key-c")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// deleting a key deletes everything of it
+	assert.NoError(t, storage.PutUser(StoredUser{ID: "user", KeyHash: "key-a", UserKeyHash: "user-key", Name: "User", WrappedKey: []byte{1}}))
+	assert.NoError(t, storage.PutToken(StoredToken{TokenHash: "token", KeyHash: "key-a", XorRand: []byte{1}}))
+	assert.NoError(t, storage.PutToken(StoredToken{TokenHash: "user-token", KeyHash: "user-key", XorRand: []byte{1}}))
+	assert.NoError(t, storage.WriteModel("key-a", StoredModel{ID: "model"}, []byte("model")))
+	assert.NoError(t, storage.AddHistory("key-a", "model", "version", []byte("version"), 10))
+	assert.NoError(t, storage.AddAuditEntry("key-a", StoredAuditEntry{Timestamp: time.Now(), Action: "Some Action"}))
+	assert.NoError(t, storage.DeleteKey("key-a"))
+	keyHashes, err = storage.ListKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key-b"}, keyHashes)
+	_, found, err := storage.GetUser("user-key")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	for _, tokenHash := range []string{"token", "user-token"} {
+		_, found, err = storage.GetToken(tokenHash)
+		assert.NoError(t, err)
+		assert.False(t, found, tokenHash)
+	}
+	assert.NoError(t, storage.CreateKey("key-a"))
+	models, err := storage.ListModels("key-a")
+	assert.NoError(t, err)
+	assert.Empty(t, models)
+	entries, err := storage.ListAuditEntries("key-a")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.ErrorIs(t, storage.DeleteKey("key-c"), errNotFound)
+}
+
+func testStorageUsers(t *testing.T, open func() Storage) {
+	storage := open()
+	created := time.Unix(1700000000, 0)
+	first := StoredUser{ID: "first", KeyHash: "key", UserKeyHash: "first-user-key", Name: "First", WrappedKey: []byte{1, 2},
+		Roles: map[string]string{"model": roleEditor}, Created: created.Add(time.Second)}
+	second := StoredUser{ID: "second", KeyHash: "key", UserKeyHash: "second-user-key", Name: "Second", WrappedKey: []byte{3},
+		Roles: map[string]string{}, Created: created}
+	other := StoredUser{ID: "other", KeyHash: "other-key", UserKeyHash: "other-user-key", Name: "Other", WrappedKey: []byte{4},
+		Roles: map[string]string{}, Created: created}
+	for _, user := range []StoredUser{first, second, other} {
+		assert.NoError(t, storage.PutUser(user))
+	}
+
+	user, found, err := storage.GetUser("first-user-key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assertEqualUser(t, first, user)
+	_, found, err = storage.GetUser("unknown-user-key")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	first.Name = "Renamed"
+	first.Roles = map[string]string{"model": roleViewer, "other-model": roleOwner}
+	assert.NoError(t, storage.PutUser(first)) // replaces the user
+	users, err := storage.ListUsers("key")
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	if len(users) == 2 {
+		assertEqualUser(t, second, users[0]) // the oldest first
+		assertEqualUser(t, first, users[1])
+	}
+
+	// deleting a user deletes the tokens of the user
+	assert.NoError(t, storage.PutToken(StoredToken{TokenHash: "token", KeyHash: "first-user-key", XorRand: []byte{1}}))
+	assert.NoError(t, storage.DeleteUser("key", "first"))
+	_, found, err = storage.GetToken("token")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	users, err = storage.ListUsers("key")
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.ErrorIs(t, storage.DeleteUser("key", "first"), errNotFound)
+	assert.ErrorIs(t, storage.DeleteUser("key", "other"), errNotFound) // of another key
+}
+
+func assertEqualUser(t *testing.T, expected StoredUser, actual StoredUser) {
+	assert.True(t, expected.Created.Equal(actual.Created), "created %v instead of %v", actual.Created, expected.Created)
+	expected.Created, actual.Created = time.Time{}, time.Time{}
+	assert.Equal(t, expected, actual)
+}
+
+func testStorageTokens(t *testing.T, open func() Storage) {
+	storage := open()
+	first := StoredToken{TokenHash: "first", KeyHash: "key", Role: roleViewer, XorRand: []byte{1, 2}, CreatedNanoTime: 100, LastAccessedNanoTime: 100}
+	second := StoredToken{TokenHash: "second", KeyHash: "key", XorRand: []byte{3}, CreatedNanoTime: 100, LastAccessedNanoTime: 100}
+	other := StoredToken{TokenHash: "other", KeyHash: "other-key", XorRand: []byte{4}, CreatedNanoTime: 200, LastAccessedNanoTime: 200}
+	assert.NoError(t, storage.PutToken(first))
+	token, found, err := storage.GetToken("first")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, first, token)
+
+	assert.NoError(t, storage.PutToken(second)) // replaces the token of the same key
+	assert.NoError(t, storage.PutToken(other))
+	_, found, err = storage.GetToken("first")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, storage.TouchToken("second", 300))
+	second.LastAccessedNanoTime = 300
+	token, _, err = storage.GetToken("second")
+	assert.NoError(t, err)
+	assert.Equal(t, second, token)
+	assert.NoError(t, storage.TouchToken("unknown", 300))
+
+	assert.NoError(t, storage.DeleteTokensIdleOrCreatedBefore(250, 50)) // the other token is idle
+	_, found, _ = storage.GetToken("other")
+	assert.False(t, found)
+	_, found, _ = storage.GetToken("second")
+	assert.True(t, found)
+	assert.NoError(t, storage.DeleteTokensIdleOrCreatedBefore(250, 150)) // the second token is too old
+	_, found, _ = storage.GetToken("second")
+	assert.False(t, found)
+
+	assert.NoError(t, storage.PutToken(first))
+	assert.NoError(t, storage.DeleteToken("first"))
+	_, found, _ = storage.GetToken("first")
+	assert.False(t, found)
+	assert.NoError(t, storage.DeleteToken("first")) // no longer existing
+}
+
+func testStorageModels(t *testing.T, open func() Storage) {
+	storage := open()
+	assert.NoError(t, storage.CreateKey("key"))
+	created, modified := time.Unix(1700000000, 0), time.Unix(1700001000, 0)
+	assert.NoError(t, storage.WriteModel("key", StoredModel{ID: "second", Created: created, Modified: modified}, []byte("second")))
+	assert.NoError(t, storage.WriteModel("key", StoredModel{ID: "first", Created: created, Modified: modified}, []byte("first")))
+
+	models, err := storage.ListModels("key")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, modelIds(models))
+	for _, model := range models {
+		assert.True(t, created.Equal(model.Created), "created %v", model.Created)
+		assert.True(t, modified.Equal(model.Modified), "modified %v", model.Modified)
+	}
+	exists, err := storage.ModelExists("key", "first")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	data, err := storage.ReadModel("key", "first")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("first"), data)
+
+	assert.NoError(t, storage.WriteModel("key", StoredModel{ID: "first"}, []byte("changed")))
+	data, err = storage.ReadModel("key", "first")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("changed"), data)
+	models, err = storage.ListModels("key")
+	assert.NoError(t, err)
+	assert.True(t, created.Equal(models[0].Created), "created %v", models[0].Created) // kept
+	assert.True(t, models[0].Modified.After(modified), "modified %v", models[0].Modified)
+
+	// deleting a model deletes its history
+	assert.NoError(t, storage.AddHistory("key", "first", "version", []byte("version"), 10))
+	assert.NoError(t, storage.DeleteModel("key", "first"))
+	exists, err = storage.ModelExists("key", "first")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	_, err = storage.ReadModel("key", "first")
+	assert.ErrorIs(t, err, errNotFound)
+	versionIDs, err := storage.ListHistory("key", "first")
+	assert.NoError(t, err)
+	assert.Empty(t, versionIDs)
+	assert.ErrorIs(t, storage.DeleteModel("key", "first"), errNotFound)
+	models, err = storage.ListModels("key")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"second"}, modelIds(models))
+}
+
+func modelIds(models []StoredModel) []string {
+	ids := make([]string, 0)
+	for _, model := range models {
+		ids = append(ids, model.ID)
+	}
+	return ids
+}
+
+func testStorageHistory(t *testing.T, open func() Storage) {
+	storage := open()
+	assert.NoError(t, storage.CreateKey("key"))
+	assert.NoError(t, storage.WriteModel("key", StoredModel{ID: "model"}, []byte("model")))
+	for _, versionID := range []string{"2024-01-01 00:00:01 First", "2024-01-01 00:00:03 Third", "2024-01-01 00:00:02 Second"} {
+		assert.NoError(t, storage.AddHistory("key", "model", versionID, []byte(versionID), 10))
+	}
+	versionIDs, err := storage.ListHistory("key", "model")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-01-01 00:00:01 First", "2024-01-01 00:00:02 Second", "2024-01-01 00:00:03 Third"}, versionIDs)
+	data, err := storage.ReadHistory("key", "model", "2024-01-01 00:00:02 Second")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("2024-01-01 00:00:02 Second"), data)
+	_, err = storage.ReadHistory("key", "model", "2024-01-01 00:00:04 Unknown")
+	assert.ErrorIs(t, err, errNotFound)
+
+	assert.NoError(t, storage.AddHistory("key", "model", "2024-01-01 00:00:04 Fourth", []byte("fourth"), 2)) // only the newest are kept
+	assert.NoError(t, storage.AddHistory("key", "model", "2024-01-01 00:00:04 Fourth", []byte("replaced"), 2))
+	versionIDs, err = storage.ListHistory("key", "model")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-01-01 00:00:03 Third", "2024-01-01 00:00:04 Fourth"}, versionIDs)
+	data, err = storage.ReadHistory("key", "model", "2024-01-01 00:00:04 Fourth")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("replaced"), data)
+
+	versionIDs, err = storage.ListHistory("key", "other-model")
+	assert.NoError(t, err)
+	assert.Empty(t, versionIDs)
+}
+
+func testStorageAudit(t *testing.T, open func() Storage) {
+	storage := open()
+	assert.NoError(t, storage.CreateKey("key"))
+	assert.NoError(t, storage.CreateKey("other-key"))
+	timestamp := time.Unix(1700000000, 123456789)
+	entries := []StoredAuditEntry{
+		{Timestamp: timestamp, UserName: "owner", Action: "New Model Creation", ModelID: "model"},
+		{Timestamp: timestamp.Add(time.Second), UserID: "user", UserName: "User", Action: "Cover Update", ModelID: "model"},
+		{Timestamp: timestamp.Add(2 * time.Second), UserName: "owner", Action: "User Creation"},
+	}
+	for _, entry := range entries {
+		assert.NoError(t, storage.AddAuditEntry("key", entry))
+	}
+	stored, err := storage.ListAuditEntries("key")
+	assert.NoError(t, err)
+	assert.Len(t, stored, len(entries))
+	for i := range stored {
+		assert.True(t, entries[i].Timestamp.Equal(stored[i].Timestamp), "timestamp %v", stored[i].Timestamp)
+		stored[i].Timestamp = entries[i].Timestamp
+	}
+	assert.Equal(t, entries, stored)
+	stored, err = storage.ListAuditEntries("other-key")
+	assert.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func testStorageLocks(t *testing.T, open func() Storage) {
+	replica, otherReplica := open(), open()
+	assert.NoError(t, replica.Lock("folder"))
+	assert.NoError(t, otherReplica.Lock("other-folder")) // not blocked by other locks
+	assert.NoError(t, otherReplica.Unlock("other-folder"))
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- otherReplica.Lock("folder")
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("lock acquired although held by another replica")
+	case <-time.After(200 * time.Millisecond):
+	}
+	assert.NoError(t, replica.Unlock("folder"))
+	assert.NoError(t, <-acquired)
+	assert.Error(t, replica.Unlock("folder")) // not held
+	assert.NoError(t, otherReplica.Unlock("folder"))
+
+	// only one of the replicas holds the lock at a time
+	var holders atomic.Int32
+	var overlapping atomic.Bool
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+		go func(storage Storage) {
+			defer waitGroup.Done()
+			for j := 0; j < 5; j++ {
+				if !assert.NoError(t, storage.Lock("folder")) {
+					return
+				}
+				if holders.Add(1) > 1 {
+					overlapping.Store(true)
+				}
+				time.Sleep(time.Millisecond)
+				holders.Add(-1)
+				assert.NoError(t, storage.Unlock("folder"))
+			}
+		}(open())
+	}
+	waitGroup.Wait()
+	assert.False(t, overlapping.Load())
+}
+
+// locksOf returns the locks of a storage to shorten their timeouts
+func locksOf(storage Storage) *storageLocks {
+	switch storage := storage.(type) {
+	case *filesystemStorage:
+		return storage.locks
+	case *sqlStorage:
+		return storage.locks
+	}
+	return nil
+}
+
+func TestStorageLockTimeouts(t *testing.T) {
+	for backend, openStorage := range storageBackends {
+		t.Run(backend, func(t *testing.T) {
+			folder := t.TempDir()
+			replica, otherReplica := openStorage(t, folder), openStorage(t, folder)
+			locksOf(replica).refreshInterval = 50 * time.Millisecond
+			locksOf(otherReplica).timeout = 300 * time.Millisecond
+			locksOf(otherReplica).waitTimeout = time.Second
+
+			// a held lock is refreshed, so it does not become stale, and waiting for it is bounded
+			assert.NoError(t, replica.Lock("folder"))
+			started := time.Now()
+			assert.Error(t, otherReplica.Lock("folder"))
+			assert.Less(t, time.Since(started), 3*time.Second)
+
+			// a lock no longer refreshed (e.g. of a crashed replica) becomes stale and is taken over
+			locksOf(replica).refreshInterval = time.Hour
+			assert.NoError(t, replica.Unlock("folder"))
+			assert.NoError(t, replica.Lock("folder"))
+			assert.NoError(t, otherReplica.Lock("folder"))
+			assert.ErrorIs(t, replica.Unlock("folder"), errLockTakenOver) // the owner token differs
+			assert.NoError(t, otherReplica.Unlock("folder"))
+		})
+	}
+}
+
+func TestMigrateStorage(t *testing.T) {
+	folder := t.TempDir()
+	from := storageBackends["filesystem"](t, folder)
+	created, modified := time.Unix(1700000000, 0), time.Unix(1700001000, 0)
+	for _, keyHash := range []string{"key", "other-key"} {
+		assert.NoError(t, from.CreateKey(keyHash))
+		assert.NoError(t, from.PutUser(StoredUser{ID: keyHash + "-user", KeyHash: keyHash, UserKeyHash: keyHash + "-user-key", Name: "User",
+			WrappedKey: []byte(keyHash), Roles: map[string]string{"model": roleEditor}, Created: created}))
+		assert.NoError(t, from.PutToken(StoredToken{TokenHash: keyHash + "-token", KeyHash: keyHash, XorRand: []byte{1}}))
+		assert.NoError(t, from.AddAuditEntry(keyHash, StoredAuditEntry{Timestamp: created, UserName: "owner", ModelID: "model", Action: "Cover Update"}))
+		for _, modelID := range []string{"model", "other-model"} {
+			assert.NoError(t, from.WriteModel(keyHash, StoredModel{ID: modelID, Created: created, Modified: modified}, []byte(keyHash+modelID)))
+			for _, versionID := range []string{"2024-01-01 00:00:01 First", "2024-01-01 00:00:02 Second"} {
+				assert.NoError(t, from.AddHistory(keyHash, modelID, versionID, []byte(keyHash+modelID+versionID), 10))
+			}
+		}
+		assert.NoError(t, from.WriteModel(keyHash, StoredModel{ID: "other-model", Created: created, Modified: modified}, []byte(keyHash+"other-model")))
+	}
+	expected := dumpStorage(t, from)
+
+	// there and back again
+	to := storageBackends["sqlite"](t, folder)
+	back := storageBackends["filesystem"](t, t.TempDir())
+	reporter := common.DefaultProgressReporter{}
+	assert.NoError(t, MigrateStorage(from, to, reporter))
+	assert.Equal(t, expected, dumpStorage(t, to))
+	assert.NoError(t, MigrateStorage(to, back, reporter))
+	assert.Equal(t, expected, dumpStorage(t, back))
+
+	for _, storage := range []Storage{to, back} {
+		_, found, err := storage.GetToken("key-token")
+		assert.NoError(t, err)
+		assert.False(t, found) // tokens are not migrated
+	}
+}
+
+// dumpStorage returns the keys, users, audit entries, models and history of the storage with times as unix nano times
+func dumpStorage(t *testing.T, storage Storage) map[string]any {
+	dump := make(map[string]any)
+	keyHashes, err := storage.ListKeys()
+	assert.NoError(t, err)
+	for _, keyHash := range keyHashes {
+		users, err := storage.ListUsers(keyHash)
+		assert.NoError(t, err)
+		for _, user := range users {
+			dump[keyHash+"/users/"+user.ID] = []any{user.UserKeyHash, user.Name, user.WrappedKey, user.Roles, user.Created.UnixNano()}
+		}
+		entries, err := storage.ListAuditEntries(keyHash)
+		assert.NoError(t, err)
+		for i, entry := range entries {
+			dump[keyHash+"/audit/"+strconv.Itoa(i)] = []any{entry.Timestamp.UnixNano(), entry.UserID, entry.UserName, entry.ModelID, entry.Action}
+		}
+		models, err := storage.ListModels(keyHash)
+		assert.NoError(t, err)
+		for _, model := range models {
+			data, err := storage.ReadModel(keyHash, model.ID)
+			assert.NoError(t, err)
+			dump[keyHash+"/models/"+model.ID] = []any{string(data), model.Created.UnixNano(), model.Modified.UnixNano()}
+			versionIDs, err := storage.ListHistory(keyHash, model.ID)
+			assert.NoError(t, err)
+			for _, versionID := range versionIDs {
+				data, err := storage.ReadHistory(keyHash, model.ID, versionID)
+				assert.NoError(t, err)
+				dump[keyHash+"/models/"+model.ID+"/history/"+versionID] = string(data)
+			}
+		}
+	}
+	return dump
+}
+
+func TestLockFolderFailsRequestWithoutStorageLock(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := server.createModel(token)
+	folderNameOfKey, _ := server.keyOfToken(token)
+	locksOf(server.server.storage).waitTimeout = 100 * time.Millisecond
+
+	otherReplica := openTestStorage(t, FilesystemStorage, server.server.config.ServerFolder)
+	assert.NoError(t, otherReplica.Lock(folderNameOfKey))
+	var response struct {
+		Error string `json:"error"`
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, server.withToken(token, http.MethodPut, "/models/"+modelID+"/cover", payloadCover{Title: "Changed Title"}, &response))
+	assert.Equal(t, "unable to lock storage", response.Error)
+	assert.NoError(t, otherReplica.Unlock(folderNameOfKey))
+
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPut, "/models/"+modelID+"/cover", payloadCover{Title: "Changed Title"}, nil))
+	assert.Equal(t, "Changed Title", server.readModel(token, modelID).Title)
+}
@@ -0,0 +1,512 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type payloadTechnicalAsset struct {
+	Title                   string   `yaml:"title" json:"title"`
+	Id                      string   `yaml:"id" json:"id"`
+	Description             string   `yaml:"description" json:"description"`
+	Type                    string   `yaml:"type" json:"type"`
+	Usage                   string   `yaml:"usage" json:"usage"`
+	UsedAsClientByHuman     bool     `yaml:"used_as_client_by_human" json:"used_as_client_by_human"`
+	OutOfScope              bool     `yaml:"out_of_scope" json:"out_of_scope"`
+	JustificationOutOfScope string   `yaml:"justification_out_of_scope" json:"justification_out_of_scope"`
+	Size                    string   `yaml:"size" json:"size"`
+	Technology              string   `yaml:"technology" json:"technology"`
+	Tags                    []string `yaml:"tags" json:"tags"`
+	Internet                bool     `yaml:"internet" json:"internet"`
+	Machine                 string   `yaml:"machine" json:"machine"`
+	Encryption              string   `yaml:"encryption" json:"encryption"`
+	Owner                   string   `yaml:"owner" json:"owner"`
+	Confidentiality         string   `yaml:"confidentiality" json:"confidentiality"`
+	Integrity               string   `yaml:"integrity" json:"integrity"`
+	Availability            string   `yaml:"availability" json:"availability"`
+	JustificationCiaRating  string   `yaml:"justification_cia_rating" json:"justification_cia_rating"`
+	MultiTenant             bool     `yaml:"multi_tenant" json:"multi_tenant"`
+	Redundant               bool     `yaml:"redundant" json:"redundant"`
+	CustomDevelopedParts    bool     `yaml:"custom_developed_parts" json:"custom_developed_parts"`
+	DataAssetsProcessed     []string `yaml:"data_assets_processed" json:"data_assets_processed"`
+	DataAssetsStored        []string `yaml:"data_assets_stored" json:"data_assets_stored"`
+	DataFormatsAccepted     []string `yaml:"data_formats_accepted" json:"data_formats_accepted"`
+	DiagramTweakOrder       int      `yaml:"diagram_tweak_order" json:"diagram_tweak_order"`
+}
+
+func (s *server) getTechnicalAsset(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, techAsset := range modelInput.TechnicalAssets {
+			if techAsset.ID == ginContext.Param("technical-asset-id") {
+				ginContext.JSON(http.StatusOK, gin.H{
+					title: techAsset,
+				})
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "technical asset not found",
+		})
+	}
+}
+
+func (s *server) createNewTechnicalAsset(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		payload := payloadTechnicalAsset{}
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		if _, exists := modelInput.TechnicalAssets[payload.Title]; exists {
+			ginContext.JSON(http.StatusConflict, gin.H{
+				"error": "technical asset with this title already exists",
+			})
+			return
+		}
+		// but later it will in memory keyed by its "id", so do this uniqueness check also
+		for _, techAsset := range modelInput.TechnicalAssets {
+			if techAsset.ID == payload.Id {
+				ginContext.JSON(http.StatusConflict, gin.H{
+					"error": "technical asset with this id already exists",
+				})
+				return
+			}
+		}
+		techAssetInput, ok := s.populateTechnicalAsset(ginContext, modelInput, payload)
+		if !ok {
+			return
+		}
+		if modelInput.TechnicalAssets == nil {
+			modelInput.TechnicalAssets = make(map[string]input.TechnicalAsset)
+		}
+		modelInput.TechnicalAssets[payload.Title] = techAssetInput
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Technical Asset Creation")
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "technical asset created",
+				"id":      techAssetInput.ID,
+			})
+		}
+	}
+}
+
+func (s *server) setTechnicalAsset(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, techAsset := range modelInput.TechnicalAssets {
+			if techAsset.ID == ginContext.Param("technical-asset-id") {
+				payload := payloadTechnicalAsset{}
+				err := ginContext.BindJSON(&payload)
+				if err != nil {
+					log.Println(err)
+					ginContext.JSON(http.StatusBadRequest, gin.H{
+						"error": "unable to parse request payload",
+					})
+					return
+				}
+				for otherTitle, other := range modelInput.TechnicalAssets {
+					if otherTitle != title && (otherTitle == payload.Title || other.ID == payload.Id) {
+						ginContext.JSON(http.StatusConflict, gin.H{
+							"error": "technical asset with this title or id already exists",
+						})
+						return
+					}
+				}
+				techAssetInput, ok := s.populateTechnicalAsset(ginContext, modelInput, payload)
+				if !ok {
+					return
+				}
+				// the communication links are maintained as sub-resource of the technical asset
+				techAssetInput.CommunicationLinks = techAsset.CommunicationLinks
+				// in order to also update the title, remove the asset from the map and re-insert it (with new key)
+				delete(modelInput.TechnicalAssets, title)
+				modelInput.TechnicalAssets[payload.Title] = techAssetInput
+				idChanged := techAssetInput.ID != techAsset.ID
+				if idChanged { // ID-CHANGE-PROPAGATION
+					renameTechnicalAssetReferences(&modelInput, techAsset.ID, techAssetInput.ID)
+				}
+				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Technical Asset Update")
+				if ok {
+					ginContext.JSON(http.StatusOK, gin.H{
+						"message":    "technical asset updated",
+						"id":         techAssetInput.ID,
+						"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
+					})
+				}
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "technical asset not found",
+		})
+	}
+}
+
+// deleting a technical asset also deletes its communication links and all links targeting it, and removes it from
+// trust boundaries, shared runtimes, individual risks, risk tracking and diagram tweaks
+func (s *server) deleteTechnicalAsset(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, techAsset := range modelInput.TechnicalAssets {
+			if techAsset.ID == ginContext.Param("technical-asset-id") {
+				referencesDeleted := deleteTechnicalAssetReferences(&modelInput, techAsset)
+				// remove it itself
+				delete(modelInput.TechnicalAssets, title)
+				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Technical Asset Deletion")
+				if ok {
+					ginContext.JSON(http.StatusOK, gin.H{
+						"message":            "technical asset deleted",
+						"id":                 techAsset.ID,
+						"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
+					})
+				}
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "technical asset not found",
+		})
+	}
+}
+
+func (s *server) populateTechnicalAsset(ginContext *gin.Context, modelInput input.Model, payload payloadTechnicalAsset) (techAssetInput input.TechnicalAsset, ok bool) {
+	assetType, err := types.ParseTechnicalAssetType(payload.Type)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return techAssetInput, false
+	}
+	usage, err := types.ParseUsage(payload.Usage)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return techAssetInput, false
+	}
+	size, err := types.ParseTechnicalAssetSize(payload.Size)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return techAssetInput, false
+	}
+	technology, err := s.parseTechnology(modelInput, payload.Technology)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return techAssetInput, false
+	}
+	machine, err := types.ParseTechnicalAssetMachine(payload.Machine)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return techAssetInput, false
+	}
+	encryption, err := types.ParseEncryptionStyle(payload.Encryption)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return techAssetInput, false
+	}
+	confidentiality, err := types.ParseConfidentiality(payload.Confidentiality)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return techAssetInput, false
+	}
+	integrity, err := types.ParseCriticality(payload.Integrity)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return techAssetInput, false
+	}
+	availability, err := types.ParseCriticality(payload.Availability)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return techAssetInput, false
+	}
+	dataFormats := make([]string, 0)
+	for _, value := range payload.DataFormatsAccepted {
+		dataFormat, err := types.ParseDataFormat(value)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return techAssetInput, false
+		}
+		dataFormats = append(dataFormats, dataFormat.String())
+	}
+	if !checkDataAssetsExisting(modelInput, payload.DataAssetsProcessed) || !checkDataAssetsExisting(modelInput, payload.DataAssetsStored) {
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "referenced data asset does not exist",
+		})
+		return techAssetInput, false
+	}
+	techAssetInput = input.TechnicalAsset{
+		ID:                      payload.Id,
+		Description:             payload.Description,
+		Type:                    assetType.String(),
+		Usage:                   usage.String(),
+		UsedAsClientByHuman:     payload.UsedAsClientByHuman,
+		OutOfScope:              payload.OutOfScope,
+		JustificationOutOfScope: payload.JustificationOutOfScope,
+		Size:                    size.String(),
+		Technology:              technology,
+		Tags:                    lowerCaseAndTrim(payload.Tags),
+		Internet:                payload.Internet,
+		Machine:                 machine.String(),
+		Encryption:              encryption.String(),
+		Owner:                   payload.Owner,
+		Confidentiality:         confidentiality.String(),
+		Integrity:               integrity.String(),
+		Availability:            availability.String(),
+		JustificationCiaRating:  payload.JustificationCiaRating,
+		MultiTenant:             payload.MultiTenant,
+		Redundant:               payload.Redundant,
+		CustomDevelopedParts:    payload.CustomDevelopedParts,
+		DataAssetsProcessed:     payload.DataAssetsProcessed,
+		DataAssetsStored:        payload.DataAssetsStored,
+		DataFormatsAccepted:     dataFormats,
+		DiagramTweakOrder:       payload.DiagramTweakOrder,
+	}
+	return techAssetInput, true
+}
+
+// parseTechnology returns the name of a built-in technology or of one declared by the model or the config
+func (s *server) parseTechnology(modelInput input.Model, value string) (string, error) {
+	builtIn, err := types.ParseTechnicalAssetTechnology(value)
+	if err == nil {
+		return builtIn.String(), nil
+	}
+	technologies, _ := model.ResolveTechnologies(model.WithTechnologies(&modelInput, s.config.Technologies))
+	if technology, found := technologies[strings.TrimSpace(value)]; found {
+		return technology.Name, nil
+	}
+	return "", err
+}
+
+func checkDataAssetsExisting(modelInput input.Model, dataAssetIDs []string) (ok bool) {
+	for _, dataAssetID := range dataAssetIDs {
+		exists := false
+		for _, val := range modelInput.DataAssets {
+			if val.ID == dataAssetID {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			return false
+		}
+	}
+	return true
+}
+
+func renameTechnicalAssetReferences(modelInput *input.Model, oldID string, newID string) {
+	for techAssetTitle, techAsset := range modelInput.TechnicalAssets {
+		for linkTitle, commLink := range techAsset.CommunicationLinks {
+			if commLink.Target == oldID {
+				commLink.Target = newID
+				modelInput.TechnicalAssets[techAssetTitle].CommunicationLinks[linkTitle] = commLink
+			}
+		}
+	}
+	for title, trustBoundary := range modelInput.TrustBoundaries {
+		if replaceValue(trustBoundary.TechnicalAssetsInside, oldID, newID) {
+			modelInput.TrustBoundaries[title] = trustBoundary
+		}
+	}
+	for title, sharedRuntime := range modelInput.SharedRuntimes {
+		if replaceValue(sharedRuntime.TechnicalAssetsRunning, oldID, newID) {
+			modelInput.SharedRuntimes[title] = sharedRuntime
+		}
+	}
+	for individualRiskCatTitle, individualRiskCat := range modelInput.IndividualRiskCategories {
+		for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+			if individualRiskInstance.MostRelevantTechnicalAsset == oldID {
+				individualRiskInstance.MostRelevantTechnicalAsset = newID
+			}
+			if linkTitle, found := strings.CutPrefix(individualRiskInstance.MostRelevantCommunicationLink, oldID+">"); found {
+				individualRiskInstance.MostRelevantCommunicationLink = newID + ">" + linkTitle
+			}
+			replaceValue(individualRiskInstance.DataBreachTechnicalAssets, oldID, newID)
+			modelInput.IndividualRiskCategories[individualRiskCatTitle].RisksIdentified[individualRiskInstanceTitle] = individualRiskInstance
+		}
+	}
+	renamedRiskTracking := make(map[string]input.RiskTracking)
+	for syntheticRiskId, riskTracking := range modelInput.RiskTracking {
+		elementIDs := strings.Split(syntheticRiskId, "@")
+		renamed := false
+		for i := 1; i < len(elementIDs); i++ { // the first one is the risk category
+			if elementIDs[i] == oldID {
+				elementIDs[i] = newID
+				renamed = true
+			} else if linkTitle, found := strings.CutPrefix(elementIDs[i], oldID+">"); found {
+				elementIDs[i] = newID + ">" + linkTitle
+				renamed = true
+			}
+		}
+		if renamed {
+			delete(modelInput.RiskTracking, syntheticRiskId)
+			renamedRiskTracking[strings.Join(elementIDs, "@")] = riskTracking
+		}
+	}
+	for syntheticRiskId, riskTracking := range renamedRiskTracking {
+		modelInput.RiskTracking[syntheticRiskId] = riskTracking
+	}
+	for i, invisibleConnection := range modelInput.DiagramTweakInvisibleConnectionsBetweenAssets {
+		assetIDs := strings.Split(invisibleConnection, ":")
+		replaceValue(assetIDs, oldID, newID)
+		modelInput.DiagramTweakInvisibleConnectionsBetweenAssets[i] = strings.Join(assetIDs, ":")
+	}
+	for i, sameRank := range modelInput.DiagramTweakSameRankAssets {
+		assetIDs := strings.Split(sameRank, ":")
+		replaceValue(assetIDs, oldID, newID)
+		modelInput.DiagramTweakSameRankAssets[i] = strings.Join(assetIDs, ":")
+	}
+}
+
+func deleteTechnicalAssetReferences(modelInput *input.Model, deletedAsset input.TechnicalAsset) (referencesDeleted bool) {
+	deletedLinkIDs := make(map[string]bool)
+	for linkTitle := range deletedAsset.CommunicationLinks {
+		deletedLinkIDs[communicationLinkID(deletedAsset.ID, linkTitle)] = true
+	}
+	for techAssetTitle, techAsset := range modelInput.TechnicalAssets {
+		for linkTitle, commLink := range techAsset.CommunicationLinks {
+			if commLink.Target == deletedAsset.ID && techAsset.ID != deletedAsset.ID {
+				referencesDeleted = true
+				deletedLinkIDs[communicationLinkID(techAsset.ID, linkTitle)] = true
+				delete(modelInput.TechnicalAssets[techAssetTitle].CommunicationLinks, linkTitle)
+			}
+		}
+	}
+	for title, trustBoundary := range modelInput.TrustBoundaries {
+		var removed bool
+		trustBoundary.TechnicalAssetsInside, removed = removeValue(trustBoundary.TechnicalAssetsInside, deletedAsset.ID)
+		if removed {
+			referencesDeleted = true
+			modelInput.TrustBoundaries[title] = trustBoundary
+		}
+	}
+	for title, sharedRuntime := range modelInput.SharedRuntimes {
+		var removed bool
+		sharedRuntime.TechnicalAssetsRunning, removed = removeValue(sharedRuntime.TechnicalAssetsRunning, deletedAsset.ID)
+		if removed {
+			referencesDeleted = true
+			modelInput.SharedRuntimes[title] = sharedRuntime
+		}
+	}
+	for individualRiskCatTitle, individualRiskCat := range modelInput.IndividualRiskCategories {
+		for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+			var removed bool
+			if individualRiskInstance.MostRelevantTechnicalAsset == deletedAsset.ID {
+				individualRiskInstance.MostRelevantTechnicalAsset = ""
+				removed = true
+			}
+			if deletedLinkIDs[individualRiskInstance.MostRelevantCommunicationLink] {
+				individualRiskInstance.MostRelevantCommunicationLink = ""
+				removed = true
+			}
+			var removedBreach bool
+			individualRiskInstance.DataBreachTechnicalAssets, removedBreach = removeValue(individualRiskInstance.DataBreachTechnicalAssets, deletedAsset.ID)
+			if removed || removedBreach {
+				referencesDeleted = true
+				modelInput.IndividualRiskCategories[individualRiskCatTitle].RisksIdentified[individualRiskInstanceTitle] = individualRiskInstance
+			}
+		}
+	}
+	for syntheticRiskId := range modelInput.RiskTracking {
+		for _, elementID := range strings.Split(syntheticRiskId, "@")[1:] { // the first one is the risk category
+			if elementID == deletedAsset.ID || deletedLinkIDs[elementID] {
+				referencesDeleted = true
+				delete(modelInput.RiskTracking, syntheticRiskId)
+				break
+			}
+		}
+	}
+	invisibleConnections := make([]string, 0)
+	for _, invisibleConnection := range modelInput.DiagramTweakInvisibleConnectionsBetweenAssets {
+		if _, removed := removeValue(strings.Split(invisibleConnection, ":"), deletedAsset.ID); removed {
+			referencesDeleted = true
+			continue
+		}
+		invisibleConnections = append(invisibleConnections, invisibleConnection)
+	}
+	modelInput.DiagramTweakInvisibleConnectionsBetweenAssets = invisibleConnections
+	sameRanks := make([]string, 0)
+	for _, sameRank := range modelInput.DiagramTweakSameRankAssets {
+		assetIDs, removed := removeValue(strings.Split(sameRank, ":"), deletedAsset.ID)
+		if removed {
+			referencesDeleted = true
+			if len(assetIDs) < 2 {
+				continue
+			}
+		}
+		sameRanks = append(sameRanks, strings.Join(assetIDs, ":"))
+	}
+	modelInput.DiagramTweakSameRankAssets = sameRanks
+	return referencesDeleted
+}
+
+// replaceValue replaces all occurrences of oldValue in place and returns whether any was found
+func replaceValue(values []string, oldValue string, newValue string) (replaced bool) {
+	for i, value := range values {
+		if value == oldValue {
+			values[i] = newValue
+			replaced = true
+		}
+	}
+	return replaced
+}
+
+// removeValue returns a copy of values without any occurrence of value and whether any was found
+func removeValue(values []string, value string) (result []string, removed bool) {
+	if values == nil {
+		return nil, false
+	}
+	result = make([]string, 0, len(values))
+	for _, candidate := range values {
+		if candidate == value {
+			removed = true
+			continue
+		}
+		result = append(result, candidate)
+	}
+	return result, removed
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func createTechnicalAssetTestModel(server *testServer, token string) string {
+	modelID := server.createModel(token)
+	modelInput := server.readModel(token, modelID)
+	technicalAsset := func(id string, links map[string]input.CommunicationLink) input.TechnicalAsset {
+		return input.TechnicalAsset{ID: id, Type: "process", Usage: "business", Size: "service", Technology: "web-server", Machine: "virtual",
+			Encryption: "none", Confidentiality: "internal", Integrity: "operational", Availability: "operational", CommunicationLinks: links}
+	}
+	communicationLink := func(target string, protocol string) map[string]input.CommunicationLink {
+		return map[string]input.CommunicationLink{target: {Target: target, Protocol: protocol, Authentication: "none", Authorization: "none", Usage: "business"}}
+	}
+	modelInput.BusinessCriticality = "important"
+	modelInput.TechnicalAssets = map[string]input.TechnicalAsset{
+		"Web Server": technicalAsset("web", communicationLink("db", "jdbc")),
+		"Database":   technicalAsset("db", communicationLink("backup", "ssh")),
+		"Backup":     technicalAsset("backup", nil),
+	}
+	modelInput.RiskTracking = map[string]input.RiskTracking{
+		"sql-nosql-injection@db@web>db":                     {Status: "mitigated"},
+		"unencrypted-asset@db":                              {Status: "accepted", Justification: "Encrypted disks"},
+		"unencrypted-asset@web":                             {Status: "accepted"},
+		"unencrypted-communication@backup@db>backup":        {Status: "in-progress"},
+		"unencrypted-communication@*@web>db":                {Status: "unchecked"},
+		"missing-vault@*":                                   {Status: "accepted"},
+		"unguarded-direct-datastore-access@dba@dba>console": {Status: "accepted"}, // db is a prefix only
+	}
+	server.writeModel(token, modelID, &modelInput)
+	return modelID
+}
+
+func riskTrackingIds(modelInput input.Model) []string {
+	ids := make([]string, 0)
+	for id := range modelInput.RiskTracking {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestRenameTechnicalAssetRenamesRiskTracking(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createTechnicalAssetTestModel(server, token)
+
+	var response struct {
+		ID        string `json:"id"`
+		IDChanged bool   `json:"id_changed"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPut, "/models/"+modelID+"/technical-assets/db", payloadTechnicalAsset{
+		Title: "Database", Id: "database", Type: "datastore", Usage: "business", Size: "service", Technology: "database", Machine: "virtual",
+		Encryption: "none", Confidentiality: "internal", Integrity: "operational", Availability: "operational",
+	}, &response))
+	assert.True(t, response.IDChanged)
+
+	modelInput := server.readModel(token, modelID)
+	assert.Equal(t, []string{
+		"missing-vault@*",
+		"sql-nosql-injection@database@web>db",
+		"unencrypted-asset@database",
+		"unencrypted-asset@web",
+		"unencrypted-communication@*@web>db",
+		"unencrypted-communication@backup@database>backup",
+		"unguarded-direct-datastore-access@dba@dba>console",
+	}, riskTrackingIds(modelInput))
+	assert.Equal(t, "Encrypted disks", modelInput.RiskTracking["unencrypted-asset@database"].Justification)
+	assert.Equal(t, "database", modelInput.TechnicalAssets["Web Server"].CommunicationLinks["db"].Target)
+}
+
+func TestDeleteTechnicalAssetDeletesRiskTracking(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createTechnicalAssetTestModel(server, token)
+
+	var response struct {
+		ReferencesDeleted bool `json:"references_deleted"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodDelete, "/models/"+modelID+"/technical-assets/db", nil, &response))
+	assert.True(t, response.ReferencesDeleted)
+
+	modelInput := server.readModel(token, modelID)
+	assert.Equal(t, []string{ // without the ones of the asset, its own links and the links targeting it
+		"missing-vault@*",
+		"unencrypted-asset@web",
+		"unguarded-direct-datastore-access@dba@dba>console",
+	}, riskTrackingIds(modelInput))
+	assert.NotContains(t, modelInput.TechnicalAssets, "Database")
+	assert.Empty(t, modelInput.TechnicalAssets["Web Server"].CommunicationLinks)
+
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodDelete, "/models/"+modelID+"/technical-assets/db", nil, nil))
+}
+
+func TestDeleteTechnicalAssetDeletesItFromTrustBoundariesAndSharedRuntimes(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createTechnicalAssetTestModel(server, token)
+	modelInput := server.readModel(token, modelID)
+	modelInput.TrustBoundaries = map[string]input.TrustBoundary{
+		"Backend": {ID: "backend", Type: "network-cloud-security-group", TechnicalAssetsInside: []string{"db", "backup"}},
+		"Web":     {ID: "web-zone", Type: "network-cloud-security-group", TechnicalAssetsInside: []string{"web"}},
+	}
+	modelInput.SharedRuntimes = map[string]input.SharedRuntime{
+		"Cluster":  {ID: "cluster", TechnicalAssetsRunning: []string{"web", "db"}},
+		"Database": {ID: "database", TechnicalAssetsRunning: []string{"db"}},
+	}
+	server.writeModel(token, modelID, &modelInput)
+
+	var response struct {
+		ReferencesDeleted bool `json:"references_deleted"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodDelete, "/models/"+modelID+"/technical-assets/db", nil, &response))
+	assert.True(t, response.ReferencesDeleted)
+
+	modelInput = server.readModel(token, modelID)
+	assert.Equal(t, []string{"backup"}, modelInput.TrustBoundaries["Backend"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"web"}, modelInput.TrustBoundaries["Web"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"web"}, modelInput.SharedRuntimes["Cluster"].TechnicalAssetsRunning)
+	assert.Empty(t, modelInput.SharedRuntimes["Database"].TechnicalAssetsRunning) // the runtime itself stays
+	assert.Contains(t, modelInput.SharedRuntimes, "Database")
+}
+
+func TestCreateTechnicalAssetWithDeclaredTechnology(t *testing.T) {
+	server := newTestServer(t)
+	server.server.config.Technologies["feature-store"] = input.Technology{Parent: "database"}
+	_, token := server.createKey()
+	modelID := server.createModel(token)
+	modelInput := server.readModel(token, modelID)
+	modelInput.Technologies = map[string]input.Technology{
+		"api-management": {Parent: "web-server", Traits: map[string]bool{"traffic-forwarding": true}},
+	}
+	server.writeModel(token, modelID, &modelInput)
+
+	technicalAsset := func(id string, technology string) payloadTechnicalAsset {
+		return payloadTechnicalAsset{Title: id, Id: id, Type: "process", Usage: "business", Size: "service", Technology: technology, Machine: "virtual",
+			Encryption: "none", Confidentiality: "internal", Integrity: "operational", Availability: "operational"}
+	}
+	path := "/models/" + modelID + "/technical-assets"
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPost, path, technicalAsset("gateway", "api-management"), nil))
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPost, path, technicalAsset("features", " feature-store "), nil))
+	assert.Equal(t, http.StatusBadRequest, server.withToken(token, http.MethodPost, path, technicalAsset("unknown", "api-gateway-of-some-vendor"), nil))
+	modelInput = server.readModel(token, modelID)
+	assert.Equal(t, "api-management", modelInput.TechnicalAssets["gateway"].Technology)
+	assert.Equal(t, "feature-store", modelInput.TechnicalAssets["features"].Technology) // declared by the config
+	assert.NotContains(t, modelInput.TechnicalAssets, "unknown")
+
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPut, path+"/features", technicalAsset("features", "api-management"), nil))
+	assert.Equal(t, "api-management", server.readModel(token, modelID).TechnicalAssets["features"].Technology)
+}
@@ -6,11 +6,8 @@ package server
 import (
 	"crypto/rand"
 	"encoding/base64"
-	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -23,11 +20,6 @@ type keyHeader struct {
 	Key string `header:"key"`
 }
 
-type timeoutStruct struct {
-	xorRand                               []byte
-	createdNanoTime, lastAccessedNanoTime int64
-}
-
 func (s *server) createKey(ginContext *gin.Context) {
 	ok := s.checkObjectCreationThrottler(ginContext, "KEY")
 	if !ok {
@@ -45,7 +37,7 @@ func (s *server) createKey(ginContext *gin.Context) {
 		})
 		return
 	}
-	err = os.MkdirAll(s.folderNameFromKey(keyBytesArr), 0700)
+	err = s.storage.CreateKey(s.folderNameFromKey(keyBytesArr))
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusInternalServerError, gin.H{
@@ -103,12 +95,12 @@ func (s *server) checkObjectCreationThrottler(ginContext *gin.Context, typeName
 
 func (s *server) deleteKey(ginContext *gin.Context) {
 	folderName, _, ok := s.checkKeyToFolderName(ginContext)
-	if !ok {
+	if !ok || !s.checkKeyOwner(ginContext) {
 		return
 	}
 	s.globalLock.Lock()
 	defer s.globalLock.Unlock()
-	err := os.RemoveAll(folderName)
+	err := s.storage.DeleteKey(folderName)
 	if err != nil {
 		log.Println("error during key delete: " + err.Error())
 		ginContext.JSON(http.StatusNotFound, gin.H{
@@ -121,17 +113,22 @@ func (s *server) deleteKey(ginContext *gin.Context) {
 	})
 }
 
+// creates a token for a key or user key, the optional "role" query parameter limits the roles of the token
+// (e.g. to hand out a read-only token)
 func (s *server) createToken(ginContext *gin.Context) {
-	folderName, key, ok := s.checkKeyToFolderName(ginContext)
+	_, key, ok := s.checkKeyToFolderName(ginContext)
 	if !ok {
 		return
 	}
+	role := ginContext.Query("role")
+	if _, valid := roleRanks[role]; len(role) > 0 && !valid {
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "unknown role: " + role,
+		})
+		return
+	}
 	s.globalLock.Lock()
 	defer s.globalLock.Unlock()
-	if tokenHash, exists := s.mapFolderNameToTokenHash[folderName]; exists {
-		// invalidate previous token
-		delete(s.mapTokenHashToTimeoutStruct, tokenHash)
-	}
 	// create a strong random 256 bit value (used to xor)
 	xorBytesArr := make([]byte, keySize)
 	n, err := rand.Read(xorBytesArr[:])
@@ -145,13 +142,22 @@ func (s *server) createToken(ginContext *gin.Context) {
 	now := time.Now().UnixNano()
 	token := xor(key, xorBytesArr)
 	tokenHash := hashSHA256(token)
-	s.housekeepingTokenMaps()
-	s.mapTokenHashToTimeoutStruct[tokenHash] = timeoutStruct{
-		xorRand:              xorBytesArr,
-		createdNanoTime:      now,
-		lastAccessedNanoTime: now,
+	s.housekeepingTokens()
+	err = s.storage.PutToken(StoredToken{ // invalidates the previous token of the key or user key
+		TokenHash:            tokenHash,
+		KeyHash:              principalOf(ginContext).keyHash,
+		Role:                 role,
+		XorRand:              xorBytesArr,
+		CreatedNanoTime:      now,
+		LastAccessedNanoTime: now,
+	})
+	if err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "unable to create token",
+		})
+		return
 	}
-	s.mapFolderNameToTokenHash[folderName] = tokenHash
 	ginContext.JSON(http.StatusCreated, gin.H{
 		"token": base64.RawURLEncoding.EncodeToString(token[:]),
 	})
@@ -181,7 +187,9 @@ func (s *server) deleteToken(ginContext *gin.Context) {
 	}
 	s.globalLock.Lock()
 	defer s.globalLock.Unlock()
-	s.deleteTokenHashFromMaps(hashSHA256(token))
+	if err = s.storage.DeleteToken(hashSHA256(token)); err != nil {
+		log.Println(err)
+	}
 	ginContext.JSON(http.StatusOK, gin.H{
 		"message": "token deleted",
 	})
@@ -206,14 +214,14 @@ func (s *server) checkKeyToFolderName(ginContext *gin.Context) (folderNameOfKey
 		})
 		return folderNameOfKey, key, false
 	}
-	folderNameOfKey = s.folderNameFromKey(key)
-	if _, err := os.Stat(folderNameOfKey); os.IsNotExist(err) {
-		log.Println(err)
+	folderNameOfKey, key, p, ok := s.resolveKey(key) // a user key is resolved to the key of the models
+	if !ok {
 		ginContext.JSON(http.StatusNotFound, gin.H{
 			"error": "key not found",
 		})
 		return folderNameOfKey, key, false
 	}
+	ginContext.Set(principalContextKey, p)
 	return folderNameOfKey, key, true
 }
 
@@ -238,60 +246,71 @@ func (s *server) checkTokenToFolderName(ginContext *gin.Context) (folderNameOfKe
 	}
 	s.globalLock.Lock()
 	defer s.globalLock.Unlock()
-	s.housekeepingTokenMaps() // to remove timed-out ones
+	s.housekeepingTokens() // to remove timed-out ones
 	tokenHash := hashSHA256(token)
-	if timeoutStruct, exists := s.mapTokenHashToTimeoutStruct[tokenHash]; exists {
-		// re-create the key from token
-		key := xor(token, timeoutStruct.xorRand)
-		folderNameOfKey := s.folderNameFromKey(key)
-		if _, err := os.Stat(folderNameOfKey); os.IsNotExist(err) {
+	storedToken, exists, err := s.storage.GetToken(tokenHash)
+	if err != nil {
+		log.Println(err)
+	}
+	if !exists {
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "token not found",
+		})
+		return folderNameOfKey, key, false
+	}
+	// re-create the key from token
+	key = xor(token, storedToken.XorRand)
+	folderNameOfKey = s.folderNameFromKey(key)
+	if exists, err := s.storage.KeyExists(folderNameOfKey); !exists {
+		if err != nil {
 			log.Println(err)
-			ginContext.JSON(http.StatusNotFound, gin.H{
-				"error": "token not found",
-			})
-			return folderNameOfKey, key, false
 		}
-		timeoutStruct.lastAccessedNanoTime = time.Now().UnixNano()
-		return folderNameOfKey, key, true
-	} else {
 		ginContext.JSON(http.StatusNotFound, gin.H{
 			"error": "token not found",
 		})
 		return folderNameOfKey, key, false
 	}
+	p := principal{keyHash: folderNameOfKey, maxRole: storedToken.Role}
+	if storedToken.KeyHash != folderNameOfKey { // token of a user, which is checked again to revoke access immediately
+		user, found, err := s.storage.GetUser(storedToken.KeyHash)
+		if err != nil {
+			log.Println(err)
+		}
+		if !found || user.KeyHash != folderNameOfKey {
+			ginContext.JSON(http.StatusNotFound, gin.H{
+				"error": "token not found",
+			})
+			return folderNameOfKey, key, false
+		}
+		p = userPrincipal(storedToken.KeyHash, user)
+		p.maxRole = storedToken.Role
+	}
+	ginContext.Set(principalContextKey, p)
+	if err = s.storage.TouchToken(tokenHash, time.Now().UnixNano()); err != nil {
+		log.Println(err)
+	}
+	return folderNameOfKey, key, true
 }
 
+// folderNameFromKey returns the hash of the key, which identifies the key in the storage (as folder name of the key
+// in case of the filesystem storage)
 func (s *server) folderNameFromKey(key []byte) string {
-	sha512Hash := hashSHA256(key)
-	return filepath.Join(s.config.ServerFolder, s.config.KeyFolder, sha512Hash)
+	return hashSHA256(key)
 }
 
-func (s *server) housekeepingTokenMaps() {
+func (s *server) housekeepingTokens() {
 	now := time.Now().UnixNano()
-	for tokenHash, val := range s.mapTokenHashToTimeoutStruct {
-		if s.extremeShortTimeoutsForTesting {
-			// remove all elements older than 1 minute (= 60000000000 ns) soft
-			// and all elements older than 3 minutes (= 180000000000 ns) hard
-			if now-val.lastAccessedNanoTime > 60000000000 || now-val.createdNanoTime > 180000000000 {
-				fmt.Println("About to remove a token hash from maps")
-				s.deleteTokenHashFromMaps(tokenHash)
-			}
-		} else {
-			// remove all elements older than 30 minutes (= 1800000000000 ns) soft
-			// and all elements older than 10 hours (= 36000000000000 ns) hard
-			if now-val.lastAccessedNanoTime > 1800000000000 || now-val.createdNanoTime > 36000000000000 {
-				s.deleteTokenHashFromMaps(tokenHash)
-			}
-		}
+	var err error
+	if s.extremeShortTimeoutsForTesting {
+		// remove all elements older than 1 minute (= 60000000000 ns) soft
+		// and all elements older than 3 minutes (= 180000000000 ns) hard
+		err = s.storage.DeleteTokensIdleOrCreatedBefore(now-60000000000, now-180000000000)
+	} else {
+		// remove all elements older than 30 minutes (= 1800000000000 ns) soft
+		// and all elements older than 10 hours (= 36000000000000 ns) hard
+		err = s.storage.DeleteTokensIdleOrCreatedBefore(now-1800000000000, now-36000000000000)
 	}
-}
-
-func (s *server) deleteTokenHashFromMaps(tokenHash string) {
-	delete(s.mapTokenHashToTimeoutStruct, tokenHash)
-	for folderName, check := range s.mapFolderNameToTokenHash {
-		if check == tokenHash {
-			delete(s.mapFolderNameToTokenHash, folderName)
-			break
-		}
+	if err != nil {
+		log.Println(err)
 	}
 }
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenRevocation(t *testing.T) {
+	server := newTestServer(t)
+	key, ownerToken := server.createKey()
+	modelID := server.createModel(ownerToken)
+	modelPath := "/models/" + modelID
+
+	// deleting a token revokes it
+	assert.Equal(t, http.StatusOK, server.withToken(ownerToken, http.MethodDelete, "/auth/tokens", nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(ownerToken, http.MethodGet, modelPath, nil, nil))
+
+	// creating a token revokes the previous one of the same key
+	firstToken := server.createToken(key, "")
+	secondToken := server.createToken(key, roleViewer)
+	assert.Equal(t, http.StatusNotFound, server.withToken(firstToken, http.MethodGet, modelPath, nil, nil))
+	assert.Equal(t, http.StatusOK, server.withToken(secondToken, http.MethodGet, modelPath, nil, nil))
+	ownerToken = server.createToken(key, "")
+
+	// changing the roles of a user or deleting the user revokes access immediately
+	userID, userKey := server.createUser(ownerToken, "User", map[string]string{modelID: roleEditor})
+	userToken := server.createToken(userKey, "")
+	assert.Equal(t, http.StatusOK, server.withToken(userToken, http.MethodGet, modelPath, nil, nil))
+	assert.Equal(t, http.StatusOK, server.withToken(ownerToken, http.MethodPut, "/auth/users/"+userID, payloadUser{Name: "User", Roles: map[string]string{modelID: roleViewer}}, nil))
+	assert.Equal(t, http.StatusForbidden, server.withToken(userToken, http.MethodPut, modelPath+"/cover", payloadCover{Title: "Changed Title"}, nil))
+	assert.Equal(t, http.StatusOK, server.withToken(ownerToken, http.MethodPut, "/auth/users/"+userID, payloadUser{Name: "User"}, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(userToken, http.MethodGet, modelPath, nil, nil))
+	assert.Equal(t, http.StatusOK, server.withToken(ownerToken, http.MethodDelete, "/auth/users/"+userID, nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(userToken, http.MethodGet, "/models", nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.request(http.MethodPost, "/auth/tokens", map[string]string{"key": userKey}, nil, nil))
+
+	// the token of a user does not revoke the one of the key owner
+	_, otherUserKey := server.createUser(ownerToken, "Other User", map[string]string{modelID: roleViewer})
+	_ = server.createToken(otherUserKey, "")
+	assert.Equal(t, http.StatusOK, server.withToken(ownerToken, http.MethodGet, modelPath, nil, nil))
+
+	// deleting the key revokes all tokens
+	assert.Equal(t, http.StatusOK, server.request(http.MethodDelete, "/auth/keys", map[string]string{"key": key}, nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(ownerToken, http.MethodGet, "/models", nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.request(http.MethodPost, "/auth/tokens", map[string]string{"key": key}, nil, nil))
+}
+
+func TestCreateTokenWithUnknownRole(t *testing.T) {
+	server := newTestServer(t)
+	key, _ := server.createKey()
+	assert.Equal(t, http.StatusBadRequest, server.request(http.MethodPost, "/auth/tokens?role=admin", map[string]string{"key": key}, nil, nil))
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type payloadTrustBoundary struct {
+	Title                 string   `yaml:"title" json:"title"`
+	Id                    string   `yaml:"id" json:"id"`
+	Description           string   `yaml:"description" json:"description"`
+	Type                  string   `yaml:"type" json:"type"`
+	Tags                  []string `yaml:"tags" json:"tags"`
+	TechnicalAssetsInside []string `yaml:"technical_assets_inside" json:"technical_assets_inside"`
+	TrustBoundariesNested []string `yaml:"trust_boundaries_nested" json:"trust_boundaries_nested"`
+}
+
+func (s *server) getTrustBoundary(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleViewer)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, trustBoundary := range modelInput.TrustBoundaries {
+			if trustBoundary.ID == ginContext.Param("trust-boundary-id") {
+				ginContext.JSON(http.StatusOK, gin.H{
+					title: trustBoundary,
+				})
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "trust boundary not found",
+		})
+	}
+}
+
+func (s *server) createNewTrustBoundary(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		payload := payloadTrustBoundary{}
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		if _, exists := modelInput.TrustBoundaries[payload.Title]; exists {
+			ginContext.JSON(http.StatusConflict, gin.H{
+				"error": "trust boundary with this title already exists",
+			})
+			return
+		}
+		// but later it will in memory keyed by its "id", so do this uniqueness check also
+		for _, trustBoundary := range modelInput.TrustBoundaries {
+			if trustBoundary.ID == payload.Id {
+				ginContext.JSON(http.StatusConflict, gin.H{
+					"error": "trust boundary with this id already exists",
+				})
+				return
+			}
+		}
+		trustBoundaryInput, ok := populateTrustBoundary(ginContext, modelInput, "", payload)
+		if !ok {
+			return
+		}
+		if modelInput.TrustBoundaries == nil {
+			modelInput.TrustBoundaries = make(map[string]input.TrustBoundary)
+		}
+		modelInput.TrustBoundaries[payload.Title] = trustBoundaryInput
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Trust Boundary Creation")
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "trust boundary created",
+				"id":      trustBoundaryInput.ID,
+			})
+		}
+	}
+}
+
+func (s *server) setTrustBoundary(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, trustBoundary := range modelInput.TrustBoundaries {
+			if trustBoundary.ID == ginContext.Param("trust-boundary-id") {
+				payload := payloadTrustBoundary{}
+				err := ginContext.BindJSON(&payload)
+				if err != nil {
+					log.Println(err)
+					ginContext.JSON(http.StatusBadRequest, gin.H{
+						"error": "unable to parse request payload",
+					})
+					return
+				}
+				for otherTitle, other := range modelInput.TrustBoundaries {
+					if otherTitle != title && (otherTitle == payload.Title || other.ID == payload.Id) {
+						ginContext.JSON(http.StatusConflict, gin.H{
+							"error": "trust boundary with this title or id already exists",
+						})
+						return
+					}
+				}
+				trustBoundaryInput, ok := populateTrustBoundary(ginContext, modelInput, title, payload)
+				if !ok {
+					return
+				}
+				// in order to also update the title, remove the trust boundary from the map and re-insert it (with new key)
+				delete(modelInput.TrustBoundaries, title)
+				modelInput.TrustBoundaries[payload.Title] = trustBoundaryInput
+				idChanged := trustBoundaryInput.ID != trustBoundary.ID
+				if idChanged { // ID-CHANGE-PROPAGATION
+					for otherTitle, other := range modelInput.TrustBoundaries {
+						if replaceValue(other.TrustBoundariesNested, trustBoundary.ID, trustBoundaryInput.ID) {
+							modelInput.TrustBoundaries[otherTitle] = other
+						}
+					}
+					for individualRiskCatTitle, individualRiskCat := range modelInput.IndividualRiskCategories {
+						for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+							if individualRiskInstance.MostRelevantTrustBoundary == trustBoundary.ID { // apply the ID change
+								individualRiskInstance.MostRelevantTrustBoundary = trustBoundaryInput.ID
+								modelInput.IndividualRiskCategories[individualRiskCatTitle].RisksIdentified[individualRiskInstanceTitle] = individualRiskInstance
+							}
+						}
+					}
+				}
+				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Trust Boundary Update")
+				if ok {
+					ginContext.JSON(http.StatusOK, gin.H{
+						"message":    "trust boundary updated",
+						"id":         trustBoundaryInput.ID,
+						"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
+					})
+				}
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "trust boundary not found",
+		})
+	}
+}
+
+func (s *server) deleteTrustBoundary(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToModel(ginContext, roleEditor)
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		referencesDeleted := false
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, trustBoundary := range modelInput.TrustBoundaries {
+			if trustBoundary.ID == ginContext.Param("trust-boundary-id") {
+				// also remove all usages of this trust boundary !!
+				for otherTitle, other := range modelInput.TrustBoundaries {
+					var removed bool
+					other.TrustBoundariesNested, removed = removeValue(other.TrustBoundariesNested, trustBoundary.ID)
+					if removed {
+						referencesDeleted = true
+						modelInput.TrustBoundaries[otherTitle] = other
+					}
+				}
+				for individualRiskCatTitle, individualRiskCat := range modelInput.IndividualRiskCategories {
+					for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+						if individualRiskInstance.MostRelevantTrustBoundary == trustBoundary.ID { // apply the removal
+							referencesDeleted = true
+							individualRiskInstance.MostRelevantTrustBoundary = ""
+							modelInput.IndividualRiskCategories[individualRiskCatTitle].RisksIdentified[individualRiskInstanceTitle] = individualRiskInstance
+						}
+					}
+				}
+				// remove it itself
+				delete(modelInput.TrustBoundaries, title)
+				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Trust Boundary Deletion")
+				if ok {
+					ginContext.JSON(http.StatusOK, gin.H{
+						"message":            "trust boundary deleted",
+						"id":                 trustBoundary.ID,
+						"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
+					})
+				}
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "trust boundary not found",
+		})
+	}
+}
+
+// populateTrustBoundary checks the payload against the model, ownTitle is the current title of the trust boundary
+// being updated (empty on creation) so that its own references are not reported as conflicts
+func populateTrustBoundary(ginContext *gin.Context, modelInput input.Model, ownTitle string, payload payloadTrustBoundary) (trustBoundaryInput input.TrustBoundary, ok bool) {
+	boundaryType, err := types.ParseTrustBoundary(payload.Type)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return trustBoundaryInput, false
+	}
+	if !checkTechnicalAssetsExisting(modelInput, payload.TechnicalAssetsInside) {
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "referenced technical asset does not exist",
+		})
+		return trustBoundaryInput, false
+	}
+	for title, trustBoundary := range modelInput.TrustBoundaries {
+		if title == ownTitle {
+			continue
+		}
+		for _, techAssetID := range payload.TechnicalAssetsInside {
+			if contains(trustBoundary.TechnicalAssetsInside, techAssetID) {
+				ginContext.JSON(http.StatusConflict, gin.H{
+					"error": "referenced technical asset is already inside another trust boundary: " + techAssetID,
+				})
+				return trustBoundaryInput, false
+			}
+		}
+	}
+	for _, nestedID := range payload.TrustBoundariesNested {
+		exists := false
+		for title, trustBoundary := range modelInput.TrustBoundaries {
+			if trustBoundary.ID == nestedID && title != ownTitle {
+				exists = true
+				break
+			}
+		}
+		if !exists || nestedID == payload.Id {
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "referenced nested trust boundary does not exist",
+			})
+			return trustBoundaryInput, false
+		}
+	}
+	trustBoundaryInput = input.TrustBoundary{
+		ID:                    payload.Id,
+		Description:           payload.Description,
+		Type:                  boundaryType.String(),
+		Tags:                  lowerCaseAndTrim(payload.Tags),
+		TechnicalAssetsInside: payload.TechnicalAssetsInside,
+		TrustBoundariesNested: payload.TrustBoundariesNested,
+	}
+	return trustBoundaryInput, true
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
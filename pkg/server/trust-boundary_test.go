@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func TestTrustBoundaryCreateUpdateDelete(t *testing.T) {
+	server := newTestServer(t)
+	_, token := server.createKey()
+	modelID := createTechnicalAssetTestModel(server, token)
+	path := "/models/" + modelID + "/trust-boundaries"
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPost, path, payloadTrustBoundary{
+		Title: "Cloud", Id: "cloud", Type: "network-cloud-provider", Tags: []string{" AWS "}, TechnicalAssetsInside: []string{"web"},
+	}, &created))
+	assert.Equal(t, "cloud", created.ID)
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPost, path, payloadTrustBoundary{
+		Title: "Backend", Id: "backend", Type: "network-cloud-security-group", TechnicalAssetsInside: []string{"db"}, TrustBoundariesNested: []string{"cloud"},
+	}, nil))
+
+	// conflicts and invalid references are rejected
+	for name, test := range map[string]struct {
+		payload payloadTrustBoundary
+		status  int
+	}{
+		"same title":                  {payloadTrustBoundary{Title: "Cloud", Id: "other", Type: "network-cloud-provider"}, http.StatusConflict},
+		"same id":                     {payloadTrustBoundary{Title: "Other", Id: "cloud", Type: "network-cloud-provider"}, http.StatusConflict},
+		"asset in other boundary":     {payloadTrustBoundary{Title: "Other", Id: "other", Type: "network-cloud-provider", TechnicalAssetsInside: []string{"web"}}, http.StatusConflict},
+		"unknown asset":               {payloadTrustBoundary{Title: "Other", Id: "other", Type: "network-cloud-provider", TechnicalAssetsInside: []string{"unknown"}}, http.StatusBadRequest},
+		"unknown nested boundary":     {payloadTrustBoundary{Title: "Other", Id: "other", Type: "network-cloud-provider", TrustBoundariesNested: []string{"unknown"}}, http.StatusBadRequest},
+		"nesting itself":              {payloadTrustBoundary{Title: "Other", Id: "other", Type: "network-cloud-provider", TrustBoundariesNested: []string{"other"}}, http.StatusBadRequest},
+		"unknown trust boundary type": {payloadTrustBoundary{Title: "Other", Id: "other", Type: "fence"}, http.StatusBadRequest},
+	} {
+		assert.Equal(t, test.status, server.withToken(token, http.MethodPost, path, test.payload, nil), name)
+	}
+
+	var read map[string]input.TrustBoundary
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodGet, path+"/cloud", nil, &read))
+	assert.Equal(t, map[string]input.TrustBoundary{"Cloud": {ID: "cloud", Type: "network-cloud-provider", Tags: []string{"aws"}, TechnicalAssetsInside: []string{"web"}}}, read)
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodGet, path+"/unknown", nil, nil))
+
+	// renaming the boundary updates the boundaries it is nested in
+	var updated struct {
+		ID        string `json:"id"`
+		IDChanged bool   `json:"id_changed"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodPut, path+"/cloud", payloadTrustBoundary{
+		Title: "Public Cloud", Id: "public-cloud", Type: "network-cloud-provider", TechnicalAssetsInside: []string{"web", "backup"},
+	}, &updated))
+	assert.Equal(t, "public-cloud", updated.ID)
+	assert.True(t, updated.IDChanged)
+	modelInput := server.readModel(token, modelID)
+	assert.NotContains(t, modelInput.TrustBoundaries, "Cloud")
+	assert.Equal(t, []string{"web", "backup"}, modelInput.TrustBoundaries["Public Cloud"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"public-cloud"}, modelInput.TrustBoundaries["Backend"].TrustBoundariesNested)
+	assert.Equal(t, http.StatusConflict, server.withToken(token, http.MethodPut, path+"/public-cloud", payloadTrustBoundary{
+		Title: "Backend", Id: "public-cloud", Type: "network-cloud-provider",
+	}, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodPut, path+"/cloud", payloadTrustBoundary{
+		Title: "Cloud", Id: "cloud", Type: "network-cloud-provider",
+	}, nil))
+
+	// deleting the boundary removes it from the boundaries it is nested in
+	var deleted struct {
+		ID                string `json:"id"`
+		ReferencesDeleted bool   `json:"references_deleted"`
+	}
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodDelete, path+"/public-cloud", nil, &deleted))
+	assert.Equal(t, "public-cloud", deleted.ID)
+	assert.True(t, deleted.ReferencesDeleted)
+	modelInput = server.readModel(token, modelID)
+	assert.NotContains(t, modelInput.TrustBoundaries, "Public Cloud")
+	assert.Empty(t, modelInput.TrustBoundaries["Backend"].TrustBoundariesNested)
+	assert.Equal(t, http.StatusNotFound, server.withToken(token, http.MethodDelete, path+"/public-cloud", nil, nil))
+
+	assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodDelete, path+"/backend", nil, &deleted))
+	assert.False(t, deleted.ReferencesDeleted)
+	assert.Empty(t, server.readModel(token, modelID).TrustBoundaries)
+}
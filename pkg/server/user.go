@@ -0,0 +1,493 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// the owner of a key is owner of all models of the key and manages the users, who get roles per model:
+// viewers may read the model and its reports, editors may also change the model, owners may also delete it
+
+const (
+	roleViewer = "viewer"
+	roleEditor = "editor"
+	roleOwner  = "owner"
+)
+
+var roleRanks = map[string]int{
+	roleViewer: 1,
+	roleEditor: 2,
+	roleOwner:  3,
+}
+
+const (
+	principalContextKey = "principal"
+	keyOwnerName        = "key owner"
+)
+
+// principal is who authenticated with a key, user key or token, kept in the gin context of the request
+type principal struct {
+	keyHash  string            // hash of the key or user key authenticated with
+	userID   string            // empty for the owner of the key
+	userName string            // empty for the owner of the key
+	roles    map[string]string // roles per model id of the user
+	maxRole  string            // limits the roles (when the token was created for a role), empty for no limit
+}
+
+// role returns the role of the principal for the model, or an empty string when the model is not shared with the principal
+func (p principal) role(modelID string) string {
+	role := roleOwner
+	if p.userID != "" {
+		role = p.roles[modelID]
+	}
+	if len(role) > 0 && len(p.maxRole) > 0 && roleRanks[p.maxRole] < roleRanks[role] {
+		role = p.maxRole
+	}
+	return role
+}
+
+func (p principal) isKeyOwner() bool {
+	return p.userID == "" && (p.maxRole == "" || p.maxRole == roleOwner)
+}
+
+func (p principal) name() string {
+	if p.userID == "" {
+		return keyOwnerName
+	}
+	return p.userName
+}
+
+func principalOf(ginContext *gin.Context) principal {
+	if value, exists := ginContext.Get(principalContextKey); exists {
+		if p, ok := value.(principal); ok {
+			return p
+		}
+	}
+	return principal{userID: "-"} // denies everything, but every authenticated request has a principal anyway
+}
+
+// resolveKey returns the hash and the key of the models for a key or user key, as well as the principal of the key
+func (s *server) resolveKey(key []byte) (folderNameOfKey string, modelsKey []byte, p principal, ok bool) {
+	keyHash := s.folderNameFromKey(key)
+	exists, err := s.storage.KeyExists(keyHash)
+	if err != nil {
+		log.Println(err)
+	}
+	if exists {
+		return keyHash, key, principal{keyHash: keyHash}, true
+	}
+	user, found, err := s.storage.GetUser(keyHash)
+	if err != nil {
+		log.Println(err)
+	}
+	if !found {
+		return folderNameOfKey, modelsKey, p, false
+	}
+	modelsKey, err = unwrapKey(user.WrappedKey, key)
+	if err != nil || s.folderNameFromKey(modelsKey) != user.KeyHash {
+		if err != nil {
+			log.Println(err)
+		}
+		return folderNameOfKey, modelsKey, p, false
+	}
+	return user.KeyHash, modelsKey, userPrincipal(keyHash, user), true
+}
+
+func userPrincipal(keyHash string, user StoredUser) principal {
+	return principal{
+		keyHash:  keyHash,
+		userID:   user.ID,
+		userName: user.Name,
+		roles:    user.Roles,
+	}
+}
+
+// checkTokenToModel checks the token and that the role for the model of the request is at least the required role
+func (s *server) checkTokenToModel(ginContext *gin.Context, requiredRole string) (folderNameOfKey string, key []byte, ok bool) {
+	folderNameOfKey, key, ok = s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return folderNameOfKey, key, false
+	}
+	return folderNameOfKey, key, s.checkRole(ginContext, ginContext.Param("model-id"), requiredRole)
+}
+
+func (s *server) checkRole(ginContext *gin.Context, modelUUID string, requiredRole string) bool {
+	modelID := modelUUID
+	if uuidParsed, err := uuid.Parse(modelUUID); err == nil {
+		modelID = uuidParsed.String()
+	}
+	role := principalOf(ginContext).role(modelID)
+	if len(role) == 0 { // don't reveal the existence of models not shared with the user
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "model not found",
+		})
+		return false
+	}
+	if roleRanks[role] < roleRanks[requiredRole] {
+		ginContext.JSON(http.StatusForbidden, gin.H{
+			"error": "insufficient permissions: role " + requiredRole + " required",
+		})
+		return false
+	}
+	return true
+}
+
+func (s *server) checkKeyOwner(ginContext *gin.Context) bool {
+	if !principalOf(ginContext).isKeyOwner() {
+		ginContext.JSON(http.StatusForbidden, gin.H{
+			"error": "insufficient permissions: owner of the key required",
+		})
+		return false
+	}
+	return true
+}
+
+// audit records who changed what, errors are only logged as the change itself is already done
+func (s *server) audit(ginContext *gin.Context, folderNameOfKey string, modelID string, action string) {
+	p := principalOf(ginContext)
+	err := s.storage.AddAuditEntry(folderNameOfKey, StoredAuditEntry{
+		Timestamp: time.Now(),
+		UserID:    p.userID,
+		UserName:  p.name(),
+		ModelID:   modelID,
+		Action:    action,
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+type payloadUser struct {
+	Name  string            `yaml:"name" json:"name"`
+	Roles map[string]string `yaml:"roles" json:"roles"`
+}
+
+type payloadUserDetails struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Roles   map[string]string `json:"roles"`
+	Created time.Time         `json:"created"`
+}
+
+type payloadAuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	UserID    string    `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	ModelID   string    `json:"model_id"`
+	Action    string    `json:"action"`
+}
+
+// creates a user with roles on models of the key, the returned user key is used like a key, i.e. to create tokens
+func (s *server) createUser(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok || !s.checkKeyOwner(ginContext) {
+		return
+	}
+	ok = s.checkObjectCreationThrottler(ginContext, "USER")
+	if !ok {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	payload, ok := s.bindUser(ginContext, folderNameOfKey)
+	if !ok {
+		return
+	}
+	userKey := make([]byte, keySize)
+	n, err := rand.Read(userKey)
+	if n != keySize || err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "unable to create user",
+		})
+		return
+	}
+	wrappedKey, err := wrapKey(key, userKey)
+	if err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "unable to create user",
+		})
+		return
+	}
+	user := StoredUser{
+		ID:          uuid.New().String(),
+		KeyHash:     folderNameOfKey,
+		UserKeyHash: s.folderNameFromKey(userKey),
+		Name:        payload.Name,
+		WrappedKey:  wrappedKey,
+		Roles:       payload.Roles,
+		Created:     time.Now(),
+	}
+	if err = s.storage.PutUser(user); err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "unable to create user",
+		})
+		return
+	}
+	s.audit(ginContext, folderNameOfKey, "", "User Creation: "+user.Name)
+	ginContext.JSON(http.StatusCreated, gin.H{
+		"message": "user created",
+		"id":      user.ID,
+		"key":     base64.RawURLEncoding.EncodeToString(userKey),
+	})
+}
+
+func (s *server) listUsers(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok || !s.checkKeyOwner(ginContext) {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	users, err := s.storage.ListUsers(folderNameOfKey)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	result := make([]payloadUserDetails, 0)
+	for _, user := range users {
+		result = append(result, userDetails(user))
+	}
+	ginContext.JSON(http.StatusOK, result)
+}
+
+func (s *server) getUser(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok || !s.checkKeyOwner(ginContext) {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	user, ok := s.findUser(ginContext, folderNameOfKey)
+	if ok {
+		ginContext.JSON(http.StatusOK, userDetails(user))
+	}
+}
+
+// replaces the name and roles of a user, the user key stays the same
+func (s *server) setUser(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok || !s.checkKeyOwner(ginContext) {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	user, ok := s.findUser(ginContext, folderNameOfKey)
+	if !ok {
+		return
+	}
+	payload, ok := s.bindUser(ginContext, folderNameOfKey)
+	if !ok {
+		return
+	}
+	user.Name = payload.Name
+	user.Roles = payload.Roles
+	if err := s.storage.PutUser(user); err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	s.audit(ginContext, folderNameOfKey, "", "User Update: "+user.Name)
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "user updated",
+		"id":      user.ID,
+	})
+}
+
+// deletes a user including its tokens, which revokes all access of the user immediately
+func (s *server) deleteUser(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok || !s.checkKeyOwner(ginContext) {
+		return
+	}
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	user, ok := s.findUser(ginContext, folderNameOfKey)
+	if !ok {
+		return
+	}
+	if err := s.storage.DeleteUser(folderNameOfKey, user.ID); err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	s.audit(ginContext, folderNameOfKey, "", "User Deletion: "+user.Name)
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "user deleted",
+		"id":      user.ID,
+	})
+}
+
+// returns the audit log of all models and users of the key
+func (s *server) getAuditLog(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok || !s.checkKeyOwner(ginContext) {
+		return
+	}
+	s.respondAuditLog(ginContext, folderNameOfKey, func(entry StoredAuditEntry) bool {
+		return true
+	})
+}
+
+func (s *server) getModelAuditLog(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToModel(ginContext, roleOwner)
+	if !ok {
+		return
+	}
+	modelID, ok := s.checkModel(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	if !ok {
+		return
+	}
+	s.respondAuditLog(ginContext, folderNameOfKey, func(entry StoredAuditEntry) bool {
+		return entry.ModelID == modelID
+	})
+}
+
+func (s *server) respondAuditLog(ginContext *gin.Context, folderNameOfKey string, matches func(entry StoredAuditEntry) bool) {
+	if !s.lockFolder(ginContext, folderNameOfKey) {
+		return
+	}
+	defer s.unlockFolder(folderNameOfKey)
+	entries, err := s.storage.ListAuditEntries(folderNameOfKey)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	result := make([]payloadAuditEntry, 0)
+	for _, entry := range entries {
+		if matches(entry) {
+			result = append(result, payloadAuditEntry(entry))
+		}
+	}
+	ginContext.JSON(http.StatusOK, result)
+}
+
+func (s *server) findUser(ginContext *gin.Context, folderNameOfKey string) (user StoredUser, ok bool) {
+	users, err := s.storage.ListUsers(folderNameOfKey)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return user, false
+	}
+	for _, user := range users {
+		if user.ID == ginContext.Param("user-id") {
+			return user, true
+		}
+	}
+	ginContext.JSON(http.StatusNotFound, gin.H{
+		"error": "user not found",
+	})
+	return user, false
+}
+
+// bindUser parses the user payload and checks that the roles are valid for existing models (normalizing the model ids)
+func (s *server) bindUser(ginContext *gin.Context, folderNameOfKey string) (payload payloadUser, ok bool) {
+	err := ginContext.BindJSON(&payload)
+	if err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "unable to parse request payload",
+		})
+		return payload, false
+	}
+	payload.Name = strings.TrimSpace(payload.Name)
+	if len(payload.Name) == 0 {
+		ginContext.JSON(http.StatusBadRequest, gin.H{
+			"error": "user name is required",
+		})
+		return payload, false
+	}
+	roles := make(map[string]string)
+	for modelUUID, role := range payload.Roles {
+		if _, valid := roleRanks[role]; !valid {
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("unknown role %q: expected one of %v", role, roleNames()),
+			})
+			return payload, false
+		}
+		modelID, ok := s.checkModel(ginContext, modelUUID, folderNameOfKey)
+		if !ok {
+			return payload, false
+		}
+		roles[modelID] = role
+	}
+	payload.Roles = roles
+	return payload, true
+}
+
+func userDetails(user StoredUser) payloadUserDetails {
+	roles := user.Roles
+	if roles == nil {
+		roles = make(map[string]string)
+	}
+	return payloadUserDetails{
+		ID:      user.ID,
+		Name:    user.Name,
+		Roles:   roles,
+		Created: user.Created,
+	}
+}
+
+func roleNames() []string {
+	names := make([]string, 0, len(roleRanks))
+	for name := range roleRanks {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return roleRanks[names[i]] < roleRanks[names[j]]
+	})
+	return names
+}
+
+// wrapKey encrypts the key of the models with the user key, so that only the user key holder can access the models
+func wrapKey(key []byte, userKey []byte) ([]byte, error) {
+	aesGcm, err := newGCM(userKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aesGcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aesGcm.Seal(nonce, nonce, key, nil), nil
+}
+
+func unwrapKey(wrappedKey []byte, userKey []byte) ([]byte, error) {
+	aesGcm, err := newGCM(userKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrappedKey) < aesGcm.NonceSize() {
+		return nil, fmt.Errorf("invalid wrapped key")
+	}
+	return aesGcm.Open(nil, wrappedKey[:aesGcm.NonceSize()], wrappedKey[aesGcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(generateKeyFromAlreadyStrongRandomInput(key))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createUser creates a user with the roles on models of the key of the owner token and returns the user id and user key
+func (what *testServer) createUser(ownerToken string, name string, roles map[string]string) (userID string, userKey string) {
+	var created struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}
+	assert.Equal(what.t, http.StatusCreated, what.withToken(ownerToken, http.MethodPost, "/auth/users", payloadUser{Name: name, Roles: roles}, &created))
+	return created.ID, created.Key
+}
+
+// auditLog returns the audit log of the key (or of the model if not empty)
+func (what *testServer) auditLog(ownerToken string, modelID string) []payloadAuditEntry {
+	path := "/auth/audit-log"
+	if len(modelID) > 0 {
+		path = "/models/" + modelID + "/audit-log"
+	}
+	entries := make([]payloadAuditEntry, 0)
+	assert.Equal(what.t, http.StatusOK, what.withToken(ownerToken, http.MethodGet, path, nil, &entries))
+	for _, entry := range entries {
+		assert.False(what.t, entry.Timestamp.IsZero())
+	}
+	return entries
+}
+
+func TestViewerIsForbiddenToChangeAnything(t *testing.T) {
+	server := newTestServer(t)
+	key, ownerToken := server.createKey()
+	modelID := server.createModel(ownerToken)
+	_, userKey := server.createUser(ownerToken, "Viewer", map[string]string{modelID: roleViewer})
+	_, editorUserKey := server.createUser(ownerToken, "Editor", map[string]string{modelID: roleEditor})
+
+	routeParameter := regexp.MustCompile(`:[a-z-]+`)
+	for name, token := range map[string]string{
+		"viewer token of the key owner":  server.createToken(key, roleViewer),
+		"token of a viewer":              server.createToken(userKey, ""),
+		"viewer token of an editor user": server.createToken(editorUserKey, roleViewer),
+	} {
+		checked := 0
+		for _, route := range server.router.Routes() {
+			if route.Method == http.MethodGet || strings.HasPrefix(route.Path, "/direct/") || strings.HasPrefix(route.Path, "/auth/keys") || strings.HasPrefix(route.Path, "/auth/tokens") {
+				continue // reading, stateless or authenticated by a key (tokens are revoked by themselves)
+			}
+			path := routeParameter.ReplaceAllStringFunc(strings.ReplaceAll(route.Path, ":model-id", modelID), func(string) string {
+				return "some-id"
+			})
+			assert.Equal(t, http.StatusForbidden, server.withToken(token, route.Method, path, nil, nil), "%v: %v %v", name, route.Method, route.Path)
+			checked++
+		}
+		assert.Greater(t, checked, 30)
+
+		// but reading is fine
+		for _, path := range []string{"/models/" + modelID, "/models/" + modelID + "/cover", "/models/" + modelID + "/history"} {
+			assert.Equal(t, http.StatusOK, server.withToken(token, http.MethodGet, path, nil, nil), "%v: %v", name, path)
+		}
+	}
+
+	ownerToken = server.createToken(key, "") // the viewer token of the key owner replaced the previous token
+	modelInput := server.readModel(ownerToken, modelID)
+	assert.Equal(t, "New Threat Model", modelInput.Title)
+	assert.Len(t, server.auditLog(ownerToken, ""), 3) // the model and user creations only
+}
+
+func TestEditorAndOwnerRoles(t *testing.T) {
+	server := newTestServer(t)
+	key, ownerToken := server.createKey()
+	modelID := server.createModel(ownerToken)
+	otherModelID := server.createModel(ownerToken)
+	_, editorKey := server.createUser(ownerToken, "Editor", map[string]string{modelID: roleEditor, otherModelID: roleViewer})
+	_, ownerKey := server.createUser(ownerToken, "Owner", map[string]string{modelID: roleOwner})
+	editorToken := server.createToken(editorKey, "")
+	ownerUserToken := server.createToken(ownerKey, "")
+	cover := payloadCover{Title: "Changed Title"}
+
+	// editors change models, but neither delete them nor read their audit log
+	assert.Equal(t, http.StatusOK, server.withToken(editorToken, http.MethodPut, "/models/"+modelID+"/cover", cover, nil))
+	assert.Equal(t, http.StatusForbidden, server.withToken(editorToken, http.MethodPut, "/models/"+otherModelID+"/cover", cover, nil))
+	assert.Equal(t, http.StatusForbidden, server.withToken(editorToken, http.MethodGet, "/models/"+modelID+"/audit-log", nil, nil))
+	assert.Equal(t, http.StatusForbidden, server.withToken(editorToken, http.MethodDelete, "/models/"+modelID, nil, nil))
+
+	// owners of a model (and the key owner with a token limited to editing) neither
+	editingKeyOwnerToken := server.createToken(key, roleEditor)
+	assert.Equal(t, http.StatusOK, server.withToken(editingKeyOwnerToken, http.MethodPut, "/models/"+otherModelID+"/cover", cover, nil))
+	assert.Equal(t, http.StatusForbidden, server.withToken(editingKeyOwnerToken, http.MethodDelete, "/models/"+otherModelID, nil, nil))
+	assert.Equal(t, http.StatusOK, server.withToken(ownerUserToken, http.MethodGet, "/models/"+modelID+"/audit-log", nil, nil))
+
+	// models not shared with a user are not revealed
+	assert.Equal(t, http.StatusNotFound, server.withToken(ownerUserToken, http.MethodGet, "/models/"+otherModelID, nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(ownerUserToken, http.MethodDelete, "/models/"+otherModelID, nil, nil))
+	var models []payloadModels
+	assert.Equal(t, http.StatusOK, server.withToken(ownerUserToken, http.MethodGet, "/models", nil, &models))
+	assert.Len(t, models, 1)
+
+	// only the key owner creates models and manages users, not even the owner of a model
+	for _, token := range []string{editorToken, ownerUserToken, editingKeyOwnerToken} {
+		assert.Equal(t, http.StatusForbidden, server.withToken(token, http.MethodPost, "/models", nil, nil))
+		assert.Equal(t, http.StatusForbidden, server.withToken(token, http.MethodGet, "/auth/users", nil, nil))
+		assert.Equal(t, http.StatusForbidden, server.withToken(token, http.MethodPost, "/auth/users", payloadUser{Name: "Other"}, nil))
+		assert.Equal(t, http.StatusForbidden, server.withToken(token, http.MethodGet, "/auth/audit-log", nil, nil))
+	}
+	assert.Equal(t, http.StatusForbidden, server.request(http.MethodDelete, "/auth/keys", map[string]string{"key": editorKey}, nil, nil))
+
+	assert.Equal(t, http.StatusOK, server.withToken(ownerUserToken, http.MethodDelete, "/models/"+modelID, nil, nil))
+	assert.Equal(t, http.StatusNotFound, server.withToken(editingKeyOwnerToken, http.MethodGet, "/models/"+modelID, nil, nil))
+}
+
+func TestChangesAreAudited(t *testing.T) {
+	server := newTestServer(t)
+	_, ownerToken := server.createKey()
+	modelID := server.createModel(ownerToken)
+	otherModelID := server.createModel(ownerToken)
+	editorID, editorKey := server.createUser(ownerToken, "Editor", map[string]string{modelID: roleEditor})
+	editorToken := server.createToken(editorKey, "")
+
+	assert.Equal(t, http.StatusOK, server.withToken(editorToken, http.MethodPut, "/models/"+modelID+"/cover", payloadCover{Title: "Changed Title"}, nil))
+	assert.Equal(t, http.StatusOK, server.withToken(ownerToken, http.MethodPut, "/models/"+otherModelID+"/overview", payloadOverview{BusinessCriticality: "important"}, nil))
+	assert.Equal(t, http.StatusForbidden, server.withToken(editorToken, http.MethodDelete, "/models/"+modelID, nil, nil)) // nothing changed
+
+	type entry struct{ userID, userName, modelID, action string }
+	entries := make([]entry, 0)
+	for _, e := range server.auditLog(ownerToken, "") {
+		entries = append(entries, entry{e.UserID, e.UserName, e.ModelID, e.Action})
+	}
+	assert.Equal(t, []entry{
+		{"", keyOwnerName, modelID, "New Model Creation"},
+		{"", keyOwnerName, otherModelID, "New Model Creation"},
+		{"", keyOwnerName, "", "User Creation: Editor"},
+		{editorID, "Editor", modelID, "Cover Update"},
+		{"", keyOwnerName, otherModelID, "Overview Update"},
+	}, entries)
+
+	modelEntries := server.auditLog(ownerToken, modelID)
+	assert.Len(t, modelEntries, 2)
+	assert.Equal(t, "Cover Update", modelEntries[1].Action)
+	assert.Equal(t, "Editor", modelEntries[1].UserName)
+}
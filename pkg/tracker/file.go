@@ -0,0 +1,122 @@
+package tracker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const fileTicketPrefix = "LOCAL-"
+
+// fileTracker keeps the issues in a local JSON file, a stand-in for a real tracker (e.g. for testing or air-gapped setups):
+// issues are closed or accepted by editing their state in the file
+type fileTracker struct {
+	filename      string
+	acceptedLabel string
+}
+
+type fileIssue struct {
+	Issue
+	State IssueState `json:"state"`
+}
+
+type fileIssues struct {
+	Issues map[string]fileIssue `json:"issues"`
+}
+
+func newFileTracker(filename string, acceptedLabel string) *fileTracker {
+	return &fileTracker{filename: filepath.Clean(filename), acceptedLabel: acceptedLabel}
+}
+
+func (what *fileTracker) Name() string {
+	return "file " + what.filename
+}
+
+func (what *fileTracker) Owns(ticket string) bool {
+	number, found := strings.CutPrefix(strings.TrimSpace(ticket), fileTicketPrefix)
+	if !found {
+		return false
+	}
+	_, err := strconv.Atoi(number)
+	return err == nil
+}
+
+func (what *fileTracker) Create(issue Issue) (string, error) {
+	issues, err := what.load()
+	if err != nil {
+		return "", err
+	}
+	ticket := fileTicketPrefix + strconv.Itoa(len(issues.Issues)+1)
+	issues.Issues[ticket] = fileIssue{Issue: issue, State: IssueOpen}
+	return ticket, what.save(issues)
+}
+
+func (what *fileTracker) Update(ticket string, issue Issue) error {
+	issues, err := what.load()
+	if err != nil {
+		return err
+	}
+	existing, ok := issues.Issues[strings.TrimSpace(ticket)]
+	if !ok {
+		return fmt.Errorf("unknown ticket %q in %v", ticket, what.filename)
+	}
+	existing.Title = issue.Title
+	existing.Body = issue.Body
+	issues.Issues[strings.TrimSpace(ticket)] = existing
+	return what.save(issues)
+}
+
+func (what *fileTracker) State(ticket string) (IssueState, error) {
+	issues, err := what.load()
+	if err != nil {
+		return "", err
+	}
+	issue, ok := issues.Issues[strings.TrimSpace(ticket)]
+	if !ok {
+		return "", fmt.Errorf("unknown ticket %q in %v", ticket, what.filename)
+	}
+	if hasLabel(issue.Labels, what.acceptedLabel) {
+		return IssueAccepted, nil
+	}
+	switch issue.State {
+	case IssueOpen, IssueClosed, IssueAccepted:
+		return issue.State, nil
+	default:
+		return "", fmt.Errorf("invalid state %q of ticket %q in %v", issue.State, ticket, what.filename)
+	}
+}
+
+func (what *fileTracker) load() (*fileIssues, error) {
+	issues := &fileIssues{Issues: make(map[string]fileIssue)}
+	data, err := os.ReadFile(what.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return issues, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read issue file: %w", err)
+	}
+	err = json.Unmarshal(data, issues)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse issue file %v: %w", what.filename, err)
+	}
+	if issues.Issues == nil {
+		issues.Issues = make(map[string]fileIssue)
+	}
+	return issues, nil
+}
+
+func (what *fileTracker) save(issues *fileIssues) error {
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal issues: %w", err)
+	}
+	err = os.WriteFile(what.filename, data, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write issue file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const gitHubDefaultURL = "https://api.github.com"
+
+// gitHubTracker references issues as owner/repo#number
+type gitHubTracker struct {
+	client        *restClient
+	repository    string
+	acceptedLabel string
+}
+
+type gitHubIssue struct {
+	Number      int    `json:"number,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Body        string `json:"body,omitempty"`
+	State       string `json:"state,omitempty"`
+	StateReason string `json:"state_reason,omitempty"`
+	Labels      []struct {
+		Name string `json:"name"`
+	} `json:"labels,omitempty"`
+}
+
+func newGitHubTracker(baseURL string, repository string, token string, acceptedLabel string) *gitHubTracker {
+	if len(baseURL) == 0 {
+		baseURL = gitHubDefaultURL
+	}
+	return &gitHubTracker{
+		client: newRestClient(baseURL, func(request *http.Request) {
+			request.Header.Set("Accept", "application/vnd.github+json")
+			if len(token) > 0 {
+				request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}),
+		repository:    repository,
+		acceptedLabel: acceptedLabel,
+	}
+}
+
+func (what *gitHubTracker) Name() string {
+	return "GitHub " + what.repository
+}
+
+func (what *gitHubTracker) Owns(ticket string) bool {
+	_, err := what.number(ticket)
+	return err == nil
+}
+
+func (what *gitHubTracker) Create(issue Issue) (string, error) {
+	var created gitHubIssue
+	err := what.client.do(http.MethodPost, "/repos/"+what.repository+"/issues", map[string]any{
+		"title":  issue.Title,
+		"body":   issue.Body,
+		"labels": issue.Labels,
+	}, &created)
+	if err != nil {
+		return "", err
+	}
+	return what.repository + "#" + strconv.Itoa(created.Number), nil
+}
+
+func (what *gitHubTracker) Update(ticket string, issue Issue) error {
+	number, err := what.number(ticket)
+	if err != nil {
+		return err
+	}
+	return what.client.do(http.MethodPatch, "/repos/"+what.repository+"/issues/"+number, map[string]any{
+		"title": issue.Title,
+		"body":  issue.Body,
+	}, nil)
+}
+
+func (what *gitHubTracker) State(ticket string) (IssueState, error) {
+	number, err := what.number(ticket)
+	if err != nil {
+		return "", err
+	}
+	var issue gitHubIssue
+	err = what.client.do(http.MethodGet, "/repos/"+what.repository+"/issues/"+number, nil, &issue)
+	if err != nil {
+		return "", err
+	}
+
+	labels := make([]string, 0)
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	switch {
+	case hasLabel(labels, what.acceptedLabel), issue.State == "closed" && issue.StateReason == "not_planned":
+		return IssueAccepted, nil
+	case issue.State == "closed":
+		return IssueClosed, nil
+	default:
+		return IssueOpen, nil
+	}
+}
+
+func (what *gitHubTracker) number(ticket string) (string, error) {
+	number, found := strings.CutPrefix(strings.TrimSpace(ticket), what.repository+"#")
+	if !found {
+		return "", fmt.Errorf("ticket %q is no issue of %v", ticket, what.repository)
+	}
+	if _, err := strconv.Atoi(number); err != nil {
+		return "", fmt.Errorf("ticket %q is no issue of %v", ticket, what.repository)
+	}
+	return number, nil
+}
@@ -0,0 +1,108 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const gitLabDefaultURL = "https://gitlab.com"
+
+// gitLabTracker references issues as group/project#iid
+type gitLabTracker struct {
+	client        *restClient
+	project       string
+	acceptedLabel string
+}
+
+type gitLabIssue struct {
+	IID    int      `json:"iid,omitempty"`
+	State  string   `json:"state,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+func newGitLabTracker(baseURL string, project string, token string, acceptedLabel string) *gitLabTracker {
+	if len(baseURL) == 0 {
+		baseURL = gitLabDefaultURL
+	}
+	return &gitLabTracker{
+		client: newRestClient(baseURL, func(request *http.Request) {
+			if len(token) > 0 {
+				request.Header.Set("PRIVATE-TOKEN", token)
+			}
+		}),
+		project:       project,
+		acceptedLabel: acceptedLabel,
+	}
+}
+
+func (what *gitLabTracker) Name() string {
+	return "GitLab " + what.project
+}
+
+func (what *gitLabTracker) Owns(ticket string) bool {
+	_, err := what.iid(ticket)
+	return err == nil
+}
+
+func (what *gitLabTracker) Create(issue Issue) (string, error) {
+	var created gitLabIssue
+	err := what.client.do(http.MethodPost, what.issuesPath(), map[string]any{
+		"title":       issue.Title,
+		"description": issue.Body,
+		"labels":      strings.Join(issue.Labels, ","),
+	}, &created)
+	if err != nil {
+		return "", err
+	}
+	return what.project + "#" + strconv.Itoa(created.IID), nil
+}
+
+func (what *gitLabTracker) Update(ticket string, issue Issue) error {
+	iid, err := what.iid(ticket)
+	if err != nil {
+		return err
+	}
+	return what.client.do(http.MethodPut, what.issuesPath()+"/"+iid, map[string]any{
+		"title":       issue.Title,
+		"description": issue.Body,
+	}, nil)
+}
+
+func (what *gitLabTracker) State(ticket string) (IssueState, error) {
+	iid, err := what.iid(ticket)
+	if err != nil {
+		return "", err
+	}
+	var issue gitLabIssue
+	err = what.client.do(http.MethodGet, what.issuesPath()+"/"+iid, nil, &issue)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case hasLabel(issue.Labels, what.acceptedLabel):
+		return IssueAccepted, nil
+	case issue.State == "closed":
+		return IssueClosed, nil
+	default:
+		return IssueOpen, nil
+	}
+}
+
+func (what *gitLabTracker) issuesPath() string {
+	return "/api/v4/projects/" + url.PathEscape(what.project) + "/issues"
+}
+
+func (what *gitLabTracker) iid(ticket string) (string, error) {
+	iid, found := strings.CutPrefix(strings.TrimSpace(ticket), what.project+"#")
+	if !found {
+		return "", fmt.Errorf("ticket %q is no issue of %v", ticket, what.project)
+	}
+	if _, err := strconv.Atoi(iid); err != nil {
+		return "", fmt.Errorf("ticket %q is no issue of %v", ticket, what.project)
+	}
+	return iid, nil
+}
@@ -0,0 +1,117 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// jiraTracker references issues by their keys, e.g. SEC-123
+type jiraTracker struct {
+	client        *restClient
+	projectKey    string
+	acceptedLabel string
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Labels []string `json:"labels"`
+		Status struct {
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+		Resolution *struct {
+			Name string `json:"name"`
+		} `json:"resolution"`
+	} `json:"fields"`
+}
+
+// resolutions of done issues treated as accepted risks
+var jiraAcceptedResolutions = []string{"Won't Do", "Won't Fix", "Accepted", "Risk Accepted"}
+
+func newJiraTracker(baseURL string, projectKey string, user string, token string, acceptedLabel string) *jiraTracker {
+	return &jiraTracker{
+		client: newRestClient(baseURL, func(request *http.Request) {
+			switch {
+			case len(user) > 0:
+				request.SetBasicAuth(user, token)
+			case len(token) > 0:
+				request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}),
+		projectKey:    projectKey,
+		acceptedLabel: acceptedLabel,
+	}
+}
+
+func (what *jiraTracker) Name() string {
+	return "Jira " + what.projectKey
+}
+
+func (what *jiraTracker) Owns(ticket string) bool {
+	number, found := strings.CutPrefix(strings.TrimSpace(ticket), what.projectKey+"-")
+	if !found {
+		return false
+	}
+	_, err := strconv.Atoi(number)
+	return err == nil
+}
+
+func (what *jiraTracker) Create(issue Issue) (string, error) {
+	labels := make([]string, 0)
+	for _, label := range issue.Labels {
+		labels = append(labels, strings.ReplaceAll(label, " ", "-")) // jira labels must not contain spaces
+	}
+
+	var created jiraIssue
+	err := what.client.do(http.MethodPost, "/rest/api/2/issue", map[string]any{
+		"fields": map[string]any{
+			"project":     map[string]string{"key": what.projectKey},
+			"issuetype":   map[string]string{"name": "Task"},
+			"summary":     issue.Title,
+			"description": issue.Body,
+			"labels":      labels,
+		},
+	}, &created)
+	if err != nil {
+		return "", err
+	}
+	return created.Key, nil
+}
+
+func (what *jiraTracker) Update(ticket string, issue Issue) error {
+	if !what.Owns(ticket) {
+		return fmt.Errorf("ticket %q is no issue of %v", ticket, what.projectKey)
+	}
+	return what.client.do(http.MethodPut, "/rest/api/2/issue/"+strings.TrimSpace(ticket), map[string]any{
+		"fields": map[string]any{
+			"summary":     issue.Title,
+			"description": issue.Body,
+		},
+	}, nil)
+}
+
+func (what *jiraTracker) State(ticket string) (IssueState, error) {
+	if !what.Owns(ticket) {
+		return "", fmt.Errorf("ticket %q is no issue of %v", ticket, what.projectKey)
+	}
+	var issue jiraIssue
+	err := what.client.do(http.MethodGet, "/rest/api/2/issue/"+strings.TrimSpace(ticket)+"?fields=status,resolution,labels", nil, &issue)
+	if err != nil {
+		return "", err
+	}
+
+	if hasLabel(issue.Fields.Labels, what.acceptedLabel) {
+		return IssueAccepted, nil
+	}
+	if issue.Fields.Status.StatusCategory.Key != "done" {
+		return IssueOpen, nil
+	}
+	if issue.Fields.Resolution != nil && hasLabel(jiraAcceptedResolutions, issue.Fields.Resolution.Name) {
+		return IssueAccepted, nil
+	}
+	return IssueClosed, nil
+}
@@ -0,0 +1,174 @@
+package tracker
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type progressReporter interface {
+	Info(a ...any)
+	Warn(a ...any)
+	Error(a ...any)
+}
+
+// Sync creates or updates an issue for every risk still at risk and pulls the state of closed or accepted issues back
+// into the risk tracking. New tickets and pulled states are written into the risk tracking of the model input, which is
+// also updated when an error aborts the synchronization half-way. Returns a description of each action taken.
+func Sync(tracker Tracker, modelInput *input.Model, parsedModel *types.ParsedModel, progressReporter progressReporter) ([]string, error) {
+	actions := make([]string, 0)
+	if modelInput.RiskTracking == nil {
+		modelInput.RiskTracking = make(map[string]input.RiskTracking)
+	}
+
+	risks := make([]types.Risk, 0)
+	for _, risk := range parsedModel.GeneratedRisksBySyntheticId {
+		risks = append(risks, risk)
+	}
+	sort.Slice(risks, func(i, j int) bool {
+		return risks[i].SyntheticId < risks[j].SyntheticId
+	})
+
+	for _, risk := range risks {
+		tracking := risk.GetRiskTracking(parsedModel)
+		status := risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel)
+		ticket := strings.TrimSpace(tracking.Ticket)
+
+		if len(ticket) > 0 && !tracker.Owns(ticket) {
+			progressReporter.Info("Skipping risk with ticket " + ticket + " not managed by " + tracker.Name() + ": " + risk.SyntheticId)
+			continue
+		}
+
+		if len(ticket) == 0 {
+			if !status.IsStillAtRisk() {
+				continue
+			}
+			created, err := tracker.Create(riskIssue(parsedModel, risk))
+			if err != nil {
+				return actions, fmt.Errorf("unable to create ticket for risk %v: %w", risk.SyntheticId, err)
+			}
+			entry := modelRiskTracking(modelInput, risk, tracking)
+			entry.Ticket = created
+			modelInput.RiskTracking[risk.SyntheticId] = entry
+			actions = append(actions, "creating ticket "+created+" for risk: "+risk.SyntheticId)
+			continue
+		}
+
+		state, err := tracker.State(ticket)
+		if err != nil {
+			return actions, fmt.Errorf("unable to get state of ticket %v of risk %v: %w", ticket, risk.SyntheticId, err)
+		}
+
+		switch state {
+		case IssueOpen:
+			if !status.IsStillAtRisk() {
+				continue
+			}
+			err = tracker.Update(ticket, riskIssue(parsedModel, risk))
+			if err != nil {
+				return actions, fmt.Errorf("unable to update ticket %v of risk %v: %w", ticket, risk.SyntheticId, err)
+			}
+			actions = append(actions, "updating ticket "+ticket+" of risk: "+risk.SyntheticId)
+
+		case IssueClosed:
+			if status != types.Mitigated {
+				pullStatus(modelInput, risk, tracking, ticket, types.Mitigated)
+				actions = append(actions, "setting status "+types.Mitigated.String()+" from ticket "+ticket+" of risk: "+risk.SyntheticId)
+			}
+
+		case IssueAccepted:
+			if tracking.Expired {
+				progressReporter.Warn("WARNING: Ticket " + ticket + " is still accepted, but the risk tracking has expired: " + risk.SyntheticId)
+				continue
+			}
+			if status != types.Accepted {
+				pullStatus(modelInput, risk, tracking, ticket, types.Accepted)
+				actions = append(actions, "setting status "+types.Accepted.String()+" from ticket "+ticket+" of risk: "+risk.SyntheticId)
+			}
+		}
+	}
+	return actions, nil
+}
+
+func pullStatus(modelInput *input.Model, risk types.Risk, tracking types.RiskTracking, ticket string, status types.RiskStatus) {
+	entry := modelRiskTracking(modelInput, risk, tracking)
+	entry.Status = status.String()
+	entry.Date = types.Today().Format("2006-01-02")
+	if len(strings.TrimSpace(entry.Justification)) == 0 {
+		entry.Justification = "Status taken from ticket " + ticket
+	}
+	modelInput.RiskTracking[risk.SyntheticId] = entry
+}
+
+// modelRiskTracking returns the risk tracking of the risk in the model input: either the existing one or a new one
+// initialized from the (e.g. wildcard) risk tracking applied to the risk
+func modelRiskTracking(modelInput *input.Model, risk types.Risk, tracking types.RiskTracking) input.RiskTracking {
+	if existing, ok := modelInput.RiskTracking[risk.SyntheticId]; ok {
+		return existing
+	}
+
+	entry := input.RiskTracking{
+		Status:        tracking.Status.String(),
+		Justification: tracking.Justification,
+		Ticket:        tracking.Ticket,
+		CheckedBy:     tracking.CheckedBy,
+		Owner:         tracking.Owner,
+	}
+	if tracking.Expired {
+		entry.Status = tracking.ExpiredStatus.String()
+	}
+	if !tracking.Date.IsZero() {
+		entry.Date = tracking.Date.Format("2006-01-02")
+	}
+	if !tracking.Expires.IsZero() {
+		entry.Expires = tracking.Expires.Format("2006-01-02")
+	}
+	if !tracking.ReviewBy.IsZero() {
+		entry.ReviewBy = tracking.ReviewBy.Format("2006-01-02")
+	}
+	return entry
+}
+
+var formattingTags = regexp.MustCompile(`<[^>]*>`)
+
+func riskIssue(parsedModel *types.ParsedModel, risk types.Risk) Issue {
+	var body strings.Builder
+	body.WriteString("**Risk ID:** `" + risk.SyntheticId + "`\n\n")
+	body.WriteString("**Severity:** " + risk.Severity.Title() + " (" + risk.ExploitationLikelihood.Title() + " likelihood, " +
+		risk.ExploitationImpact.Title() + " impact)\n\n")
+
+	category := types.GetRiskCategory(parsedModel, risk.CategoryId)
+	if category != nil {
+		body.WriteString("**Category:** " + category.Title)
+		if category.CWE > 0 {
+			body.WriteString(" (CWE-" + strconv.Itoa(category.CWE) + ")")
+		}
+		body.WriteString("\n\n")
+		if len(category.Action) > 0 {
+			body.WriteString("## " + formattingTags.ReplaceAllString(category.Action, "") + "\n\n")
+		}
+		body.WriteString("### Mitigation\n\n" + formattingTags.ReplaceAllString(category.Mitigation, "") + "\n\n")
+		if len(category.Check) > 0 {
+			body.WriteString("### Check\n\n" + formattingTags.ReplaceAllString(category.Check, "") + "\n\n")
+		}
+		if len(category.ASVS) > 0 {
+			body.WriteString("ASVS: " + category.ASVS + "\n\n")
+		}
+		if len(category.CheatSheet) > 0 {
+			body.WriteString("Cheat Sheet: " + category.CheatSheet + "\n\n")
+		}
+	}
+	body.WriteString("_This ticket is synchronized by Threagile. Close it once the risk is mitigated, " +
+		"mark it as accepted when the risk is accepted._\n")
+
+	return Issue{
+		Title:  formattingTags.ReplaceAllString(risk.Title, ""),
+		Body:   body.String(),
+		Labels: []string{"threagile", "severity:" + risk.Severity.String()},
+	}
+}
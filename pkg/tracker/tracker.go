@@ -0,0 +1,143 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/common"
+)
+
+const (
+	TokenEnvironmentVariable = "THREAGILE_TRACKER_TOKEN"
+	UserEnvironmentVariable  = "THREAGILE_TRACKER_USER" // only used by jira (basic auth with an API token)
+
+	requestTimeout = 30 * time.Second
+)
+
+type IssueState string
+
+const (
+	IssueOpen     IssueState = "open"
+	IssueClosed   IssueState = "closed"   // the risk has been mitigated
+	IssueAccepted IssueState = "accepted" // the risk has been accepted, closed as not planned or labeled as accepted
+)
+
+// Issue is the tracker independent content of a ticket created for a risk
+type Issue struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// Tracker creates and updates the tickets of risks, tickets are referenced by the strings stored in the risk tracking
+type Tracker interface {
+	Name() string
+	// Owns tells whether the ticket reference belongs to this tracker, tickets of other systems are left untouched
+	Owns(ticket string) bool
+	Create(issue Issue) (ticket string, err error)
+	Update(ticket string, issue Issue) error
+	State(ticket string) (IssueState, error)
+}
+
+func NewTracker(config common.TrackerConfig) (Tracker, error) {
+	if len(config.Project) == 0 {
+		return nil, fmt.Errorf("no tracker project given")
+	}
+	acceptedLabel := config.AcceptedLabel
+	if len(acceptedLabel) == 0 {
+		acceptedLabel = common.DefaultTrackerAcceptedLabel
+	}
+	token := os.Getenv(TokenEnvironmentVariable)
+
+	switch strings.ToLower(config.Type) {
+	case "github":
+		return newGitHubTracker(config.URL, config.Project, token, acceptedLabel), nil
+	case "gitlab":
+		return newGitLabTracker(config.URL, config.Project, token, acceptedLabel), nil
+	case "jira":
+		if len(config.URL) == 0 {
+			return nil, fmt.Errorf("no jira URL given")
+		}
+		return newJiraTracker(config.URL, config.Project, os.Getenv(UserEnvironmentVariable), token, acceptedLabel), nil
+	case "file":
+		return newFileTracker(config.Project, acceptedLabel), nil
+	case "":
+		return nil, fmt.Errorf("no tracker type given (github, gitlab, jira or file)")
+	default:
+		return nil, fmt.Errorf("unknown tracker type %q (expected github, gitlab, jira or file)", config.Type)
+	}
+}
+
+// restClient sends JSON requests to the API of a tracker
+type restClient struct {
+	baseURL       string
+	authorize     func(request *http.Request)
+	client        *http.Client
+	maxErrorBytes int64
+}
+
+func newRestClient(baseURL string, authorize func(request *http.Request)) *restClient {
+	return &restClient{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		authorize:     authorize,
+		client:        &http.Client{Timeout: requestTimeout},
+		maxErrorBytes: 512,
+	}
+}
+
+func (what *restClient) do(method string, path string, requestBody any, responseBody any) error {
+	var body io.Reader
+	if requestBody != nil {
+		data, err := json.Marshal(requestBody)
+		if err != nil {
+			return fmt.Errorf("unable to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequest(method, what.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	request.Header.Set("Accept", "application/json")
+	if requestBody != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if what.authorize != nil {
+		what.authorize(request)
+	}
+
+	response, err := what.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("%v %v failed: %w", method, path, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, what.maxErrorBytes))
+		return fmt.Errorf("%v %v failed with status %v: %v", method, path, response.Status, strings.TrimSpace(string(message)))
+	}
+	if responseBody == nil {
+		return nil
+	}
+	err = json.NewDecoder(response.Body).Decode(responseBody)
+	if err != nil {
+		return fmt.Errorf("unable to parse response of %v %v: %w", method, path, err)
+	}
+	return nil
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, candidate := range labels {
+		if strings.EqualFold(candidate, label) {
+			return true
+		}
+	}
+	return false
+}
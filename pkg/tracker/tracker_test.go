@@ -0,0 +1,227 @@
+package tracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type nullProgressReporter struct{}
+
+func (nullProgressReporter) Info(a ...any)  {}
+func (nullProgressReporter) Warn(a ...any)  {}
+func (nullProgressReporter) Error(a ...any) {}
+
+func syncTestModel() *types.ParsedModel {
+	return &types.ParsedModel{
+		GeneratedRisksBySyntheticId: map[string]types.Risk{
+			"some-category@a": {CategoryId: "some-category", SyntheticId: "some-category@a", Title: "<b>Some</b> risk at A", Severity: types.HighSeverity},
+			"some-category@b": {CategoryId: "some-category", SyntheticId: "some-category@b", Title: "<b>Some</b> risk at B", Severity: types.MediumSeverity},
+			"some-category@c": {CategoryId: "some-category", SyntheticId: "some-category@c", Title: "<b>Some</b> risk at C", Severity: types.LowSeverity},
+			"some-category@d": {CategoryId: "some-category", SyntheticId: "some-category@d", Title: "<b>Some</b> risk at D", Severity: types.LowSeverity},
+		},
+		BuiltInRiskCategories: map[string]types.RiskCategory{
+			"some-category": {Id: "some-category", Title: "Some Category", Mitigation: "Do something."},
+		},
+		RiskTracking: map[string]types.RiskTracking{
+			"some-category@c": {SyntheticRiskId: "some-category@c", Status: types.Mitigated},
+			"some-category@d": {SyntheticRiskId: "some-category@d", Status: types.InProgress, Ticket: "XYZ-1234"},
+		},
+	}
+}
+
+func TestSyncWithFileTracker(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "issues.json")
+	issueTracker := newFileTracker(filename, "risk-accepted")
+	parsedModel := syncTestModel()
+	modelInput := new(input.Model).Defaults()
+
+	actions, err := Sync(issueTracker, modelInput, parsedModel, nullProgressReporter{})
+	assert.NoError(t, err)
+	assert.Len(t, actions, 2)
+	assert.Equal(t, "LOCAL-1", modelInput.RiskTracking["some-category@a"].Ticket)
+	assert.Equal(t, "unchecked", modelInput.RiskTracking["some-category@a"].Status)
+	assert.Equal(t, "LOCAL-2", modelInput.RiskTracking["some-category@b"].Ticket)
+	assert.NotContains(t, modelInput.RiskTracking, "some-category@c") // not at risk
+	assert.NotContains(t, modelInput.RiskTracking, "some-category@d") // ticket of another tracker
+
+	issues, err := issueTracker.load()
+	assert.NoError(t, err)
+	assert.Equal(t, "Some risk at A", issues.Issues["LOCAL-1"].Title)
+	assert.Contains(t, issues.Issues["LOCAL-1"].Body, "Do something.")
+
+	issue := issues.Issues["LOCAL-1"]
+	issue.State = IssueClosed
+	issues.Issues["LOCAL-1"] = issue
+	issue = issues.Issues["LOCAL-2"]
+	issue.Labels = append(issue.Labels, "risk-accepted")
+	issues.Issues["LOCAL-2"] = issue
+	assert.NoError(t, issueTracker.save(issues))
+
+	parsedModel.RiskTracking["some-category@a"] = types.RiskTracking{SyntheticRiskId: "some-category@a", Ticket: "LOCAL-1"}
+	parsedModel.RiskTracking["some-category@b"] = types.RiskTracking{SyntheticRiskId: "some-category@b", Ticket: "LOCAL-2"}
+	actions, err = Sync(issueTracker, modelInput, parsedModel, nullProgressReporter{})
+	assert.NoError(t, err)
+	assert.Len(t, actions, 2)
+	assert.Equal(t, "mitigated", modelInput.RiskTracking["some-category@a"].Status)
+	assert.Equal(t, "accepted", modelInput.RiskTracking["some-category@b"].Status)
+	assert.NotEmpty(t, modelInput.RiskTracking["some-category@b"].Date)
+}
+
+func TestGitHubTracker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer secret", request.Header.Get("Authorization"))
+		switch request.Method + " " + request.URL.Path {
+		case "POST /repos/acme/shop/issues":
+			var issue map[string]any
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&issue))
+			assert.Equal(t, "Some risk at A", issue["title"])
+			_, _ = response.Write([]byte(`{"number": 42}`))
+		case "GET /repos/acme/shop/issues/42":
+			_, _ = response.Write([]byte(`{"number": 42, "state": "closed", "state_reason": "completed"}`))
+		case "GET /repos/acme/shop/issues/43":
+			_, _ = response.Write([]byte(`{"number": 43, "state": "closed", "state_reason": "not_planned"}`))
+		default:
+			response.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	issueTracker := newGitHubTracker(server.URL, "acme/shop", "secret", "risk-accepted")
+
+	assert.True(t, issueTracker.Owns("acme/shop#1"))
+	assert.False(t, issueTracker.Owns("acme/other#1"))
+	assert.False(t, issueTracker.Owns("XYZ-1234"))
+
+	ticket, err := issueTracker.Create(Issue{Title: "Some risk at A"})
+	assert.NoError(t, err)
+	assert.Equal(t, "acme/shop#42", ticket)
+
+	state, err := issueTracker.State("acme/shop#42")
+	assert.NoError(t, err)
+	assert.Equal(t, IssueClosed, state)
+	state, err = issueTracker.State("acme/shop#43")
+	assert.NoError(t, err)
+	assert.Equal(t, IssueAccepted, state)
+	_, err = issueTracker.State("acme/shop#44")
+	assert.Error(t, err)
+}
+
+func TestGitLabTracker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "secret", request.Header.Get("PRIVATE-TOKEN"))
+		switch request.Method + " " + request.URL.EscapedPath() {
+		case "POST /api/v4/projects/acme%2Fshop/issues":
+			var issue map[string]any
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&issue))
+			assert.Equal(t, "Some risk at A", issue["title"])
+			assert.Equal(t, "Do something.", issue["description"])
+			assert.Equal(t, "threagile,high", issue["labels"])
+			_, _ = response.Write([]byte(`{"iid": 42}`))
+		case "PUT /api/v4/projects/acme%2Fshop/issues/42":
+			var issue map[string]any
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&issue))
+			assert.Equal(t, "Some risk at B", issue["title"])
+			_, _ = response.Write([]byte(`{"iid": 42}`))
+		case "GET /api/v4/projects/acme%2Fshop/issues/42":
+			_, _ = response.Write([]byte(`{"iid": 42, "state": "closed"}`))
+		case "GET /api/v4/projects/acme%2Fshop/issues/43":
+			_, _ = response.Write([]byte(`{"iid": 43, "state": "closed", "labels": ["threagile", "risk-accepted"]}`))
+		case "GET /api/v4/projects/acme%2Fshop/issues/44":
+			_, _ = response.Write([]byte(`{"iid": 44, "state": "opened"}`))
+		default:
+			response.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	issueTracker := newGitLabTracker(server.URL, "acme/shop", "secret", "risk-accepted")
+
+	assert.True(t, issueTracker.Owns("acme/shop#1"))
+	assert.False(t, issueTracker.Owns("acme/shop#one"))
+	assert.False(t, issueTracker.Owns("acme/other#1"))
+	assert.False(t, issueTracker.Owns("XYZ-1234"))
+
+	ticket, err := issueTracker.Create(Issue{Title: "Some risk at A", Body: "Do something.", Labels: []string{"threagile", "high"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "acme/shop#42", ticket)
+	assert.NoError(t, issueTracker.Update(ticket, Issue{Title: "Some risk at B"}))
+	assert.Error(t, issueTracker.Update("acme/other#42", Issue{Title: "Some risk at B"}))
+
+	for ticket, expected := range map[string]IssueState{"acme/shop#42": IssueClosed, "acme/shop#43": IssueAccepted, "acme/shop#44": IssueOpen} {
+		state, err := issueTracker.State(ticket)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, state, ticket)
+	}
+	_, err = issueTracker.State("acme/shop#45")
+	assert.Error(t, err)
+}
+
+func TestJiraTracker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		user, token, ok := request.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "jane", user)
+		assert.Equal(t, "secret", token)
+		if request.Method == http.MethodGet {
+			assert.Equal(t, "status,resolution,labels", request.URL.Query().Get("fields"))
+		}
+		switch request.Method + " " + request.URL.Path {
+		case "POST /rest/api/2/issue":
+			var issue struct {
+				Fields map[string]any `json:"fields"`
+			}
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&issue))
+			assert.Equal(t, map[string]any{"key": "SEC"}, issue.Fields["project"])
+			assert.Equal(t, "Some risk at A", issue.Fields["summary"])
+			assert.Equal(t, []any{"threagile", "some-category"}, issue.Fields["labels"])
+			_, _ = response.Write([]byte(`{"key": "SEC-42"}`))
+		case "PUT /rest/api/2/issue/SEC-42":
+			var issue struct {
+				Fields map[string]any `json:"fields"`
+			}
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&issue))
+			assert.Equal(t, "Some risk at B", issue.Fields["summary"])
+			response.WriteHeader(http.StatusNoContent)
+		case "GET /rest/api/2/issue/SEC-42":
+			_, _ = response.Write([]byte(`{"key": "SEC-42", "fields": {"status": {"statusCategory": {"key": "done"}}, "resolution": {"name": "Done"}}}`))
+		case "GET /rest/api/2/issue/SEC-43":
+			_, _ = response.Write([]byte(`{"key": "SEC-43", "fields": {"status": {"statusCategory": {"key": "done"}}, "resolution": {"name": "Won't Fix"}}}`))
+		case "GET /rest/api/2/issue/SEC-44":
+			_, _ = response.Write([]byte(`{"key": "SEC-44", "fields": {"labels": ["risk-accepted"], "status": {"statusCategory": {"key": "indeterminate"}}}}`))
+		case "GET /rest/api/2/issue/SEC-45":
+			_, _ = response.Write([]byte(`{"key": "SEC-45", "fields": {"status": {"statusCategory": {"key": "new"}}}}`))
+		default:
+			response.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	issueTracker := newJiraTracker(server.URL, "SEC", "jane", "secret", "risk-accepted")
+
+	assert.True(t, issueTracker.Owns("SEC-1"))
+	assert.False(t, issueTracker.Owns("SEC-one"))
+	assert.False(t, issueTracker.Owns("XYZ-1234"))
+	assert.False(t, issueTracker.Owns("acme/shop#1"))
+
+	ticket, err := issueTracker.Create(Issue{Title: "Some risk at A", Labels: []string{"threagile", "some category"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "SEC-42", ticket)
+	assert.NoError(t, issueTracker.Update(ticket, Issue{Title: "Some risk at B"}))
+	assert.Error(t, issueTracker.Update("XYZ-42", Issue{Title: "Some risk at B"}))
+
+	for ticket, expected := range map[string]IssueState{"SEC-42": IssueClosed, "SEC-43": IssueAccepted, "SEC-44": IssueAccepted, "SEC-45": IssueOpen} {
+		state, err := issueTracker.State(ticket)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, state, ticket)
+	}
+	_, err = issueTracker.State("SEC-46")
+	assert.Error(t, err)
+}